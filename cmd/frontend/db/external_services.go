@@ -31,6 +31,7 @@ type ExternalServicesStore struct {
 	GitHubValidators          []func(*schema.GitHubConnection) error
 	GitLabValidators          []func(*schema.GitLabConnection, []schema.AuthProviders) error
 	BitbucketServerValidators []func(*schema.BitbucketServerConnection) error
+	GerritValidators          []func(*schema.GerritConnection) error
 }
 
 // ExternalServiceKinds contains a map of all supported kinds of
@@ -39,9 +40,11 @@ var ExternalServiceKinds = map[string]ExternalServiceKind{
 	extsvc.KindAWSCodeCommit:   {CodeHost: true, JSONSchema: schema.AWSCodeCommitSchemaJSON},
 	extsvc.KindBitbucketCloud:  {CodeHost: true, JSONSchema: schema.BitbucketCloudSchemaJSON},
 	extsvc.KindBitbucketServer: {CodeHost: true, JSONSchema: schema.BitbucketServerSchemaJSON},
+	extsvc.KindGerrit:          {CodeHost: true, JSONSchema: schema.GerritSchemaJSON},
 	extsvc.KindGitHub:          {CodeHost: true, JSONSchema: schema.GitHubSchemaJSON},
 	extsvc.KindGitLab:          {CodeHost: true, JSONSchema: schema.GitLabSchemaJSON},
 	extsvc.KindGitolite:        {CodeHost: true, JSONSchema: schema.GitoliteSchemaJSON},
+	extsvc.KindManifest:        {CodeHost: true, JSONSchema: schema.ManifestSchemaJSON},
 	extsvc.KindPhabricator:     {CodeHost: true, JSONSchema: schema.PhabricatorSchemaJSON},
 	extsvc.KindOther:           {CodeHost: true, JSONSchema: schema.OtherExternalServiceSchemaJSON},
 }
@@ -139,12 +142,26 @@ func (e *ExternalServicesStore) ValidateConfig(ctx context.Context, id int64, ki
 		}
 		err = e.validateBitbucketCloudConnection(ctx, id, &c)
 
+	case extsvc.KindGerrit:
+		var c schema.GerritConnection
+		if err = json.Unmarshal(normalized, &c); err != nil {
+			return err
+		}
+		err = e.validateGerritConnection(&c)
+
 	case extsvc.KindOther:
 		var c schema.OtherExternalServiceConnection
 		if err = json.Unmarshal(normalized, &c); err != nil {
 			return err
 		}
 		err = validateOtherExternalServiceConnection(&c)
+
+	case extsvc.KindManifest:
+		var c schema.ManifestConnection
+		if err = json.Unmarshal(normalized, &c); err != nil {
+			return err
+		}
+		err = validateManifestConnection(&c)
 	}
 
 	return multierror.Append(errs, err).ErrorOrNil()
@@ -179,6 +196,19 @@ func validateOtherExternalServiceConnection(c *schema.OtherExternalServiceConnec
 	return nil
 }
 
+// validateManifestConnection validates that the manifest is either fetched from
+// a URL or read from a file in a repository, which JSON Schema can't express in
+// a way the Monaco editor supports.
+func validateManifestConnection(c *schema.ManifestConnection) error {
+	switch {
+	case c.Url != "" && (c.Repository != "" || c.Path != ""):
+		return errors.New(`either "url", or "repository" and "path" must be set, but not both`)
+	case c.Url == "" && (c.Repository == "" || c.Path == ""):
+		return errors.New(`either "url", or "repository" and "path" must be set`)
+	}
+	return nil
+}
+
 func (e *ExternalServicesStore) validateGitHubConnection(ctx context.Context, id int64, c *schema.GitHubConnection) error {
 	err := new(multierror.Error)
 	for _, validate := range e.GitHubValidators {
@@ -224,6 +254,15 @@ func (e *ExternalServicesStore) validateBitbucketCloudConnection(ctx context.Con
 	return e.validateDuplicateRateLimits(ctx, id, extsvc.KindBitbucketCloud, c)
 }
 
+func (e *ExternalServicesStore) validateGerritConnection(c *schema.GerritConnection) error {
+	err := new(multierror.Error)
+	for _, validate := range e.GerritValidators {
+		err = multierror.Append(err, validate(c))
+	}
+
+	return err.ErrorOrNil()
+}
+
 func (e *ExternalServicesStore) validateDuplicateRateLimits(ctx context.Context, id int64, kind string, parsedConfig interface{}) error {
 	// Check if rate limit is already defined for this code host on another external service
 	rlc, err := extsvc.GetLimitFromConfig(kind, parsedConfig)
@@ -490,6 +529,17 @@ func (e *ExternalServicesStore) ListBitbucketServerConnections(ctx context.Conte
 	return connections, nil
 }
 
+// ListGerritConnections returns a list of GerritConnection configs.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (e *ExternalServicesStore) ListGerritConnections(ctx context.Context) ([]*types.GerritConnection, error) {
+	var connections []*types.GerritConnection
+	if err := e.listConfigs(ctx, extsvc.KindGerrit, &connections); err != nil {
+		return nil, err
+	}
+	return connections, nil
+}
+
 // ListGitHubConnections returns a list of GitHubConnection configs.
 //
 // 🚨 SECURITY: The caller must ensure that the actor is a site admin.
@@ -115,6 +115,30 @@ func TestExternalServicesStore_ValidateConfig(t *testing.T) {
 			},
 			wantErr: "1 error occurred:\n\t* existing external service, \"GITHUB 1\", already has a rate limit set\n\n",
 		},
+		{
+			name:    "manifest from url",
+			kind:    extsvc.KindManifest,
+			config:  `{"url": "https://build.mycorp.com/repos.json"}`,
+			wantErr: "<nil>",
+		},
+		{
+			name:    "manifest from repository",
+			kind:    extsvc.KindManifest,
+			config:  `{"repository": "git.mycorp.com/infra/repos", "path": "repos.yaml"}`,
+			wantErr: "<nil>",
+		},
+		{
+			name:    "manifest without path",
+			kind:    extsvc.KindManifest,
+			config:  `{"repository": "git.mycorp.com/infra/repos"}`,
+			wantErr: "1 error occurred:\n\t* either \"url\", or \"repository\" and \"path\" must be set\n\n",
+		},
+		{
+			name:    "manifest from url and repository",
+			kind:    extsvc.KindManifest,
+			config:  `{"url": "https://build.mycorp.com/repos.json", "repository": "git.mycorp.com/infra/repos", "path": "repos.yaml"}`,
+			wantErr: "1 error occurred:\n\t* either \"url\", or \"repository\" and \"path\" must be set, but not both\n\n",
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -0,0 +1,164 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbutil"
+)
+
+// ListGitserverPlacements returns the gitserver addresses of the repositories
+// that were moved off the gitserver their name hashes to, by repository name.
+// The placements override the hashing of gitserver.Client.AddrForRepo.
+func (*repos) ListGitserverPlacements(ctx context.Context) (map[api.RepoName]string, error) {
+	if Mocks.Repos.ListGitserverPlacements != nil {
+		return Mocks.Repos.ListGitserverPlacements(ctx)
+	}
+
+	q := sqlf.Sprintf(`
+SELECT repo.name, p.gitserver_addr
+FROM gitserver_repo_placements p
+JOIN repo ON repo.id = p.repo_id
+WHERE repo.deleted_at IS NULL`)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	placements := map[api.RepoName]string{}
+	for rows.Next() {
+		var (
+			name api.RepoName
+			addr string
+		)
+		if err := rows.Scan(&name, &addr); err != nil {
+			return nil, err
+		}
+		placements[name] = addr
+	}
+	return placements, rows.Err()
+}
+
+// SetGitserverPlacement places the given repository on the gitserver at
+// addr, replacing its previous placement. An empty addr removes the
+// placement, so that the repository is placed by hashing its name again.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (*repos) SetGitserverPlacement(ctx context.Context, repoID api.RepoID, addr string) error {
+	if Mocks.Repos.SetGitserverPlacement != nil {
+		return Mocks.Repos.SetGitserverPlacement(ctx, repoID, addr)
+	}
+
+	q := sqlf.Sprintf("DELETE FROM gitserver_repo_placements WHERE repo_id=%d", repoID)
+	if addr != "" {
+		q = sqlf.Sprintf(`
+INSERT INTO gitserver_repo_placements (repo_id, gitserver_addr)
+VALUES (%d, %s)
+ON CONFLICT (repo_id) DO UPDATE SET gitserver_addr = excluded.gitserver_addr, updated_at = now()`,
+			repoID, addr,
+		)
+	}
+	_, err := dbconn.Global.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	return err
+}
+
+// The states of a GitserverRepoMove.
+const (
+	GitserverRepoMoveMoving = "MOVING"
+	GitserverRepoMoveMoved  = "MOVED"
+	GitserverRepoMoveFailed = "FAILED"
+)
+
+// GitserverRepoMove is the latest move of a repository to another gitserver.
+type GitserverRepoMove struct {
+	RepoID         api.RepoID
+	Gitserver      string
+	State          string
+	FailureMessage string
+	StartedAt      time.Time
+	FinishedAt     time.Time
+}
+
+// GetGitserverMove returns the latest move of the given repository to another
+// gitserver, or nil if it was never moved.
+func (*repos) GetGitserverMove(ctx context.Context, repoID api.RepoID) (*GitserverRepoMove, error) {
+	if Mocks.Repos.GetGitserverMove != nil {
+		return Mocks.Repos.GetGitserverMove(ctx, repoID)
+	}
+
+	q := sqlf.Sprintf(`
+SELECT repo_id, gitserver_addr, state, failure_message, started_at, finished_at
+FROM gitserver_repo_moves
+WHERE repo_id=%d`, repoID)
+	var m GitserverRepoMove
+	err := dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(
+		&m.RepoID,
+		&m.Gitserver,
+		&m.State,
+		&dbutil.NullString{S: &m.FailureMessage},
+		&m.StartedAt,
+		&dbutil.NullTime{Time: &m.FinishedAt},
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// StartGitserverMove records that the given repository is being moved to the
+// gitserver at addr. It reports false if another move of the repository
+// started less than timeout ago and didn't finish yet, in which case nothing
+// is recorded.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (*repos) StartGitserverMove(ctx context.Context, repoID api.RepoID, addr string, timeout time.Duration) (started bool, err error) {
+	if Mocks.Repos.StartGitserverMove != nil {
+		return Mocks.Repos.StartGitserverMove(ctx, repoID, addr, timeout)
+	}
+
+	q := sqlf.Sprintf(`
+INSERT INTO gitserver_repo_moves (repo_id, gitserver_addr, state)
+VALUES (%d, %s, %s)
+ON CONFLICT (repo_id) DO UPDATE
+SET gitserver_addr = excluded.gitserver_addr, state = excluded.state, failure_message = NULL, started_at = now(), finished_at = NULL
+WHERE gitserver_repo_moves.state <> %s OR gitserver_repo_moves.started_at < now() - (%d * interval '1 second')`,
+		repoID, addr, GitserverRepoMoveMoving,
+		GitserverRepoMoveMoving, int64(timeout/time.Second),
+	)
+	res, err := dbconn.Global.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return false, err
+	}
+	nrows, err := res.RowsAffected()
+	return nrows > 0, err
+}
+
+// FinishGitserverMove records that the move of the given repository started
+// with StartGitserverMove finished. An empty failureMessage means that the
+// repository was moved.
+func (*repos) FinishGitserverMove(ctx context.Context, repoID api.RepoID, failureMessage string) error {
+	if Mocks.Repos.FinishGitserverMove != nil {
+		return Mocks.Repos.FinishGitserverMove(ctx, repoID, failureMessage)
+	}
+
+	state := GitserverRepoMoveMoved
+	if failureMessage != "" {
+		state = GitserverRepoMoveFailed
+	}
+	q := sqlf.Sprintf(`
+UPDATE gitserver_repo_moves
+SET state = %s, failure_message = NULLIF(%s, ''), finished_at = now()
+WHERE repo_id=%d`,
+		state, failureMessage, repoID,
+	)
+	_, err := dbconn.Global.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	return err
+}
@@ -0,0 +1,94 @@
+package db
+
+import (
+	"context"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+)
+
+// RepoKVP is a key-value pair set on a repository by a site admin. A nil
+// Value means the key is set without a value.
+type RepoKVP struct {
+	Key   string
+	Value *string
+}
+
+// ListKVPs returns the key-value pairs of the given repository, ordered by key.
+func (*repos) ListKVPs(ctx context.Context, repoID api.RepoID) ([]RepoKVP, error) {
+	if Mocks.Repos.ListKVPs != nil {
+		return Mocks.Repos.ListKVPs(ctx, repoID)
+	}
+
+	q := sqlf.Sprintf("SELECT key, value FROM repo_kvps WHERE repo_id=%d ORDER BY key", repoID)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var kvps []RepoKVP
+	for rows.Next() {
+		var kvp RepoKVP
+		if err := rows.Scan(&kvp.Key, &kvp.Value); err != nil {
+			return nil, err
+		}
+		kvps = append(kvps, kvp)
+	}
+	return kvps, rows.Err()
+}
+
+// SetKVP sets the key-value pair on the given repository, replacing the value
+// of the key if it is already set. An error occurs if the repository does not
+// exist.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (s *repos) SetKVP(ctx context.Context, repoID api.RepoID, kvp RepoKVP) error {
+	if Mocks.Repos.SetKVP != nil {
+		return Mocks.Repos.SetKVP(ctx, repoID, kvp)
+	}
+
+	if _, err := s.Get(ctx, repoID); err != nil {
+		return err
+	}
+
+	q := sqlf.Sprintf(`
+INSERT INTO repo_kvps (repo_id, key, value)
+VALUES (%d, %s, %s)
+ON CONFLICT (repo_id, key) DO UPDATE SET value = excluded.value`,
+		repoID, kvp.Key, kvp.Value,
+	)
+	_, err := dbconn.Global.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	return err
+}
+
+// DeleteKVP removes the key and its value from the given repository. Removing
+// a key that isn't set is not an error.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (*repos) DeleteKVP(ctx context.Context, repoID api.RepoID, key string) error {
+	if Mocks.Repos.DeleteKVP != nil {
+		return Mocks.Repos.DeleteKVP(ctx, repoID, key)
+	}
+
+	q := sqlf.Sprintf("DELETE FROM repo_kvps WHERE repo_id=%d AND key=%s", repoID, key)
+	_, err := dbconn.Global.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	return err
+}
+
+// repoHasTopicSQL returns a condition that matches the repos that have the
+// given topic.
+func repoHasTopicSQL(topic string) *sqlf.Query {
+	return sqlf.Sprintf("EXISTS (SELECT 1 FROM unnest(topics) AS topic WHERE lower(topic) = lower(%s))", topic)
+}
+
+// repoHasKVPSQL returns a condition that matches the repos that have the
+// given key-value pair, or the given key with any value if kvp.Value is nil.
+// Like topics, keys and values are matched case-insensitively.
+func repoHasKVPSQL(kvp RepoKVP) *sqlf.Query {
+	if kvp.Value == nil {
+		return sqlf.Sprintf("EXISTS (SELECT 1 FROM repo_kvps WHERE repo_id = repo.id AND lower(key) = lower(%s))", kvp.Key)
+	}
+	return sqlf.Sprintf("EXISTS (SELECT 1 FROM repo_kvps WHERE repo_id = repo.id AND lower(key) = lower(%s) AND lower(value) = lower(%s))", kvp.Key, *kvp.Value)
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db/query"
@@ -64,7 +65,9 @@ func (s *repos) Get(ctx context.Context, id api.RepoID) (*types.Repo, error) {
 
 // GetByName returns the repository with the given nameOrUri from the
 // database, or an error. If we have a match on name and uri, we prefer the
-// match on name.
+// match on name. If neither matches, the repository that was renamed from
+// the given name on its code host is returned, so callers can tell a renamed
+// repository by comparing names and redirect to its new name.
 //
 // Name is the name for this repository (e.g., "github.com/user/repo"). It is
 // the same as URI, unless the user configures a non-default
@@ -91,6 +94,15 @@ func (s *repos) GetByName(ctx context.Context, nameOrURI api.RepoName) (*types.R
 		return nil, err
 	}
 
+	if len(repos) == 1 {
+		return repos[0], nil
+	}
+
+	repos, err = s.getBySQL(ctx, sqlf.Sprintf("id=(SELECT repo_id FROM repo_redirects WHERE name=%s) LIMIT 1", nameOrURI))
+	if err != nil {
+		return nil, err
+	}
+
 	if len(repos) == 0 {
 		return nil, &RepoNotFoundErr{Name: nameOrURI}
 	}
@@ -154,6 +166,8 @@ var getBySQLColumns = []string{
 	"language",
 	"fork",
 	"archived",
+	"topics",
+	"stars",
 }
 
 func (s *repos) getBySQL(ctx context.Context, querySuffix *sqlf.Query) ([]*types.Repo, error) {
@@ -222,6 +236,8 @@ func scanRepo(rows *sql.Rows, r *types.Repo) (err error) {
 		&r.Language,
 		&r.Fork,
 		&r.Archived,
+		pq.Array(&r.Topics),
+		&r.Stars,
 	)
 }
 
@@ -270,6 +286,23 @@ type ReposListOptions struct {
 	// OnlyPrivate excludes non-private repositories from the list.
 	OnlyPrivate bool
 
+	// Topics is a list of topics, all of which the repositories returned in
+	// the list must have. Topics are matched case-insensitively.
+	Topics []string
+
+	// ExcludeTopics is a list of topics, none of which the repositories
+	// returned in the list may have.
+	ExcludeTopics []string
+
+	// KVPs is a list of key-value pairs, all of which the repositories
+	// returned in the list must have. A pair with a nil Value matches any
+	// value of its key.
+	KVPs []RepoKVP
+
+	// ExcludeKVPs is a list of key-value pairs, none of which the
+	// repositories returned in the list may have.
+	ExcludeKVPs []RepoKVP
+
 	// OnlyRepoIDs skips fetching of RepoFields in each Repo.
 	OnlyRepoIDs bool
 
@@ -464,6 +497,18 @@ func (*repos) listSQL(opt ReposListOptions) (conds []*sqlf.Query, err error) {
 	if opt.OnlyPrivate {
 		conds = append(conds, sqlf.Sprintf("private"))
 	}
+	for _, topic := range opt.Topics {
+		conds = append(conds, repoHasTopicSQL(topic))
+	}
+	for _, topic := range opt.ExcludeTopics {
+		conds = append(conds, sqlf.Sprintf("NOT %s", repoHasTopicSQL(topic)))
+	}
+	for _, kvp := range opt.KVPs {
+		conds = append(conds, repoHasKVPSQL(kvp))
+	}
+	for _, kvp := range opt.ExcludeKVPs {
+		conds = append(conds, sqlf.Sprintf("NOT %s", repoHasKVPSQL(kvp)))
+	}
 	if len(opt.Names) > 0 {
 		queries := make([]*sqlf.Query, 0, len(opt.Names))
 		for _, repo := range opt.Names {
@@ -6,6 +6,7 @@ import (
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/keegancsmith/sqlf"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
@@ -15,6 +16,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
 )
 
 /*
@@ -203,6 +205,44 @@ func TestRepos_Get(t *testing.T) {
 	}
 }
 
+func TestRepos_GetByName_redirect(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	want := mustCreate(ctx, t, &types.Repo{Name: "github.com/foo/new"}, &types.Repo{Name: "github.com/foo/old"})
+
+	q := sqlf.Sprintf("INSERT INTO repo_redirects (name, repo_id) VALUES (%s, %d), (%s, %d)",
+		"github.com/foo/older", want[0].ID,
+		"github.com/foo/old", want[0].ID,
+	)
+	if _, err := dbconn.Global.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, wantName := range map[api.RepoName]api.RepoName{
+		"github.com/foo/new":   "github.com/foo/new",
+		"github.com/foo/older": "github.com/foo/new",
+		"github.com/Foo/Older": "github.com/foo/new",
+		"github.com/foo/old":   "github.com/foo/old", // existing repos take precedence
+	} {
+		repo, err := Repos.GetByName(ctx, name)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		if repo.Name != wantName {
+			t.Errorf("%s: have repo %q, want %q", name, repo.Name, wantName)
+		}
+	}
+
+	if _, err := Repos.GetByName(ctx, "github.com/foo/unknown"); !errcode.IsNotFound(err) {
+		t.Errorf("have error %v, want not found", err)
+	}
+}
+
 func TestRepos_GetByIDs(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
@@ -306,6 +346,113 @@ func TestRepos_List_fork(t *testing.T) {
 	}
 }
 
+func TestRepos_List_metadata(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	MockAuthzFilter = func(ctx context.Context, repos []*types.Repo, p authz.Perms) ([]*types.Repo, error) {
+		return repos, nil
+	}
+	defer func() { MockAuthzFilter = nil }()
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+	ctx = actor.WithActor(ctx, &actor.Actor{})
+
+	payments := mustCreate(ctx, t, &types.Repo{Name: "a/payments"})[0]
+	search := mustCreate(ctx, t, &types.Repo{Name: "b/search"})[0]
+
+	if _, err := dbconn.Global.ExecContext(ctx, "UPDATE repo SET topics = '{Payments,go}' WHERE id = $1", payments.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dbconn.Global.ExecContext(ctx, "UPDATE repo SET topics = '{go}' WHERE id = $1", search.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	teamA := "team-a"
+	for _, kvp := range []struct {
+		repo api.RepoID
+		RepoKVP
+	}{
+		{payments.ID, RepoKVP{Key: "owner", Value: &teamA}},
+		{payments.ID, RepoKVP{Key: "pci"}},
+		{search.ID, RepoKVP{Key: "owner", Value: strptr("team-b")}},
+	} {
+		if err := Repos.SetKVP(ctx, kvp.repo, kvp.RepoKVP); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tc := range []struct {
+		name string
+		opt  ReposListOptions
+		want []api.RepoName
+	}{
+		{"topic", ReposListOptions{Topics: []string{"payments"}}, []api.RepoName{"a/payments"}},
+		{"topics", ReposListOptions{Topics: []string{"go", "payments"}}, []api.RepoName{"a/payments"}},
+		{"exclude topic", ReposListOptions{Topics: []string{"go"}, ExcludeTopics: []string{"payments"}}, []api.RepoName{"b/search"}},
+		{"key", ReposListOptions{KVPs: []RepoKVP{{Key: "owner"}}}, []api.RepoName{"a/payments", "b/search"}},
+		{"key value", ReposListOptions{KVPs: []RepoKVP{{Key: "owner", Value: &teamA}}}, []api.RepoName{"a/payments"}},
+		{"key value case-insensitive", ReposListOptions{KVPs: []RepoKVP{{Key: "Owner", Value: strptr("Team-A")}}}, []api.RepoName{"a/payments"}},
+		{"key without value", ReposListOptions{KVPs: []RepoKVP{{Key: "pci"}}}, []api.RepoName{"a/payments"}},
+		{"exclude key", ReposListOptions{ExcludeKVPs: []RepoKVP{{Key: "pci"}}}, []api.RepoName{"b/search"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			repos, err := Repos.List(ctx, tc.opt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if have := sortedRepoNames(repos); !reflect.DeepEqual(have, tc.want) {
+				t.Errorf("have %v, want %v", have, tc.want)
+			}
+		})
+	}
+}
+
+func TestRepos_KVPs(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	repo := mustCreate(ctx, t, &types.Repo{Name: "a/b"})[0]
+
+	value := "team-a"
+	if err := Repos.SetKVP(ctx, repo.ID, RepoKVP{Key: "owner", Value: strptr("team-b")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Repos.SetKVP(ctx, repo.ID, RepoKVP{Key: "owner", Value: &value}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Repos.SetKVP(ctx, repo.ID, RepoKVP{Key: "pci"}); err != nil {
+		t.Fatal(err)
+	}
+
+	kvps, err := Repos.ListKVPs(ctx, repo.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []RepoKVP{{Key: "owner", Value: &value}, {Key: "pci"}}; !reflect.DeepEqual(kvps, want) {
+		t.Errorf("have %+v, want %+v", kvps, want)
+	}
+
+	if err := Repos.DeleteKVP(ctx, repo.ID, "owner"); err != nil {
+		t.Fatal(err)
+	}
+	if kvps, err = Repos.ListKVPs(ctx, repo.ID); err != nil {
+		t.Fatal(err)
+	}
+	if want := []RepoKVP{{Key: "pci"}}; !reflect.DeepEqual(kvps, want) {
+		t.Errorf("have %+v, want %+v", kvps, want)
+	}
+
+	if err := Repos.SetKVP(ctx, 1234, RepoKVP{Key: "owner"}); !errcode.IsNotFound(err) {
+		t.Errorf("have err %v, want not found", err)
+	}
+}
+
 func TestRepos_List_pagination(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
@@ -773,3 +920,81 @@ func TestRepos_Create_dupe(t *testing.T) {
 	// Add another repo with the same name.
 	createRepo(ctx, t, &types.Repo{Name: "a/b"})
 }
+
+func TestRepos_GitserverPlacements(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	repos := mustCreate(ctx, t, &types.Repo{Name: "a/monorepo"}, &types.Repo{Name: "b/other"})
+	for _, p := range []struct {
+		repo api.RepoID
+		addr string
+	}{
+		{repos[0].ID, "gitserver-1:3178"},
+		{repos[0].ID, "gitserver-big:3178"},
+		{repos[1].ID, "gitserver-1:3178"},
+		{repos[1].ID, ""},
+	} {
+		if err := Repos.SetGitserverPlacement(ctx, p.repo, p.addr); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	placements, err := Repos.ListGitserverPlacements(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[api.RepoName]string{"a/monorepo": "gitserver-big:3178"}; !reflect.DeepEqual(placements, want) {
+		t.Errorf("got placements %v, want %v", placements, want)
+	}
+}
+
+func TestRepos_GitserverMoves(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	repo := mustCreate(ctx, t, &types.Repo{Name: "a/monorepo"})[0]
+	if m, err := Repos.GetGitserverMove(ctx, repo.ID); err != nil || m != nil {
+		t.Fatalf("got move %+v (%v) of a repo that was never moved, want nil", m, err)
+	}
+
+	if started, err := Repos.StartGitserverMove(ctx, repo.ID, "gitserver-big:3178", time.Hour); err != nil || !started {
+		t.Fatalf("got started %v (%v), want true", started, err)
+	}
+	// Only one move of a repo runs at a time, unless it timed out.
+	if started, err := Repos.StartGitserverMove(ctx, repo.ID, "gitserver-1:3178", time.Hour); err != nil || started {
+		t.Fatalf("got started %v (%v) while another move runs, want false", started, err)
+	}
+	if started, err := Repos.StartGitserverMove(ctx, repo.ID, "gitserver-1:3178", 0); err != nil || !started {
+		t.Fatalf("got started %v (%v) after the other move timed out, want true", started, err)
+	}
+	if err := Repos.FinishGitserverMove(ctx, repo.ID, "copy failed"); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := Repos.GetGitserverMove(ctx, repo.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Gitserver != "gitserver-1:3178" || m.State != GitserverRepoMoveFailed || m.FailureMessage != "copy failed" || m.FinishedAt.IsZero() {
+		t.Errorf("got move %+v, want a failed move to gitserver-1:3178", m)
+	}
+
+	if started, err := Repos.StartGitserverMove(ctx, repo.ID, "gitserver-big:3178", time.Hour); err != nil || !started {
+		t.Fatalf("got started %v (%v) after the other move failed, want true", started, err)
+	}
+	if err := Repos.FinishGitserverMove(ctx, repo.ID, ""); err != nil {
+		t.Fatal(err)
+	}
+	if m, err := Repos.GetGitserverMove(ctx, repo.ID); err != nil || m.State != GitserverRepoMoveMoved || m.FailureMessage != "" {
+		t.Errorf("got move %+v (%v), want a finished move", m, err)
+	}
+}
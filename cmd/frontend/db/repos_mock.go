@@ -2,6 +2,7 @@ package db
 
 import (
 	"testing"
+	"time"
 
 	"context"
 
@@ -10,11 +11,19 @@ import (
 )
 
 type MockRepos struct {
-	Get       func(ctx context.Context, repo api.RepoID) (*types.Repo, error)
-	GetByName func(ctx context.Context, repo api.RepoName) (*types.Repo, error)
-	GetByIDs  func(ctx context.Context, ids ...api.RepoID) ([]*types.Repo, error)
-	List      func(v0 context.Context, v1 ReposListOptions) ([]*types.Repo, error)
-	Count     func(ctx context.Context, opt ReposListOptions) (int, error)
+	Get                     func(ctx context.Context, repo api.RepoID) (*types.Repo, error)
+	GetByName               func(ctx context.Context, repo api.RepoName) (*types.Repo, error)
+	GetByIDs                func(ctx context.Context, ids ...api.RepoID) ([]*types.Repo, error)
+	List                    func(v0 context.Context, v1 ReposListOptions) ([]*types.Repo, error)
+	Count                   func(ctx context.Context, opt ReposListOptions) (int, error)
+	ListKVPs                func(ctx context.Context, repo api.RepoID) ([]RepoKVP, error)
+	SetKVP                  func(ctx context.Context, repo api.RepoID, kvp RepoKVP) error
+	DeleteKVP               func(ctx context.Context, repo api.RepoID, key string) error
+	ListGitserverPlacements func(ctx context.Context) (map[api.RepoName]string, error)
+	SetGitserverPlacement   func(ctx context.Context, repo api.RepoID, addr string) error
+	GetGitserverMove        func(ctx context.Context, repo api.RepoID) (*GitserverRepoMove, error)
+	StartGitserverMove      func(ctx context.Context, repo api.RepoID, addr string, timeout time.Duration) (bool, error)
+	FinishGitserverMove     func(ctx context.Context, repo api.RepoID, failureMessage string) error
 }
 
 func (s *MockRepos) MockGet(t *testing.T, wantRepo api.RepoID) (called *bool) {
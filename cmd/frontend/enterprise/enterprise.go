@@ -10,27 +10,33 @@ import (
 // Services is a bag of HTTP handlers and factory functions that are registered by the
 // enterprise frontend setup hook.
 type Services struct {
-	GithubWebhook             http.Handler
-	BitbucketServerWebhook    http.Handler
-	NewCodeIntelUploadHandler NewCodeIntelUploadHandler
-	AuthzResolver             graphqlbackend.AuthzResolver
-	CampaignsResolver         graphqlbackend.CampaignsResolver
-	CodeIntelResolver         graphqlbackend.CodeIntelResolver
+	GithubWebhook               http.Handler
+	BitbucketServerWebhook      http.Handler
+	NewCodeIntelUploadHandler   NewCodeIntelUploadHandler
+	NewCodeIntelValidateHandler NewCodeIntelValidateHandler
+	AuthzResolver               graphqlbackend.AuthzResolver
+	CampaignsResolver           graphqlbackend.CampaignsResolver
+	CodeIntelResolver           graphqlbackend.CodeIntelResolver
 }
 
 // NewCodeIntelUploadHandler creates a new handler for the LSIF upload endpoint. The
 // resulting handler skips auth checks when the internal flag is true.
 type NewCodeIntelUploadHandler func(internal bool) http.Handler
 
+// NewCodeIntelValidateHandler creates a new handler for the LSIF validation endpoint. The
+// resulting handler skips auth checks when the internal flag is true.
+type NewCodeIntelValidateHandler func(internal bool) http.Handler
+
 // DefaultServices creates a new Services value that has default implementations for all services.
 func DefaultServices() Services {
 	return Services{
-		GithubWebhook:             makeNotFoundHandler("github webhook"),
-		BitbucketServerWebhook:    makeNotFoundHandler("bitbucket server webhook"),
-		NewCodeIntelUploadHandler: func(_ bool) http.Handler { return makeNotFoundHandler("code intel upload") },
-		AuthzResolver:             graphqlbackend.DefaultAuthzResolver,
-		CampaignsResolver:         graphqlbackend.DefaultCampaignsResolver,
-		CodeIntelResolver:         graphqlbackend.DefaultCodeIntelResolver,
+		GithubWebhook:               makeNotFoundHandler("github webhook"),
+		BitbucketServerWebhook:      makeNotFoundHandler("bitbucket server webhook"),
+		NewCodeIntelUploadHandler:   func(_ bool) http.Handler { return makeNotFoundHandler("code intel upload") },
+		NewCodeIntelValidateHandler: func(_ bool) http.Handler { return makeNotFoundHandler("code intel validate") },
+		AuthzResolver:               graphqlbackend.DefaultAuthzResolver,
+		CampaignsResolver:           graphqlbackend.DefaultCampaignsResolver,
+		CodeIntelResolver:           graphqlbackend.DefaultCodeIntelResolver,
 	}
 }
 
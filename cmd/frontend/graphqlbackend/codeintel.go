@@ -20,6 +20,7 @@ type CodeIntelResolver interface {
 	LSIFIndexesByRepo(ctx context.Context, args *LSIFRepositoryIndexesQueryArgs) (LSIFIndexConnectionResolver, error)
 	DeleteLSIFIndex(ctx context.Context, id graphql.ID) (*EmptyResponse, error)
 	GitBlobLSIFData(ctx context.Context, args *GitBlobLSIFDataArgs) (GitBlobLSIFDataResolver, error)
+	LSIFUploadRetentionPreview(ctx context.Context, repositoryID graphql.ID) ([]LSIFUploadExpirationResolver, error)
 }
 
 var codeIntelOnlyInEnterprise = errors.New("lsif uploads and queries are only available in enterprise")
@@ -64,6 +65,10 @@ func (defaultCodeIntelResolver) GitBlobLSIFData(ctx context.Context, args *GitBl
 	return nil, codeIntelOnlyInEnterprise
 }
 
+func (defaultCodeIntelResolver) LSIFUploadRetentionPreview(ctx context.Context, repositoryID graphql.ID) ([]LSIFUploadExpirationResolver, error) {
+	return nil, codeIntelOnlyInEnterprise
+}
+
 func (r *schemaResolver) LSIFUploads(ctx context.Context, args *LSIFUploadsQueryArgs) (LSIFUploadConnectionResolver, error) {
 	return r.CodeIntelResolver.LSIFUploads(ctx, args)
 }
@@ -108,6 +113,11 @@ type LSIFUploadResolver interface {
 	ProjectRoot(ctx context.Context) (*GitTreeEntryResolver, error)
 }
 
+type LSIFUploadExpirationResolver interface {
+	Upload() LSIFUploadResolver
+	Reason() string
+}
+
 type LSIFUploadConnectionResolver interface {
 	Nodes(ctx context.Context) ([]LSIFUploadResolver, error)
 	TotalCount(ctx context.Context) (*int32, error)
@@ -174,7 +184,9 @@ type LSIFQueryPositionArgs struct {
 type LSIFPagedQueryPositionArgs struct {
 	LSIFQueryPositionArgs
 	graphqlutil.ConnectionArgs
-	After *string
+	After             *string
+	VersionMatch      *string
+	VersionConstraint *string
 }
 
 type LSIFDiagnosticsArgs struct {
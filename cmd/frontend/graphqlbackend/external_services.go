@@ -19,6 +19,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
 )
 
 var extsvcConfigAllowEdits, _ = strconv.ParseBool(env.Get("EXTSVC_CONFIG_ALLOW_EDITS", "false", "When EXTSVC_CONFIG_FILE is in use, allow edits in the application to be made which will be overwritten on next process restart"))
@@ -164,6 +165,80 @@ func (*schemaResolver) DeleteExternalService(ctx context.Context, args *struct {
 	return &EmptyResponse{}, nil
 }
 
+func (*schemaResolver) PreviewExternalServiceSync(ctx context.Context, args *struct {
+	Kind            string
+	Config          string
+	ExternalService *graphql.ID
+	First           int32
+}) (*externalServiceSyncPreviewResolver, error) {
+	// 🚨 SECURITY: Only site admins may read external services (they have secrets).
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	svc := api.ExternalService{Kind: args.Kind, Config: args.Config}
+	if args.ExternalService != nil {
+		id, err := unmarshalExternalServiceID(*args.ExternalService)
+		if err != nil {
+			return nil, err
+		}
+
+		externalService, err := db.ExternalServices.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if externalService.Kind != args.Kind {
+			return nil, errors.Errorf("external service kind %s does not match %s", externalService.Kind, args.Kind)
+		}
+		svc.ID = externalService.ID
+		svc.DisplayName = externalService.DisplayName
+	}
+
+	if err := db.ExternalServices.ValidateConfig(ctx, svc.ID, svc.Kind, svc.Config, conf.Get().AuthProviders); err != nil {
+		return nil, err
+	}
+
+	res, err := repoupdater.DefaultClient.ExternalServiceDryRun(ctx, svc, int(args.First))
+	if err != nil {
+		return nil, err
+	}
+	return &externalServiceSyncPreviewResolver{res: res}, nil
+}
+
+type externalServiceSyncPreviewResolver struct {
+	res *protocol.ExternalServiceDryRunResult
+}
+
+func (r *externalServiceSyncPreviewResolver) Added() *externalServiceSyncPreviewRepositoriesResolver {
+	return &externalServiceSyncPreviewRepositoriesResolver{count: r.res.AddedCount, names: r.res.Added}
+}
+
+func (r *externalServiceSyncPreviewResolver) Modified() *externalServiceSyncPreviewRepositoriesResolver {
+	return &externalServiceSyncPreviewRepositoriesResolver{count: r.res.ModifiedCount, names: r.res.Modified}
+}
+
+func (r *externalServiceSyncPreviewResolver) Deleted() *externalServiceSyncPreviewRepositoriesResolver {
+	return &externalServiceSyncPreviewRepositoriesResolver{count: r.res.DeletedCount, names: r.res.Deleted}
+}
+
+func (r *externalServiceSyncPreviewResolver) UnmodifiedCount() int32 {
+	return int32(r.res.UnmodifiedCount)
+}
+
+type externalServiceSyncPreviewRepositoriesResolver struct {
+	count int
+	names []string
+}
+
+func (r *externalServiceSyncPreviewRepositoriesResolver) TotalCount() int32 { return int32(r.count) }
+
+func (r *externalServiceSyncPreviewRepositoriesResolver) Names() []string {
+	if r.names == nil {
+		return []string{}
+	}
+	return r.names
+}
+
 func (r *schemaResolver) ExternalServices(ctx context.Context, args *struct {
 	graphqlutil.ConnectionArgs
 }) (*externalServiceConnectionResolver, error) {
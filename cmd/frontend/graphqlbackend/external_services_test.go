@@ -2,15 +2,22 @@ package graphqlbackend
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/graph-gophers/graphql-go/gqltesting"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
@@ -221,3 +228,106 @@ func TestDeleteExternalService(t *testing.T) {
 		},
 	})
 }
+
+func TestPreviewExternalServiceSync(t *testing.T) {
+	t.Run("authenticated as non-admin", func(t *testing.T) {
+		db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+			return &types.User{}, nil
+		}
+		t.Cleanup(func() {
+			db.Mocks.Users = db.MockUsers{}
+		})
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+		result, err := (&schemaResolver{}).PreviewExternalServiceSync(ctx, nil)
+		if want := backend.ErrMustBeSiteAdmin; err != want {
+			t.Errorf("err: want %q but got %v", want, err)
+		}
+		if result != nil {
+			t.Errorf("result: want nil but got %v", result)
+		}
+	})
+
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{SiteAdmin: true}, nil
+	}
+	db.Mocks.ExternalServices.GetByID = func(id int64) (*types.ExternalService, error) {
+		return &types.ExternalService{
+			ID:          id,
+			Kind:        "GITHUB",
+			DisplayName: "GitHub",
+			Config:      `{"url": "https://github.com", "token": "abc", "repos": ["foo/bar"]}`,
+		}, nil
+	}
+	t.Cleanup(func() {
+		db.Mocks.Users = db.MockUsers{}
+		db.Mocks.ExternalServices = db.MockExternalServices{}
+	})
+
+	var dryRun protocol.ExternalServiceDryRunRequest
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/external-service-dry-run" {
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&dryRun); err != nil {
+			t.Error(err)
+		}
+		_ = json.NewEncoder(w).Encode(&protocol.ExternalServiceDryRunResult{
+			Added:           []string{"github.com/foo/baz"},
+			Deleted:         []string{"github.com/foo/bar"},
+			AddedCount:      1,
+			DeletedCount:    4000,
+			UnmodifiedCount: 2,
+		})
+	}))
+	defer s.Close()
+
+	orig := repoupdater.DefaultClient
+	repoupdater.DefaultClient = &repoupdater.Client{URL: s.URL}
+	defer func() { repoupdater.DefaultClient = orig }()
+
+	config := `{"url": "https://github.com", "token": "abc", "repos": ["foo/baz"]}`
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Schema: mustParseGraphQLSchema(t),
+			Query: fmt.Sprintf(`
+			{
+				previewExternalServiceSync(
+					kind: GITHUB,
+					config: %q,
+					externalService: "RXh0ZXJuYWxTZXJ2aWNlOjQ=",
+					first: 1
+				) {
+					added { totalCount names }
+					modified { totalCount names }
+					deleted { totalCount names }
+					unmodifiedCount
+				}
+			}
+		`, config),
+			ExpectedResult: `
+			{
+				"previewExternalServiceSync": {
+					"added": {"totalCount": 1, "names": ["github.com/foo/baz"]},
+					"modified": {"totalCount": 0, "names": []},
+					"deleted": {"totalCount": 4000, "names": ["github.com/foo/bar"]},
+					"unmodifiedCount": 2
+				}
+			}
+		`,
+		},
+	})
+
+	want := protocol.ExternalServiceDryRunRequest{
+		ExternalService: api.ExternalService{
+			ID:          4,
+			Kind:        "GITHUB",
+			DisplayName: "GitHub",
+			Config:      config,
+		},
+		SampleSize: 1,
+	}
+	if diff := cmp.Diff(want, dryRun); diff != "" {
+		t.Errorf("unexpected dry run request (-want +got):\n%s", diff)
+	}
+}
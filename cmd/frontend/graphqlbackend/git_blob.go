@@ -5,6 +5,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/lfs"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 )
 
@@ -32,3 +33,28 @@ func (r *GitTreeEntryResolver) Blame(ctx context.Context,
 
 	return hunksResolver, nil
 }
+
+func (r *GitTreeEntryResolver) LFS(ctx context.Context) (*lfsResolver, error) {
+	// Pointer files are small, so we don't read the content of larger files.
+	if r.stat.Size() > lfs.MaxPointerSize {
+		return nil, nil
+	}
+	content, err := r.Content(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pointer, ok := lfs.ParsePointer([]byte(content))
+	if !ok {
+		return nil, nil
+	}
+	return &lfsResolver{pointer: pointer}, nil
+}
+
+type lfsResolver struct {
+	pointer *lfs.Pointer
+}
+
+// ByteSize is a float since the size of LFS files may not fit in an int32.
+func (r *lfsResolver) ByteSize() float64 {
+	return float64(r.pointer.Size)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/google/zoekt"
 	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/inconshreveable/log15"
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
@@ -16,6 +17,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
 	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 )
@@ -297,6 +299,157 @@ func (r *schemaResolver) SetRepositoryEnabled(ctx context.Context, args *struct
 	return &EmptyResponse{}, nil
 }
 
+func (r *schemaResolver) RestoreRepository(ctx context.Context, args *struct {
+	Name string
+}) (*RepositoryResolver, error) {
+	// 🚨 SECURITY: Only site admins can restore repositories, because it's a
+	// site-wide action.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	res, err := repoupdater.DefaultClient.RestoreRepo(ctx, api.RepoName(args.Name))
+	if err != nil {
+		return nil, errors.Wrap(err, "repo-updater.restore-repo")
+	}
+
+	repo, err := backend.Repos.Get(ctx, res.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &RepositoryResolver{repo: repo}, nil
+}
+
+// gitserverMoveTimeout is how long a move of a repository to another
+// gitserver may take. Moves that were interrupted by a restart of the
+// frontend can be started again after that.
+const gitserverMoveTimeout = 12 * time.Hour
+
+func (r *schemaResolver) MoveRepositoryToGitserver(ctx context.Context, args *struct {
+	Repository graphql.ID
+	Gitserver  string
+}) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins can move repositories between
+	// gitservers, because it's a site-wide action.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	repoID, err := UnmarshalRepositoryID(args.Repository)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := backend.Repos.Get(ctx, repoID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := gitserver.DefaultClient.CheckMoveTarget(ctx, repo.Name, args.Gitserver); err != nil {
+		return nil, err
+	}
+
+	started, err := db.Repos.StartGitserverMove(ctx, repo.ID, args.Gitserver, gitserverMoveTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if !started {
+		return nil, errors.Errorf("repository %s is already being moved", repo.Name)
+	}
+
+	// Copying large repositories takes long, so the move runs in the
+	// background and its state is available with mirrorInfo.gitserverMove.
+	goroutine.Go(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), gitserverMoveTimeout)
+		defer cancel()
+
+		var failureMessage string
+		if err := moveRepositoryToGitserver(ctx, repo, args.Gitserver); err != nil {
+			log15.Error("failed to move repository to another gitserver", "repo", repo.Name, "to", args.Gitserver, "error", err)
+			failureMessage = err.Error()
+		}
+		if err := db.Repos.FinishGitserverMove(ctx, repo.ID, failureMessage); err != nil {
+			log15.Error("failed to record the end of a repository move", "repo", repo.Name, "error", err)
+		}
+	})
+
+	return &EmptyResponse{}, nil
+}
+
+// moveRepositoryToGitserver copies the clone of repo to the gitserver at
+// target and places repo there. The gitserver the repo was on keeps its clone
+// unless the placement is committed, so that it is still served if the move
+// fails.
+func moveRepositoryToGitserver(ctx context.Context, repo *types.Repo, target string) error {
+	source, err := gitserver.DefaultClient.MoveRepo(ctx, repo.Name, target)
+	if err != nil {
+		return errors.Wrap(err, "gitserver.move-repo")
+	}
+	// Services pick up the new placement with the next configuration poll.
+	if err := db.Repos.SetGitserverPlacement(ctx, repo.ID, target); err != nil {
+		return errors.Wrap(err, "placing repository")
+	}
+	if err := gitserver.DefaultClient.MarkMoved(ctx, repo.Name, source, target); err != nil {
+		// The repository is moved, but the old clone is kept on the source
+		// gitserver until it is removed by hand.
+		log15.Warn("failed to mark the old clone of a moved repository for removal", "repo", repo.Name, "from", source, "error", err)
+	}
+	log15.Info("moved repository to another gitserver", "repo", repo.Name, "from", source, "to", target)
+	return nil
+}
+
+func (r *schemaResolver) SetRepositoryKeyValuePair(ctx context.Context, args *struct {
+	Repository graphql.ID
+	Key        string
+	Value      *string
+}) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins can set key-value pairs on repositories,
+	// because they are visible to and searchable by all users.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	// Keys and values that can't be written in a repo:has.key(key:value)
+	// search query could never be matched.
+	if args.Key == "" {
+		return nil, errors.New("key must not be empty")
+	}
+	if strings.ContainsAny(args.Key, ":()") {
+		return nil, errors.New(`key must not contain ":", "(" or ")"`)
+	}
+	if args.Value != nil && strings.ContainsAny(*args.Value, "()") {
+		return nil, errors.New(`value must not contain "(" or ")"`)
+	}
+
+	repoID, err := UnmarshalRepositoryID(args.Repository)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Repos.SetKVP(ctx, repoID, db.RepoKVP{Key: args.Key, Value: args.Value}); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
+func (r *schemaResolver) DeleteRepositoryKeyValuePair(ctx context.Context, args *struct {
+	Repository graphql.ID
+	Key        string
+}) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins can delete key-value pairs of repositories.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	repoID, err := UnmarshalRepositoryID(args.Repository)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Repos.DeleteKVP(ctx, repoID, args.Key); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
 func repoNamesToStrings(repoNames []api.RepoName) []string {
 	strings := make([]string, len(repoNames))
 	for i, repoName := range repoNames {
@@ -2,10 +2,15 @@ package graphqlbackend
 
 import (
 	"context"
+	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/gqltesting"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 )
 
 func TestRepositories(t *testing.T) {
@@ -62,3 +67,75 @@ func TestRepositories(t *testing.T) {
 		},
 	})
 }
+
+func TestSetRepositoryKeyValuePair(t *testing.T) {
+	resetMocks()
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{SiteAdmin: true}, nil
+	}
+
+	var set []db.RepoKVP
+	db.Mocks.Repos.SetKVP = func(ctx context.Context, repoID api.RepoID, kvp db.RepoKVP) error {
+		if repoID != 1 {
+			t.Errorf("unexpected repo ID %d", repoID)
+		}
+		set = append(set, kvp)
+		return nil
+	}
+	var deleted []string
+	db.Mocks.Repos.DeleteKVP = func(ctx context.Context, repoID api.RepoID, key string) error {
+		deleted = append(deleted, key)
+		return nil
+	}
+
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Schema: mustParseGraphQLSchema(t),
+			Query: `
+				mutation {
+					a: setRepositoryKeyValuePair(repository: "UmVwb3NpdG9yeTox", key: "owner", value: "team-a") { alwaysNil }
+					b: setRepositoryKeyValuePair(repository: "UmVwb3NpdG9yeTox", key: "deprecated") { alwaysNil }
+					c: deleteRepositoryKeyValuePair(repository: "UmVwb3NpdG9yeTox", key: "tier") { alwaysNil }
+				}
+			`,
+			ExpectedResult: `
+				{
+					"a": { "alwaysNil": null },
+					"b": { "alwaysNil": null },
+					"c": { "alwaysNil": null }
+				}
+			`,
+		},
+	})
+
+	value := "team-a"
+	if diff := cmp.Diff([]db.RepoKVP{{Key: "owner", Value: &value}, {Key: "deprecated"}}, set); diff != "" {
+		t.Errorf("unexpected key-value pairs set (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"tier"}, deleted); diff != "" {
+		t.Errorf("unexpected keys deleted (-want +got):\n%s", diff)
+	}
+
+	set = nil
+	for _, tc := range []struct {
+		key   string
+		value *string
+	}{
+		{key: ""},
+		{key: "owner:team"},
+		{key: "has(owner)"},
+		{key: "owner", value: strptr("team(a)")},
+	} {
+		_, err := (&schemaResolver{}).SetRepositoryKeyValuePair(context.Background(), &struct {
+			Repository graphql.ID
+			Key        string
+			Value      *string
+		}{Repository: "UmVwb3NpdG9yeTox", Key: tc.key, Value: tc.value})
+		if err == nil || !strings.Contains(err.Error(), "must not") {
+			t.Errorf("expected validation error for key %q, got %v", tc.key, err)
+		}
+	}
+	if len(set) != 0 {
+		t.Errorf("unexpected key-value pairs set: %v", set)
+	}
+}
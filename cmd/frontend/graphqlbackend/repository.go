@@ -110,6 +110,45 @@ func (r *RepositoryResolver) IsPrivate(ctx context.Context) (bool, error) {
 	return r.repo.Private, nil
 }
 
+func (r *RepositoryResolver) Topics(ctx context.Context) ([]string, error) {
+	err := r.hydrate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if r.repo.Topics == nil {
+		return []string{}, nil
+	}
+	return r.repo.Topics, nil
+}
+
+func (r *RepositoryResolver) Stars(ctx context.Context) (int32, error) {
+	err := r.hydrate(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return int32(r.repo.Stars), nil
+}
+
+func (r *RepositoryResolver) KeyValuePairs(ctx context.Context) ([]*keyValuePairResolver, error) {
+	kvps, err := db.Repos.ListKVPs(ctx, r.repo.ID)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*keyValuePairResolver, len(kvps))
+	for i, kvp := range kvps {
+		resolvers[i] = &keyValuePairResolver{kvp: kvp}
+	}
+	return resolvers, nil
+}
+
+type keyValuePairResolver struct {
+	kvp db.RepoKVP
+}
+
+func (r *keyValuePairResolver) Key() string { return r.kvp.Key }
+
+func (r *keyValuePairResolver) Value() *string { return r.kvp.Value }
+
 func (r *RepositoryResolver) URI(ctx context.Context) (string, error) {
 	err := r.hydrate(ctx)
 	if err != nil {
@@ -323,6 +362,10 @@ func (r *RepositoryResolver) LSIFIndexes(ctx context.Context, args *LSIFIndexesQ
 	})
 }
 
+func (r *RepositoryResolver) LSIFUploadRetentionPreview(ctx context.Context) ([]LSIFUploadExpirationResolver, error) {
+	return EnterpriseResolvers.codeIntelResolver.LSIFUploadRetentionPreview(ctx, r.ID())
+}
+
 type AuthorizedUserArgs struct {
 	RepositoryID graphql.ID
 	Permission   string
@@ -114,6 +114,75 @@ func (r *repositoryMirrorInfoResolver) UpdatedAt(ctx context.Context) (*DateTime
 	return DateTimeOrNil(info.LastFetched), nil
 }
 
+func (r *repositoryMirrorInfoResolver) LastBackupAt(ctx context.Context) (*DateTime, error) {
+	// 🚨 SECURITY: Only site admins can see the state of the backups.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	info, err := r.gitserverRepoInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return DateTimeOrNil(info.LastBackup), nil
+}
+
+func (r *repositoryMirrorInfoResolver) LastSyncError(ctx context.Context) (*string, error) {
+	// 🚨 SECURITY: Only site admins can see the errors, which may include
+	// details of the code host.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	info, err := r.gitserverRepoInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if info.LastSyncError == "" {
+		return nil, nil
+	}
+	return &info.LastSyncError, nil
+}
+
+func (r *repositoryMirrorInfoResolver) GitserverMove(ctx context.Context) (*gitserverRepositoryMoveResolver, error) {
+	// 🚨 SECURITY: Only site admins can see where repositories are moved.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	move, err := db.Repos.GetGitserverMove(ctx, r.repository.repo.ID)
+	if err != nil || move == nil {
+		return nil, err
+	}
+	return &gitserverRepositoryMoveResolver{move: move}, nil
+}
+
+type gitserverRepositoryMoveResolver struct {
+	move *db.GitserverRepoMove
+}
+
+func (r *gitserverRepositoryMoveResolver) Gitserver() string { return r.move.Gitserver }
+
+func (r *gitserverRepositoryMoveResolver) State() string { return r.move.State }
+
+func (r *gitserverRepositoryMoveResolver) FailureMessage() *string {
+	if r.move.FailureMessage == "" {
+		return nil
+	}
+	return &r.move.FailureMessage
+}
+
+func (r *gitserverRepositoryMoveResolver) StartedAt() DateTime {
+	return DateTime{Time: r.move.StartedAt}
+}
+
+func (r *gitserverRepositoryMoveResolver) FinishedAt() *DateTime {
+	if r.move.FinishedAt.IsZero() {
+		return nil
+	}
+	return &DateTime{Time: r.move.FinishedAt}
+}
+
 func (r *repositoryMirrorInfoResolver) UpdateSchedule(ctx context.Context) (*updateScheduleResolver, error) {
 	info, err := r.repoUpdateSchedulerInfo(ctx)
 	if err != nil {
@@ -145,6 +214,25 @@ func (r *updateScheduleResolver) Total() int32 {
 	return int32(r.schedule.Total)
 }
 
+func (r *updateScheduleResolver) Class() *string {
+	if r.schedule.Class == "" {
+		return nil
+	}
+	return &r.schedule.Class
+}
+
+func (r *updateScheduleResolver) MinIntervalSeconds() int32 {
+	return int32(r.schedule.MinIntervalSeconds)
+}
+
+func (r *updateScheduleResolver) MaxIntervalSeconds() int32 {
+	return int32(r.schedule.MaxIntervalSeconds)
+}
+
+func (r *updateScheduleResolver) Priority() int32 {
+	return int32(r.schedule.Priority)
+}
+
 func (r *repositoryMirrorInfoResolver) UpdateQueue(ctx context.Context) (*updateQueueResolver, error) {
 	info, err := r.repoUpdateSchedulerInfo(ctx)
 	if err != nil {
@@ -110,6 +110,55 @@ type Mutation {
     updateExternalService(input: UpdateExternalServiceInput!): ExternalService!
     # Delete an external service. Only site admins may perform this mutation.
     deleteExternalService(externalService: ID!): EmptyResponse!
+    # Restores a repository that was removed because no external service yields
+    # it anymore. Only repositories removed within the repoPurgeGracePeriod site
+    # configuration are restored, since their clones are still on disk. Syncs
+    # keep the restored repository for another grace period, after which it is
+    # removed again unless an external service yields it by then.
+    #
+    # Only site admins may perform this mutation.
+    restoreRepository(
+        # The name the repository had before it was removed.
+        name: String!
+    ): Repository!
+    # Starts moving the clone of a repository to another gitserver, for example to drain a gitserver
+    # for maintenance or to put a large repository on a dedicated gitserver. The clone is copied to
+    # the other gitserver in the background, which verifies the copy, and the repository is then
+    # placed on the other gitserver instead of the one its name hashes to. Services send their
+    # requests for the repository to the other gitserver once they pick up the new placement, and
+    # the old clone is removed an hour later. The state of the move is available with
+    # mirrorInfo.gitserverMove.
+    #
+    # Only site admins may perform this mutation.
+    moveRepositoryToGitserver(
+        # The repository to move.
+        repository: ID!
+        # The address of the gitserver to move the repository to, as listed in SRC_GIT_SERVERS.
+        gitserver: String!
+    ): EmptyResponse!
+    # Sets a key-value pair on a repository, replacing the value of the key if it is
+    # already set. Repositories can be filtered by their key-value pairs in search
+    # queries with repo:has.key(key) and repo:has.key(key:value).
+    #
+    # Only site admins may perform this mutation.
+    setRepositoryKeyValuePair(
+        # The repository to set the key-value pair on.
+        repository: ID!
+        # The key to set. It must not contain ":", "(" or ")".
+        key: String!
+        # The value of the key, or null to set the key without a value. It must not
+        # contain "(" or ")".
+        value: String
+    ): EmptyResponse!
+    # Removes a key and its value from a repository.
+    #
+    # Only site admins may perform this mutation.
+    deleteRepositoryKeyValuePair(
+        # The repository to remove the key from.
+        repository: ID!
+        # The key to remove.
+        key: String!
+    ): EmptyResponse!
     # DEPRECATED: All repositories are accessible or deleted. To prevent a
     # repository from being accessed on Sourcegraph add it to the external
     # service exclude configuration. This mutation will be removed in 3.6.
@@ -696,6 +745,17 @@ enum ChangesetCheckState {
     FAILED
 }
 
+# The state of the signature of the head commit of a changeset.
+enum ChangesetSignatureState {
+    # The head commit is not signed.
+    UNSIGNED
+    # The code host verified the signature of the head commit.
+    VERIFIED
+    # The head commit is signed, but the code host could not verify the signature, for example
+    # because the signing key is not added to the account of the committer.
+    UNVERIFIED
+}
+
 # The input to the createChangesets mutation.
 input CreateChangesetInput {
     # The ID of the repository that this changeset belongs to.
@@ -874,6 +934,10 @@ type ExternalChangeset implements Node & Changeset {
     # The state of the checks (e.g., for continuous integration) on this changeset, or null if no
     # checks have been configured.
     checkState: ChangesetCheckState
+
+    # Whether the code host verified the signature of the head commit of this changeset, or null
+    # if the code host doesn't verify signatures.
+    signatureState: ChangesetSignatureState
 }
 
 # A list of changesets.
@@ -1136,6 +1200,23 @@ type Query {
         # Returns the first n external services from the list.
         first: Int
     ): ExternalServiceConnection!
+    # Previews the changes that syncing an external service with the given
+    # configuration would make to the repositories on Sourcegraph, without saving
+    # the configuration. This lists all repositories of the external service on
+    # its code host, so it may be slow.
+    #
+    # Only site admins may perform this query.
+    previewExternalServiceSync(
+        # The kind of the external service.
+        kind: ExternalServiceKind!
+        # The proposed JSON configuration of the external service.
+        config: String!
+        # The ID of the external service whose configuration would be changed, or
+        # null for a new external service.
+        externalService: ID
+        # The maximum number of repository names returned for each kind of change.
+        first: Int = 10
+    ): ExternalServiceSyncPreview!
     # List all repositories.
     repositories(
         # Returns the first n repositories from the list.
@@ -1670,6 +1751,31 @@ type Highlight {
     length: Int!
 }
 
+# The changes that syncing an external service would make to the repositories on
+# Sourcegraph.
+type ExternalServiceSyncPreview {
+    # The repositories that would be added.
+    added: ExternalServiceSyncPreviewRepositories!
+    # The repositories that would be modified, for example because their metadata
+    # changed on the code host or the external service no longer yields them but
+    # other external services do.
+    modified: ExternalServiceSyncPreviewRepositories!
+    # The repositories that would be deleted, because no external service yields
+    # them anymore.
+    deleted: ExternalServiceSyncPreviewRepositories!
+    # The number of repositories yielded by the external service that would be
+    # left unmodified.
+    unmodifiedCount: Int!
+}
+
+# Repositories that syncing an external service would change in the same way.
+type ExternalServiceSyncPreviewRepositories {
+    # The total number of repositories.
+    totalCount: Int!
+    # The names of the first repositories, ordered by name.
+    names: [String!]!
+}
+
 # A list of external services.
 type ExternalServiceConnection {
     # A list of external services.
@@ -1687,9 +1793,11 @@ enum ExternalServiceKind {
     AWSCODECOMMIT
     BITBUCKETCLOUD
     BITBUCKETSERVER
+    GERRIT
     GITHUB
     GITLAB
     GITOLITE
+    MANIFEST
     PHABRICATOR
     OTHER
 }
@@ -1733,6 +1841,14 @@ type RepositoryConnection {
     pageInfo: PageInfo!
 }
 
+# A key-value pair set on a repository.
+type KeyValuePair {
+    # The key.
+    key: String!
+    # The value of the key, or null if the key is set without a value.
+    value: String
+}
+
 # A repository is a Git source control repository that is mirrored from some origin code host.
 type Repository implements Node & GenericSearchResultInterface {
     # The repository's unique ID.
@@ -1782,6 +1898,12 @@ type Repository implements Node & GenericSearchResultInterface {
     isArchived: Boolean!
     # Whether the repository is private.
     isPrivate: Boolean!
+    # The topics of the repository on its code host.
+    topics: [String!]!
+    # The number of stars of the repository on its code host.
+    stars: Int!
+    # The key-value pairs set on the repository by site admins.
+    keyValuePairs: [KeyValuePair!]!
     # Lists all external services which yield this repository.
     externalServices(
         # Returns the first n external services from the list.
@@ -1918,6 +2040,14 @@ type Repository implements Node & GenericSearchResultInterface {
         after: String
     ): LSIFIndexConnection!
 
+    # (experimental) The LSIF API may change substantially in the near future as we
+    # continue to adjust it for our use cases. Changes will not be documented in the
+    # CHANGELOG during this time.
+    # The repository's completed LSIF uploads that would be removed by the retention policy
+    # configured for this repository in the "codeIntel.retentionPolicies" site configuration.
+    # No data is removed by this query. Only site admins may preview retention.
+    lsifUploadRetentionPreview: [LSIFUploadExpiration!]!
+
     # A list of authorized users to access this repository with the given permission.
     # This API currently only returns permissions from the Sourcegraph provider, i.e.
     # "permissions.userMapping" in site configuration.
@@ -1988,6 +2118,39 @@ type MirrorRepositoryInfo {
     updateSchedule: UpdateSchedule
     # The state of this repository in the update queue.
     updateQueue: UpdateQueue
+    # When the repository was last backed up to the gitserver backup store, or null if it never was (or
+    # backups are disabled). Only site admins can access this field.
+    lastBackupAt: DateTime
+    # The error of the last clone or update of the repository, or null if it succeeded. Only site admins can
+    # access this field.
+    lastSyncError: String
+    # The latest move of the repository to another gitserver (with the moveRepositoryToGitserver
+    # mutation), or null if it was never moved. Only site admins can access this field.
+    gitserverMove: GitserverRepositoryMove
+}
+
+# A move of a repository to another gitserver.
+type GitserverRepositoryMove {
+    # The address of the gitserver the repository is moved to.
+    gitserver: String!
+    # The state of the move.
+    state: GitserverRepositoryMoveState!
+    # The error that made the move fail, or null if it didn't fail.
+    failureMessage: String
+    # When the move started.
+    startedAt: DateTime!
+    # When the move finished, or null if it is still running.
+    finishedAt: DateTime
+}
+
+# The state of a move of a repository to another gitserver.
+enum GitserverRepositoryMoveState {
+    # The clone is being copied to the other gitserver.
+    MOVING
+    # The repository was placed on the other gitserver.
+    MOVED
+    # The move failed, and the repository is still on the gitserver it was on.
+    FAILED
 }
 
 # The state of a repository in the update schedule.
@@ -2000,6 +2163,16 @@ type UpdateSchedule {
     index: Int!
     # The total number of repos in the schedule.
     total: Int!
+    # The name of the update class (configured in the gitUpdateClasses site configuration) that the repo
+    # belongs to, or null if it belongs to none.
+    class: String
+    # The minimum interval between scheduled updates of the repo.
+    minIntervalSeconds: Int!
+    # The maximum interval between scheduled updates of the repo.
+    maxIntervalSeconds: Int!
+    # The priority of the scheduled updates of the repo. Scheduled updates with a higher priority are run
+    # before the ones with a lower priority when they are due at the same time.
+    priority: Int!
 }
 
 # The state of a repository in the update queue.
@@ -2662,6 +2835,12 @@ type Submodule {
     path: String!
 }
 
+# A file stored in Git LFS.
+type LFS {
+    # The size of the file stored in LFS in bytes.
+    byteSize: Float!
+}
+
 # A file, directory, or other tree entry.
 interface TreeEntry {
     # The full path (relative to the repository root) of this tree entry.
@@ -2913,6 +3092,9 @@ type GitBlob implements TreeEntry & File2 {
     highlight(disableTimeout: Boolean!, isLightTheme: Boolean!, highlightLongLines: Boolean = false): HighlightedFile!
     # Submodule metadata if this tree points to a submodule
     submodule: Submodule
+    # Git LFS metadata if this blob is a Git LFS pointer file. The content of this blob is then the
+    # pointer, not the content of the file stored in LFS.
+    lfs: LFS
     # Symbols defined in this blob.
     symbols(
         # Returns the first n symbols from the list.
@@ -2941,6 +3123,18 @@ type GitBlob implements TreeEntry & File2 {
     ): GitBlobLSIFData
 }
 
+# Determines which versions of a package are searched for references from other repositories.
+enum LSIFVersionMatch {
+    # Only the version of the package that defines the symbol.
+    EXACT
+
+    # Every version of the package referenced by another repository.
+    ANY
+
+    # Every version of the package referenced by another repository that satisfies a semver range.
+    SEMVER
+}
+
 # LSIF data available for a tree entry.
 interface TreeEntryLSIFData {
     # Code diagnostics provided through LSIF.
@@ -2984,6 +3178,14 @@ type GitBlobLSIFData implements TreeEntryLSIFData {
         # the first N results (relative to the cursor) should be returned. i.e.
         # how many results to return per page.
         first: Int
+
+        # Which versions of the package defining the symbol are searched for references
+        # from other repositories. Defaults to EXACT. Only read on the first page of results.
+        versionMatch: LSIFVersionMatch
+
+        # The semver range (e.g. ">= 1.2, < 2") of versions searched when versionMatch is
+        # SEMVER. Defaults to the versions compatible with the defining version (^version).
+        versionConstraint: String
     ): LocationConnection
 
     # (experimental) The LSIF API may change substantially in the near future as we
@@ -3572,6 +3774,42 @@ type Site implements SettingsSubject {
         # Days of history (based on current UTC time).
         days: Int
     ): MonitoringStatistics!
+    # The git commands that gitserver recently ran on behalf of other services, aggregated by repository
+    # and kind of command, with the largest total duration first. Only site admins can access this field.
+    #
+    # Each gitserver keeps a log of its most recent commands (configured with SRC_EXEC_LOG_SIZE), so this
+    # only covers the recent past.
+    gitserverCommandStatistics(
+        # Include only the commands run on this repository (e.g. "github.com/foo/bar").
+        repositoryName: String
+        # Returns the first n aggregates.
+        first: Int = 20
+    ): [GitserverCommandStatistics!]!
+}
+
+# Statistics of the git commands of a kind that gitserver recently ran on a repository.
+type GitserverCommandStatistics {
+    # The name of the repository.
+    repositoryName: String!
+    # The git subcommand and the names of the flags it was run with, without their values (e.g.
+    # "log -S --format"). Commands that only differ by revisions, paths or patterns are aggregated.
+    command: String!
+    # The number of times the command ran.
+    count: Int!
+    # The number of times the command was slow for its kind of command.
+    slowCount: Int!
+    # The number of times the command failed or didn't run.
+    failureCount: Int!
+    # The total duration of the commands in milliseconds.
+    totalDurationMilliseconds: Int!
+    # The duration of the slowest command in milliseconds.
+    maxDurationMilliseconds: Int!
+    # The total size of the output of the commands in bytes.
+    outputBytes: Float!
+    # The services (user agents) that ran the command, such as "frontend" or "searcher".
+    clients: [String!]!
+    # The trace of the slowest command, if it was traced.
+    slowestTrace: String
 }
 
 # The configuration for a site.
@@ -4192,6 +4430,15 @@ type LSIFUploadConnection {
     pageInfo: PageInfo!
 }
 
+# An LSIF upload that would be removed by a retention policy.
+type LSIFUploadExpiration {
+    # The upload.
+    upload: LSIFUpload!
+
+    # A human-readable description of why the upload would be removed.
+    reason: String!
+}
+
 # The state an LSIF index can be in.
 enum LSIFIndexState {
     # This index is being processed.
@@ -600,6 +600,30 @@ func findPatternRevs(includePatterns []string) (includePatternRevs []patternRevs
 	return
 }
 
+// splitRepoPredicates splits the values of repo: fields into the patterns
+// matching repository names, and the topics and key-value pairs of the
+// has.topic(...) and has.key(...) repo predicates.
+func splitRepoPredicates(values []string) (patterns, topics []string, kvps []db.RepoKVP, err error) {
+	for _, v := range values {
+		p, ok, err := query.ParseRepoPredicate(v)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if !ok {
+			patterns = append(patterns, v)
+			continue
+		}
+		switch p.Name {
+		case query.RepoPredicateTopic:
+			topics = append(topics, p.Value)
+		case query.RepoPredicateKey:
+			key, value := p.KeyValue()
+			kvps = append(kvps, db.RepoKVP{Key: key, Value: value})
+		}
+	}
+	return patterns, topics, kvps, nil
+}
+
 type resolveRepoOp struct {
 	repoFilters        []string
 	minusRepoFilters   []string
@@ -622,13 +646,18 @@ func resolveRepositories(ctx context.Context, op resolveRepoOp) (repoRevisions,
 		tr.Finish()
 	}()
 
-	includePatterns := op.repoFilters
-	if includePatterns != nil {
-		// Copy to avoid race condition.
-		includePatterns = append([]string{}, includePatterns...)
+	// Repo predicates filter repositories by their metadata, so they are
+	// removed from the patterns matching repository names. This also copies
+	// the include patterns to avoid a race condition.
+	includePatterns, topics, kvps, err := splitRepoPredicates(op.repoFilters)
+	if err != nil {
+		return nil, nil, false, nil, err
 	}
-
-	excludePatterns := op.minusRepoFilters
+	excludePatterns, excludeTopics, excludeKVPs, err := splitRepoPredicates(op.minusRepoFilters)
+	if err != nil {
+		return nil, nil, false, nil, err
+	}
+	hasRepoPredicates := len(topics) > 0 || len(kvps) > 0 || len(excludeTopics) > 0 || len(excludeKVPs) > 0
 
 	maxRepoListSize := maxReposToSearch()
 
@@ -678,7 +707,7 @@ func resolveRepositories(ctx context.Context, op resolveRepoOp) (repoRevisions,
 	}
 
 	var defaultRepos []*types.Repo
-	if envvar.SourcegraphDotComMode() && len(includePatterns) == 0 {
+	if envvar.SourcegraphDotComMode() && len(includePatterns) == 0 && !hasRepoPredicates {
 		getIndexedRepos := func(ctx context.Context, revs []*search.RepositoryRevisions) (indexed, unindexed []*search.RepositoryRevisions, err error) {
 			return zoektIndexedRepos(ctx, search.Indexed(), revs, nil)
 		}
@@ -702,13 +731,17 @@ func resolveRepositories(ctx context.Context, op resolveRepoOp) (repoRevisions,
 			Names:           versionContextRepositories,
 			ExcludePattern:  unionRegExps(excludePatterns),
 			// List N+1 repos so we can see if there are repos omitted due to our repo limit.
-			LimitOffset:  &db.LimitOffset{Limit: maxRepoListSize + 1},
-			NoForks:      op.noForks,
-			OnlyForks:    op.onlyForks,
-			NoArchived:   op.noArchived,
-			OnlyArchived: op.onlyArchived,
-			NoPrivate:    op.onlyPublic,
-			OnlyPrivate:  op.onlyPrivate,
+			LimitOffset:   &db.LimitOffset{Limit: maxRepoListSize + 1},
+			NoForks:       op.noForks,
+			OnlyForks:     op.onlyForks,
+			NoArchived:    op.noArchived,
+			OnlyArchived:  op.onlyArchived,
+			NoPrivate:     op.onlyPublic,
+			OnlyPrivate:   op.onlyPrivate,
+			Topics:        topics,
+			ExcludeTopics: excludeTopics,
+			KVPs:          kvps,
+			ExcludeKVPs:   excludeKVPs,
 		}
 		excludedRepos = computeExcludedRepositories(ctx, op.query, options)
 		repos, err = db.Repos.List(ctx, options)
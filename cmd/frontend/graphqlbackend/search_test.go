@@ -659,6 +659,58 @@ func TestVersionContext(t *testing.T) {
 	}
 }
 
+func TestResolveRepositories_RepoPredicates(t *testing.T) {
+	defer func() { db.Mocks = db.MockStores{} }()
+
+	var have db.ReposListOptions
+	db.Mocks.Repos.List = func(ctx context.Context, opts db.ReposListOptions) ([]*types.Repo, error) {
+		have = opts
+		return []*types.Repo{{Name: "github.com/sourcegraph/payments"}}, nil
+	}
+
+	_, _, _, _, err := resolveRepositories(context.Background(), resolveRepoOp{
+		repoFilters:      []string{"sourcegraph", "has.topic(payments)", "has.key(owner:team-a)"},
+		minusRepoFilters: []string{"has.key(deprecated)", "has.topic(archive)"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := db.ReposListOptions{
+		IncludePatterns: []string{"sourcegraph"},
+		Topics:          []string{"payments"},
+		KVPs:            []db.RepoKVP{{Key: "owner", Value: strptr("team-a")}},
+		ExcludeTopics:   []string{"archive"},
+		ExcludeKVPs:     []db.RepoKVP{{Key: "deprecated"}},
+	}
+	if diff := cmp.Diff(want.IncludePatterns, have.IncludePatterns); diff != "" {
+		t.Errorf("IncludePatterns mismatch (-want +have):\n%s", diff)
+	}
+	if have.ExcludePattern != "" {
+		t.Errorf("unexpected ExcludePattern %q", have.ExcludePattern)
+	}
+	for _, c := range []struct {
+		name       string
+		want, have interface{}
+	}{
+		{"Topics", want.Topics, have.Topics},
+		{"KVPs", want.KVPs, have.KVPs},
+		{"ExcludeTopics", want.ExcludeTopics, have.ExcludeTopics},
+		{"ExcludeKVPs", want.ExcludeKVPs, have.ExcludeKVPs},
+	} {
+		if diff := cmp.Diff(c.want, c.have); diff != "" {
+			t.Errorf("%s mismatch (-want +have):\n%s", c.name, diff)
+		}
+	}
+
+	_, _, _, _, err = resolveRepositories(context.Background(), resolveRepoOp{
+		repoFilters: []string{"has.stars(100)"},
+	})
+	if err == nil {
+		t.Error("expected error for unknown repo predicate")
+	}
+}
+
 func TestComputeExcludedRepositories(t *testing.T) {
 	cases := []struct {
 		Name              string
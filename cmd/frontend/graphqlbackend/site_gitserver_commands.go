@@ -0,0 +1,79 @@
+package graphqlbackend
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+var mockGitserverExecLog func(ctx context.Context, repo api.RepoName, limit int) (*protocol.ExecLogResponse, error)
+
+func (r *siteResolver) GitserverCommandStatistics(ctx context.Context, args *struct {
+	RepositoryName *string
+	First          int32
+}) ([]*gitserverCommandStatisticsResolver, error) {
+	// 🚨 SECURITY: Only site admins can see the commands run on all repositories.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if args.First < 0 {
+		return nil, errors.New("first must not be negative")
+	}
+
+	var repo api.RepoName
+	if args.RepositoryName != nil {
+		repo = api.RepoName(*args.RepositoryName)
+	}
+	execLog := gitserver.DefaultClient.ExecLog
+	if mockGitserverExecLog != nil {
+		execLog = mockGitserverExecLog
+	}
+	resp, err := execLog(ctx, repo, int(args.First))
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*gitserverCommandStatisticsResolver, 0, len(resp.Stats))
+	for _, st := range resp.Stats {
+		resolvers = append(resolvers, &gitserverCommandStatisticsResolver{stats: st})
+	}
+	return resolvers, nil
+}
+
+type gitserverCommandStatisticsResolver struct {
+	stats protocol.ExecLogStats
+}
+
+func (r *gitserverCommandStatisticsResolver) RepositoryName() string { return string(r.stats.Repo) }
+func (r *gitserverCommandStatisticsResolver) Command() string        { return r.stats.Command }
+func (r *gitserverCommandStatisticsResolver) Count() int32           { return int32(r.stats.Count) }
+func (r *gitserverCommandStatisticsResolver) SlowCount() int32       { return int32(r.stats.SlowCount) }
+func (r *gitserverCommandStatisticsResolver) FailureCount() int32    { return int32(r.stats.FailureCount) }
+
+func (r *gitserverCommandStatisticsResolver) TotalDurationMilliseconds() int32 {
+	return int32(r.stats.TotalDuration / time.Millisecond)
+}
+
+func (r *gitserverCommandStatisticsResolver) MaxDurationMilliseconds() int32 {
+	return int32(r.stats.MaxDuration / time.Millisecond)
+}
+
+func (r *gitserverCommandStatisticsResolver) OutputBytes() float64 {
+	return float64(r.stats.StdoutBytes)
+}
+
+func (r *gitserverCommandStatisticsResolver) Clients() []string { return r.stats.Clients }
+
+func (r *gitserverCommandStatisticsResolver) SlowestTrace() *string {
+	if r.stats.SlowestTrace == "" {
+		return nil
+	}
+	return &r.stats.SlowestTrace
+}
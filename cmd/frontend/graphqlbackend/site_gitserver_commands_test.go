@@ -0,0 +1,97 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/graph-gophers/graphql-go/gqltesting"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+func TestSiteGitserverCommandStatistics(t *testing.T) {
+	resetMocks()
+	var gotRepo api.RepoName
+	var gotLimit int
+	mockGitserverExecLog = func(ctx context.Context, repo api.RepoName, limit int) (*protocol.ExecLogResponse, error) {
+		gotRepo, gotLimit = repo, limit
+		return &protocol.ExecLogResponse{Stats: []protocol.ExecLogStats{{
+			Repo:          "github.com/foo/bar",
+			Command:       "log -S",
+			Count:         3,
+			SlowCount:     2,
+			TotalDuration: 12 * time.Second,
+			MaxDuration:   8 * time.Second,
+			StdoutBytes:   1024,
+			Clients:       []string{"frontend"},
+		}}}, nil
+	}
+	defer func() { mockGitserverExecLog = nil }()
+
+	t.Run("non-admin", func(t *testing.T) {
+		db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+			return &types.User{}, nil
+		}
+		_, err := (&siteResolver{}).GitserverCommandStatistics(context.Background(), &struct {
+			RepositoryName *string
+			First          int32
+		}{First: 20})
+		if err != backend.ErrMustBeSiteAdmin {
+			t.Fatalf("got error %v, want %v", err, backend.ErrMustBeSiteAdmin)
+		}
+	})
+
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{SiteAdmin: true}, nil
+	}
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Schema: mustParseGraphQLSchema(t),
+			Query: `
+				{
+					site {
+						gitserverCommandStatistics(repositoryName: "github.com/foo/bar", first: 5) {
+							repositoryName
+							command
+							count
+							slowCount
+							failureCount
+							totalDurationMilliseconds
+							maxDurationMilliseconds
+							outputBytes
+							clients
+							slowestTrace
+						}
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"site": {
+						"gitserverCommandStatistics": [
+							{
+								"repositoryName": "github.com/foo/bar",
+								"command": "log -S",
+								"count": 3,
+								"slowCount": 2,
+								"failureCount": 0,
+								"totalDurationMilliseconds": 12000,
+								"maxDurationMilliseconds": 8000,
+								"outputBytes": 1024,
+								"clients": ["frontend"],
+								"slowestTrace": null
+							}
+						]
+					}
+				}
+			`,
+		},
+	})
+	if gotRepo != "github.com/foo/bar" || gotLimit != 5 {
+		t.Errorf("got repo %q and limit %d, want github.com/foo/bar and 5", gotRepo, gotLimit)
+	}
+}
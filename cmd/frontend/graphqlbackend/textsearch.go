@@ -187,9 +187,10 @@ func (lm *lineMatch) LimitHit() bool {
 
 var mockTextSearch func(ctx context.Context, repo gitserver.Repo, commit api.CommitID, p *search.TextPatternInfo, fetchTimeout time.Duration) (matches []*FileMatchResolver, limitHit bool, err error)
 
-// textSearch searches repo@commit with p.
+// textSearch searches repo@commit with p. The contents of submodules are
+// searched under their paths.
 // Note: the returned matches do not set fileMatch.uri
-func textSearch(ctx context.Context, searcherURLs *endpoint.Map, repo gitserver.Repo, commit api.CommitID, p *search.TextPatternInfo, fetchTimeout time.Duration) (matches []*FileMatchResolver, limitHit bool, err error) {
+func textSearch(ctx context.Context, searcherURLs *endpoint.Map, repo gitserver.Repo, commit api.CommitID, submodules []searchSubmodule, p *search.TextPatternInfo, fetchTimeout time.Duration) (matches []*FileMatchResolver, limitHit bool, err error) {
 	if mockTextSearch != nil {
 		return mockTextSearch(ctx, repo, commit, p, fetchTimeout)
 	}
@@ -241,6 +242,11 @@ func textSearch(ctx context.Context, searcherURLs *endpoint.Map, repo gitserver.
 	// these fields from old frontends that do not (and provide a default in the latter case).
 	q.Set("PatternMatchesContent", strconv.FormatBool(p.PatternMatchesContent))
 	q.Set("PatternMatchesPath", strconv.FormatBool(p.PatternMatchesPath))
+	for i, sm := range submodules {
+		q.Set(fmt.Sprintf("Submodules.%d.Path", i), sm.path)
+		q.Set(fmt.Sprintf("Submodules.%d.Repo", i), string(sm.repo.Name))
+		q.Set(fmt.Sprintf("Submodules.%d.Commit", i), string(sm.commit))
+	}
 	rawQuery := q.Encode()
 
 	// Searcher caches the file contents for repo@commit since it is
@@ -384,7 +390,14 @@ func searchFilesInRepo(ctx context.Context, searcherURLs *endpoint.Map, repo *ty
 		return nil, false, err
 	}
 
-	matches, limitHit, err = textSearch(ctx, searcherURLs, gitserverRepo, commit, info, fetchTimeout)
+	submodules, err := searchSubmodules(ctx, repo, gitserverRepo, commit)
+	if err != nil {
+		// Search the repository without its submodules.
+		log15.Warn("searchFilesInRepo: failed to find submodules", "repo", repo.Name, "commit", commit, "error", err)
+		submodules = nil
+	}
+
+	matches, limitHit, err = textSearch(ctx, searcherURLs, gitserverRepo, commit, submodules, info, fetchTimeout)
 	if err != nil {
 		return nil, false, err
 	}
@@ -392,6 +405,9 @@ func searchFilesInRepo(ctx context.Context, searcherURLs *endpoint.Map, repo *ty
 	workspace := fileMatchURI(repo.Name, rev, "")
 	repoResolver := &RepositoryResolver{repo: repo}
 	for _, fm := range matches {
+		if attributeSubmoduleMatch(fm, submodules) {
+			continue
+		}
 		fm.uri = workspace + fm.JPath
 		fm.Repo = repoResolver
 		fm.CommitID = commit
@@ -427,7 +443,7 @@ func repoShouldBeSearched(ctx context.Context, searcherURLs *endpoint.Map, searc
 func repoHasFilesWithNamesMatching(ctx context.Context, searcherURLs *endpoint.Map, include bool, repoHasFileFlag []string, gitserverRepo gitserver.Repo, commit api.CommitID, fetchTimeout time.Duration) (bool, error) {
 	for _, pattern := range repoHasFileFlag {
 		p := search.TextPatternInfo{IsRegExp: true, FileMatchLimit: 1, IncludePatterns: []string{pattern}, PathPatternsAreRegExps: true, PathPatternsAreCaseSensitive: false, PatternMatchesContent: true, PatternMatchesPath: true}
-		matches, _, err := textSearch(ctx, searcherURLs, gitserverRepo, commit, &p, fetchTimeout)
+		matches, _, err := textSearch(ctx, searcherURLs, gitserverRepo, commit, nil, &p, fetchTimeout)
 		if err != nil {
 			return false, err
 		}
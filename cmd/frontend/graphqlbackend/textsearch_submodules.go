@@ -0,0 +1,168 @@
+package graphqlbackend
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/src-d/go-git.v4/plumbing/format/config"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// maxGitmodulesSize is the maximum number of bytes of a .gitmodules file
+// that are read to find the submodules of a searched commit.
+const maxGitmodulesSize = 1 << 20
+
+// searchSubmodule is a submodule of a searched commit whose contents are
+// searched along with it.
+type searchSubmodule struct {
+	// path is the path of the submodule relative to the repository root.
+	path string
+
+	// repo is the repository on Sourcegraph the submodule points to.
+	repo *types.Repo
+
+	// commit is the commit of repo pinned by the submodule.
+	commit api.CommitID
+}
+
+var mockSearchSubmodules func(ctx context.Context, repo *types.Repo, commit api.CommitID) ([]searchSubmodule, error)
+
+// searchSubmodules returns the submodules of repo at commit whose contents
+// are searched along with it. It returns nil unless search.includeSubmodules
+// is enabled in site configuration.
+//
+// Only submodules whose clone URL maps to a repository the user can access
+// on Sourcegraph are returned. Nested submodules are not.
+func searchSubmodules(ctx context.Context, repo *types.Repo, gitserverRepo gitserver.Repo, commit api.CommitID) ([]searchSubmodule, error) {
+	if mockSearchSubmodules != nil {
+		return mockSearchSubmodules(ctx, repo, commit)
+	}
+	if !conf.Get().SearchIncludeSubmodules {
+		return nil, nil
+	}
+
+	data, err := git.ReadFile(ctx, gitserverRepo, commit, ".gitmodules", maxGitmodulesSize)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	entries, err := parseGitmodules(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var submodules []searchSubmodule
+	for _, e := range entries {
+		fi, err := git.Lstat(ctx, gitserverRepo, commit, e.path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				// .gitmodules can list submodules that were removed from the tree.
+				continue
+			}
+			return nil, err
+		}
+		sm, ok := fi.Sys().(git.Submodule)
+		if !ok {
+			continue
+		}
+
+		name, err := reposourceCloneURLToRepoName(ctx, resolveSubmoduleURL(repo.Name, e.url))
+		if err != nil {
+			return nil, err
+		}
+		if name == "" || name == repo.Name {
+			continue
+		}
+		smRepo, err := db.Repos.GetByName(ctx, name)
+		if err != nil {
+			if errcode.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		submodules = append(submodules, searchSubmodule{
+			path:   e.path,
+			repo:   smRepo,
+			commit: sm.CommitID,
+		})
+	}
+	return submodules, nil
+}
+
+type gitmodulesEntry struct {
+	path string
+	url  string
+}
+
+// parseGitmodules returns the path and URL of each submodule configured in
+// the contents of a .gitmodules file. Submodules without a path or URL are
+// skipped.
+func parseGitmodules(data []byte) ([]gitmodulesEntry, error) {
+	var cfg config.Config
+	if err := config.NewDecoder(bytes.NewReader(data)).Decode(&cfg); err != nil {
+		return nil, errors.Wrap(err, "parsing .gitmodules")
+	}
+
+	var entries []gitmodulesEntry
+	for _, sub := range cfg.Section("submodule").Subsections {
+		path := strings.Trim(sub.Option("path"), "/")
+		cloneURL := sub.Option("url")
+		if path == "" || cloneURL == "" {
+			continue
+		}
+		entries = append(entries, gitmodulesEntry{path: path, url: cloneURL})
+	}
+	return entries, nil
+}
+
+// resolveSubmoduleURL resolves a submodule URL relative to the superproject
+// (such as "../other.git") against the HTTPS URL derived from the name of
+// the superproject repository. Other URLs are returned unchanged.
+func resolveSubmoduleURL(superproject api.RepoName, submoduleURL string) string {
+	if !strings.HasPrefix(submoduleURL, "./") && !strings.HasPrefix(submoduleURL, "../") {
+		return submoduleURL
+	}
+	base, err := url.Parse("https://" + string(superproject) + "/")
+	if err != nil {
+		return submoduleURL
+	}
+	ref, err := url.Parse(submoduleURL)
+	if err != nil {
+		return submoduleURL
+	}
+	return base.ResolveReference(ref).String()
+}
+
+// attributeSubmoduleMatch attributes fm to the submodule containing it, if
+// any. The path of fm is made relative to the submodule and fm refers to the
+// submodule repository at the pinned commit. It reports whether fm is in a
+// submodule.
+func attributeSubmoduleMatch(fm *FileMatchResolver, submodules []searchSubmodule) bool {
+	for _, sm := range submodules {
+		if !strings.HasPrefix(fm.JPath, sm.path+"/") {
+			continue
+		}
+		rev := string(sm.commit)
+		fm.JPath = strings.TrimPrefix(fm.JPath, sm.path+"/")
+		fm.uri = fileMatchURI(sm.repo.Name, rev, fm.JPath)
+		fm.Repo = &RepositoryResolver{repo: sm.repo}
+		fm.CommitID = sm.commit
+		fm.InputRev = &rev
+		return true
+	}
+	return false
+}
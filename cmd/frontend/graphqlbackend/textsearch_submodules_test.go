@@ -0,0 +1,79 @@
+package graphqlbackend
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+)
+
+func TestParseGitmodules(t *testing.T) {
+	data := []byte(`[submodule "vendor/foo"]
+	path = vendor/foo
+	url = https://github.com/org/foo.git
+[submodule "bar"]
+	path = third_party/bar/
+	url = ../bar.git
+[submodule "nourl"]
+	path = nourl
+`)
+	got, err := parseGitmodules(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []gitmodulesEntry{
+		{path: "vendor/foo", url: "https://github.com/org/foo.git"},
+		{path: "third_party/bar", url: "../bar.git"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestResolveSubmoduleURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://github.com/org/foo.git", "https://github.com/org/foo.git"},
+		{"git@github.com:org/foo.git", "git@github.com:org/foo.git"},
+		{"../foo.git", "https://github.com/org/foo.git"},
+		{"./foo", "https://github.com/org/repo/foo"},
+	}
+	for _, test := range tests {
+		if got := resolveSubmoduleURL("github.com/org/repo", test.url); got != test.want {
+			t.Errorf("resolveSubmoduleURL(%q) = %q, want %q", test.url, got, test.want)
+		}
+	}
+}
+
+func TestAttributeSubmoduleMatch(t *testing.T) {
+	submodules := []searchSubmodule{{
+		path:   "vendor/foo",
+		repo:   &types.Repo{ID: 2, Name: "github.com/org/foo"},
+		commit: "cafebabecafebabecafebabecafebabecafebabe",
+	}}
+
+	fm := &FileMatchResolver{JPath: "vendor/foo/lib/lib.go"}
+	if !attributeSubmoduleMatch(fm, submodules) {
+		t.Fatal("expected match in submodule to be attributed")
+	}
+	if fm.JPath != "lib/lib.go" {
+		t.Errorf("got path %q, want %q", fm.JPath, "lib/lib.go")
+	}
+	if fm.Repo.repo.Name != "github.com/org/foo" {
+		t.Errorf("got repo %q, want %q", fm.Repo.repo.Name, "github.com/org/foo")
+	}
+	if fm.CommitID != submodules[0].commit || *fm.InputRev != string(submodules[0].commit) {
+		t.Errorf("got commit %q and rev %q, want %q", fm.CommitID, *fm.InputRev, submodules[0].commit)
+	}
+	if want := "git://github.com/org/foo?cafebabecafebabecafebabecafebabecafebabe#lib/lib.go"; fm.uri != want {
+		t.Errorf("got uri %q, want %q", fm.uri, want)
+	}
+
+	for _, path := range []string{"main.go", "vendor/foobar/x.go", "vendor/foo"} {
+		if attributeSubmoduleMatch(&FileMatchResolver{JPath: path}, submodules) {
+			t.Errorf("expected match %q not to be attributed to a submodule", path)
+		}
+	}
+}
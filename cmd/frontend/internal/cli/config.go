@@ -21,6 +21,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
 	"github.com/sourcegraph/sourcegraph/internal/db/confdb"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/jsonc"
 )
 
@@ -209,9 +210,23 @@ func (c configurationSource) Read(ctx context.Context) (conftypes.RawUnified, er
 	if err != nil {
 		return conftypes.RawUnified{}, errors.Wrap(err, "confdb.SiteGetLatest")
 	}
+	// The placements of moved repositories change at runtime, so they are
+	// read on every poll.
+	placements, err := db.Repos.ListGitserverPlacements(ctx)
+	if err != nil {
+		return conftypes.RawUnified{}, errors.Wrap(err, "db.Repos.ListGitserverPlacements")
+	}
+	serviceConnections := serviceConnections()
+	if len(placements) > 0 {
+		serviceConnections.GitServerPlacements = make(map[string]string, len(placements))
+		for repo, addr := range placements {
+			serviceConnections.GitServerPlacements[string(protocol.NormalizeRepo(repo))] = addr
+		}
+	}
+
 	return conftypes.RawUnified{
 		Site:               site.Contents,
-		ServiceConnections: serviceConnections(),
+		ServiceConnections: serviceConnections,
 	}, nil
 }
 
@@ -29,14 +29,14 @@ import (
 
 // newExternalHTTPHandler creates and returns the HTTP handler that serves the app and API pages to
 // external clients.
-func newExternalHTTPHandler(schema *graphql.Schema, githubWebhook, bitbucketServerWebhook http.Handler, newCodeIntelUploadHandler enterprise.NewCodeIntelUploadHandler) (http.Handler, error) {
+func newExternalHTTPHandler(schema *graphql.Schema, githubWebhook, bitbucketServerWebhook http.Handler, newCodeIntelUploadHandler enterprise.NewCodeIntelUploadHandler, newCodeIntelValidateHandler enterprise.NewCodeIntelValidateHandler) (http.Handler, error) {
 	// Each auth middleware determines on a per-request basis whether it should be enabled (if not, it
 	// immediately delegates the request to the next middleware in the chain).
 	authMiddlewares := auth.AuthMiddleware()
 
 	// HTTP API handler, the call order of middleware is LIFO.
 	r := router.New(mux.NewRouter().PathPrefix("/.api/").Subrouter())
-	apiHandler := internalhttpapi.NewHandler(r, schema, githubWebhook, bitbucketServerWebhook, newCodeIntelUploadHandler)
+	apiHandler := internalhttpapi.NewHandler(r, schema, githubWebhook, bitbucketServerWebhook, newCodeIntelUploadHandler, newCodeIntelValidateHandler)
 	if hooks.PostAuthMiddleware != nil {
 		// 🚨 SECURITY: These all run after the auth handler so the client is authenticated.
 		apiHandler = hooks.PostAuthMiddleware(apiHandler)
@@ -48,6 +48,16 @@ func newExternalHTTPHandler(schema *graphql.Schema, githubWebhook, bitbucketServ
 	apiHandler = internalhttpapi.AccessTokenAuthMiddleware(apiHandler)                                // API accepts access tokens
 	apiHandler = gziphandler.GzipHandler(apiHandler)
 
+	// Git handler for git clients, the call order of middleware is LIFO.
+	gitHandler := internalhttpapi.NewGitServeHandler()
+	if hooks.PostAuthMiddleware != nil {
+		// 🚨 SECURITY: These all run after the auth handler so the client is authenticated.
+		gitHandler = hooks.PostAuthMiddleware(gitHandler)
+	}
+	gitHandler = authMiddlewares.API(gitHandler)                         // 🚨 SECURITY: auth middleware
+	gitHandler = internalhttpapi.AccessTokenAuthMiddleware(gitHandler)   // git clients authenticate with access tokens
+	gitHandler = internalhttpapi.GitServeBasicAuthMiddleware(gitHandler) // access tokens can be passed as the basic auth password
+
 	// App handler (HTML pages), the call order of middleware is LIFO.
 	appHandler := app.NewHandler()
 	if hooks.PostAuthMiddleware != nil {
@@ -64,6 +74,7 @@ func newExternalHTTPHandler(schema *graphql.Schema, githubWebhook, bitbucketServ
 	// Mount handlers and assets.
 	sm := http.NewServeMux()
 	sm.Handle("/.api/", apiHandler)
+	sm.Handle(internalhttpapi.GitServePathPrefix, gitHandler)
 	sm.Handle("/", appHandler)
 	assetsutil.Mount(sm)
 
@@ -98,7 +109,7 @@ func healthCheckMiddleware(next http.Handler) http.Handler {
 
 // newInternalHTTPHandler creates and returns the HTTP handler for the internal API (accessible to
 // other internal services).
-func newInternalHTTPHandler(schema *graphql.Schema, newCodeIntelUploadHandler enterprise.NewCodeIntelUploadHandler) http.Handler {
+func newInternalHTTPHandler(schema *graphql.Schema, newCodeIntelUploadHandler enterprise.NewCodeIntelUploadHandler, newCodeIntelValidateHandler enterprise.NewCodeIntelValidateHandler) http.Handler {
 	internalMux := http.NewServeMux()
 	internalMux.Handle("/.internal/", gziphandler.GzipHandler(
 		withInternalActor(
@@ -106,6 +117,7 @@ func newInternalHTTPHandler(schema *graphql.Schema, newCodeIntelUploadHandler en
 				router.NewInternal(mux.NewRouter().PathPrefix("/.internal/").Subrouter()),
 				schema,
 				newCodeIntelUploadHandler,
+				newCodeIntelValidateHandler,
 			),
 		),
 	))
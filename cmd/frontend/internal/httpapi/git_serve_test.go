@@ -0,0 +1,169 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type fakeGitServeGitserver struct {
+	addr   string
+	cloned bool
+}
+
+func (g *fakeGitServeGitserver) AddrForRepo(context.Context, api.RepoName) string { return g.addr }
+
+func (g *fakeGitServeGitserver) IsRepoCloned(context.Context, api.RepoName) (bool, error) {
+	return g.cloned, nil
+}
+
+func TestGitServeHandler(t *testing.T) {
+	var gotPath, gotQuery, gotAuth, gotProtocol string
+	gs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery = r.URL.Path, r.URL.RawQuery
+		gotAuth, gotProtocol = r.Header.Get("Authorization"), r.Header.Get("Git-Protocol")
+		_, _ = w.Write([]byte("refs"))
+	}))
+	defer gs.Close()
+	gsURL, _ := url.Parse(gs.URL)
+
+	gitserverMock := &fakeGitServeGitserver{addr: gsURL.Host, cloned: true}
+	h := &gitServeHandler{Gitserver: gitserverMock}
+
+	db.Mocks.Repos.GetByName = func(ctx context.Context, name api.RepoName) (*types.Repo, error) {
+		if name == "github.com/foo/bar" {
+			return &types.Repo{ID: 1, Name: name}, nil
+		}
+		return nil, &db.RepoNotFoundErr{Name: name}
+	}
+	defer func() { db.Mocks.Repos.GetByName = nil }()
+
+	var enqueued api.RepoName
+	repoupdater.MockEnqueueRepoUpdate = func(ctx context.Context, repo gitserver.Repo) (*protocol.RepoUpdateResponse, error) {
+		enqueued = repo.Name
+		return &protocol.RepoUpdateResponse{}, nil
+	}
+	defer func() { repoupdater.MockEnqueueRepoUpdate = nil }()
+
+	serve := func(method, target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		req = req.WithContext(actor.WithActor(req.Context(), &actor.Actor{UID: 1}))
+		req.Header.Set("Authorization", "token secret")
+		req.Header.Set("Git-Protocol", "version=2")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		if rec := serve("GET", "/.git/github.com/foo/bar/info/refs?service=git-upload-pack"); rec.Code != http.StatusNotFound {
+			t.Fatalf("got status %d, want %d", rec.Code, http.StatusNotFound)
+		}
+	})
+
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{GitServeRepositories: true}})
+	defer conf.Mock(nil)
+
+	t.Run("info refs", func(t *testing.T) {
+		rec := serve("GET", "/.git/github.com/foo/bar.git/info/refs?service=git-upload-pack")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("got status %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+		}
+		if want := "/public-git/github.com/foo/bar/info/refs"; gotPath != want {
+			t.Errorf("got gitserver path %q, want %q", gotPath, want)
+		}
+		if want := "service=git-upload-pack"; gotQuery != want {
+			t.Errorf("got gitserver query %q, want %q", gotQuery, want)
+		}
+		if gotAuth != "" {
+			t.Errorf("expected Authorization header not to be proxied, got %q", gotAuth)
+		}
+		if want := "version=2"; gotProtocol != want {
+			t.Errorf("got Git-Protocol header %q, want %q", gotProtocol, want)
+		}
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/.git/github.com/foo/bar/info/refs?service=git-upload-pack", nil)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("got status %d, want %d", rec.Code, http.StatusUnauthorized)
+		}
+		if got, want := rec.Header().Get("WWW-Authenticate"), `Basic realm="Sourcegraph"`; got != want {
+			t.Errorf("got WWW-Authenticate header %q, want %q", got, want)
+		}
+	})
+
+	t.Run("upload pack", func(t *testing.T) {
+		rec := serve("POST", "/.git/github.com/foo/bar/git-upload-pack")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("got status %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+		}
+		if want := "/public-git/github.com/foo/bar/git-upload-pack"; gotPath != want {
+			t.Errorf("got gitserver path %q, want %q", gotPath, want)
+		}
+	})
+
+	t.Run("push", func(t *testing.T) {
+		if rec := serve("GET", "/.git/github.com/foo/bar/info/refs?service=git-receive-pack"); rec.Code != http.StatusForbidden {
+			t.Fatalf("got status %d, want %d", rec.Code, http.StatusForbidden)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		// Repositories that the user can't read are not found either.
+		rec := serve("GET", "/.git/github.com/foo/private/info/refs?service=git-upload-pack")
+		if rec.Code != http.StatusNotFound || !strings.Contains(rec.Body.String(), "repository not found") {
+			t.Fatalf("got status %d (%s), want %d", rec.Code, rec.Body, http.StatusNotFound)
+		}
+	})
+
+	t.Run("not cloned", func(t *testing.T) {
+		gitserverMock.cloned = false
+		defer func() { gitserverMock.cloned = true }()
+
+		rec := serve("GET", "/.git/github.com/foo/bar/info/refs?service=git-upload-pack")
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Fatalf("got status %d, want %d", rec.Code, http.StatusServiceUnavailable)
+		}
+		if enqueued != "github.com/foo/bar" {
+			t.Errorf("expected clone of github.com/foo/bar to be enqueued, got %q", enqueued)
+		}
+	})
+}
+
+func TestGitServeBasicAuthMiddleware(t *testing.T) {
+	const token = "0123456789abcdef0123456789abcdef01234567"
+	for _, tc := range []struct {
+		user, password, want string
+	}{
+		{token, "", token},
+		{"git", token, token},
+		{token, "x-oauth-basic", token},
+	} {
+		var got string
+		h := GitServeBasicAuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got, _, _ = r.BasicAuth()
+		}))
+		req := httptest.NewRequest("GET", "/.git/github.com/foo/bar/info/refs", nil)
+		req.SetBasicAuth(tc.user, tc.password)
+		h.ServeHTTP(httptest.NewRecorder(), req)
+		if got != tc.want {
+			t.Errorf("basic auth %s:%s: got token %q, want %q", tc.user, tc.password, got, tc.want)
+		}
+	}
+}
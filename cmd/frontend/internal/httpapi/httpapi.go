@@ -31,7 +31,7 @@ import (
 //
 // 🚨 SECURITY: The caller MUST wrap the returned handler in middleware that checks authentication
 // and sets the actor in the request context.
-func NewHandler(m *mux.Router, schema *graphql.Schema, githubWebhook, bitbucketServerWebhook http.Handler, newCodeIntelUploadHandler enterprise.NewCodeIntelUploadHandler) http.Handler {
+func NewHandler(m *mux.Router, schema *graphql.Schema, githubWebhook, bitbucketServerWebhook http.Handler, newCodeIntelUploadHandler enterprise.NewCodeIntelUploadHandler, newCodeIntelValidateHandler enterprise.NewCodeIntelValidateHandler) http.Handler {
 	if m == nil {
 		m = apirouter.New(nil)
 	}
@@ -49,9 +49,11 @@ func NewHandler(m *mux.Router, schema *graphql.Schema, githubWebhook, bitbucketS
 
 	m.Get(apirouter.RepoRefresh).Handler(trace.TraceRoute(handler(serveRepoRefresh)))
 
-	m.Get(apirouter.GitHubWebhooks).Handler(trace.TraceRoute(githubWebhook))
-	m.Get(apirouter.BitbucketServerWebhooks).Handler(trace.TraceRoute(bitbucketServerWebhook))
+	m.Get(apirouter.GitHubWebhooks).Handler(trace.TraceRoute(repoWebhookHandler("github", isGitHubRepoEvent, githubWebhook)))
+	m.Get(apirouter.BitbucketServerWebhooks).Handler(trace.TraceRoute(repoWebhookHandler("bitbucket-server", isBitbucketServerRepoEvent, bitbucketServerWebhook)))
+	m.Get(apirouter.GitLabWebhooks).Handler(trace.TraceRoute(repoWebhookHandler("gitlab", nil, nil)))
 	m.Get(apirouter.LSIFUpload).Handler(trace.TraceRoute(newCodeIntelUploadHandler(false)))
+	m.Get(apirouter.LSIFValidate).Handler(trace.TraceRoute(newCodeIntelValidateHandler(false)))
 
 	if envvar.SourcegraphDotComMode() {
 		m.Path("/updates").Methods("GET", "POST").Name("updatecheck").Handler(trace.TraceRoute(http.HandlerFunc(updatecheck.Handler)))
@@ -79,7 +81,7 @@ func NewHandler(m *mux.Router, schema *graphql.Schema, githubWebhook, bitbucketS
 // 🚨 SECURITY: This handler should not be served on a publicly exposed port. 🚨
 // This handler is not guaranteed to provide the same authorization checks as
 // public API handlers.
-func NewInternalHandler(m *mux.Router, schema *graphql.Schema, newCodeIntelUploadHandler enterprise.NewCodeIntelUploadHandler) http.Handler {
+func NewInternalHandler(m *mux.Router, schema *graphql.Schema, newCodeIntelUploadHandler enterprise.NewCodeIntelUploadHandler, newCodeIntelValidateHandler enterprise.NewCodeIntelValidateHandler) http.Handler {
 	if m == nil {
 		m = apirouter.New(nil)
 	}
@@ -129,6 +131,7 @@ func NewInternalHandler(m *mux.Router, schema *graphql.Schema, newCodeIntelUploa
 	m.Path("/ping").Methods("GET").Name("ping").HandlerFunc(handlePing)
 
 	m.Get(apirouter.LSIFUpload).Handler(trace.TraceRoute(newCodeIntelUploadHandler(true)))
+	m.Get(apirouter.LSIFValidate).Handler(trace.TraceRoute(newCodeIntelValidateHandler(true)))
 
 	m.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		log.Printf("API no route: %s %s from %s", r.Method, r.URL, r.Referer())
@@ -0,0 +1,70 @@
+package httpapi
+
+import (
+	"io/ioutil"
+	"net/http"
+
+	"github.com/inconshreveable/log15"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
+)
+
+// repoWebhookHandler forwards the repository and push events of code host
+// webhooks to the repo-updater, which applies them to the stored repositories
+// and schedules updates of pushed repositories right away.
+// Requests for which isRepoEvent returns false are served by next. If
+// isRepoEvent is nil, all requests are forwarded.
+//
+// 🚨 SECURITY: The repo-updater authenticates the requests with the webhook
+// secrets of the external services.
+func repoWebhookHandler(kind string, isRepoEvent func(*http.Request) bool, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isRepoEvent != nil && !isRepoEvent(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		payload, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		code, err := repoupdater.DefaultClient.ForwardWebhook(r.Context(), kind, r, payload)
+		if err != nil {
+			log15.Error("Forwarding repository webhook event to repo-updater failed", "kind", kind, "code", code, "error", err)
+			if code == 0 {
+				code = http.StatusBadGateway
+			}
+			// The error isn't passed on, since the request may not be authenticated.
+			http.Error(w, http.StatusText(code), code)
+			return
+		}
+		w.WriteHeader(code)
+	})
+}
+
+// isGitHubRepoEvent reports whether the request is a GitHub "repository"
+// webhook event, which is sent when a repository is created, deleted,
+// archived, renamed, transferred or has its visibility changed, or a "push"
+// webhook event.
+func isGitHubRepoEvent(r *http.Request) bool {
+	switch r.Header.Get("X-GitHub-Event") {
+	case "repository", "push":
+		return true
+	default:
+		return false
+	}
+}
+
+// isBitbucketServerRepoEvent reports whether the request is a Bitbucket
+// Server "repo:modified" webhook event, which is sent when a repository is
+// renamed or moved to another project, or a "repo:refs_changed" webhook event,
+// which is sent on pushes.
+func isBitbucketServerRepoEvent(r *http.Request) bool {
+	switch r.Header.Get("X-Event-Key") {
+	case "repo:modified", "repo:refs_changed":
+		return true
+	default:
+		return false
+	}
+}
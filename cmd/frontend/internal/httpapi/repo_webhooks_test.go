@@ -0,0 +1,71 @@
+package httpapi
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
+)
+
+func TestRepoWebhookHandler(t *testing.T) {
+	var forwarded []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		forwarded = append(forwarded, r.URL.RequestURI()+" "+r.Header.Get("X-GitHub-Event")+" "+string(body))
+		if r.Header.Get("X-Hub-Signature") != "valid" {
+			http.Error(w, "webhook request could not be authenticated", http.StatusUnauthorized)
+		}
+	}))
+	defer s.Close()
+
+	orig := repoupdater.DefaultClient
+	repoupdater.DefaultClient = &repoupdater.Client{URL: s.URL}
+	defer func() { repoupdater.DefaultClient = orig }()
+
+	var served int
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served++
+		w.WriteHeader(http.StatusNoContent)
+	})
+	h := repoWebhookHandler("github", isGitHubRepoEvent, next)
+
+	for _, tc := range []struct {
+		event, signature string
+		code             int
+		body             string
+	}{
+		{"repository", "valid", http.StatusOK, ""},
+		{"repository", "invalid", http.StatusUnauthorized, "Unauthorized\n"},
+		{"push", "valid", http.StatusOK, ""},
+		{"pull_request", "valid", http.StatusNoContent, ""},
+	} {
+		req := httptest.NewRequest("POST", "/.api/github-webhooks?externalServiceID=1", strings.NewReader("{}"))
+		req.Header.Set("X-GitHub-Event", tc.event)
+		req.Header.Set("X-Hub-Signature", tc.signature)
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		if rec.Code != tc.code {
+			t.Errorf("%s: have status %d, want %d", tc.event, rec.Code, tc.code)
+		}
+		if have := rec.Body.String(); have != tc.body {
+			t.Errorf("%s: have body %q, want %q", tc.event, have, tc.body)
+		}
+	}
+
+	want := []string{
+		"/webhooks/github?externalServiceID=1 repository {}",
+		"/webhooks/github?externalServiceID=1 repository {}",
+		"/webhooks/github?externalServiceID=1 push {}",
+	}
+	if strings.Join(forwarded, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected forwarded requests:\nhave %q\nwant %q", forwarded, want)
+	}
+	if served != 1 {
+		t.Errorf("next handler served %d requests, want 1", served)
+	}
+}
@@ -7,8 +7,9 @@ import (
 )
 
 const (
-	LSIFUpload = "lsif.upload"
-	GraphQL    = "graphql"
+	LSIFUpload   = "lsif.upload"
+	LSIFValidate = "lsif.validate"
+	GraphQL      = "graphql"
 
 	SrcCliVersion  = "src-cli.version"
 	SrcCliDownload = "src-cli.download"
@@ -21,6 +22,7 @@ const (
 
 	GitHubWebhooks          = "github.webhooks"
 	BitbucketServerWebhooks = "bitbucketServer.webhooks"
+	GitLabWebhooks          = "gitlab.webhooks"
 
 	SavedQueriesListAll    = "internal.saved-queries.list-all"
 	SavedQueriesGetInfo    = "internal.saved-queries.get-info"
@@ -66,7 +68,9 @@ func New(base *mux.Router) *mux.Router {
 	addGraphQLRoute(base)
 	base.Path("/github-webhooks").Methods("POST").Name(GitHubWebhooks)
 	base.Path("/bitbucket-server-webhooks").Methods("POST").Name(BitbucketServerWebhooks)
+	base.Path("/gitlab-webhooks").Methods("POST").Name(GitLabWebhooks)
 	base.Path("/lsif/upload").Methods("POST").Name(LSIFUpload)
+	base.Path("/lsif/validate").Methods("POST").Name(LSIFValidate)
 	base.Path("/src-cli/version").Methods("GET").Name(SrcCliVersion)
 	base.Path("/src-cli/{rest:.*}").Methods("GET").Name(SrcCliDownload)
 
@@ -121,6 +125,7 @@ func NewInternal(base *mux.Router) *mux.Router {
 	base.Path("/search/configuration").Methods("GET").Name(SearchConfiguration)
 	base.Path("/telemetry").Methods("POST").Name(Telemetry)
 	base.Path("/lsif/upload").Methods("POST").Name(LSIFUpload)
+	base.Path("/lsif/validate").Methods("POST").Name(LSIFValidate)
 	addRegistryRoute(base)
 	addGraphQLRoute(base)
 
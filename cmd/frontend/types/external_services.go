@@ -45,6 +45,18 @@ func (c *BitbucketServerConnection) SetURN(urn string) {
 	c.URN = urn
 }
 
+var _ CodeHostConnection = (*GerritConnection)(nil)
+
+type GerritConnection struct {
+	// The unique resource identifier of the external service.
+	URN string
+	*schema.GerritConnection
+}
+
+func (c *GerritConnection) SetURN(urn string) {
+	c.URN = urn
+}
+
 var _ CodeHostConnection = (*GitHubConnection)(nil)
 
 type GitHubConnection struct {
@@ -28,6 +28,12 @@ type RepoFields struct {
 
 	// Archived is whether this repository has been archived.
 	Archived bool
+
+	// Topics are the topics, tags or labels of this repository on its code host.
+	Topics []string
+
+	// Stars is the number of stars of this repository on its code host.
+	Stars int
 }
 
 // Repo represents a source code repository.
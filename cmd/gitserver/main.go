@@ -19,6 +19,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/gitserver/server"
 	"github.com/sourcegraph/sourcegraph/internal/debugserver"
 	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/objectstorage"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 	"github.com/sourcegraph/sourcegraph/internal/tracer"
 )
@@ -28,6 +29,22 @@ var (
 	runRepoCleanup, _ = strconv.ParseBool(env.Get("SRC_RUN_REPO_CLEANUP", "", "Periodically remove inactive repositories."))
 	wantPctFree       = env.Get("SRC_REPOS_DESIRED_PERCENT_FREE", "10", "Target percentage of free space on disk.")
 	janitorInterval   = env.Get("SRC_REPOS_JANITOR_INTERVAL", "1m", "Interval between cleanup runs")
+
+	maintenanceInterval    = env.Get("SRC_REPOS_MAINTENANCE_INTERVAL", "24h", "Interval between git maintenance runs (repack, commit-graph and multi-pack-index) on each repository. Set to 0 to disable.")
+	maintenanceConcurrency = env.Get("SRC_REPOS_MAINTENANCE_CONCURRENCY", "1", "Maximum number of repositories git maintenance runs on at the same time.")
+	maintenanceCPUBudget   = env.Get("SRC_REPOS_MAINTENANCE_CPU_BUDGET", "15m", "CPU time git maintenance may use per hour. Set to 0 for no limit.")
+
+	backupStore              = env.Get("SRC_REPOS_BACKUP_STORE", "", "Where to back up repositories so that clones can be restored from them: empty (disabled), local, or s3.")
+	backupStoreDir           = env.Get("SRC_REPOS_BACKUP_STORE_DIR", "", "Root dir of the local backup store (e.g. a volume that outlives the gitserver disk).")
+	backupStoreS3Bucket      = env.Get("SRC_REPOS_BACKUP_STORE_S3_BUCKET", "", "Bucket of the S3 backup store.")
+	backupStoreS3Endpoint    = env.Get("SRC_REPOS_BACKUP_STORE_S3_ENDPOINT", "", "Endpoint of an S3-compatible service (e.g. MinIO or GCS) to use instead of AWS.")
+	backupStoreS3Region      = env.Get("SRC_REPOS_BACKUP_STORE_S3_REGION", "us-east-1", "Region of the S3 backup store bucket.")
+	backupStoreS3AccessKeyID = env.Get("SRC_REPOS_BACKUP_STORE_S3_ACCESS_KEY_ID", "", "Access key of the S3 backup store. Defaults to the AWS credential chain.")
+	backupStoreS3SecretKey   = env.Get("SRC_REPOS_BACKUP_STORE_S3_SECRET_ACCESS_KEY", "", "Secret key of the S3 backup store.")
+	backupStoreS3PathStyle   = env.Get("SRC_REPOS_BACKUP_STORE_S3_FORCE_PATH_STYLE", "false", "Address the S3 backup store bucket by path (required by most self-hosted services).")
+	backupInterval           = env.Get("SRC_REPOS_BACKUP_INTERVAL", "24h", "Interval between backups of each repository to the backup store.")
+
+	execLogSize = env.Get("SRC_EXEC_LOG_SIZE", "10000", "Number of recent exec requests kept in the exec log served at /debug/exec-log. Set to 0 to disable.")
 )
 
 func main() {
@@ -46,10 +63,40 @@ func main() {
 	if err != nil {
 		log.Fatalf("parsing $SRC_REPOS_DESIRED_PERCENT_FREE: %v", err)
 	}
+	maintenanceInterval2, err := time.ParseDuration(maintenanceInterval)
+	if err != nil {
+		log.Fatalf("parsing $SRC_REPOS_MAINTENANCE_INTERVAL: %v", err)
+	}
+	maintenanceConcurrency2, err := strconv.Atoi(maintenanceConcurrency)
+	if err != nil {
+		log.Fatalf("parsing $SRC_REPOS_MAINTENANCE_CONCURRENCY: %v", err)
+	}
+	maintenanceCPUBudget2, err := time.ParseDuration(maintenanceCPUBudget)
+	if err != nil {
+		log.Fatalf("parsing $SRC_REPOS_MAINTENANCE_CPU_BUDGET: %v", err)
+	}
+	backupStore2, err := newBackupStore()
+	if err != nil {
+		log.Fatalf("configuring the backup store: %v", err)
+	}
+	backupInterval2, err := time.ParseDuration(backupInterval)
+	if err != nil {
+		log.Fatalf("parsing $SRC_REPOS_BACKUP_INTERVAL: %v", err)
+	}
+	execLogSize2, err := strconv.Atoi(execLogSize)
+	if err != nil {
+		log.Fatalf("parsing $SRC_EXEC_LOG_SIZE: %v", err)
+	}
 	gitserver := server.Server{
 		ReposDir:                reposDir,
 		DeleteStaleRepositories: runRepoCleanup,
 		DesiredPercentFree:      wantPctFree2,
+		MaintenanceInterval:     maintenanceInterval2,
+		MaintenanceConcurrency:  maintenanceConcurrency2,
+		MaintenanceCPUBudget:    maintenanceCPUBudget2,
+		BackupStore:             backupStore2,
+		BackupInterval:          backupInterval2,
+		ExecLogSize:             execLogSize2,
 	}
 	gitserver.RegisterMetrics()
 
@@ -76,6 +123,18 @@ func main() {
 			time.Sleep(janitorInterval2)
 		}
 	}()
+	go func() {
+		for {
+			gitserver.Maintenance()
+			time.Sleep(janitorInterval2)
+		}
+	}()
+	go func() {
+		for {
+			gitserver.Backup()
+			time.Sleep(janitorInterval2)
+		}
+	}()
 
 	port := "3178"
 	host := ""
@@ -111,6 +170,35 @@ func main() {
 	gitserver.Stop()
 }
 
+// newBackupStore returns the store configured by $SRC_REPOS_BACKUP_STORE,
+// or nil if backups are disabled.
+func newBackupStore() (objectstorage.Store, error) {
+	switch backupStore {
+	case "":
+		return nil, nil
+	case "local":
+		if backupStoreDir == "" {
+			return nil, errors.New("$SRC_REPOS_BACKUP_STORE_DIR is required")
+		}
+		return objectstorage.NewLocalStore(backupStoreDir)
+	case "s3":
+		forcePathStyle, err := strconv.ParseBool(backupStoreS3PathStyle)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing $SRC_REPOS_BACKUP_STORE_S3_FORCE_PATH_STYLE")
+		}
+		return objectstorage.NewS3Store(objectstorage.S3Config{
+			Bucket:          backupStoreS3Bucket,
+			Endpoint:        backupStoreS3Endpoint,
+			Region:          backupStoreS3Region,
+			AccessKeyID:     backupStoreS3AccessKeyID,
+			SecretAccessKey: backupStoreS3SecretKey,
+			ForcePathStyle:  forcePathStyle,
+		})
+	default:
+		return nil, fmt.Errorf("unknown $SRC_REPOS_BACKUP_STORE %q, expected local or s3", backupStore)
+	}
+}
+
 func parsePercent(s string) (int, error) {
 	p, err := strconv.Atoi(s)
 	if err != nil {
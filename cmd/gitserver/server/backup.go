@@ -0,0 +1,289 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/objectstorage"
+)
+
+var (
+	backupDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "src_gitserver_backup_duration_seconds",
+		Help:    "time spent backing up a repo to the backup store",
+		Buckets: []float64{0.1, 0.5, 1, 5, 15, 60, 300, 900, 3600},
+	}, []string{"success"})
+	backupBytes = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "src_gitserver_backup_bytes_total",
+		Help: "size of the repo bundles uploaded to the backup store",
+	})
+	backupReposDue = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "src_gitserver_backup_repos_due",
+		Help: "number of repos due for a backup at the start of the last backup run",
+	})
+	backupRestores = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "src_gitserver_backup_restores_total",
+		Help: "number of clones that were restored from the backup store",
+	}, []string{"success"})
+)
+
+// backupKey returns the key of the bundle of the repo in the backup store.
+// Each repo only has one bundle, which is replaced by every backup.
+func backupKey(repo api.RepoName) string {
+	return path.Join("repos", string(repo)) + ".bundle"
+}
+
+// Backup uploads a bundle of the repos that were last backed up longer than
+// s.BackupInterval ago to s.BackupStore, least recently backed up first, so
+// that cloneRepo can restore them instead of recloning them from the code
+// host. Repos that didn't change since their last backup aren't uploaded
+// again.
+//
+// Only full clones of git repositories are backed up: partial clones can't be
+// bundled, and the restore only applies to full clones.
+func (s *Server) Backup() {
+	if s.BackupStore == nil || s.BackupInterval <= 0 {
+		return
+	}
+
+	ctx, cancel := s.serverContext()
+	defer cancel()
+
+	dirs, err := s.findGitDirs()
+	if err != nil {
+		log15.Error("backup: error finding repositories", "error", err)
+		return
+	}
+
+	type candidate struct {
+		dir  GitDir
+		last time.Time
+	}
+	var due []candidate
+	now := time.Now()
+	for _, dir := range dirs {
+		if _, cloning := s.locker.Status(dir); cloning {
+			continue
+		}
+		if strategy, err := getCloneStrategy(dir); err != nil || strategy != (cloneStrategy{}) || quickIsPartialClone(dir) {
+			continue
+		}
+		// Conversions from other version control systems are restored by
+		// converting them again.
+		if remoteURL, err := repoRemoteURL(ctx, dir); err != nil || vcsSyncer(remoteURL).Type() != "git" {
+			continue
+		}
+		last, err := getBackupTime(dir)
+		if err != nil {
+			log15.Warn("backup: error getting last backup time", "repo", dir, "error", err)
+			continue
+		}
+		if now.Sub(last) < s.BackupInterval+jitterDuration(string(dir), s.BackupInterval/4) {
+			continue
+		}
+		due = append(due, candidate{dir: dir, last: last})
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].last.Before(due[j].last) })
+	backupReposDue.Set(float64(len(due)))
+
+	for _, c := range due {
+		if ctx.Err() != nil {
+			return
+		}
+
+		start := time.Now()
+		err := s.backupRepo(ctx, c.dir, c.last)
+		backupDuration.WithLabelValues(strconv.FormatBool(err == nil)).Observe(time.Since(start).Seconds())
+		if err != nil {
+			log15.Error("backup: error backing up repo", "repo", c.dir, "error", err)
+		}
+	}
+}
+
+// backupRepo uploads a bundle of all the refs of the repo to the backup
+// store, unless the repo didn't change since the last backup.
+func (s *Server) backupRepo(ctx context.Context, dir GitDir, last time.Time) error {
+	now := time.Now()
+
+	lastChanged, err := repoLastChanged(dir)
+	if err != nil {
+		return err
+	}
+	if !last.IsZero() && lastChanged.Before(last) {
+		return setBackupTime(dir, now)
+	}
+
+	// git bundle refuses to create empty bundles.
+	cmd := exec.CommandContext(ctx, "git", "for-each-ref", "--count=1")
+	cmd.Dir = string(dir)
+	out, err := cmd.Output()
+	if err != nil {
+		return wrapCmdError(cmd, err)
+	}
+	if len(bytes.TrimSpace(out)) == 0 {
+		return nil
+	}
+
+	// Stream the bundle to the store. A failing git command makes the
+	// upload fail, so partial bundles are never stored.
+	pr, pw := io.Pipe()
+	cmd = exec.CommandContext(ctx, "git", "bundle", "create", "-", "--all")
+	cmd.Dir = string(dir)
+	cmd.Stdout = pw
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		err := cmd.Wait()
+		if err != nil {
+			err = errors.Wrapf(err, "git bundle failed. Output: %s", stderr.String())
+		}
+		_ = pw.CloseWithError(err)
+	}()
+
+	cr := &countingReader{r: pr}
+	err = s.BackupStore.Upload(ctx, backupKey(s.name(dir)), cr)
+	// Unblock the git command if the upload stopped reading early.
+	_ = pr.CloseWithError(errors.New("upload stopped"))
+	<-done
+	if err != nil {
+		return errors.Wrap(err, "failed to upload bundle")
+	}
+
+	backupBytes.Add(float64(cr.n))
+	log15.Debug("backup: backed up repo", "repo", dir, "size", cr.n)
+	return setBackupTime(dir, now)
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// restoreBackup clones the latest backup of the repo from the backup store
+// into the bare repository tmp, and sets url as its origin so that it can be
+// fetched from the code host. It reports whether there was a backup to
+// restore.
+func (s *Server) restoreBackup(ctx context.Context, repo api.RepoName, url string, tmp GitDir) (restored bool, err error) {
+	defer func() {
+		if restored || err != nil {
+			backupRestores.WithLabelValues(strconv.FormatBool(err == nil)).Inc()
+		}
+	}()
+
+	key := backupKey(repo)
+	objects, err := s.BackupStore.List(ctx, key)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to list backups")
+	}
+	var backup *objectstorage.Object
+	for i := range objects {
+		if objects[i].Key == key {
+			backup = &objects[i]
+		}
+	}
+	if backup == nil {
+		return false, nil
+	}
+
+	// git clone needs to seek in the bundle, so download it first.
+	tmpDir, err := s.tempDir("restore-")
+	if err != nil {
+		return false, err
+	}
+	defer os.RemoveAll(tmpDir)
+	bundlePath := filepath.Join(tmpDir, "repo.bundle")
+	if err := s.downloadBackup(ctx, key, bundlePath); err != nil {
+		return false, err
+	}
+
+	for _, args := range [][]string{
+		{"clone", "--mirror", bundlePath, string(tmp)},
+		{"-C", string(tmp), "remote", "set-url", "origin", "--", url},
+	} {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			// 🚨 SECURITY: The output could include the clone url, which may
+			// contain a sensitive token.
+			return false, errors.Wrapf(err, "failed to restore backup. Output: %s", newURLRedactor(url).redact(string(out)))
+		}
+	}
+
+	if err := setBackupTime(tmp, backup.LastModified); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *Server) downloadBackup(ctx context.Context, key, dst string) error {
+	rc, err := s.BackupStore.Get(ctx, key)
+	if err != nil {
+		return errors.Wrap(err, "failed to get backup")
+	}
+	defer rc.Close()
+
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, rc); err != nil {
+		f.Close()
+		return errors.Wrap(err, "failed to download backup")
+	}
+	return f.Close()
+}
+
+// setBackupTime sets the time the repository was last backed up.
+func setBackupTime(dir GitDir, t time.Time) error {
+	err := gitConfigSet(dir, "sourcegraph.backupTimestamp", strconv.FormatInt(t.Unix(), 10))
+	if err != nil {
+		return errors.Wrap(err, "failed to update backupTimestamp")
+	}
+	return nil
+}
+
+// getBackupTime returns the time the repository was last backed up, or the
+// zero time if it never was.
+func getBackupTime(dir GitDir) (time.Time, error) {
+	value, err := gitConfigGet(dir, "sourcegraph.backupTimestamp")
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to determine backup timestamp")
+	}
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	sec, err := strconv.ParseInt(strings.TrimSpace(value), 10, 0)
+	if err != nil {
+		// Treat a bad value like a missing one, so that the repo is backed
+		// up and the value is overwritten.
+		return time.Time{}, nil
+	}
+	return time.Unix(sec, 0), nil
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/mutablelimiter"
+	"github.com/sourcegraph/sourcegraph/internal/objectstorage"
+)
+
+func TestBackupAndRestore(t *testing.T) {
+	remote := tmpDir(t)
+	defer os.RemoveAll(remote)
+	runCmd(t, remote, "git", "init", ".")
+	runCmd(t, remote, "git", "commit", "--allow-empty", "-m", "a")
+
+	storeDir := tmpDir(t)
+	defer os.RemoveAll(storeDir)
+	store, err := objectstorage.NewLocalStore(storeDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reposDir := tmpDir(t)
+	defer os.RemoveAll(reposDir)
+	s := &Server{
+		ReposDir:         reposDir,
+		BackupStore:      store,
+		BackupInterval:   time.Hour,
+		ctx:              context.Background(),
+		locker:           &RepositoryLocker{},
+		cloneLimiter:     mutablelimiter.New(1),
+		cloneableLimiter: mutablelimiter.New(1),
+	}
+
+	const repo = api.RepoName("example.com/foo/bar")
+	if _, err := s.cloneRepo(context.Background(), repo, remote, &cloneOptions{Block: true}); err != nil {
+		t.Fatal(err)
+	}
+	dir := s.dir(repo)
+	if last, err := getBackupTime(dir); err != nil || !last.IsZero() {
+		t.Fatalf("expected a clone without backups not to have a backup time, got %v (%v)", last, err)
+	}
+
+	s.Backup()
+
+	objects, err := store.List(context.Background(), "repos/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objects) != 1 || objects[0].Key != "repos/example.com/foo/bar.bundle" {
+		t.Fatalf("unexpected backups %+v", objects)
+	}
+	info, err := s.repoInfo(context.Background(), repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.LastBackup == nil || time.Since(*info.LastBackup) > time.Minute {
+		t.Fatalf("unexpected last backup time %v", info.LastBackup)
+	}
+
+	// The reclone restores the backup and fetches the new commit.
+	runCmd(t, remote, "git", "commit", "--allow-empty", "-m", "b")
+	wantCommit := runCmd(t, remote, "git", "rev-parse", "HEAD")
+	if _, err := s.cloneRepo(context.Background(), repo, remote, &cloneOptions{Block: true, Overwrite: true}); err != nil {
+		t.Fatal(err)
+	}
+	if gotCommit := runCmd(t, string(dir), "git", "rev-parse", "HEAD"); gotCommit != wantCommit {
+		t.Fatalf("got HEAD %s, want %s", gotCommit, wantCommit)
+	}
+	if last, err := getBackupTime(dir); err != nil || !last.Equal(objects[0].LastModified.Truncate(time.Second)) {
+		t.Fatalf("expected the restored clone to have the time of the backup, got %v (%v)", last, err)
+	}
+	if url, err := repoRemoteURL(context.Background(), dir); err != nil || url != remote {
+		t.Fatalf("got remote URL %q (%v), want %q", url, err, remote)
+	}
+}
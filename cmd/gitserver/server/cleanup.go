@@ -56,6 +56,8 @@ var (
 // 2. Remove stale lock files.
 // 3. Remove inactive repos on sourcegraph.com
 // 4. Reclone repos after a while. (simulate git gc)
+// 5. Reclone repos whose configured clone strategy changed.
+// 6. Remove repos that were moved to another gitserver a while ago.
 func (s *Server) cleanupRepos() {
 	bCtx, bCancel := s.serverContext()
 	defer bCancel()
@@ -76,6 +78,20 @@ func (s *Server) cleanupRepos() {
 		return true, nil
 	}
 
+	maybeRemoveMoved := func(dir GitDir) (done bool, err error) {
+		movedTime, err := getMovedTime(dir)
+		if err != nil || movedTime.IsZero() || time.Since(movedTime) < movedRepoTTL {
+			return false, err
+		}
+
+		log15.Info("removing repo moved to another gitserver", "repo", dir, "moved", movedTime)
+		if err := s.removeRepoDirectory(dir); err != nil {
+			return true, err
+		}
+		reposRemoved.Inc()
+		return true, nil
+	}
+
 	ensureGitAttributes := func(dir GitDir) (done bool, err error) {
 		return false, setGitAttributes(dir)
 	}
@@ -102,6 +118,11 @@ func (s *Server) cleanupRepos() {
 				reason = fmt.Sprintf("git gc %s", string(bytes.TrimSpace(gclog)))
 			}
 		}
+		if !useRefspecOverrides() {
+			if strategy, err := getCloneStrategy(dir); err == nil && strategy != configuredCloneStrategy(s.name(dir)) {
+				reason = fmt.Sprintf("clone strategy changed from %s to %s", strategy, configuredCloneStrategy(s.name(dir)))
+			}
+		}
 		if reason == "" {
 			return false, nil
 		}
@@ -173,6 +194,9 @@ func (s *Server) cleanupRepos() {
 	cleanups := []cleanupFn{
 		// Do some sanity checks on the repository.
 		{"maybe remove corrupt", maybeRemoveCorrupt},
+		// Repos moved to another gitserver are removed once all services
+		// send their requests to the other gitserver.
+		{"maybe remove moved", maybeRemoveMoved},
 		// If git is interrupted it can leave lock files lying around. It does
 		// not clean these up, and instead fails commands.
 		{"remove stale locks", removeStaleLocks},
@@ -417,15 +441,27 @@ func (s *Server) removeRepoDirectory(gitDir GitDir) error {
 
 	// Everything after this point is just cleanup, so any error that occurs
 	// should not be returned, just logged.
+	s.removeEmptyParents(dir)
+
+	// Delete the atomically renamed dir. We do this last since if it fails we
+	// will rely on a janitor job to clean up for us.
+	if err := os.RemoveAll(filepath.Join(tmp, "repo")); err != nil {
+		log15.Warn("failed to cleanup after removing dir", "dir", dir, "error", err)
+	}
+
+	return nil
+}
 
-	// Cleanup empty parent directories. We just attempt to remove and if we
-	// have a failure we assume it's due to the directory having other
-	// children. If we checked first we could race with someone else adding a
-	// new clone.
+// removeEmptyParents removes the empty parent directories of a repository
+// directory that was moved away, up to ReposDir. Errors are only logged.
+func (s *Server) removeEmptyParents(dir string) {
+	// We just attempt to remove and if we have a failure we assume it's due
+	// to the directory having other children. If we checked first we could
+	// race with someone else adding a new clone.
 	rootInfo, err := os.Stat(s.ReposDir)
 	if err != nil {
 		log15.Warn("Failed to stat ReposDir", "error", err)
-		return nil
+		return
 	}
 	current := dir
 	for {
@@ -443,7 +479,7 @@ func (s *Server) removeRepoDirectory(gitDir GitDir) error {
 		}
 		if err != nil {
 			log15.Warn("failed to stat parent directory", "dir", current, "error", err)
-			return nil
+			return
 		}
 		if os.SameFile(rootInfo, info) {
 			// Stop, we are at the parent.
@@ -455,14 +491,6 @@ func (s *Server) removeRepoDirectory(gitDir GitDir) error {
 			break
 		}
 	}
-
-	// Delete the atomically renamed dir. We do this last since if it fails we
-	// will rely on a janitor job to clean up for us.
-	if err := os.RemoveAll(filepath.Join(tmp, "repo")); err != nil {
-		log15.Warn("failed to cleanup after removing dir", "dir", dir, "error", err)
-	}
-
-	return nil
 }
 
 // cleanTmpFiles tries to remove tmp_pack_* files from .git/objects/pack.
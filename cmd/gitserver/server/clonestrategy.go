@@ -0,0 +1,290 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// cloneStrategy is how a repository is cloned and fetched. It is configured
+// with the gitCloneStrategies site configuration. Since a clone can't change
+// its strategy without being recloned, the strategy a repository was cloned
+// with is stored in its git config and used by fetches.
+type cloneStrategy struct {
+	// Blobless clones are partial clones (--filter=blob:none) that only
+	// contain commits and trees. Missing blobs are fetched on demand.
+	Blobless bool
+
+	// DefaultBranchAndTagsOnly clones only contain the default branch and
+	// tags.
+	DefaultBranchAndTagsOnly bool
+}
+
+// String returns the name of the strategy reported in RepoInfo and stored in
+// the git config of clones.
+func (c cloneStrategy) String() string {
+	var names []string
+	if c.Blobless {
+		names = append(names, "blobless")
+	}
+	if c.DefaultBranchAndTagsOnly {
+		names = append(names, "default-branch-and-tags")
+	}
+	if len(names) == 0 {
+		return "full"
+	}
+	return strings.Join(names, ",")
+}
+
+// parseCloneStrategy parses the name of a strategy returned by String.
+func parseCloneStrategy(s string) cloneStrategy {
+	var c cloneStrategy
+	for _, name := range strings.Split(strings.TrimSpace(s), ",") {
+		switch name {
+		case "blobless":
+			c.Blobless = true
+		case "default-branch-and-tags":
+			c.DefaultBranchAndTagsOnly = true
+		}
+	}
+	return c
+}
+
+type cloneStrategyMapping struct {
+	pattern  *regexp.Regexp
+	strategy cloneStrategy
+}
+
+var cloneStrategies = conf.Cached(func() interface{} {
+	return buildCloneStrategyMappings(conf.Get().GitCloneStrategies)
+})
+
+func buildCloneStrategyMappings(c []*schema.GitCloneStrategy) []cloneStrategyMapping {
+	mappings := make([]cloneStrategyMapping, 0, len(c))
+	for _, s := range c {
+		pattern, err := regexp.Compile(s.RepositoryPattern)
+		if err != nil {
+			log15.Error("ignoring gitCloneStrategies entry with invalid repositoryPattern", "pattern", s.RepositoryPattern, "err", err)
+			continue
+		}
+		mappings = append(mappings, cloneStrategyMapping{
+			pattern: pattern,
+			strategy: cloneStrategy{
+				Blobless:                 s.Blobless,
+				DefaultBranchAndTagsOnly: s.DefaultBranchAndTagsOnly,
+			},
+		})
+	}
+	return mappings
+}
+
+// configuredCloneStrategy returns the strategy that the repository should be
+// cloned with.
+func configuredCloneStrategy(repo api.RepoName) cloneStrategy {
+	for _, m := range cloneStrategies().([]cloneStrategyMapping) {
+		if m.pattern.MatchString(string(repo)) {
+			return m.strategy
+		}
+	}
+	return cloneStrategy{}
+}
+
+// setCloneStrategy stores the strategy that the repository was cloned with.
+func setCloneStrategy(dir GitDir, c cloneStrategy) error {
+	if err := gitConfigSet(dir, "sourcegraph.cloneStrategy", c.String()); err != nil {
+		return errors.Wrap(err, "failed to set clone strategy")
+	}
+	return nil
+}
+
+// getCloneStrategy returns the strategy that the repository was cloned
+// with. Repositories cloned before strategies were stored are full clones.
+func getCloneStrategy(dir GitDir) (cloneStrategy, error) {
+	value, err := gitConfigGet(dir, "sourcegraph.cloneStrategy")
+	if err != nil {
+		return cloneStrategy{}, errors.Wrap(err, "failed to determine clone strategy")
+	}
+	return parseCloneStrategy(value), nil
+}
+
+// quickIsPartialClone best-effort mimics checking the git config of the
+// repository for a partial clone filter, but doesn't exec a child process.
+func quickIsPartialClone(dir GitDir) bool {
+	config, err := ioutil.ReadFile(dir.Path("config"))
+	if err != nil {
+		return false
+	}
+	return bytes.Contains(bytes.ToLower(config), []byte("partialclonefilter"))
+}
+
+// cloneCmd returns the command that clones the repository into tmpPath with
+// the strategy. It must not be called for full clones.
+func (c cloneStrategy) cloneCmd(ctx context.Context, url, tmpPath string) (*exec.Cmd, error) {
+	if !c.DefaultBranchAndTagsOnly {
+		return exec.CommandContext(ctx, "git", "clone", "--mirror", "--progress", "--filter=blob:none", url, tmpPath), nil
+	}
+
+	// Like refspecOverridesCloneCmd, we init a bare repo with only the
+	// refspecs we care about and then fetch.
+	branch, err := remoteDefaultBranch(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(tmpPath, os.ModePerm); err != nil {
+		return nil, errors.Wrapf(err, "clone failed to create tmp dir")
+	}
+	cmds := [][]string{
+		{"init", "--bare", "."},
+		{"config", "--add", "remote.origin.url", url},
+		{"symbolic-ref", "HEAD", "refs/heads/" + branch},
+	}
+	for _, refspec := range defaultBranchAndTagsRefspecs(branch) {
+		cmds = append(cmds, []string{"config", "--add", "remote.origin.fetch", refspec})
+	}
+	for _, args := range cmds {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = tmpPath
+		if err := cmd.Run(); err != nil {
+			return nil, errors.Wrapf(err, "clone setup failed")
+		}
+	}
+
+	args := []string{"fetch", "--progress"}
+	if c.Blobless {
+		args = append(args, "--filter=blob:none")
+	}
+	cmd := exec.CommandContext(ctx, "git", append(args, "origin")...)
+	cmd.Dir = tmpPath
+	return cmd, nil
+}
+
+// fetchCmd returns the git command that fetches the repository's refs from
+// url into dir with the strategy.
+func (c cloneStrategy) fetchCmd(ctx context.Context, url string, dir GitDir) (*exec.Cmd, error) {
+	args := []string{"fetch", "--prune"}
+	remote := url
+	if c.Blobless {
+		// Fetching with a filter registers the remote as a promisor remote in
+		// the git config. Fetch from origin so that no section named after
+		// the URL, which may contain credentials, is added to the config.
+		cmd := exec.CommandContext(ctx, "git", "remote", "set-url", "origin", "--", url)
+		cmd.Dir = string(dir)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			// 🚨 SECURITY: The output could include the clone url, which may
+			// contain a sensitive token.
+			return nil, errors.Wrapf(err, "failed to set remote URL. Output: %s", newURLRedactor(url).redact(string(out)))
+		}
+		args = append(args, "--filter=blob:none")
+		remote = "origin"
+	}
+	args = append(args, remote)
+
+	var refspecs []string
+	if !c.DefaultBranchAndTagsOnly {
+		refspecs = fetchRefspecs
+	} else {
+		branch, err := remoteDefaultBranch(ctx, url)
+		if err != nil {
+			return nil, err
+		}
+		// --prune only prunes the refs matching the refspecs, so a previous
+		// default branch has to be removed separately.
+		if err := removeOtherBranches(ctx, dir, branch); err != nil {
+			return nil, err
+		}
+		refspecs = defaultBranchAndTagsRefspecs(branch)
+	}
+
+	cmd := exec.CommandContext(ctx, "git", append(args, refspecs...)...)
+	cmd.Dir = string(dir)
+	return cmd, nil
+}
+
+// removeOtherBranches deletes all branches except for branch from the
+// repository.
+func removeOtherBranches(ctx context.Context, dir GitDir, branch string) error {
+	cmd := exec.CommandContext(ctx, "git", "for-each-ref", "--format=%(refname)", "refs/heads/")
+	cmd.Dir = string(dir)
+	out, err := cmd.Output()
+	if err != nil {
+		return wrapCmdError(cmd, err)
+	}
+
+	for _, ref := range strings.Fields(string(out)) {
+		if ref == "refs/heads/"+branch {
+			continue
+		}
+		cmd := exec.CommandContext(ctx, "git", "update-ref", "-d", ref)
+		cmd.Dir = string(dir)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return errors.Wrapf(err, "failed to remove branch %s. Output: %s", ref, string(out))
+		}
+	}
+	return nil
+}
+
+func defaultBranchAndTagsRefspecs(branch string) []string {
+	return []string{"+refs/heads/" + branch + ":refs/heads/" + branch, "+refs/tags/*:refs/tags/*"}
+}
+
+// remoteDefaultBranch returns the name of the branch that HEAD of the remote
+// repository points to.
+func remoteDefaultBranch(ctx context.Context, url string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "ls-remote", "--symref", url, "HEAD")
+	out, err := runWithRemoteOpts(ctx, cmd, nil)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to determine default branch. Output: %s", string(out))
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		// ref: refs/heads/master	HEAD
+		if fields := strings.Fields(line); len(fields) == 3 && fields[0] == "ref:" && fields[2] == "HEAD" {
+			return strings.TrimPrefix(fields[1], "refs/heads/"), nil
+		}
+	}
+	return "", errors.New("failed to determine default branch: remote HEAD is not a symbolic ref")
+}
+
+// fetchMissingBlobs fetches the blobs of treeish under paths that are missing
+// from a partial clone in a single request. Otherwise git fetches them one at
+// a time when they are first read, which is very slow for commands such as
+// git archive that read many blobs.
+func fetchMissingBlobs(ctx context.Context, dir GitDir, treeish string, paths []string) error {
+	args := append([]string{"rev-list", "--objects", "--no-walk", "--missing=print", treeish, "--"}, paths...)
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = string(dir)
+	out, err := cmd.Output()
+	if err != nil {
+		return wrapCmdError(cmd, err)
+	}
+
+	var missing bytes.Buffer
+	for _, line := range bytes.Split(out, []byte("\n")) {
+		if bytes.HasPrefix(line, []byte("?")) {
+			missing.Write(line[1:])
+			missing.WriteByte('\n')
+		}
+	}
+	if missing.Len() == 0 {
+		return nil
+	}
+
+	// This is the command git itself runs to fetch missing objects.
+	cmd = exec.CommandContext(ctx, "git", "-c", "fetch.negotiationAlgorithm=noop", "fetch", "origin",
+		"--no-tags", "--no-write-fetch-head", "--recurse-submodules=no", "--filter=blob:none", "--stdin")
+	cmd.Dir = string(dir)
+	cmd.Stdin = &missing
+	if out, err := runWithRemoteOpts(ctx, cmd, nil); err != nil {
+		return errors.Wrapf(err, "failed to fetch missing blobs. Output: %s", string(out))
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/mutablelimiter"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestCloneStrategy_String(t *testing.T) {
+	for _, c := range []cloneStrategy{
+		{},
+		{Blobless: true},
+		{DefaultBranchAndTagsOnly: true},
+		{Blobless: true, DefaultBranchAndTagsOnly: true},
+	} {
+		if have := parseCloneStrategy(c.String()); have != c {
+			t.Errorf("parseCloneStrategy(%q) = %+v, want %+v", c.String(), have, c)
+		}
+	}
+	if have, want := parseCloneStrategy(""), (cloneStrategy{}); have != want {
+		t.Errorf("parseCloneStrategy(\"\") = %+v, want %+v", have, want)
+	}
+}
+
+func TestCloneRepo_Strategy(t *testing.T) {
+	remote := tmpDir(t)
+	cmd := func(dir string, name string, arg ...string) string {
+		t.Helper()
+		return strings.TrimSpace(runCmd(t, dir, name, arg...))
+	}
+
+	cmd(remote, "git", "init", ".")
+	cmd(remote, "git", "checkout", "-b", "main")
+	cmd(remote, "git", "config", "uploadpack.allowFilter", "true")
+	cmd(remote, "git", "config", "uploadpack.allowAnySHA1InWant", "true")
+	cmd(remote, "sh", "-c", "mkdir dir && echo hello > dir/hello.txt && echo world > world.txt")
+	cmd(remote, "git", "add", ".")
+	cmd(remote, "git", "commit", "-m", "hello")
+	cmd(remote, "git", "tag", "v1")
+	cmd(remote, "git", "branch", "feature")
+
+	orig := cloneStrategies
+	cloneStrategies = func() interface{} {
+		return buildCloneStrategyMappings([]*schema.GitCloneStrategy{
+			{RepositoryPattern: "^example\\.com/big$", Blobless: true, DefaultBranchAndTagsOnly: true},
+		})
+	}
+	defer func() { cloneStrategies = orig }()
+
+	s := &Server{
+		ReposDir:         tmpDir(t),
+		ctx:              context.Background(),
+		locker:           &RepositoryLocker{},
+		cloneLimiter:     mutablelimiter.New(1),
+		cloneableLimiter: mutablelimiter.New(1),
+	}
+	repo := api.RepoName("example.com/big")
+	url := "file://" + remote
+	if _, err := s.cloneRepo(context.Background(), repo, url, &cloneOptions{Block: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := s.dir(repo)
+	if have, want := cmd(string(dir), "git", "for-each-ref", "--format=%(refname)"), "refs/heads/main\nrefs/tags/v1"; have != want {
+		t.Fatalf("unexpected refs:\nhave %q\nwant %q", have, want)
+	}
+	if head := cmd(string(dir), "git", "symbolic-ref", "HEAD"); head != "refs/heads/main" {
+		t.Fatalf("unexpected HEAD %q", head)
+	}
+	if !quickIsPartialClone(dir) {
+		t.Fatal("expected a partial clone")
+	}
+	missing := func() string {
+		t.Helper()
+		return cmd(string(dir), "git", "rev-list", "--objects", "--no-walk", "--missing=print", "main")
+	}
+	if have := strings.Count(missing(), "?"); have != 2 {
+		t.Fatalf("expected 2 missing blobs, got %d", have)
+	}
+
+	info, err := s.repoInfo(context.Background(), repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := info.CloneStrategy, "blobless,default-branch-and-tags"; have != want {
+		t.Fatalf("have clone strategy %q, want %q", have, want)
+	}
+
+	// Missing blobs are fetched in one go.
+	if err := fetchMissingBlobs(context.Background(), dir, "main", []string{"dir"}); err != nil {
+		t.Fatal(err)
+	}
+	if have := strings.Count(missing(), "?"); have != 1 {
+		t.Fatalf("expected 1 missing blob, got %d", have)
+	}
+
+	// Updates keep fetching only the default branch and tags without blobs.
+	cmd(remote, "sh", "-c", "echo again > world.txt")
+	cmd(remote, "git", "commit", "-am", "again")
+	cmd(remote, "git", "branch", "feature2")
+	if err := s.doRepoUpdate2(repo, url); err != nil {
+		t.Fatal(err)
+	}
+	if have, want := cmd(string(dir), "git", "rev-parse", "main"), cmd(remote, "git", "rev-parse", "main"); have != want {
+		t.Fatalf("expected main to be updated to %s, got %s", want, have)
+	}
+	if have, want := cmd(string(dir), "git", "for-each-ref", "--format=%(refname)"), "refs/heads/main\nrefs/tags/v1"; have != want {
+		t.Fatalf("unexpected refs after update:\nhave %q\nwant %q", have, want)
+	}
+	if have := strings.Count(missing(), "?"); have != 1 {
+		t.Fatalf("expected 1 missing blob after update, got %d", have)
+	}
+
+	// The URL is only stored as the URL of origin, not as a remote of its own.
+	for _, key := range strings.Fields(cmd(string(dir), "git", "config", "--name-only", "--get-regexp", `^remote\.`)) {
+		if !strings.HasPrefix(key, "remote.origin.") {
+			t.Fatalf("unexpected remote config %q", key)
+		}
+	}
+
+	// A change of the default branch replaces the previous one.
+	cmd(remote, "git", "checkout", "-b", "trunk")
+	if err := s.doRepoUpdate2(repo, url); err != nil {
+		t.Fatal(err)
+	}
+	if have, want := cmd(string(dir), "git", "for-each-ref", "--format=%(refname)"), "refs/heads/trunk\nrefs/tags/v1"; have != want {
+		t.Fatalf("unexpected refs after default branch change:\nhave %q\nwant %q", have, want)
+	}
+	if head := cmd(string(dir), "git", "symbolic-ref", "HEAD"); head != "refs/heads/trunk" {
+		t.Fatalf("unexpected HEAD %q after default branch change", head)
+	}
+}
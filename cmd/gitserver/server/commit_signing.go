@@ -0,0 +1,126 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// commitSigner signs the commits created by createCommitFromPatch with the
+// key of the campaigns.commitSigning site configuration.
+type commitSigner struct {
+	Format         string
+	SigningKey     string
+	CommitterName  string
+	CommitterEmail string
+}
+
+// getCommitSigner returns the commitSigner of the campaigns.commitSigning
+// site configuration, or nil if commits aren't signed.
+func getCommitSigner() *commitSigner {
+	return newCommitSigner(conf.Get().CampaignsCommitSigning)
+}
+
+func newCommitSigner(c *schema.CampaignsCommitSigning) *commitSigner {
+	if c == nil || c.SigningKey == "" {
+		return nil
+	}
+	format := c.Format
+	if format == "" {
+		format = "gpg"
+	}
+	return &commitSigner{
+		Format:         format,
+		SigningKey:     c.SigningKey,
+		CommitterName:  c.CommitterName,
+		CommitterEmail: c.CommitterEmail,
+	}
+}
+
+// setup makes the signing key available to git in dir, which must be a
+// private temporary directory that is removed once the commit is created. It
+// returns the arguments to pass to git before the commit subcommand, and the
+// environment variables to add to it.
+func (s *commitSigner) setup(ctx context.Context, dir string) (args, env []string, err error) {
+	switch s.Format {
+	case "gpg":
+		home := filepath.Join(dir, "gnupg")
+		if err := os.Mkdir(home, 0700); err != nil {
+			return nil, nil, err
+		}
+		env = []string{"GNUPGHOME=" + home}
+
+		cmd := exec.CommandContext(ctx, "gpg", "--batch", "--import")
+		cmd.Env = append(os.Environ(), env...)
+		cmd.Stdin = strings.NewReader(s.SigningKey)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return nil, nil, errors.Wrapf(err, "importing signing key failed. Output: %s", out)
+		}
+		fingerprint, err := gpgSecretKeyFingerprint(ctx, env)
+		if err != nil {
+			return nil, nil, err
+		}
+		args = []string{"-c", "gpg.format=openpgp", "-c", "user.signingkey=" + fingerprint}
+
+	case "ssh":
+		path := filepath.Join(dir, "signing_key")
+		key := s.SigningKey
+		if !strings.HasSuffix(key, "\n") {
+			// ssh-keygen rejects keys without a trailing newline.
+			key += "\n"
+		}
+		if err := ioutil.WriteFile(path, []byte(key), 0600); err != nil {
+			return nil, nil, err
+		}
+		args = []string{"-c", "gpg.format=ssh", "-c", "user.signingkey=" + path}
+
+	default:
+		return nil, nil, errors.Errorf("unknown commit signing format %q", s.Format)
+	}
+	return append(args, "-c", "commit.gpgsign=true"), env, nil
+}
+
+// cleanup stops the gpg-agent started for the GNUPGHOME in env, if any.
+func (s *commitSigner) cleanup(env []string) {
+	if s.Format != "gpg" {
+		return
+	}
+	cmd := exec.Command("gpgconf", "--kill", "gpg-agent")
+	cmd.Env = append(os.Environ(), env...)
+	_ = cmd.Run()
+}
+
+// gpgSecretKeyFingerprint returns the fingerprint of the first secret key in
+// the GNUPGHOME in env.
+func gpgSecretKeyFingerprint(ctx context.Context, env []string) (string, error) {
+	cmd := exec.CommandContext(ctx, "gpg", "--batch", "--with-colons", "--list-secret-keys")
+	cmd.Env = append(os.Environ(), env...)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", errors.Wrap(err, "listing signing keys failed")
+	}
+
+	// The first fpr record after the sec record is the fingerprint of the
+	// primary key, the others are those of its subkeys.
+	var inSec bool
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		fields := strings.Split(sc.Text(), ":")
+		switch {
+		case fields[0] == "sec":
+			inSec = true
+		case fields[0] == "fpr" && inSec && len(fields) > 9:
+			return fields[9], nil
+		}
+	}
+	return "", errors.New("the signing key contains no secret key")
+}
@@ -0,0 +1,159 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/mutablelimiter"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestCreateCommitFromPatch_Signing(t *testing.T) {
+	remote := tmpDir(t)
+	defer os.RemoveAll(remote)
+	runCmd(t, remote, "git", "init", ".")
+	runCmd(t, remote, "git", "commit", "--allow-empty", "-m", "a")
+	base := strings.TrimSpace(runCmd(t, remote, "git", "rev-parse", "HEAD"))
+
+	s := &Server{
+		ReposDir:         tmpDir(t),
+		ctx:              context.Background(),
+		locker:           &RepositoryLocker{},
+		cloneLimiter:     mutablelimiter.New(1),
+		cloneableLimiter: mutablelimiter.New(1),
+	}
+	defer os.RemoveAll(s.ReposDir)
+	const repo = api.RepoName("example.com/foo/bar")
+	if _, err := s.cloneRepo(context.Background(), repo, remote, &cloneOptions{Block: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	keys := tmpDir(t)
+	defer os.RemoveAll(keys)
+
+	for _, tc := range []struct {
+		format string
+		// key generates a signing key in dir and returns it, with the git
+		// config of verify-commit that trusts it.
+		key func(t *testing.T, dir string) (key string, verifyArgs []string, env []string)
+	}{
+		{
+			format: "ssh",
+			key: func(t *testing.T, dir string) (string, []string, []string) {
+				if _, err := exec.LookPath("ssh-keygen"); err != nil {
+					t.Skip("ssh-keygen not found")
+				}
+				path := filepath.Join(dir, "id_ed25519")
+				runCmd(t, dir, "ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-C", "", "-f", path)
+				pub, err := ioutil.ReadFile(path + ".pub")
+				if err != nil {
+					t.Fatal(err)
+				}
+				signers := filepath.Join(dir, "allowed_signers")
+				if err := ioutil.WriteFile(signers, []byte("bot@example.com "+string(pub)), 0600); err != nil {
+					t.Fatal(err)
+				}
+				key, err := ioutil.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return string(key), []string{"-c", "gpg.ssh.allowedSignersFile=" + signers}, nil
+			},
+		},
+		{
+			format: "gpg",
+			key: func(t *testing.T, dir string) (string, []string, []string) {
+				if _, err := exec.LookPath("gpg"); err != nil {
+					t.Skip("gpg not found")
+				}
+				home := filepath.Join(dir, "gnupg")
+				if err := os.Mkdir(home, 0700); err != nil {
+					t.Fatal(err)
+				}
+				env := []string{"GNUPGHOME=" + home}
+				gpg := func(args ...string) string {
+					cmd := exec.Command("gpg", append([]string{"--batch", "--passphrase", ""}, args...)...)
+					cmd.Env = append(os.Environ(), env...)
+					out, err := cmd.Output()
+					if err != nil {
+						t.Fatalf("gpg %s: %v", strings.Join(args, " "), err)
+					}
+					return string(out)
+				}
+				gpg("--quick-gen-key", "Bot <bot@example.com>", "default", "default", "never")
+				key := gpg("--armor", "--export-secret-keys")
+				t.Cleanup(func() { (&commitSigner{Format: "gpg"}).cleanup(env) })
+				return key, nil, env
+			},
+		},
+	} {
+		t.Run(tc.format, func(t *testing.T) {
+			key, verifyArgs, verifyEnv := tc.key(t, keys)
+			conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+				CampaignsCommitSigning: &schema.CampaignsCommitSigning{
+					Format:         tc.format,
+					SigningKey:     key,
+					CommitterName:  "Bot",
+					CommitterEmail: "bot@example.com",
+				},
+			}})
+			defer conf.Mock(nil)
+
+			status, resp := s.createCommitFromPatch(context.Background(), protocol.CreateCommitFromPatchRequest{
+				Repo:       repo,
+				BaseCommit: api.CommitID(base),
+				Patch:      "diff --git a/README b/README\nnew file mode 100644\n--- /dev/null\n+++ b/README\n@@ -0,0 +1 @@\n+hello\n",
+				TargetRef:  "refs/heads/signed-" + tc.format,
+				CommitInfo: protocol.PatchCommitInfo{
+					Message:     "add README",
+					AuthorName:  "Alice",
+					AuthorEmail: "alice@example.com",
+				},
+			})
+			if resp.Error != nil {
+				t.Fatalf("got status %d: %s: %s", status, resp.Error.InternalError, resp.Error.CombinedOutput)
+			}
+
+			dir := string(s.dir(repo))
+			got := strings.TrimSpace(runCmd(t, dir, "git", "log", "-1", "--format=%an <%ae>, %cn <%ce>", resp.Rev))
+			if want := "Alice <alice@example.com>, Bot <bot@example.com>"; got != want {
+				t.Errorf("got author and committer %q, want %q", got, want)
+			}
+
+			cmd := exec.Command("git", append(verifyArgs, "verify-commit", resp.Rev)...)
+			cmd.Dir = dir
+			cmd.Env = append(os.Environ(), verifyEnv...)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Errorf("verifying signature failed: %v\n%s", err, out)
+			}
+		})
+	}
+
+	// Without a signing key, commits are unsigned and committed by the
+	// author.
+	status, resp := s.createCommitFromPatch(context.Background(), protocol.CreateCommitFromPatchRequest{
+		Repo:       repo,
+		BaseCommit: api.CommitID(base),
+		Patch:      "diff --git a/README b/README\nnew file mode 100644\n--- /dev/null\n+++ b/README\n@@ -0,0 +1 @@\n+hello\n",
+		TargetRef:  "refs/heads/unsigned",
+		CommitInfo: protocol.PatchCommitInfo{AuthorName: "Alice", AuthorEmail: "alice@example.com"},
+	})
+	if resp.Error != nil {
+		t.Fatalf("got status %d: %s", status, resp.Error.InternalError)
+	}
+	dir := string(s.dir(repo))
+	if got := runCmd(t, dir, "git", "cat-file", "commit", resp.Rev); strings.Contains(got, "gpgsig") {
+		t.Errorf("expected unsigned commit, got\n%s", got)
+	}
+	if got, want := strings.TrimSpace(runCmd(t, dir, "git", "log", "-1", "--format=%cn <%ce>", resp.Rev)), "Alice <alice@example.com>"; got != want {
+		t.Errorf("got committer %q, want %q", got, want)
+	}
+}
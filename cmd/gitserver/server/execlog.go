@@ -0,0 +1,184 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+// execLog is a ring buffer of the most recent exec requests, which is served
+// at /debug/exec-log to find out which clients run expensive git commands on
+// which repos. A nil execLog discards entries.
+type execLog struct {
+	mu      sync.Mutex
+	entries []protocol.ExecLogEntry
+	next    int  // index of the next entry to overwrite
+	full    bool // whether entries wrapped around
+}
+
+func newExecLog(size int) *execLog {
+	if size <= 0 {
+		return nil
+	}
+	return &execLog{entries: make([]protocol.ExecLogEntry, size)}
+}
+
+func (l *execLog) add(e protocol.ExecLogEntry) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries[l.next] = e
+	l.next++
+	if l.next == len(l.entries) {
+		l.next = 0
+		l.full = true
+	}
+}
+
+// recent returns the entries matching repo (all if empty), most recent
+// first.
+func (l *execLog) recent(repo api.RepoName) []protocol.ExecLogEntry {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	n := l.next
+	if l.full {
+		n = len(l.entries)
+	}
+	var entries []protocol.ExecLogEntry
+	for i := 1; i <= n; i++ {
+		e := l.entries[(l.next-i+len(l.entries))%len(l.entries)]
+		if repo == "" || e.Repo == repo {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// execLogStats aggregates the entries by repo and command, with the largest
+// total duration first.
+func execLogStats(entries []protocol.ExecLogEntry) []protocol.ExecLogStats {
+	type key struct {
+		repo    api.RepoName
+		command string
+	}
+	byKey := map[key]*protocol.ExecLogStats{}
+	clients := map[key]map[string]bool{}
+	for _, e := range entries {
+		k := key{repo: e.Repo, command: execCommandShape(e.Args)}
+		st, ok := byKey[k]
+		if !ok {
+			st = &protocol.ExecLogStats{Repo: k.repo, Command: k.command}
+			byKey[k] = st
+			clients[k] = map[string]bool{}
+		}
+		st.Count++
+		if e.Slow {
+			st.SlowCount++
+		}
+		if e.Status != "0" {
+			st.FailureCount++
+		}
+		st.TotalDuration += e.Duration
+		if e.Duration > st.MaxDuration || st.Count == 1 {
+			st.MaxDuration = e.Duration
+			st.SlowestTrace = e.Trace
+		}
+		st.StdoutBytes += e.StdoutBytes
+		if e.Client != "" && !clients[k][e.Client] {
+			clients[k][e.Client] = true
+			st.Clients = append(st.Clients, e.Client)
+		}
+	}
+
+	stats := make([]protocol.ExecLogStats, 0, len(byKey))
+	for _, st := range byKey {
+		sort.Strings(st.Clients)
+		stats = append(stats, *st)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].TotalDuration != stats[j].TotalDuration {
+			return stats[i].TotalDuration > stats[j].TotalDuration
+		}
+		if stats[i].Repo != stats[j].Repo {
+			return stats[i].Repo < stats[j].Repo
+		}
+		return stats[i].Command < stats[j].Command
+	})
+	return stats
+}
+
+// execCommandShape returns the git subcommand of args and the names of the
+// flags it is run with, without their values, so that commands that only
+// differ by revisions, paths or patterns are aggregated. For example,
+// "log -Sfoo --format=%H HEAD" becomes "log -S --format".
+func execCommandShape(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	shape := []string{args[0]}
+	for _, arg := range args[1:] {
+		switch {
+		case arg == "--":
+			return strings.Join(shape, " ")
+		case strings.HasPrefix(arg, "--"):
+			if i := strings.Index(arg, "="); i >= 0 {
+				arg = arg[:i]
+			}
+		case strings.HasPrefix(arg, "-") && len(arg) > 2:
+			// Short flags can be followed by their value (-Sfoo, -n10).
+			arg = arg[:2]
+		case !strings.HasPrefix(arg, "-"):
+			continue
+		}
+		shape = append(shape, arg)
+	}
+	return strings.Join(shape, " ")
+}
+
+// handleExecLog serves the recent exec requests and their aggregates as
+// JSON. The repo query parameter only includes the requests of a repo, and
+// limit is the maximum number of entries and aggregates (default 100).
+func (s *Server) handleExecLog(w http.ResponseWriter, r *http.Request) {
+	limit := 100
+	if v := r.URL.Query().Get("limit"); v != "" {
+		var err error
+		if limit, err = strconv.Atoi(v); err != nil || limit < 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+
+	repo := api.RepoName(r.URL.Query().Get("repo"))
+	if repo != "" {
+		repo = protocol.NormalizeRepo(repo)
+	}
+	entries := s.execLog.recent(repo)
+	resp := protocol.ExecLogResponse{
+		Entries: entries,
+		Stats:   execLogStats(entries),
+	}
+	if len(resp.Entries) > limit {
+		resp.Entries = resp.Entries[:limit]
+	}
+	if len(resp.Stats) > limit {
+		resp.Stats = resp.Stats[:limit]
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+func TestExecLog(t *testing.T) {
+	l := newExecLog(3)
+	for i, repo := range []api.RepoName{"a", "b", "a", "a"} {
+		l.add(protocol.ExecLogEntry{Repo: repo, Args: []string{"log"}, Duration: time.Duration(i)})
+	}
+
+	durations := func(entries []protocol.ExecLogEntry) (ds []time.Duration) {
+		for _, e := range entries {
+			ds = append(ds, e.Duration)
+		}
+		return ds
+	}
+	// The oldest entry was overwritten.
+	if got, want := durations(l.recent("")), []time.Duration{3, 2, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, want := durations(l.recent("a")), []time.Duration{3, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	var disabled *execLog
+	disabled.add(protocol.ExecLogEntry{Repo: "a"})
+	if entries := disabled.recent(""); len(entries) != 0 {
+		t.Errorf("expected disabled exec log to be empty, got %v", entries)
+	}
+}
+
+func TestExecLogStats(t *testing.T) {
+	entries := []protocol.ExecLogEntry{
+		{Repo: "a", Args: []string{"log", "-Sfoo", "--format=%H", "HEAD"}, Client: "frontend", Duration: 3 * time.Second, Status: "0", Slow: true, Trace: "t1", StdoutBytes: 10},
+		{Repo: "a", Args: []string{"log", "-Sbar", "--format=%H", "master", "--", "-x"}, Client: "searcher", Duration: 5 * time.Second, Status: "128", Slow: true, Trace: "t2", StdoutBytes: 5},
+		{Repo: "a", Args: []string{"rev-parse", "HEAD"}, Client: "frontend", Duration: time.Millisecond, Status: "0"},
+		{Repo: "b", Args: []string{"log", "-Sfoo"}, Client: "frontend", Duration: time.Second, Status: "0"},
+	}
+	want := []protocol.ExecLogStats{
+		{Repo: "a", Command: "log -S --format", Count: 2, SlowCount: 2, FailureCount: 1, TotalDuration: 8 * time.Second, MaxDuration: 5 * time.Second, StdoutBytes: 15, Clients: []string{"frontend", "searcher"}, SlowestTrace: "t2"},
+		{Repo: "b", Command: "log -S", Count: 1, TotalDuration: time.Second, MaxDuration: time.Second, Clients: []string{"frontend"}},
+		{Repo: "a", Command: "rev-parse", Count: 1, TotalDuration: time.Millisecond, MaxDuration: time.Millisecond, Clients: []string{"frontend"}},
+	}
+	if got := execLogStats(entries); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestHandleExecLog(t *testing.T) {
+	s := &Server{execLog: newExecLog(10)}
+	s.execLog.add(protocol.ExecLogEntry{Repo: "github.com/foo/bar", Args: []string{"log"}, Status: "0"})
+	s.execLog.add(protocol.ExecLogEntry{Repo: "github.com/foo/baz", Args: []string{"show"}, Status: "0"})
+
+	rec := httptest.NewRecorder()
+	s.handleExecLog(rec, httptest.NewRequest("GET", "/debug/exec-log?repo=github.com/Foo/Bar&limit=5", nil))
+	var resp protocol.ExecLogResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Entries) != 1 || resp.Entries[0].Repo != "github.com/foo/bar" || len(resp.Stats) != 1 || resp.Stats[0].Command != "log" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+
+	rec = httptest.NewRecorder()
+	s.handleExecLog(rec, httptest.NewRequest("GET", "/debug/exec-log?limit=x", nil))
+	if rec.Code != 400 {
+		t.Errorf("got status %d for invalid limit, want 400", rec.Code)
+	}
+}
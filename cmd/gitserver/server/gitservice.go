@@ -28,6 +28,18 @@ var uploadPackArgs = []string{
 	"--stateless-rpc", "--strict",
 }
 
+// publicUploadPackArgs are the upload-pack arguments for requests from end
+// users. They can only fetch objects reachable from a ref, which excludes
+// objects of branches that were deleted or force-pushed on the code host.
+var publicUploadPackArgs = []string{
+	// Partial clones/fetches
+	"-c", "uploadpack.allowFilter=true",
+
+	"upload-pack",
+
+	"--stateless-rpc", "--strict",
+}
+
 // gitServiceHandler is a smart Git HTTP transfer protocol as documented at
 // https://www.git-scm.com/docs/http-protocol.
 //
@@ -38,6 +50,10 @@ type gitServiceHandler struct {
 	// Dir is a funcion which takes a repository name and returns an absolute
 	// path to the GIT_DIR for it.
 	Dir func(string) string
+
+	// Public is true if the handler serves requests from end users, which
+	// the frontend proxies after checking their repository permissions.
+	Public bool
 }
 
 func (s *gitServiceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -78,6 +94,9 @@ func (s *gitServiceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}()
 
 	args := append([]string{}, uploadPackArgs...)
+	if s.Public {
+		args = append([]string{}, publicUploadPackArgs...)
+	}
 	switch svc {
 	case "/info/refs":
 		w.Header().Set("Content-Type", "application/x-git-upload-pack-advertisement")
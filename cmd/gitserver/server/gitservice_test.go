@@ -5,6 +5,7 @@ import (
 	"net/http/httptest"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -75,3 +76,43 @@ func TestGitServiceHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestGitServiceHandler_public(t *testing.T) {
+	root := tmpDir(t)
+	repo := filepath.Join(root, "testrepo")
+
+	// Setup a repo with a commit that is no longer reachable from a ref.
+	runCmd(t, root, "git", "init", repo)
+	runCmd(t, repo, "sh", "-c", "echo hello world > hello.txt")
+	runCmd(t, repo, "git", "add", "hello.txt")
+	runCmd(t, repo, "git", "commit", "-m", "hello")
+	runCmd(t, repo, "git", "checkout", "-b", "deleted")
+	runCmd(t, repo, "sh", "-c", "echo secret > secret.txt")
+	runCmd(t, repo, "git", "add", "secret.txt")
+	runCmd(t, repo, "git", "commit", "-m", "secret")
+	unreachable := strings.TrimSpace(runCmd(t, repo, "git", "rev-parse", "HEAD"))
+	runCmd(t, repo, "git", "checkout", "-")
+	runCmd(t, repo, "git", "branch", "-D", "deleted")
+
+	for _, public := range []bool{false, true} {
+		ts := httptest.NewServer(&gitServiceHandler{
+			Dir: func(s string) string {
+				return filepath.Join(root, s, ".git")
+			},
+			Public: public,
+		})
+		defer ts.Close()
+
+		dir := tmpDir(t)
+		runCmd(t, dir, "git", "init")
+		c := exec.Command("git", "-c", "protocol.version=1", "fetch", ts.URL+"/testrepo", unreachable)
+		c.Dir = dir
+		b, err := c.CombinedOutput()
+		if public && err == nil {
+			t.Fatalf("expected public fetch of unreachable commit to fail. Output:\n%s", b)
+		}
+		if !public && err != nil {
+			t.Fatalf("expected internal fetch of unreachable commit to succeed: %s\nOutput: %s", err, b)
+		}
+	}
+}
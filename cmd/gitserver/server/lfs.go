@@ -0,0 +1,521 @@
+package server
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/lfs"
+)
+
+var lfsObjectDownloads = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "src_gitserver_lfs_object_downloads_total",
+	Help: "number of Git LFS objects downloaded from code hosts",
+}, []string{"success"})
+
+// defaultLFSMaxObjectSize is the default of gitLFS.maxObjectSizeBytes.
+const defaultLFSMaxObjectSize = 10 * 1024 * 1024
+
+// lfsBatchSize is the number of objects requested from the LFS batch API at
+// once, as recommended by the spec.
+const lfsBatchSize = 100
+
+// lfsMediaType is the media type of LFS batch API requests and responses.
+const lfsMediaType = "application/vnd.git-lfs+json"
+
+type lfsOptions struct {
+	Enabled       bool
+	MaxObjectSize int64
+}
+
+// getLFSOptions returns the gitLFS site configuration.
+func getLFSOptions() lfsOptions {
+	c := conf.Get().GitLFS
+	if c == nil {
+		return lfsOptions{}
+	}
+	opts := lfsOptions{Enabled: c.Enabled, MaxObjectSize: int64(c.MaxObjectSizeBytes)}
+	if opts.MaxObjectSize <= 0 {
+		opts.MaxObjectSize = defaultLFSMaxObjectSize
+	}
+	return opts
+}
+
+// lfsObjectPath returns the path the LFS object is stored at. It uses the
+// same layout as git-lfs.
+func lfsObjectPath(dir GitDir, oid string) string {
+	return dir.Path("lfs", "objects", oid[0:2], oid[2:4], oid)
+}
+
+// repoUsesLFS returns true if the .gitattributes file at the root of treeish
+// stores files in LFS.
+func repoUsesLFS(ctx context.Context, dir GitDir, treeish string) bool {
+	cmd := exec.CommandContext(ctx, "git", "cat-file", "-p", treeish+":.gitattributes")
+	cmd.Dir = string(dir)
+	out, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+	return bytes.Contains(out, []byte("filter=lfs"))
+}
+
+// findLFSPointers returns the LFS pointers of the files of treeish under
+// paths.
+func findLFSPointers(ctx context.Context, dir GitDir, treeish string, paths []string) ([]*lfs.Pointer, error) {
+	args := append([]string{"ls-tree", "-r", "-l", "-z", treeish, "--"}, paths...)
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = string(dir)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, wrapCmdError(cmd, err)
+	}
+
+	// Only small blobs can be pointers.
+	var candidates bytes.Buffer
+	for _, entry := range bytes.Split(out, []byte{0}) {
+		// <mode> SP <type> SP <object> SP <size> TAB <path>
+		i := bytes.IndexByte(entry, '\t')
+		if i < 0 {
+			continue
+		}
+		fields := strings.Fields(string(entry[:i]))
+		if len(fields) != 4 || fields[1] != "blob" {
+			continue
+		}
+		if size, err := strconv.ParseInt(fields[3], 10, 64); err != nil || size > lfs.MaxPointerSize {
+			continue
+		}
+		candidates.WriteString(fields[2])
+		candidates.WriteByte('\n')
+	}
+	if candidates.Len() == 0 {
+		return nil, nil
+	}
+
+	cmd = exec.CommandContext(ctx, "git", "cat-file", "--batch")
+	cmd.Dir = string(dir)
+	cmd.Stdin = &candidates
+	out, err = cmd.Output()
+	if err != nil {
+		return nil, wrapCmdError(cmd, err)
+	}
+
+	var pointers []*lfs.Pointer
+	seen := map[string]bool{}
+	r := bufio.NewReader(bytes.NewReader(out))
+	for {
+		// <object> SP <type> SP <size> LF <contents> LF
+		header, err := r.ReadString('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		fields := strings.Fields(header)
+		if len(fields) != 3 {
+			// "<object> missing"
+			continue
+		}
+		size, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, errors.Errorf("unexpected git cat-file output %q", header)
+		}
+		content := make([]byte, size+1)
+		if _, err := io.ReadFull(r, content); err != nil {
+			return nil, err
+		}
+		if p, ok := lfs.ParsePointer(content[:size]); ok && !seen[p.OID] {
+			seen[p.OID] = true
+			pointers = append(pointers, p)
+		}
+	}
+	return pointers, nil
+}
+
+// fetchLFSObjects downloads the LFS objects of the files of treeish under
+// paths that are at most maxSize bytes large and not downloaded yet. They are
+// downloaded from the LFS server of the repository's remote with its
+// credentials.
+func fetchLFSObjects(ctx context.Context, dir GitDir, treeish string, paths []string, maxSize int64) error {
+	pointers, err := findLFSPointers(ctx, dir, treeish, paths)
+	if err != nil {
+		return errors.Wrap(err, "failed to find LFS pointers")
+	}
+
+	var missing []*lfs.Pointer
+	for _, p := range pointers {
+		if p.Size > maxSize {
+			continue
+		}
+		if _, err := os.Stat(lfsObjectPath(dir, p.OID)); os.IsNotExist(err) {
+			missing = append(missing, p)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	remoteURL, err := repoRemoteURL(ctx, dir)
+	if err != nil {
+		return err
+	}
+	endpoint, err := newLFSEndpoint(remoteURL)
+	if err != nil {
+		return err
+	}
+	cli, err := lfsHTTPClient()
+	if err != nil {
+		return err
+	}
+
+	for len(missing) > 0 {
+		batch := missing
+		if len(batch) > lfsBatchSize {
+			batch = batch[:lfsBatchSize]
+		}
+		missing = missing[len(batch):]
+
+		if err := endpoint.download(ctx, cli, dir, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lfsEndpoint is the LFS server of a remote.
+type lfsEndpoint struct {
+	// URL is the URL of the LFS server without credentials.
+	URL *url.URL
+
+	// User holds the credentials of the remote, if any.
+	User *url.Userinfo
+}
+
+// newLFSEndpoint returns the LFS server of the remote. Like git-lfs, it
+// defaults to the "info/lfs" path of the remote. Only HTTP(S) remotes are
+// supported.
+func newLFSEndpoint(remoteURL string) (*lfsEndpoint, error) {
+	u, err := url.Parse(remoteURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, errors.New("Git LFS is only supported for repositories cloned over HTTP(S)")
+	}
+
+	e := &lfsEndpoint{URL: u, User: u.User}
+	u.User = nil
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	if !strings.HasSuffix(u.Path, ".git") {
+		u.Path += ".git"
+	}
+	u.Path += "/info/lfs"
+	u.RawPath = ""
+	return e, nil
+}
+
+func (e *lfsEndpoint) setAuth(req *http.Request) {
+	if e.User == nil {
+		return
+	}
+	password, _ := e.User.Password()
+	req.SetBasicAuth(e.User.Username(), password)
+}
+
+type lfsBatchObject struct {
+	OID     string `json:"oid"`
+	Size    int64  `json:"size"`
+	Actions *struct {
+		Download *struct {
+			Href   string            `json:"href"`
+			Header map[string]string `json:"header"`
+		} `json:"download"`
+	} `json:"actions,omitempty"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// download downloads the objects of the pointers with the batch API.
+// Objects that can't be downloaded are logged and skipped.
+func (e *lfsEndpoint) download(ctx context.Context, cli *http.Client, dir GitDir, pointers []*lfs.Pointer) error {
+	var batchReq struct {
+		Operation string           `json:"operation"`
+		Transfers []string         `json:"transfers"`
+		Objects   []lfsBatchObject `json:"objects"`
+	}
+	batchReq.Operation = "download"
+	batchReq.Transfers = []string{"basic"}
+	for _, p := range pointers {
+		batchReq.Objects = append(batchReq.Objects, lfsBatchObject{OID: p.OID, Size: p.Size})
+	}
+	body, err := json.Marshal(batchReq)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", e.URL.String()+"/objects/batch", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", lfsMediaType)
+	req.Header.Set("Content-Type", lfsMediaType)
+	e.setAuth(req)
+
+	resp, err := cli.Do(req.WithContext(ctx))
+	if err != nil {
+		return errors.Wrap(err, "LFS batch request failed")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("LFS batch request failed with status %d", resp.StatusCode)
+	}
+
+	var batchResp struct {
+		Objects []lfsBatchObject `json:"objects"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&batchResp); err != nil {
+		return errors.Wrap(err, "failed to decode LFS batch response")
+	}
+
+	sizes := make(map[string]int64, len(pointers))
+	for _, p := range pointers {
+		sizes[p.OID] = p.Size
+	}
+	for _, o := range batchResp.Objects {
+		size, ok := sizes[o.OID]
+		if !ok {
+			continue
+		}
+		if o.Error != nil || o.Actions == nil || o.Actions.Download == nil {
+			lfsObjectDownloads.WithLabelValues("false").Inc()
+			log15.Warn("gitserver: LFS object is not available for download", "repo", dir, "oid", o.OID, "error", o.Error)
+			continue
+		}
+
+		err := e.downloadObject(ctx, cli, dir, o.OID, size, o.Actions.Download.Href, o.Actions.Download.Header)
+		lfsObjectDownloads.WithLabelValues(strconv.FormatBool(err == nil)).Inc()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			log15.Warn("gitserver: failed to download LFS object", "repo", dir, "oid", o.OID, "error", err)
+		}
+	}
+	return nil
+}
+
+// downloadObject downloads an object and stores it after verifying its size
+// and hash.
+func (e *lfsEndpoint) downloadObject(ctx context.Context, cli *http.Client, dir GitDir, oid string, size int64, href string, header map[string]string) error {
+	req, err := http.NewRequest("GET", href, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	// Objects stored on the LFS server itself need the remote's credentials,
+	// but we don't send them to other hosts such as object storage.
+	if req.Header.Get("Authorization") == "" && req.URL.Host == e.URL.Host {
+		e.setAuth(req)
+	}
+
+	resp, err := cli.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	tmpDir := dir.Path("lfs", "tmp")
+	if err := os.MkdirAll(tmpDir, os.ModePerm); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(tmpDir, oid)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(f, h), io.LimitReader(resp.Body, size+1))
+	if err != nil {
+		return err
+	}
+	if n != size {
+		return errors.Errorf("expected %d bytes, got %d", size, n)
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); sum != oid {
+		return errors.Errorf("content has unexpected hash %s", sum)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	path := lfsObjectPath(dir, oid)
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// lfsHTTPClient returns the client used to download LFS objects. Like git,
+// it uses the TLS configuration of tls.external.
+func lfsHTTPClient() (*http.Client, error) {
+	c := tlsExternal().(*tlsConfig)
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	if c.SSLNoVerify || c.SSLCAInfo != "" {
+		tr.TLSClientConfig = &tls.Config{InsecureSkipVerify: c.SSLNoVerify}
+	}
+	if c.SSLCAInfo != "" {
+		certs, err := ioutil.ReadFile(c.SSLCAInfo)
+		if err != nil {
+			return nil, err
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		pool.AppendCertsFromPEM(certs)
+		tr.TLSClientConfig.RootCAs = pool
+	}
+	return &http.Client{Transport: tr}, nil
+}
+
+// lfsArchiveWriter is a http.ResponseWriter that replaces the LFS pointer
+// files in the tar archive written to it with the downloaded LFS objects.
+// Pointers of objects that weren't downloaded are kept. Callers must call
+// Close once the archive is written.
+type lfsArchiveWriter struct {
+	http.ResponseWriter
+
+	pw   *io.PipeWriter
+	done chan error
+
+	// passthrough is set if the response is not an archive, such as a not
+	// found error.
+	passthrough bool
+}
+
+func newLFSArchiveWriter(w http.ResponseWriter, dir GitDir) *lfsArchiveWriter {
+	pr, pw := io.Pipe()
+	lw := &lfsArchiveWriter{ResponseWriter: w, pw: pw, done: make(chan error, 1)}
+	go func() {
+		err := rewriteLFSArchive(w, pr, dir)
+		// Fail the writes of the archive if it can't be rewritten.
+		pr.CloseWithError(err)
+		lw.done <- err
+	}()
+	return lw
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (w *lfsArchiveWriter) WriteHeader(code int) {
+	w.passthrough = code != http.StatusOK
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write implements http.ResponseWriter.
+func (w *lfsArchiveWriter) Write(p []byte) (int, error) {
+	if w.passthrough {
+		return w.ResponseWriter.Write(p)
+	}
+	return w.pw.Write(p)
+}
+
+// Flush implements http.Flusher. It is a no-op since the archive is written
+// to the underlying writer by another goroutine.
+func (w *lfsArchiveWriter) Flush() {}
+
+// Close waits for the rewritten archive to be written.
+func (w *lfsArchiveWriter) Close() error {
+	w.pw.Close()
+	return <-w.done
+}
+
+// rewriteLFSArchive copies the tar archive read from r to w, replacing LFS
+// pointer files with the downloaded LFS objects of dir.
+func rewriteLFSArchive(w io.Writer, r io.Reader, dir GitDir) error {
+	tr := tar.NewReader(r)
+	tw := tar.NewWriter(w)
+	// An empty response (e.g. if git archive failed) stays empty.
+	wrote := false
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		wrote = true
+
+		if (hdr.Typeflag != tar.TypeReg && hdr.Typeflag != tar.TypeRegA) || hdr.Size > lfs.MaxPointerSize {
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			if _, err := io.Copy(tw, tr); err != nil {
+				return err
+			}
+			continue
+		}
+
+		content, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+		if p, ok := lfs.ParsePointer(content); ok {
+			if f, err := os.Open(lfsObjectPath(dir, p.OID)); err == nil {
+				hdr.Size = p.Size
+				err := tw.WriteHeader(hdr)
+				if err == nil {
+					_, err = io.CopyN(tw, f, p.Size)
+				}
+				f.Close()
+				if err != nil {
+					return errors.Wrapf(err, "failed to write LFS object %s", p.OID)
+				}
+				continue
+			}
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(content); err != nil {
+			return err
+		}
+	}
+
+	// Drain the padding after the end of the archive so that the writer
+	// doesn't block.
+	if _, err := io.Copy(ioutil.Discard, r); err != nil {
+		return err
+	}
+	if !wrote {
+		return nil
+	}
+	return tw.Close()
+}
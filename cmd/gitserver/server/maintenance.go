@@ -0,0 +1,319 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	maintenanceTaskDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "src_gitserver_maintenance_task_duration_seconds",
+		Help:    "time spent running a git maintenance task on a repo",
+		Buckets: []float64{0.1, 0.5, 1, 5, 15, 60, 300, 900, 3600},
+	}, []string{"task", "success"})
+	maintenanceCPUSeconds = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "src_gitserver_maintenance_cpu_seconds_total",
+		Help: "CPU time used by git maintenance commands",
+	})
+	maintenanceReposDue = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "src_gitserver_maintenance_repos_due",
+		Help: "number of repos due for git maintenance at the start of the last maintenance run",
+	})
+	maintenanceReposDeferred = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "src_gitserver_maintenance_repos_deferred",
+		Help: "number of repos due for git maintenance that were deferred to the next run since the CPU budget was spent",
+	})
+)
+
+// maintenanceBatchSizeMax is the maximum batch size of
+// `git multi-pack-index repack`, which bounds the size of the packs it
+// writes.
+const maintenanceBatchSizeMax = 2 * 1024 * 1024 * 1024
+
+// maintenanceTask is a git maintenance task run on each repo.
+type maintenanceTask struct {
+	Name string
+
+	// Commands returns the arguments of the git commands that the task
+	// runs on the repo, in order. It returns none if there is nothing to do.
+	Commands func(dir GitDir) ([][]string, error)
+}
+
+// maintenanceTasks are the tasks run by Maintenance, in order. Unlike the
+// reclone done by cleanupRepos, they keep the repo usable while they run.
+var maintenanceTasks = []maintenanceTask{
+	// Pack the loose objects written by fetches into a new pack. Bitmaps
+	// can't be written by incremental repacks, they are written with the
+	// multi-pack-index below.
+	{"repack", func(dir GitDir) ([][]string, error) {
+		return [][]string{{"repack", "-d", "-l", "--no-write-bitmap-index"}}, nil
+	}},
+	// Fetches add a pack each time, which slows down object lookups. The
+	// multi-pack-index makes lookups across packs fast, repacks the small
+	// packs into bigger ones over time (like git maintenance's
+	// incremental-repack task) and stores a reachability bitmap.
+	{"multi-pack-index", func(dir GitDir) ([][]string, error) {
+		sizes, err := packSizes(dir)
+		if err != nil || len(sizes) == 0 {
+			return nil, err
+		}
+		cmds := [][]string{
+			{"multi-pack-index", "write", "--no-progress"},
+			{"multi-pack-index", "expire", "--no-progress"},
+		}
+		if len(sizes) > 1 {
+			// Like git maintenance, repack the packs smaller than the
+			// second largest one.
+			batchSize := sizes[1] + 1
+			if batchSize > maintenanceBatchSizeMax {
+				batchSize = maintenanceBatchSizeMax
+			}
+			cmds = append(cmds, []string{"multi-pack-index", "repack", "--no-progress", "--batch-size=" + strconv.FormatInt(batchSize, 10)})
+		}
+		return append(cmds, []string{"multi-pack-index", "write", "--no-progress", "--bitmap"}), nil
+	}},
+	// Commit-graphs with changed-path Bloom filters speed up commit walks,
+	// such as git log, merge-base and blame.
+	{"commit-graph", func(dir GitDir) ([][]string, error) {
+		return [][]string{{"commit-graph", "write", "--reachable", "--changed-paths", "--no-progress"}}, nil
+	}},
+}
+
+// packSizes returns the sizes of the repo's packs, largest first.
+func packSizes(dir GitDir) ([]int64, error) {
+	fis, err := ioutil.ReadDir(dir.Path("objects", "pack"))
+	if err != nil {
+		return nil, err
+	}
+	var sizes []int64
+	for _, fi := range fis {
+		if strings.HasPrefix(fi.Name(), "pack-") && strings.HasSuffix(fi.Name(), ".pack") {
+			sizes = append(sizes, fi.Size())
+		}
+	}
+	sort.Slice(sizes, func(i, j int) bool { return sizes[i] > sizes[j] })
+	return sizes, nil
+}
+
+// Maintenance runs the git maintenance tasks, such as writing commit-graphs,
+// on the repos that were last maintained longer than s.MaintenanceInterval
+// ago, least recently maintained first. At most s.MaintenanceConcurrency
+// repos are maintained at the same time, and no more repos are started once
+// the maintenance commands used s.MaintenanceCPUBudget within the last hour.
+// The remaining repos are maintained by the next run.
+func (s *Server) Maintenance() {
+	if s.MaintenanceInterval <= 0 {
+		return
+	}
+
+	ctx, cancel := s.serverContext()
+	defer cancel()
+
+	dirs, err := s.findGitDirs()
+	if err != nil {
+		log15.Error("maintenance: error finding repositories", "error", err)
+		return
+	}
+
+	type candidate struct {
+		dir  GitDir
+		last time.Time
+	}
+	var due []candidate
+	now := time.Now()
+	for _, dir := range dirs {
+		if _, cloning := s.locker.Status(dir); cloning {
+			continue
+		}
+		last, err := getMaintenanceTime(dir)
+		if err != nil {
+			log15.Warn("maintenance: error getting last maintenance time", "repo", dir, "error", err)
+			continue
+		}
+		// Add a jitter to spread out the maintenance of repos cloned at the
+		// same time.
+		if now.Sub(last) < s.MaintenanceInterval+jitterDuration(string(dir), s.MaintenanceInterval/4) {
+			continue
+		}
+		due = append(due, candidate{dir: dir, last: last})
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].last.Before(due[j].last) })
+	maintenanceReposDue.Set(float64(len(due)))
+
+	concurrency := s.MaintenanceConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	deferred := 0
+loop:
+	for i, c := range due {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			deferred = len(due) - i
+			break loop
+		}
+		if !s.maintenanceBudget.available(time.Now()) {
+			<-sem
+			deferred = len(due) - i
+			break
+		}
+
+		wg.Add(1)
+		go func(dir GitDir) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := s.maintainRepo(ctx, dir); err != nil {
+				log15.Error("maintenance: error maintaining repo", "repo", dir, "error", err)
+			}
+		}(c.dir)
+	}
+	wg.Wait()
+
+	maintenanceReposDeferred.Set(float64(deferred))
+	if deferred > 0 {
+		log15.Info("maintenance: CPU budget spent, deferring repos to the next run", "deferred", deferred)
+	}
+}
+
+// maintainRepo runs the maintenance tasks on the repo. A failing task
+// doesn't prevent the other tasks from running. The maintenance time is
+// updated even if tasks failed, so that failing repos are retried after
+// s.MaintenanceInterval instead of on every run.
+func (s *Server) maintainRepo(ctx context.Context, dir GitDir) error {
+	// Don't let fetches change the repo while the tasks rewrite its packs.
+	// Clones hold the repository lock instead, which isn't taken here
+	// since readers would treat the repo as being cloned.
+	mu := s.repoUpdateLocksFor(s.name(dir)).mu
+	mu.Lock()
+	defer mu.Unlock()
+	if _, cloning := s.locker.Status(dir); cloning {
+		return nil
+	}
+
+	var errs error
+	for _, task := range maintenanceTasks {
+		start := time.Now()
+		err := s.runMaintenanceTask(ctx, dir, task)
+		maintenanceTaskDuration.WithLabelValues(task.Name, strconv.FormatBool(err == nil)).Observe(time.Since(start).Seconds())
+		if err != nil {
+			errs = multierror.Append(errs, errors.Wrap(err, task.Name))
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+
+	if err := setMaintenanceTime(dir, time.Now()); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	return errs
+}
+
+func (s *Server) runMaintenanceTask(ctx context.Context, dir GitDir, task maintenanceTask) error {
+	cmds, err := task.Commands(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, args := range cmds {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = string(dir)
+		_, err := cmd.Output()
+		if cmd.ProcessState != nil {
+			cpu := cmd.ProcessState.UserTime() + cmd.ProcessState.SystemTime()
+			s.maintenanceBudget.spend(time.Now(), cpu)
+			maintenanceCPUSeconds.Add(cpu.Seconds())
+		}
+		if err != nil {
+			if ee, ok := err.(*exec.ExitError); ok {
+				checkMaybeCorruptRepo(s.name(dir), dir, string(ee.Stderr))
+			}
+			return wrapCmdError(cmd, err)
+		}
+	}
+	return nil
+}
+
+// cpuBudget tracks the CPU time spent by maintenance commands within the
+// current period of an hour.
+type cpuBudget struct {
+	mu    sync.Mutex
+	limit time.Duration
+	start time.Time
+	spent time.Duration
+}
+
+const cpuBudgetPeriod = time.Hour
+
+// available returns true if the budget is not spent yet. A zero limit is
+// unlimited.
+func (b *cpuBudget) available(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.roll(now)
+	return b.limit <= 0 || b.spent < b.limit
+}
+
+// spend records CPU time spent at the given time.
+func (b *cpuBudget) spend(now time.Time, d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.roll(now)
+	b.spent += d
+}
+
+// roll starts a new period if the current one is over. The caller must hold
+// the lock on b.mu.
+func (b *cpuBudget) roll(now time.Time) {
+	if now.Sub(b.start) >= cpuBudgetPeriod {
+		b.start = now
+		b.spent = 0
+	}
+}
+
+// setMaintenanceTime sets the time the maintenance tasks last ran on a
+// repository.
+func setMaintenanceTime(dir GitDir, now time.Time) error {
+	err := gitConfigSet(dir, "sourcegraph.maintenanceTimestamp", strconv.FormatInt(now.Unix(), 10))
+	if err != nil {
+		return errors.Wrap(err, "failed to update maintenanceTimestamp")
+	}
+	return nil
+}
+
+// getMaintenanceTime returns the time the maintenance tasks last ran on a
+// repository, or the zero time if they never ran.
+func getMaintenanceTime(dir GitDir) (time.Time, error) {
+	value, err := gitConfigGet(dir, "sourcegraph.maintenanceTimestamp")
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to determine maintenance timestamp")
+	}
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	sec, err := strconv.ParseInt(strings.TrimSpace(value), 10, 0)
+	if err != nil {
+		// Treat a bad value like a missing one, so that the repo is
+		// maintained and the value is overwritten.
+		return time.Time{}, nil
+	}
+	return time.Unix(sec, 0), nil
+}
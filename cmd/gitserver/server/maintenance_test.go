@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMaintenance(t *testing.T) {
+	root := tmpDir(t)
+	defer os.RemoveAll(root)
+
+	remote := filepath.Join(root, "remote")
+	if err := os.MkdirAll(remote, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	runCmd(t, remote, "git", "init", ".")
+	for _, msg := range []string{"a", "b", "c"} {
+		runCmd(t, remote, "git", "commit", "--allow-empty", "-m", msg)
+	}
+
+	repoDir := filepath.Join(root, "repos")
+	repo := filepath.Join(repoDir, "repo", ".git")
+	empty := filepath.Join(repoDir, "empty", ".git")
+	runCmd(t, root, "git", "clone", "--bare", remote, repo)
+	runCmd(t, root, "git", "init", "--bare", empty)
+	// Fetches add more packs.
+	for _, msg := range []string{"d", "e"} {
+		runCmd(t, remote, "git", "commit", "--allow-empty", "-m", msg)
+		runCmd(t, repo, "git", "-c", "fetch.unpackLimit=1", "fetch", remote, "+refs/heads/*:refs/heads/*")
+	}
+
+	s := &Server{
+		ReposDir:               repoDir,
+		MaintenanceInterval:    time.Hour,
+		MaintenanceConcurrency: 2,
+	}
+	s.Handler() // Handler as a side-effect sets up Server
+	s.Maintenance()
+
+	for _, path := range []string{
+		filepath.Join(repo, "objects", "info", "commit-graph"),
+		filepath.Join(repo, "objects", "pack", "multi-pack-index"),
+	} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("expected %s to be written: %s", path, err)
+		}
+	}
+	if bitmaps, _ := filepath.Glob(filepath.Join(repo, "objects", "pack", "multi-pack-index-*.bitmap")); len(bitmaps) != 1 {
+		t.Errorf("expected a multi-pack-index bitmap to be written, got %v", bitmaps)
+	}
+
+	info, err := s.repoInfo(context.Background(), "repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.LastMaintenance == nil || time.Since(*info.LastMaintenance) > time.Minute {
+		t.Fatalf("unexpected last maintenance time %v", info.LastMaintenance)
+	}
+
+	// Maintenance doesn't run again before the interval passed.
+	if err := setMaintenanceTime(GitDir(empty), time.Unix(1, 0)); err != nil {
+		t.Fatal(err)
+	}
+	s.Maintenance()
+	if last, err := getMaintenanceTime(GitDir(repo)); err != nil || !last.Equal(*info.LastMaintenance) {
+		t.Errorf("expected repo not to be maintained again, got %v (%v)", last, err)
+	}
+	if last, err := getMaintenanceTime(GitDir(empty)); err != nil || time.Since(last) > time.Minute {
+		t.Errorf("expected empty repo to be maintained again, got %v (%v)", last, err)
+	}
+}
+
+func TestMaintenance_CPUBudget(t *testing.T) {
+	root := tmpDir(t)
+	defer os.RemoveAll(root)
+
+	repo := filepath.Join(root, "repo", ".git")
+	runCmd(t, root, "git", "init", "--bare", repo)
+
+	s := &Server{
+		ReposDir:             root,
+		MaintenanceInterval:  time.Hour,
+		MaintenanceCPUBudget: time.Second,
+	}
+	s.Handler()
+	s.maintenanceBudget.spend(time.Now(), time.Second)
+	s.Maintenance()
+
+	if last, err := getMaintenanceTime(GitDir(repo)); err != nil || !last.IsZero() {
+		t.Fatalf("expected repo not to be maintained when the CPU budget is spent, got %v (%v)", last, err)
+	}
+
+	// The budget is available again in the next period.
+	s.maintenanceBudget.start = time.Now().Add(-cpuBudgetPeriod)
+	s.Maintenance()
+
+	if last, err := getMaintenanceTime(GitDir(repo)); err != nil || last.IsZero() {
+		t.Fatalf("expected repo to be maintained, got %v (%v)", last, err)
+	}
+}
+
+func TestMaintenance_RepoUpdateLock(t *testing.T) {
+	root := tmpDir(t)
+	defer os.RemoveAll(root)
+
+	repo := filepath.Join(root, "repo", ".git")
+	runCmd(t, root, "git", "init", "--bare", repo)
+
+	s := &Server{
+		ReposDir:            root,
+		MaintenanceInterval: time.Hour,
+	}
+	s.Handler()
+
+	// Maintenance waits for a running update of the repo.
+	mu := s.repoUpdateLocksFor("repo").mu
+	mu.Lock()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.Maintenance()
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("expected maintenance to wait for the repo update")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if last, err := getMaintenanceTime(GitDir(repo)); err != nil || !last.IsZero() {
+		t.Fatalf("expected repo not to be maintained during the update, got %v (%v)", last, err)
+	}
+
+	mu.Unlock()
+	<-done
+	if last, err := getMaintenanceTime(GitDir(repo)); err != nil || last.IsZero() {
+		t.Fatalf("expected repo to be maintained after the update, got %v (%v)", last, err)
+	}
+}
@@ -174,18 +174,50 @@ func (s *Server) createCommitFromPatch(ctx context.Context, req protocol.CreateC
 	if authorEmail == "" {
 		authorEmail = "support@sourcegraph.com"
 	}
+
+	// Signed commits are committed by the owner of the signing key, so that
+	// code hosts can verify the signature.
+	signer := getCommitSigner()
+	var signerName, signerEmail string
+	if signer != nil {
+		signerName, signerEmail = signer.CommitterName, signer.CommitterEmail
+	}
+
 	committerName := req.CommitInfo.CommitterName
+	if committerName == "" {
+		committerName = signerName
+	}
 	if committerName == "" {
 		committerName = authorName
 	}
 	committerEmail := req.CommitInfo.CommitterEmail
+	if committerEmail == "" {
+		committerEmail = signerEmail
+	}
 	if committerEmail == "" {
 		committerEmail = authorEmail
 	}
 
-	cmd = exec.CommandContext(ctx, "git", "commit", "-m", message)
+	var signArgs, signEnv []string
+	if signer != nil {
+		signDir, err := s.tempDir("commit-signing-")
+		if err != nil {
+			resp.SetError(repo, "", "", errors.Wrap(err, "gitserver: make tmp signing dir"))
+			return http.StatusInternalServerError, resp
+		}
+		defer os.RemoveAll(signDir)
+
+		signArgs, signEnv, err = signer.setup(ctx, signDir)
+		if err != nil {
+			resp.SetError(repo, "", "", errors.Wrap(err, "gitserver: setting up commit signing"))
+			return http.StatusInternalServerError, resp
+		}
+		defer signer.cleanup(signEnv)
+	}
+
+	cmd = exec.CommandContext(ctx, "git", append(signArgs, "commit", "-m", message)...)
 	cmd.Dir = tmpRepoDir
-	cmd.Env = append(os.Environ(), []string{
+	cmd.Env = append(append(os.Environ(), signEnv...), []string{
 		tmpGitPathEnv,
 		altObjectsEnv,
 		fmt.Sprintf("GIT_COMMITTER_NAME=%s", committerName),
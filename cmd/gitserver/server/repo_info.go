@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"os"
+	"path/filepath"
 
 	"github.com/inconshreveable/log15"
 	"github.com/sourcegraph/sourcegraph/internal/api"
@@ -21,7 +23,9 @@ func (s *Server) repoInfo(ctx context.Context, repo api.RepoName) (*protocol.Rep
 			return nil, err
 		}
 		resp.URL = remoteURL
+		resp.VCS = vcsSyncer(remoteURL).Type()
 	}
+	resp.LastSyncError = s.syncError(repo)
 	{
 		resp.CloneProgress, resp.CloneInProgress = s.locker.Status(dir)
 		if isAlwaysCloningTest(repo) {
@@ -47,6 +51,24 @@ func (s *Server) repoInfo(ctx context.Context, repo api.RepoName) (*protocol.Rep
 		} else {
 			resp.LastChanged = &lastChanged
 		}
+
+		if strategy, err := getCloneStrategy(dir); err != nil {
+			log15.Warn("error getting clone strategy", "repo", repo, "err", err)
+		} else {
+			resp.CloneStrategy = strategy.String()
+		}
+
+		if maintenanceTime, err := getMaintenanceTime(dir); err != nil {
+			log15.Warn("error getting maintenance time", "repo", repo, "err", err)
+		} else if !maintenanceTime.IsZero() {
+			resp.LastMaintenance = &maintenanceTime
+		}
+
+		if backupTime, err := getBackupTime(dir); err != nil {
+			log15.Warn("error getting backup time", "repo", repo, "err", err)
+		} else if !backupTime.IsZero() {
+			resp.LastBackup = &backupTime
+		}
 	}
 	return &resp, nil
 }
@@ -61,6 +83,15 @@ func (s *Server) repoCloneProgress(repo api.RepoName) (*protocol.RepoCloneProgre
 		resp.CloneInProgress = true
 		resp.CloneProgress = "This will never finish cloning"
 	}
+	if resp.Cloned {
+		if strategy, err := getCloneStrategy(dir); err != nil {
+			log15.Warn("error getting clone strategy", "repo", repo, "err", err)
+		} else {
+			resp.CloneStrategy = strategy.String()
+		}
+	} else if resp.CloneInProgress {
+		resp.CloneStrategy = configuredCloneStrategy(repo).String()
+	}
 	return &resp, nil
 }
 
@@ -132,3 +163,58 @@ func (s *Server) handleRepoDelete(w http.ResponseWriter, r *http.Request) {
 func (s *Server) deleteRepo(repo api.RepoName) error {
 	return s.removeRepoDirectory(s.dir(repo))
 }
+
+func (s *Server) handleRepoRename(w http.ResponseWriter, r *http.Request) {
+	var req protocol.RepoRenameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	renamed, err := s.renameRepo(req.From, req.To)
+	if err != nil {
+		log15.Error("failed to rename repository", "from", req.From, "to", req.To, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if renamed {
+		log15.Info("renamed repository", "from", req.From, "to", req.To)
+	}
+
+	if err := json.NewEncoder(w).Encode(&protocol.RepoRenameResponse{Renamed: renamed}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// renameRepo moves the clone of a repository from its old name to its new
+// name. It does nothing if the repository isn't cloned under its old name, is
+// already cloned under its new name, or either is locked (e.g. by a clone).
+func (s *Server) renameRepo(from, to api.RepoName) (renamed bool, err error) {
+	fromDir, toDir := s.dir(from), s.dir(to)
+	if fromDir == toDir || !repoCloned(fromDir) || repoCloned(toDir) {
+		return false, nil
+	}
+
+	fromLock, ok := s.locker.TryAcquire(fromDir, "renaming")
+	if !ok {
+		return false, nil
+	}
+	defer fromLock.Release()
+
+	toLock, ok := s.locker.TryAcquire(toDir, "renaming")
+	if !ok {
+		return false, nil
+	}
+	defer toLock.Release()
+
+	if err := os.MkdirAll(filepath.Dir(string(toDir)), os.ModePerm); err != nil {
+		return false, err
+	}
+	if err := renameAndSync(string(fromDir), string(toDir)); err != nil {
+		return false, err
+	}
+
+	s.removeEmptyParents(string(fromDir))
+	return true, nil
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 )
@@ -99,6 +100,7 @@ func TestServer_handleRepoInfo(t *testing.T) {
 					LastFetched: &lastFetched,
 					LastChanged: &lastChanged,
 					URL:         "u",
+					VCS:         "git",
 				},
 			},
 		}
@@ -107,6 +109,27 @@ func TestServer_handleRepoInfo(t *testing.T) {
 		}
 	})
 
+	t.Run("sync error", func(t *testing.T) {
+		origRepoCloned := repoCloned
+		repoCloned = func(dir GitDir) bool { return false }
+		defer func() { repoCloned = origRepoCloned }()
+
+		s.setSyncError("x", "https://secret@example.com/x", errors.New("fetch https://secret@example.com/x failed"))
+		want := protocol.RepoInfoResponse{
+			Results: map[api.RepoName]*protocol.RepoInfo{
+				"x": {LastSyncError: "fetch https://<redacted>@example.com/x failed"},
+			},
+		}
+		if got := getRepoInfo(t, "x"); !cmp.Equal(want, got) {
+			t.Errorf("mismatch (-want +got):\n%s", cmp.Diff(want, got))
+		}
+
+		s.setSyncError("x", "", nil)
+		if got := getRepoInfo(t, "x"); got.Results["x"].LastSyncError != "" {
+			t.Errorf("expected sync error to be cleared, got %q", got.Results["x"].LastSyncError)
+		}
+	})
+
 	t.Run("multiple", func(t *testing.T) {
 		origRepoCloned := repoCloned
 		repoCloned = func(dir GitDir) bool { return false }
@@ -126,3 +149,61 @@ func TestServer_handleRepoInfo(t *testing.T) {
 		}
 	})
 }
+
+func TestServer_renameRepo(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		files   []string
+		from    api.RepoName
+		to      api.RepoName
+		renamed bool
+		want    []string
+	}{
+		{
+			name:    "moved",
+			files:   []string{"github.com/foo/old/.git/HEAD", "github.com/foo/other/.git/HEAD"},
+			from:    "github.com/foo/old",
+			to:      "github.com/bar/new",
+			renamed: true,
+			want:    []string{"github.com/bar/new/.git/HEAD", "github.com/foo/other/.git/HEAD"},
+		},
+		{
+			name:  "not cloned",
+			files: []string{"github.com/foo/other/.git/HEAD"},
+			from:  "github.com/foo/old",
+			to:    "github.com/bar/new",
+			want:  []string{"github.com/foo/other/.git/HEAD"},
+		},
+		{
+			name:  "already cloned under new name",
+			files: []string{"github.com/foo/old/.git/HEAD", "github.com/bar/new/.git/HEAD"},
+			from:  "github.com/foo/old",
+			to:    "github.com/bar/new",
+			want:  []string{"github.com/foo/old/.git/HEAD", "github.com/bar/new/.git/HEAD"},
+		},
+		{
+			name:  "same directory",
+			files: []string{"github.com/foo/old/.git/HEAD"},
+			from:  "github.com/foo/old",
+			to:    "github.com/Foo/Old",
+			want:  []string{"github.com/foo/old/.git/HEAD"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			root := tmpDir(t)
+			mkFiles(t, root, tc.files...)
+
+			s := &Server{ReposDir: root, locker: &RepositoryLocker{}}
+
+			renamed, err := s.renameRepo(tc.from, tc.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if renamed != tc.renamed {
+				t.Errorf("have renamed %t, want %t", renamed, tc.renamed)
+			}
+
+			assertPaths(t, root, tc.want...)
+		})
+	}
+}
@@ -0,0 +1,368 @@
+package server
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+// movedRepoTTL is how long the clone of a repo that was moved to another
+// gitserver is kept, which must be long enough for all services to pick up
+// the new placement of the repo. Until then, requests for the repo may still
+// be sent to this gitserver.
+const movedRepoTTL = time.Hour
+
+// repoMoveChecksumTrailer is the HTTP trailer of /repo-receive requests that
+// holds the SHA-256 checksum of the copy, which is only known after it was
+// sent.
+const repoMoveChecksumTrailer = "X-Sourcegraph-Checksum"
+
+var (
+	// errMoveNotCloned is returned by moveRepo if the repository isn't
+	// cloned.
+	errMoveNotCloned = errors.New("repository is not cloned")
+	// errMoveTargetCloned is returned by moveRepo for renames if the target
+	// gitserver already has a clone of the new name.
+	errMoveTargetCloned = errors.New("target repository is already cloned")
+)
+
+var repoMoveDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "src_gitserver_repo_move_duration_seconds",
+	Help:    "time spent copying repos to other gitservers",
+	Buckets: []float64{1, 5, 15, 60, 300, 900, 3600, 10800},
+}, []string{"success"})
+
+func (s *Server) handleRepoMove(w http.ResponseWriter, r *http.Request) {
+	var req protocol.RepoMoveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	start := time.Now()
+	err := s.moveRepo(r.Context(), protocol.NormalizeRepo(req.Repo), req.Target, protocol.NormalizeRepo(req.TargetRepo))
+	switch err {
+	case nil:
+	case errMoveNotCloned:
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errMoveTargetCloned:
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	default:
+		repoMoveDuration.WithLabelValues("false").Observe(time.Since(start).Seconds())
+		log15.Error("failed to move repository", "repo", req.Repo, "target", req.Target, "targetRepo", req.TargetRepo, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	repoMoveDuration.WithLabelValues("true").Observe(time.Since(start).Seconds())
+	log15.Info("moved repository", "repo", req.Repo, "target", req.Target, "targetRepo", req.TargetRepo, "duration", time.Since(start))
+}
+
+// moveRepo streams the clone of the repo to the gitserver at target, which
+// verifies it against the checksum of the stream and the refs of the clone.
+// The clone is kept and served until the repo is placed on target, after
+// which the caller marks it as moved with /repo-moved.
+//
+// If targetRepo is set, the copy is stored as targetRepo on target, unless
+// target already has a clone of it. This moves the clones of repos that were
+// renamed to the gitserver that owns their new name. There is no placement
+// to wait for then, so the clone is marked as moved right away.
+func (s *Server) moveRepo(ctx context.Context, repo api.RepoName, target string, targetRepo api.RepoName) error {
+	dir := s.dir(repo)
+	if !repoCloned(dir) {
+		return errMoveNotCloned
+	}
+
+	// Don't let clones and fetches change the clone while it is copied.
+	lock, ok := s.locker.TryAcquire(dir, "moving to "+target)
+	if !ok {
+		return errors.New("repository is locked, try again later")
+	}
+	defer lock.Release()
+	mu := s.repoUpdateLocksFor(repo).mu
+	mu.Lock()
+	defer mu.Unlock()
+
+	refHash, err := computeRefHash(dir)
+	if err != nil {
+		return errors.Wrap(err, "failed to compute ref hash")
+	}
+
+	q := url.Values{
+		"repo":    []string{string(repo)},
+		"refhash": []string{string(refHash)},
+	}
+	if targetRepo != "" {
+		q.Set("repo", string(targetRepo))
+		q.Set("noreplace", "true")
+	}
+	u := "http://" + target + "/repo-receive?" + q.Encode()
+	pr, pw := io.Pipe()
+	req, err := http.NewRequest("POST", u, pr)
+	if err != nil {
+		return err
+	}
+	req.Trailer = http.Header{repoMoveChecksumTrailer: nil}
+	go func() {
+		hasher := sha256.New()
+		err := writeRepoTar(io.MultiWriter(pw, hasher), dir)
+		// The transport reads the trailer after the body, so it is set
+		// before closing it.
+		req.Trailer.Set(repoMoveChecksumTrailer, hex.EncodeToString(hasher.Sum(nil)))
+		_ = pw.CloseWithError(err)
+	}()
+
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	// Unblock writeRepoTar if the request failed before sending the body.
+	_ = pr.CloseWithError(errors.New("request done"))
+	if err != nil {
+		return errors.Wrap(err, "failed to copy repository")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusConflict {
+		return errMoveTargetCloned
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed to copy repository: %s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+
+	if targetRepo == "" {
+		return nil
+	}
+	return markMoved(dir, target)
+}
+
+func (s *Server) handleRepoMoved(w http.ResponseWriter, r *http.Request) {
+	var req protocol.RepoMovedRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	dir := s.dir(protocol.NormalizeRepo(req.Repo))
+	if !repoCloned(dir) {
+		http.Error(w, errMoveNotCloned.Error(), http.StatusNotFound)
+		return
+	}
+	if err := markMoved(dir, req.Target); err != nil {
+		log15.Error("failed to mark repository as moved", "repo", req.Repo, "target", req.Target, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// markMoved marks the clone in dir as moved to the gitserver at target, so
+// that it is removed once movedRepoTTL passed.
+func markMoved(dir GitDir, target string) error {
+	if err := gitConfigSet(dir, "sourcegraph.movedTo", target); err != nil {
+		return err
+	}
+	return gitConfigSet(dir, "sourcegraph.movedTimestamp", strconv.FormatInt(time.Now().Unix(), 10))
+}
+
+// writeRepoTar writes a tar archive of the regular files of the git
+// directory to w, skipping lock files.
+func writeRepoTar(w io.Writer, dir GitDir) error {
+	tw := tar.NewWriter(w)
+	err := filepath.Walk(string(dir), func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !fi.IsDir() && (!fi.Mode().IsRegular() || strings.HasSuffix(path, ".lock")) {
+			return nil
+		}
+		name, err := filepath.Rel(string(dir), path)
+		if err != nil || name == "." {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(fi, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(name)
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.CopyN(tw, f, hdr.Size)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+func (s *Server) handleRepoReceive(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	repo := protocol.NormalizeRepo(api.RepoName(q.Get("repo")))
+	if repo == "" {
+		http.Error(w, "missing repo", http.StatusBadRequest)
+		return
+	}
+
+	noReplace, _ := strconv.ParseBool(q.Get("noreplace"))
+	if err := s.receiveRepo(r.Context(), repo, q.Get("refhash"), noReplace, r); err == errMoveTargetCloned {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	} else if err != nil {
+		log15.Error("failed to receive moved repository", "repo", repo, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	log15.Info("received moved repository", "repo", repo)
+}
+
+// receiveRepo extracts the copy of the clone of a repo sent by moveRepo, and
+// replaces the clone of the repo (if any) with it once it is verified. If
+// noReplace is true, an existing clone is kept and errMoveTargetCloned is
+// returned instead.
+func (s *Server) receiveRepo(ctx context.Context, repo api.RepoName, refHash string, noReplace bool, r *http.Request) error {
+	dir := s.dir(repo)
+	lock, ok := s.locker.TryAcquire(dir, "receiving moved repository")
+	if !ok {
+		return errors.New("repository is locked, try again later")
+	}
+	defer lock.Release()
+
+	if noReplace && repoCloned(dir) {
+		return errMoveTargetCloned
+	}
+
+	tmp, err := s.tempDir("receive-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	tmpDir := GitDir(filepath.Join(tmp, ".git"))
+
+	hasher := sha256.New()
+	body := io.TeeReader(r.Body, hasher)
+	if err := extractRepoTar(body, string(tmpDir)); err != nil {
+		return errors.Wrap(err, "failed to extract repository")
+	}
+	// Read the padding after the end of the archive, after which the trailer
+	// is available.
+	if _, err := io.Copy(ioutil.Discard, body); err != nil {
+		return err
+	}
+	if got, want := hex.EncodeToString(hasher.Sum(nil)), r.Trailer.Get(repoMoveChecksumTrailer); got != want {
+		return fmt.Errorf("checksum mismatch: got %q, want %q", got, want)
+	}
+	if got, err := computeRefHash(tmpDir); err != nil {
+		return errors.Wrap(err, "failed to compute ref hash")
+	} else if string(got) != refHash {
+		return fmt.Errorf("ref hash mismatch: got %q, want %q", got, refHash)
+	}
+	cmd := exec.CommandContext(ctx, "git", "fsck", "--connectivity-only", "--no-dangling", "--no-progress")
+	cmd.Dir = string(tmpDir)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "git fsck failed. Output: %s", out)
+	}
+
+	// The repo may have been moved from this gitserver before.
+	for _, key := range []string{"sourcegraph.movedTo", "sourcegraph.movedTimestamp"} {
+		if err := gitConfigUnset(tmpDir, key); err != nil {
+			return err
+		}
+	}
+
+	if repoCloned(dir) {
+		if err := renameAndSync(string(dir), filepath.Join(tmp, "old")); err != nil {
+			return errors.Wrap(err, "failed to remove old clone")
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(string(dir)), os.ModePerm); err != nil {
+		return err
+	}
+	return renameAndSync(string(tmpDir), string(dir))
+}
+
+// extractRepoTar extracts the directories and regular files of the tar
+// archive read from r into dst.
+func extractRepoTar(r io.Reader, dst string) error {
+	if err := os.MkdirAll(dst, os.ModePerm); err != nil {
+		return err
+	}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// Cleaning the name as an absolute path keeps it inside dst.
+		path := filepath.Join(dst, filepath.Clean("/"+hdr.Name))
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, os.ModePerm); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(hdr.Mode).Perm())
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(f, tr)
+			if err1 := f.Close(); err == nil {
+				err = err1
+			}
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unexpected file type of %s", hdr.Name)
+		}
+	}
+}
+
+// getMovedTime returns the time the repository was moved to another
+// gitserver, or the zero time if it wasn't.
+func getMovedTime(dir GitDir) (time.Time, error) {
+	value, err := gitConfigGet(dir, "sourcegraph.movedTimestamp")
+	if err != nil || value == "" {
+		return time.Time{}, err
+	}
+	sec, err := strconv.ParseInt(strings.TrimSpace(value), 10, 0)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "invalid moved timestamp")
+	}
+	return time.Unix(sec, 0), nil
+}
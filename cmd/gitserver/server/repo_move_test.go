@@ -0,0 +1,147 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+func TestMoveRepo(t *testing.T) {
+	remote := tmpDir(t)
+	defer os.RemoveAll(remote)
+	runCmd(t, remote, "git", "init", ".")
+	runCmd(t, remote, "git", "commit", "--allow-empty", "-m", "a")
+
+	newServer := func() (*Server, *httptest.Server) {
+		s := &Server{ReposDir: tmpDir(t)}
+		ts := httptest.NewServer(s.Handler())
+		return s, ts
+	}
+	source, sourceTS := newServer()
+	defer os.RemoveAll(source.ReposDir)
+	defer sourceTS.Close()
+	target, targetTS := newServer()
+	defer os.RemoveAll(target.ReposDir)
+	defer targetTS.Close()
+
+	const repo = api.RepoName("example.com/foo/bar")
+	if _, err := source.cloneRepo(context.Background(), repo, remote, &cloneOptions{Block: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	move := func(repo, targetRepo api.RepoName) *http.Response {
+		body, err := json.Marshal(protocol.RepoMoveRequest{
+			Repo:       repo,
+			Target:     strings.TrimPrefix(targetTS.URL, "http://"),
+			TargetRepo: targetRepo,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.Post(sourceTS.URL+"/repo-move", "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+	if resp := move(repo, ""); resp.StatusCode != http.StatusOK {
+		t.Fatalf("got status %d moving repo", resp.StatusCode)
+	}
+
+	want := runCmd(t, string(source.dir(repo)), "git", "show-ref")
+	if got := runCmd(t, string(target.dir(repo)), "git", "show-ref"); got != want {
+		t.Errorf("got refs %q on target, want %q", got, want)
+	}
+	if url, err := repoRemoteURL(context.Background(), target.dir(repo)); err != nil || url != remote {
+		t.Errorf("got remote URL %q (%v) on target, want %q", url, err, remote)
+	}
+	// The source clone is only marked as moved once the repo is placed on
+	// the target.
+	if moved, err := getMovedTime(source.dir(repo)); err != nil || !moved.IsZero() {
+		t.Errorf("expected source not to record the move before it is placed, got %v (%v)", moved, err)
+	}
+	moved := func(repo api.RepoName) *http.Response {
+		body, err := json.Marshal(protocol.RepoMovedRequest{
+			Repo:   repo,
+			Target: strings.TrimPrefix(targetTS.URL, "http://"),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.Post(sourceTS.URL+"/repo-moved", "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+	if resp := moved(repo); resp.StatusCode != http.StatusOK {
+		t.Fatalf("got status %d marking repo as moved", resp.StatusCode)
+	}
+	if moved, err := getMovedTime(source.dir(repo)); err != nil || moved.IsZero() {
+		t.Errorf("expected source to record the move, got %v (%v)", moved, err)
+	}
+	if resp := moved("example.com/foo/missing"); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("got status %d marking a repo that isn't cloned as moved, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+
+	// Renames copy the clone to the new name on the target, unless it is
+	// cloned there already.
+	const renamed = api.RepoName("example.com/foo/renamed")
+	if resp := move(repo, renamed); resp.StatusCode != http.StatusOK {
+		t.Fatalf("got status %d renaming repo", resp.StatusCode)
+	}
+	if got := runCmd(t, string(target.dir(renamed)), "git", "show-ref"); got != want {
+		t.Errorf("got refs %q on target after rename, want %q", got, want)
+	}
+	if resp := move(repo, renamed); resp.StatusCode != http.StatusConflict {
+		t.Errorf("got status %d renaming repo to a cloned name, want %d", resp.StatusCode, http.StatusConflict)
+	}
+	if resp := move("example.com/foo/missing", renamed); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("got status %d renaming a repo that isn't cloned, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+
+	// The source clone is only removed once movedRepoTTL passed.
+	source.cleanupRepos()
+	if !repoCloned(source.dir(repo)) {
+		t.Fatal("expected source clone to be kept right after the move")
+	}
+	ts := time.Now().Add(-2 * movedRepoTTL).Unix()
+	if err := gitConfigSet(source.dir(repo), "sourcegraph.movedTimestamp", strconv.FormatInt(ts, 10)); err != nil {
+		t.Fatal(err)
+	}
+	source.cleanupRepos()
+	if repoCloned(source.dir(repo)) {
+		t.Error("expected source clone to be removed after movedRepoTTL")
+	}
+
+	// Copies that fail to verify are rejected and keep the existing clone.
+	u := targetTS.URL + "/repo-receive?" + url.Values{"repo": {string(repo)}, "refhash": {"x"}}.Encode()
+	req, err := http.NewRequest("POST", u, strings.NewReader("not a tar archive"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("got status %d receiving a bad copy, want %d", resp.StatusCode, http.StatusInternalServerError)
+	}
+	if !repoCloned(target.dir(repo)) {
+		t.Error("expected target clone to be kept after receiving a bad copy")
+	}
+}
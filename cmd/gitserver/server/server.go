@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/inconshreveable/log15"
+	opentracing "github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/ext"
 	otlog "github.com/opentracing/opentracing-go/log"
 	"github.com/pkg/errors"
@@ -37,6 +38,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/honey"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
 	"github.com/sourcegraph/sourcegraph/internal/mutablelimiter"
+	"github.com/sourcegraph/sourcegraph/internal/objectstorage"
 	"github.com/sourcegraph/sourcegraph/internal/repotrackutil"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
@@ -116,6 +118,32 @@ type Server struct {
 	// DiskSizer tells how much disk is free and how large the disk is.
 	DiskSizer DiskSizer
 
+	// MaintenanceInterval is how often the git maintenance tasks, such as
+	// writing commit-graphs, run on each repository. Maintenance is disabled
+	// if it is zero.
+	MaintenanceInterval time.Duration
+
+	// MaintenanceConcurrency is the maximum number of repositories that the
+	// git maintenance tasks run on at the same time.
+	MaintenanceConcurrency int
+
+	// MaintenanceCPUBudget is the CPU time that the git maintenance tasks
+	// may use per hour. It is unlimited if it is zero.
+	MaintenanceCPUBudget time.Duration
+
+	// BackupStore is where bundles of the repositories are backed up, so
+	// that clones can be restored from them instead of recloned from the
+	// code host. Backups are disabled if it is nil.
+	BackupStore objectstorage.Store
+
+	// BackupInterval is how often each repository is backed up to
+	// BackupStore. Backups are disabled if it is zero.
+	BackupInterval time.Duration
+
+	// ExecLogSize is the number of recent exec requests kept in the exec log
+	// served at /debug/exec-log. The exec log is disabled if it is zero.
+	ExecLogSize int
+
 	// skipCloneForTests is set by tests to avoid clones.
 	skipCloneForTests bool
 
@@ -138,6 +166,18 @@ type Server struct {
 
 	repoUpdateLocksMu sync.Mutex // protects the map below and also updates to locks.once
 	repoUpdateLocks   map[api.RepoName]*locks
+
+	// maintenanceBudget tracks the CPU time used by the git maintenance
+	// tasks.
+	maintenanceBudget cpuBudget
+
+	// execLog records the recent exec requests.
+	execLog *execLog
+
+	syncErrorsMu sync.Mutex
+	// syncErrors is the error of the last failed clone or fetch of each
+	// repo, which is reported by repoInfo until it succeeds.
+	syncErrors map[api.RepoName]string
 }
 
 type locks struct {
@@ -199,6 +239,8 @@ func (s *Server) Handler() http.Handler {
 	s.ctx, s.cancel = context.WithCancel(context.Background())
 	s.locker = &RepositoryLocker{}
 	s.repoUpdateLocks = make(map[api.RepoName]*locks)
+	s.maintenanceBudget.limit = s.MaintenanceCPUBudget
+	s.execLog = newExecLog(s.ExecLogSize)
 
 	// GitMaxConcurrentClones controls the maximum number of clones that
 	// can happen at once on a single gitserver.
@@ -232,9 +274,14 @@ func (s *Server) Handler() http.Handler {
 	mux.HandleFunc("/repos", s.handleRepoInfo)
 	mux.HandleFunc("/repo-clone-progress", s.handleRepoCloneProgress)
 	mux.HandleFunc("/delete", s.handleRepoDelete)
+	mux.HandleFunc("/rename", s.handleRepoRename)
+	mux.HandleFunc("/repo-move", s.handleRepoMove)
+	mux.HandleFunc("/repo-moved", s.handleRepoMoved)
+	mux.HandleFunc("/repo-receive", s.handleRepoReceive)
 	mux.HandleFunc("/repo-update", s.handleRepoUpdate)
 	mux.HandleFunc("/getGitolitePhabricatorMetadata", s.handleGetGitolitePhabricatorMetadata)
 	mux.HandleFunc("/create-commit-from-patch", s.handleCreateCommitFromPatch)
+	mux.HandleFunc("/debug/exec-log", s.handleExecLog)
 	mux.HandleFunc("/ping", func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
@@ -242,6 +289,10 @@ func (s *Server) Handler() http.Handler {
 	mux.Handle("/git/", http.StripPrefix("/git", &gitServiceHandler{
 		Dir: func(d string) string { return string(s.dir(api.RepoName(d))) },
 	}))
+	mux.Handle("/public-git/", http.StripPrefix("/public-git", &gitServiceHandler{
+		Dir:    func(d string) string { return string(s.dir(api.RepoName(d))) },
+		Public: true,
+	}))
 
 	return mux
 }
@@ -502,6 +553,36 @@ func (s *Server) handleArchive(w http.ResponseWriter, r *http.Request) {
 	req.Args = append(req.Args, treeish, "--")
 	req.Args = append(req.Args, paths...)
 
+	// git archive reads every blob, so fetch the ones missing from partial
+	// clones at once. If this fails, git still fetches them on demand.
+	dir := s.dir(protocol.NormalizeRepo(req.Repo))
+	if quickIsPartialClone(dir) {
+		ctx, cancel := context.WithTimeout(r.Context(), longGitCommandTimeout)
+		err := fetchMissingBlobs(ctx, dir, treeish, paths)
+		cancel()
+		if err != nil {
+			log15.Warn("gitserver.archive: failed to fetch missing blobs", "repo", req.Repo, "error", err)
+		}
+	}
+
+	// Replace LFS pointer files with their content. Only tar archives are
+	// rewritten, which is the format used for search.
+	if lfsOpts := getLFSOptions(); lfsOpts.Enabled && format == "tar" && repoUsesLFS(r.Context(), dir, treeish) {
+		ctx, cancel := context.WithTimeout(r.Context(), longGitCommandTimeout)
+		err := fetchLFSObjects(ctx, dir, treeish, paths, lfsOpts.MaxObjectSize)
+		cancel()
+		if err != nil {
+			log15.Warn("gitserver.archive: failed to fetch LFS objects", "repo", req.Repo, "error", err)
+		}
+
+		lw := newLFSArchiveWriter(w, dir)
+		s.exec(lw, r, req)
+		if err := lw.Close(); err != nil {
+			log15.Error("gitserver.archive: failed to replace LFS pointers", "repo", req.Repo, "error", err)
+		}
+		return
+	}
+
 	s.exec(w, r, req)
 }
 
@@ -604,9 +685,27 @@ func (s *Server) exec(w http.ResponseWriter, r *http.Request, req *protocol.Exec
 				}
 			}
 
-			if cmdDuration > shortGitCommandSlow(req.Args) {
+			slow := cmdDuration > shortGitCommandSlow(req.Args)
+			if slow {
 				log15.Warn("Long exec request", "repo", req.Repo, "args", req.Args, "duration", cmdDuration.Round(time.Millisecond))
 			}
+
+			entry := protocol.ExecLogEntry{
+				Time:        start,
+				Repo:        req.Repo,
+				Args:        req.Args,
+				Client:      r.UserAgent(),
+				Duration:    duration,
+				CmdDuration: cmdDuration,
+				Status:      status,
+				StdoutBytes: stdoutN,
+				StderrBytes: stderrN,
+				Slow:        slow,
+			}
+			if span := opentracing.SpanFromContext(r.Context()); span != nil {
+				entry.Trace = trace.SpanURL(span)
+			}
+			s.execLog.add(entry)
 			if fetchDuration > 10*time.Second {
 				log15.Warn("Slow fetch/clone for exec request", "repo", req.Repo, "args", req.Args, "duration", fetchDuration)
 			}
@@ -695,6 +794,12 @@ func (s *Server) exec(w http.ResponseWriter, r *http.Request, req *protocol.Exec
 	cmd.Dir = string(dir)
 	cmd.Stdout = stdoutW
 	cmd.Stderr = stderrW
+	if quickIsPartialClone(dir) {
+		// Missing blobs are fetched on demand, so the command talks to the
+		// remote.
+		cmd.Env = os.Environ()
+		configureRemoteGitCommand(cmd, tlsExternal().(*tlsConfig))
+	}
 
 	exitStatus, execErr = runCommand(ctx, cmd)
 
@@ -772,6 +877,7 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, url string, o
 	}
 	defer cancel()
 	if err := s.isCloneable(ctx, url); err != nil {
+		s.setSyncError(repo, url, err)
 		return "", fmt.Errorf("error cloning repo: repo %s not cloneable: %s", repo, redactor.redact(err.Error()))
 	}
 
@@ -826,25 +932,58 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, url string, o
 		tmpPath = filepath.Join(tmpPath, ".git")
 		tmp := GitDir(tmpPath)
 
+		pr, pw := io.Pipe()
+		defer pw.Close()
+		go readCloneProgress(redactor, lock, pr)
+
+		// cmd is set if the clone doesn't use the syncer of the repo:
+		// clones with refspec overrides or a clone strategy, and fetches
+		// after a restore from the backup store.
 		var cmd *exec.Cmd
-		if useRefspecOverrides() {
+		syncer := vcsSyncer(url)
+		strategy := configuredCloneStrategy(repo)
+		switch {
+		case syncer.Type() != "git":
+			strategy = cloneStrategy{}
+		case useRefspecOverrides():
+			strategy = cloneStrategy{}
 			cmd, err = refspecOverridesCloneCmd(ctx, url, tmpPath)
 			if err != nil {
 				return err
 			}
-		} else {
-			cmd = exec.CommandContext(ctx, "git", "clone", "--mirror", "--progress", url, tmpPath)
+		case strategy != (cloneStrategy{}):
+			cmd, err = strategy.cloneCmd(ctx, url, tmpPath)
+			if err != nil {
+				return err
+			}
+		case s.BackupStore != nil:
+			// Restore the latest backup, if any, and fetch what changed
+			// since instead of cloning everything from the code host.
+			lock.SetStatus("restoring from backup")
+			if restored, err := s.restoreBackup(ctx, repo, url, tmp); err != nil {
+				log15.Warn("failed to restore repo from backup, cloning from the code host", "repo", repo, "error", err)
+				if err := os.RemoveAll(tmpPath); err != nil {
+					return err
+				}
+			} else if restored {
+				log15.Info("restored repo from backup", "repo", repo)
+				cmd, err = strategy.fetchCmd(ctx, url, tmp)
+				if err != nil {
+					return err
+				}
+				lock.SetStatus("fetching changes since backup")
+			}
 		}
-		// see issue #7322: skip LFS content in repositories with Git LFS configured
-		cmd.Env = append(os.Environ(), "GIT_LFS_SKIP_SMUDGE=1")
-		log15.Info("cloning repo", "repo", repo, "tmp", tmpPath, "dst", dstPath)
+		log15.Info("cloning repo", "repo", repo, "tmp", tmpPath, "dst", dstPath, "vcs", syncer.Type(), "strategy", strategy)
 
-		pr, pw := io.Pipe()
-		defer pw.Close()
-		go readCloneProgress(redactor, lock, pr)
-
-		if output, err := runWithRemoteOpts(ctx, cmd, pw); err != nil {
-			return errors.Wrapf(err, "clone failed. Output: %s", string(output))
+		if cmd != nil {
+			// see issue #7322: skip LFS content in repositories with Git LFS configured
+			cmd.Env = append(os.Environ(), "GIT_LFS_SKIP_SMUDGE=1")
+			if output, err := runWithRemoteOpts(ctx, cmd, pw); err != nil {
+				return errors.Wrapf(err, "clone failed. Output: %s", string(output))
+			}
+		} else if err := syncer.Clone(ctx, url, tmpPath, pw); err != nil {
+			return err
 		}
 
 		removeBadRefs(ctx, tmp)
@@ -854,6 +993,10 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, url string, o
 			return errors.Wrapf(err, "failed to update last changed time")
 		}
 
+		if err := setCloneStrategy(tmp, strategy); err != nil {
+			return err
+		}
+
 		// Set gitattributes
 		if err := setGitAttributes(tmp); err != nil {
 			return err
@@ -882,7 +1025,9 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, url string, o
 
 	if opts != nil && opts.Block {
 		// We are blocking, so use the passed in context.
-		if err := doClone(ctx); err != nil {
+		err := doClone(ctx)
+		s.setCloneError(repo, url, err)
+		if err != nil {
 			return "", errors.Wrapf(err, "failed to clone %s", repo)
 		}
 		return "", nil
@@ -892,7 +1037,9 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, url string, o
 		// Create a new context because this is in a background goroutine.
 		ctx, cancel := s.serverContext()
 		defer cancel()
-		if err := doClone(ctx); err != nil {
+		err := doClone(ctx)
+		s.setCloneError(repo, url, err)
+		if err != nil {
 			log15.Error("failed to clone repo", "repo", repo, "error", err)
 		}
 	}()
@@ -995,7 +1142,8 @@ func scanCRLF(data []byte, atEOF bool) (advance int, token []byte, err error) {
 // for isCloneable when it is set.
 var testRepoExists func(ctx context.Context, url string) error
 
-// isCloneable checks to see if the Git remote URL is cloneable.
+// isCloneable checks to see if the remote URL is cloneable with the syncer
+// of its version control system.
 func (s *Server) isCloneable(ctx context.Context, url string) error {
 	args := []string{"ls-remote", url, "HEAD"}
 	ctx, cancel := context.WithTimeout(ctx, shortGitCommandTimeout(args))
@@ -1008,18 +1156,7 @@ func (s *Server) isCloneable(ctx context.Context, url string) error {
 		return testRepoExists(ctx, url)
 	}
 
-	cmd := exec.CommandContext(ctx, "git", args...)
-	out, err := runWithRemoteOpts(ctx, cmd, nil)
-	if err != nil {
-		if ctxerr := ctx.Err(); ctxerr != nil {
-			err = ctxerr
-		}
-		if len(out) > 0 {
-			err = fmt.Errorf("%s (output follows)\n\n%s", err, out)
-		}
-		return err
-	}
-	return nil
+	return vcsSyncer(url).IsCloneable(ctx, url)
 }
 
 var (
@@ -1062,15 +1199,8 @@ func (s *Server) doRepoUpdate(ctx context.Context, repo api.RepoName, url string
 	span.SetTag("url", url)
 	defer span.Finish()
 
+	l := s.repoUpdateLocksFor(repo)
 	s.repoUpdateLocksMu.Lock()
-	l, ok := s.repoUpdateLocks[repo]
-	if !ok {
-		l = &locks{
-			once: new(sync.Once),
-			mu:   new(sync.Mutex),
-		}
-		s.repoUpdateLocks[repo] = l
-	}
 	once := l.once
 	mu := l.mu
 	s.repoUpdateLocksMu.Unlock()
@@ -1091,6 +1221,7 @@ func (s *Server) doRepoUpdate(ctx context.Context, repo api.RepoName, url string
 			s.repoUpdateLocksMu.Unlock()
 
 			err = s.doRepoUpdate2(repo, url)
+			s.setSyncError(repo, url, err)
 		})
 	}()
 
@@ -1103,6 +1234,21 @@ func (s *Server) doRepoUpdate(ctx context.Context, repo api.RepoName, url string
 	}
 }
 
+// repoUpdateLocksFor returns the locks of the updates of repo.
+func (s *Server) repoUpdateLocksFor(repo api.RepoName) *locks {
+	s.repoUpdateLocksMu.Lock()
+	defer s.repoUpdateLocksMu.Unlock()
+	l, ok := s.repoUpdateLocks[repo]
+	if !ok {
+		l = &locks{
+			once: new(sync.Once),
+			mu:   new(sync.Mutex),
+		}
+		s.repoUpdateLocks[repo] = l
+	}
+	return l
+}
+
 var (
 	badRefsOnce sync.Once
 	badRefs     []string
@@ -1311,27 +1457,7 @@ func (s *Server) doRepoUpdate2(repo api.RepoName, url string) error {
 		}
 	}
 
-	configRemoteOpts := true
-	var cmd *exec.Cmd
-	if customCmd := customFetchCmd(ctx, url); customCmd != nil {
-		cmd = customCmd
-		configRemoteOpts = false
-	} else if useRefspecOverrides() {
-		cmd = refspecOverridesFetchCmd(ctx, url)
-	} else {
-		cmd = exec.CommandContext(ctx, "git", "fetch", "--prune", url,
-			// Normal git refs
-			"+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*",
-			// GitHub pull requests
-			"+refs/pull/*:refs/pull/*",
-			// GitLab merge requests
-			"+refs/merge-requests/*:refs/merge-requests/*",
-			// Bitbucket pull requests
-			"+refs/pull-requests/*:refs/pull-requests/*",
-			// Possibly deprecated refs for sourcegraph zap experiment?
-			"+refs/sourcegraph/*:refs/sourcegraph/*")
-	}
-	cmd.Dir = string(dir)
+	syncer := vcsSyncer(url)
 
 	// drop temporary pack files after a fetch. this function won't
 	// return until this fetch has completed or definitely-failed,
@@ -1339,8 +1465,8 @@ func (s *Server) doRepoUpdate2(repo api.RepoName, url string) error {
 	// when the cleanup happens, just that it does.
 	defer s.cleanTmpFiles(dir)
 
-	if output, err := runWith(ctx, cmd, configRemoteOpts, nil); err != nil {
-		log15.Error("Failed to update", "repo", repo, "error", err, "output", string(output))
+	if err := syncer.Fetch(ctx, url, dir); err != nil {
+		log15.Error("Failed to update", "repo", repo, "vcs", syncer.Type(), "error", err)
 		return errors.Wrap(err, "failed to update")
 	}
 
@@ -1351,26 +1477,18 @@ func (s *Server) doRepoUpdate2(repo api.RepoName, url string) error {
 		log15.Warn("Failed to update last changed time", "repo", repo, "error", err)
 	}
 
-	headBranch := "master"
-
 	// try to fetch HEAD from origin
-	cmd = exec.CommandContext(ctx, "git", "remote", "show", url)
-	cmd.Dir = path.Join(s.ReposDir, string(repo))
-	output, err := runWithRemoteOpts(ctx, cmd, nil)
+	headBranch, err := syncer.RemoteHeadBranch(ctx, url, dir)
 	if err != nil {
-		log15.Error("Failed to fetch remote info", "repo", repo, "error", err, "output", string(output))
+		log15.Error("Failed to fetch remote info", "repo", repo, "error", err)
 		return errors.Wrap(err, "failed to fetch remote info")
 	}
-	submatches := headBranchPattern.FindSubmatch(output)
-	if len(submatches) == 2 {
-		submatch := string(submatches[1])
-		if submatch != "(unknown)" {
-			headBranch = string(submatch)
-		}
+	if headBranch == "" {
+		headBranch = "master"
 	}
 
 	// check if branch pointed to by HEAD exists
-	cmd = exec.CommandContext(ctx, "git", "rev-parse", headBranch, "--")
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", headBranch, "--")
 	cmd.Dir = path.Join(s.ReposDir, string(repo))
 	if err := cmd.Run(); err != nil {
 		// branch does not exist, pick first branch
@@ -1378,7 +1496,7 @@ func (s *Server) doRepoUpdate2(repo api.RepoName, url string) error {
 		cmd.Dir = path.Join(s.ReposDir, string(repo))
 		list, err := cmd.Output()
 		if err != nil {
-			log15.Error("Failed to list branches", "repo", repo, "error", err, "output", string(output))
+			log15.Error("Failed to list branches", "repo", repo, "error", err)
 			return errors.Wrap(err, "failed to list branches")
 		}
 		lines := strings.Split(string(list), "\n")
@@ -1398,6 +1516,21 @@ func (s *Server) doRepoUpdate2(repo api.RepoName, url string) error {
 	return nil
 }
 
+// fetchRefspecs are the refspecs fetched by repository updates of full
+// clones.
+var fetchRefspecs = []string{
+	// Normal git refs
+	"+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*",
+	// GitHub pull requests
+	"+refs/pull/*:refs/pull/*",
+	// GitLab merge requests
+	"+refs/merge-requests/*:refs/merge-requests/*",
+	// Bitbucket pull requests
+	"+refs/pull-requests/*:refs/pull-requests/*",
+	// Possibly deprecated refs for sourcegraph zap experiment?
+	"+refs/sourcegraph/*:refs/sourcegraph/*",
+}
+
 func (s *Server) ensureRevision(ctx context.Context, repo api.RepoName, url, rev string, repoDir GitDir) (didUpdate bool) {
 	if rev == "" || rev == "HEAD" {
 		return false
@@ -0,0 +1,161 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestVCSSyncer(t *testing.T) {
+	for url, want := range map[string]string{
+		"https://github.com/foo/bar":         "git",
+		"ssh://git@example.com/foo/bar.git":  "git",
+		"perforce://admin:pw@p4:1666/depot":  "perforce",
+		"perforce+ssl://p4:1666/depot/foo":   "perforce",
+		"hg+https://hg.example.com/foo":      "mercurial",
+		"hg+ssh://hg@hg.example.com/foo/bar": "mercurial",
+	} {
+		if got := vcsSyncer(url).Type(); got != want {
+			t.Errorf("%s: got VCS %q, want %q", url, got, want)
+		}
+	}
+}
+
+func TestPerforceDepotEnv(t *testing.T) {
+	depot, env, err := perforceDepotEnv("perforce+ssl://admin:pw@p4.example.com:1666/depot/foo/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "//depot/foo"; depot != want {
+		t.Errorf("got depot %q, want %q", depot, want)
+	}
+	env = env[len(os.Environ()):]
+	if want := []string{"P4PORT=ssl:p4.example.com:1666", "P4USER=admin", "P4PASSWD=pw"}; !reflect.DeepEqual(env, want) {
+		t.Errorf("got env %q, want %q", env, want)
+	}
+
+	if _, _, err := perforceDepotEnv("perforce://p4.example.com:1666"); err == nil {
+		t.Error("expected an error for a URL without a depot")
+	}
+}
+
+func TestGitRepoSyncer(t *testing.T) {
+	remote := tmpDir(t)
+	defer os.RemoveAll(remote)
+	runCmd(t, remote, "git", "init", ".")
+	runCmd(t, remote, "git", "checkout", "-b", "main")
+	runCmd(t, remote, "git", "commit", "--allow-empty", "-m", "a")
+
+	ctx := context.Background()
+	s := vcsSyncer(remote)
+	if err := s.IsCloneable(ctx, remote); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.IsCloneable(ctx, filepath.Join(remote, "missing")); err == nil {
+		t.Error("expected a missing repo not to be cloneable")
+	}
+
+	tmp := tmpDir(t)
+	defer os.RemoveAll(tmp)
+	dir := GitDir(filepath.Join(tmp, ".git"))
+	if err := s.Clone(ctx, remote, string(dir), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	runCmd(t, remote, "git", "commit", "--allow-empty", "-m", "b")
+	if err := s.Fetch(ctx, remote, dir); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.TrimSpace(runCmd(t, remote, "git", "rev-parse", "HEAD"))
+	if got := strings.TrimSpace(runCmd(t, string(dir), "git", "rev-parse", "main")); got != want {
+		t.Errorf("got main at %s after fetch, want %s", got, want)
+	}
+
+	if branch, err := s.RemoteHeadBranch(ctx, remote, dir); err != nil || branch != "main" {
+		t.Errorf("got remote HEAD branch %q (%v), want main", branch, err)
+	}
+}
+
+// fakeCommands puts scripts named after the commands on PATH, which log their
+// arguments and environment to the returned file and succeed.
+func fakeCommands(t *testing.T, names ...string) (log string, cleanup func()) {
+	t.Helper()
+	bin := tmpDir(t)
+	log = filepath.Join(bin, "log")
+	for _, name := range names {
+		script := "#!/bin/sh\necho \"$(basename \"$0\") $* HGPLAIN=$HGPLAIN P4PORT=$P4PORT\" >> " + log + "\n"
+		if err := ioutil.WriteFile(filepath.Join(bin, name), []byte(script), 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := os.Getenv("PATH")
+	os.Setenv("PATH", bin+string(os.PathListSeparator)+path)
+	return log, func() {
+		os.Setenv("PATH", path)
+		os.RemoveAll(bin)
+	}
+}
+
+func readLog(t *testing.T, log string) []string {
+	t.Helper()
+	b, err := ioutil.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(string(b)), "\n")
+}
+
+func TestMercurialRepoSyncer(t *testing.T) {
+	log, cleanup := fakeCommands(t, "hg", "hg-fast-export.sh")
+	defer cleanup()
+
+	ctx := context.Background()
+	remote := "hg+ssh://hg@hg.example.com/foo"
+	s := vcsSyncer(remote)
+	if err := s.IsCloneable(ctx, remote); err != nil {
+		t.Fatal(err)
+	}
+
+	tmp := tmpDir(t)
+	defer os.RemoveAll(tmp)
+	dir := GitDir(filepath.Join(tmp, ".git"))
+	if err := s.Clone(ctx, remote, string(dir), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Fetch(ctx, remote, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := "--noninteractive --config ui.ssh=ssh -o BatchMode=yes -o ConnectTimeout=30"
+	want := []string{
+		"hg " + opts + " identify -- ssh://hg@hg.example.com/foo HGPLAIN=1 P4PORT=",
+		"hg " + opts + " clone --noupdate -- ssh://hg@hg.example.com/foo " + dir.Path("hg") + " HGPLAIN=1 P4PORT=",
+		"hg-fast-export.sh -r " + dir.Path("hg") + " --force HGPLAIN= P4PORT=",
+		"hg " + opts + " pull --repository " + dir.Path("hg") + " -- ssh://hg@hg.example.com/foo HGPLAIN=1 P4PORT=",
+		"hg-fast-export.sh -r " + dir.Path("hg") + " --force HGPLAIN= P4PORT=",
+	}
+	if got := readLog(t, log); !reflect.DeepEqual(got, want) {
+		t.Errorf("got commands\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if url, err := repoRemoteURL(ctx, dir); err != nil || url != remote {
+		t.Errorf("got remote URL %q (%v), want %q", url, err, remote)
+	}
+}
+
+func TestPerforceDepotSyncer_IsCloneable(t *testing.T) {
+	log, cleanup := fakeCommands(t, "p4")
+	defer cleanup()
+
+	remote := "perforce://admin:pw@p4.example.com:1666/depot/foo"
+	if err := vcsSyncer(remote).IsCloneable(context.Background(), remote); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"p4 files -m 1 //depot/foo/... HGPLAIN= P4PORT=p4.example.com:1666"}
+	if got := readLog(t, log); !reflect.DeepEqual(got, want) {
+		t.Errorf("got commands %q, want %q", got, want)
+	}
+}
@@ -96,6 +96,11 @@ func (s BitbucketCloudSource) makeRepo(r *bitbucketcloud.Repo) *Repo {
 	}
 	host = extsvc.NormalizeBaseURL(host)
 
+	var defaultBranch string
+	if r.MainBranch != nil {
+		defaultBranch = r.MainBranch.Name
+	}
+
 	urn := s.svc.URN()
 	return &Repo{
 		Name: string(reposource.BitbucketCloudRepoName(
@@ -113,9 +118,11 @@ func (s BitbucketCloudSource) makeRepo(r *bitbucketcloud.Repo) *Repo {
 			ServiceType: extsvc.TypeBitbucketCloud,
 			ServiceID:   host.String(),
 		},
-		Description: r.Description,
-		Fork:        r.Parent != nil,
-		Private:     r.IsPrivate,
+		Description:   r.Description,
+		Language:      r.Language,
+		Fork:          r.Parent != nil,
+		Private:       r.IsPrivate,
+		DefaultBranch: defaultBranch,
 		Sources: map[string]*SourceInfo{
 			urn: {
 				ID:       urn,
@@ -229,6 +229,92 @@ func (s BitbucketServerSource) UpdateChangeset(ctx context.Context, c *Changeset
 	return nil
 }
 
+// repoForEvent looks up the repository by its "PROJECT/slug" name, since the
+// Bitbucket Server API can't look up repositories by ID.
+func (s BitbucketServerSource) repoForEvent(ctx context.Context, ev RepoEvent) (*Repo, error) {
+	ps := strings.SplitN(ev.Name, "/", 2)
+	if len(ps) != 2 {
+		return nil, errors.Errorf("invalid Bitbucket Server repository name %q", ev.Name)
+	}
+
+	repo, err := s.client.Repo(ctx, ps[0], ps[1])
+	if bitbucketserver.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if s.excludes(repo) {
+		return nil, nil
+	}
+
+	if ok, err := s.includes(ctx, repo); err != nil || !ok {
+		return nil, err
+	}
+
+	archived, err := s.listAllLabeledRepos(ctx, "archived")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list repos with archived label")
+	}
+
+	_, isArchived := archived[repo.ID]
+	return s.makeRepo(repo, isArchived), nil
+}
+
+// includes reports whether the repository is selected by the repos or
+// repositoryQuery configuration of the source, which determine the
+// repositories that ListRepos yields.
+func (s BitbucketServerSource) includes(ctx context.Context, repo *bitbucketserver.Repo) (bool, error) {
+	var projectKey string
+	if repo.Project != nil {
+		projectKey = repo.Project.Key
+	}
+
+	for _, name := range s.config.Repos {
+		if strings.EqualFold(name, projectKey+"/"+repo.Slug) {
+			return true, nil
+		}
+	}
+
+	for _, q := range s.config.RepositoryQuery {
+		switch q {
+		case "none":
+			continue
+		case "all":
+			return true, nil
+		}
+
+		// Narrow the query down to the repository to find out whether it
+		// matches it, unless it already filters by name or project.
+		filters, err := url.ParseQuery(strings.TrimPrefix(q, "?"))
+		if err != nil {
+			return false, errors.Wrapf(err, "bitbucketserver.repositoryQuery: query=%q", q)
+		}
+		if filters.Get("name") == "" {
+			filters.Set("name", repo.Name)
+		}
+		if filters.Get("projectname") == "" && repo.Project != nil {
+			filters.Set("projectname", repo.Project.Name)
+		}
+
+		next := &bitbucketserver.PageToken{Limit: 1000}
+		for next.HasMore() {
+			repos, page, err := s.client.Repos(ctx, next, filters.Encode())
+			if err != nil {
+				return false, errors.Wrapf(err, "bitbucketserver.repositoryQuery: query=%q, page=%+v", q, next)
+			}
+			for _, r := range repos {
+				if r.ID == repo.ID {
+					return true, nil
+				}
+			}
+			next = page
+		}
+	}
+
+	return false, nil
+}
+
 // ExternalServices returns a singleton slice containing the external service.
 func (s BitbucketServerSource) ExternalServices() ExternalServices {
 	return ExternalServices{s.svc}
@@ -13,3 +13,13 @@ func GetUpdateInterval() time.Duration {
 	}
 	return time.Duration(v) * time.Minute
 }
+
+// GetPurgeGracePeriod returns the time that the clones of deleted repositories
+// are kept on gitserver before they are purged.
+func GetPurgeGracePeriod() time.Duration {
+	v := conf.Get().RepoPurgeGracePeriod
+	if v <= 0 { // default to one week
+		v = 7 * 24
+	}
+	return time.Duration(v) * time.Hour
+}
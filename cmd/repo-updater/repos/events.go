@@ -0,0 +1,139 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// Actions of a RepoEvent.
+const (
+	RepoCreated           = "created"
+	RepoRenamed           = "renamed"
+	RepoTransferred       = "transferred"
+	RepoDeleted           = "deleted"
+	RepoArchived          = "archived"
+	RepoUnarchived        = "unarchived"
+	RepoVisibilityChanged = "visibility_changed"
+	RepoEdited            = "edited"
+)
+
+// A RepoEvent is a change to a single repository that a code host reported,
+// typically through a webhook.
+type RepoEvent struct {
+	// Action is one of the RepoEvent actions above.
+	Action string
+	// ExternalRepo identifies the repository on the code host. Only its ID
+	// and ServiceType need to be set, the ServiceID is derived from the
+	// external service the event was received for.
+	ExternalRepo api.ExternalRepoSpec
+	// Name is the name of the repository on the code host after the event,
+	// in the form that the code host's API expects it in (e.g. "owner/name").
+	Name string
+}
+
+// A repoEventSource is a Source that can look up the current state of a single
+// repository it yields, so that RepoEvents can be applied without listing all
+// of its repositories.
+type repoEventSource interface {
+	Source
+	// repoForEvent returns the current state of the repository the event is
+	// about, or nil if it doesn't exist anymore or isn't one of the
+	// repositories that the configuration of the source selects.
+	repoForEvent(ctx context.Context, ev RepoEvent) (*Repo, error)
+}
+
+// SyncRepoEvents applies the given events, which were received for the given
+// external service, to the stored repositories. The repositories are looked
+// up individually on the code host, so that renames, visibility changes and
+// deletions are reflected without waiting for the next full Sync.
+func (s *Syncer) SyncRepoEvents(ctx context.Context, svc *ExternalService, events ...RepoEvent) (err error) {
+	var diff Diff
+
+	ctx, save := s.observe(ctx, "Syncer.SyncRepoEvents", svc.URN())
+	defer save(&diff, &err)
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	srcs, err := s.Sourcer(svc)
+	if err != nil {
+		return errors.Wrap(err, "syncer.sync-repo-events.sourcer")
+	}
+	if len(srcs) != 1 {
+		return errors.Errorf("syncer.sync-repo-events: external service %d yielded %d sources", svc.ID, len(srcs))
+	}
+
+	src := srcs[0]
+	if o, ok := src.(*observedSource); ok {
+		src = o.Source
+	}
+
+	es, ok := src.(repoEventSource)
+	if !ok {
+		return errors.Errorf("syncer.sync-repo-events: repository events are not supported for %s external services", svc.Kind)
+	}
+
+	baseURL, err := svc.BaseURL()
+	if err != nil {
+		return errors.Wrap(err, "syncer.sync-repo-events.base-url")
+	}
+
+	specs := make([]api.ExternalRepoSpec, 0, len(events))
+	for _, ev := range events {
+		spec := ev.ExternalRepo
+		spec.ServiceID = baseURL.String()
+		specs = append(specs, spec)
+	}
+
+	stored, err := s.Store.ListRepos(ctx, StoreListReposArgs{ExternalRepos: specs})
+	if err != nil {
+		return errors.Wrap(err, "syncer.sync-repo-events.store.list-repos")
+	}
+
+	byID := make(map[api.ExternalRepoSpec]*Repo, len(stored))
+	for _, r := range stored {
+		byID[r.ExternalRepo] = r
+	}
+
+	urn := svc.URN()
+	sourced := make([]*Repo, 0, len(events))
+	for i, ev := range events {
+		var r *Repo
+		if ev.Action != RepoDeleted {
+			if r, err = es.repoForEvent(ctx, ev); err != nil {
+				return errors.Wrapf(err, "syncer.sync-repo-events.source: action=%q, repo=%q", ev.Action, ev.Name)
+			}
+		}
+
+		old := byID[specs[i]]
+		switch {
+		case r != nil && old != nil:
+			// Other external services may yield the same repository, their
+			// sources must be kept.
+			for id, info := range old.Sources {
+				if id != urn {
+					r.Sources[id] = info
+				}
+			}
+		case r == nil && old != nil:
+			// The repository is gone from this external service. syncSubset
+			// deletes it unless another external service still yields it.
+			r = old.Clone()
+			delete(r.Sources, urn)
+		case r == nil:
+			continue
+		}
+
+		sourced = append(sourced, r)
+	}
+
+	if len(sourced) == 0 {
+		return nil
+	}
+
+	diff, err = s.syncSubset(ctx, false, sourced...)
+	return err
+}
@@ -269,6 +269,95 @@ func (s GithubSource) GetRepo(ctx context.Context, nameWithOwner string) (*Repo,
 	return s.makeRepo(r), nil
 }
 
+// repoForEvent looks up the repository by its GraphQL node ID, bypassing the
+// cache since the event most likely changed it.
+func (s GithubSource) repoForEvent(ctx context.Context, ev RepoEvent) (*Repo, error) {
+	r, err := s.client.GetRepositoryByNodeIDNoCache(ctx, ev.ExternalRepo.ID)
+	if github.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if s.excludes(r) {
+		return nil, nil
+	}
+
+	if ok, err := s.includes(ctx, r); err != nil || !ok {
+		return nil, err
+	}
+	return s.makeRepo(r), nil
+}
+
+// includes reports whether the repository is selected by the repos, orgs or
+// repositoryQuery configuration of the source, which determine the
+// repositories that ListRepos yields.
+func (s GithubSource) includes(ctx context.Context, r *github.Repository) (bool, error) {
+	for _, name := range s.config.Repos {
+		if strings.EqualFold(name, r.NameWithOwner) {
+			return true, nil
+		}
+	}
+
+	owner, _, err := github.SplitRepositoryNameWithOwner(r.NameWithOwner)
+	if err != nil {
+		return false, err
+	}
+
+	for _, org := range s.config.Orgs {
+		if strings.EqualFold(org, owner) {
+			return true, nil
+		}
+	}
+
+	for _, query := range s.config.RepositoryQuery {
+		switch query {
+		case "none":
+			continue
+		case "public":
+			if !s.githubDotCom && !r.IsPrivate {
+				return true, nil
+			}
+			continue
+		case "affiliated":
+			// Listing all affiliated repositories is too expensive for a
+			// single repository. Private repositories are only visible to
+			// affiliated tokens, as are public repositories the token can
+			// write to. Other affiliated repositories are left to the next
+			// full sync.
+			switch r.ViewerPermission {
+			case "ADMIN", "MAINTAIN", "WRITE", "TRIAGE":
+				return true, nil
+			}
+			if r.IsPrivate {
+				return true, nil
+			}
+			continue
+		}
+
+		if org := matchOrg(query); org != "" {
+			if strings.EqualFold(org, owner) {
+				return true, nil
+			}
+			continue
+		}
+
+		// Narrow the search down to the repository to find out whether the
+		// query matches it.
+		page, err := s.searchClient.ListRepositoriesForSearch(ctx, query+" repo:"+r.NameWithOwner, 1)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to search GitHub repositories: searchString=%q", query)
+		}
+		for _, sr := range page.Repos {
+			if sr.ID == r.ID {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
 func (s GithubSource) makeRepo(r *github.Repository) *Repo {
 	urn := s.svc.URN()
 	return &Repo{
@@ -282,11 +371,15 @@ func (s GithubSource) makeRepo(r *github.Repository) *Repo {
 			s.originalHostname,
 			r.NameWithOwner,
 		)),
-		ExternalRepo: github.ExternalRepoSpec(r, *s.baseURL),
-		Description:  r.Description,
-		Fork:         r.IsFork,
-		Archived:     r.IsArchived,
-		Private:      r.IsPrivate,
+		ExternalRepo:  github.ExternalRepoSpec(r, *s.baseURL),
+		Description:   r.Description,
+		Language:      r.Language(),
+		Fork:          r.IsFork,
+		Archived:      r.IsArchived,
+		Private:       r.IsPrivate,
+		Topics:        r.Topics(),
+		Stars:         r.Stars(),
+		DefaultBranch: r.DefaultBranch(),
 		Sources: map[string]*SourceInfo{
 			urn: {
 				ID:       urn,
@@ -526,6 +526,83 @@ func TestMatchOrg(t *testing.T) {
 	}
 }
 
+func TestGithubSource_includes(t *testing.T) {
+	repo := &github.Repository{ID: "MDEw", NameWithOwner: "org/repo"}
+	privateRepo := &github.Repository{ID: "MDEx", NameWithOwner: "org/private", IsPrivate: true}
+
+	for _, tc := range []struct {
+		name   string
+		config *schema.GitHubConnection
+		repo   *github.Repository
+		want   bool
+	}{
+		{
+			name:   "repos",
+			config: &schema.GitHubConnection{Repos: []string{"Org/Repo"}},
+			repo:   repo,
+			want:   true,
+		},
+		{
+			name:   "other repos",
+			config: &schema.GitHubConnection{Repos: []string{"org/other"}},
+			repo:   repo,
+			want:   false,
+		},
+		{
+			name:   "orgs",
+			config: &schema.GitHubConnection{Orgs: []string{"org"}},
+			repo:   repo,
+			want:   true,
+		},
+		{
+			name:   "other orgs",
+			config: &schema.GitHubConnection{Orgs: []string{"other"}},
+			repo:   repo,
+			want:   false,
+		},
+		{
+			name:   "org query",
+			config: &schema.GitHubConnection{RepositoryQuery: []string{"none", "org:org"}},
+			repo:   repo,
+			want:   true,
+		},
+		{
+			name:   "affiliated private",
+			config: &schema.GitHubConnection{RepositoryQuery: []string{"affiliated"}},
+			repo:   privateRepo,
+			want:   true,
+		},
+		{
+			name:   "public on github.com",
+			config: &schema.GitHubConnection{RepositoryQuery: []string{"public"}},
+			repo:   repo,
+			want:   false,
+		},
+		{
+			name:   "nothing",
+			config: &schema.GitHubConnection{RepositoryQuery: []string{"none"}},
+			repo:   repo,
+			want:   false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.config.Url = "https://github.com"
+			s, err := newGithubSource(&ExternalService{Kind: extsvc.KindGitHub}, tc.config, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := s.includes(context.Background(), tc.repo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
 func TestGithubSource_ListRepos(t *testing.T) {
 	assertAllReposListed := func(want []string) ReposAssertion {
 		return func(t testing.TB, rs Repos) {
@@ -106,6 +106,91 @@ func (s GitLabSource) GetRepo(ctx context.Context, pathWithNamespace string) (*R
 	return s.makeRepo(proj), nil
 }
 
+// repoForEvent looks up the project by its ID, which doesn't change when the
+// project is renamed or transferred.
+func (s GitLabSource) repoForEvent(ctx context.Context, ev RepoEvent) (*Repo, error) {
+	id, err := strconv.Atoi(ev.ExternalRepo.ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid GitLab project ID %q", ev.ExternalRepo.ID)
+	}
+
+	proj, err := s.client.GetProject(ctx, gitlab.GetProjectOp{
+		ID:       id,
+		CommonOp: gitlab.CommonOp{NoCache: true},
+	})
+	if gitlab.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if s.excludes(proj) {
+		return nil, nil
+	}
+
+	if ok, err := s.includes(ctx, proj); err != nil || !ok {
+		return nil, err
+	}
+	return s.makeRepo(proj), nil
+}
+
+// includes reports whether the project is selected by the projects or
+// projectQuery configuration of the source, which determine the projects that
+// ListRepos yields.
+func (s GitLabSource) includes(ctx context.Context, proj *gitlab.Project) (bool, error) {
+	for _, p := range s.config.Projects {
+		if p.Id == proj.ID || (p.Name != "" && strings.EqualFold(p.Name, proj.PathWithNamespace)) {
+			return true, nil
+		}
+	}
+
+	for _, projectQuery := range s.config.ProjectQuery {
+		if projectQuery == "none" {
+			continue
+		}
+
+		u, err := projectQueryToURL(projectQuery, 100)
+		if err != nil {
+			return false, errors.Wrapf(err, "invalid GitLab projectQuery=%q", projectQuery)
+		}
+
+		// Narrow the query down to the project to find out whether it matches
+		// it. Endpoints that don't support the ID filters return the first
+		// page of their projects, in which case the project is only found if
+		// it's on that page, and otherwise left to the next full sync.
+		u, err = withProjectIDFilter(u, proj.ID)
+		if err != nil {
+			return false, err
+		}
+
+		projs, _, err := s.client.ListProjects(ctx, u)
+		if err != nil {
+			return false, errors.Wrapf(err, "error listing GitLab projects: url=%q", u)
+		}
+		for _, p := range projs {
+			if p.ID == proj.ID {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// withProjectIDFilter adds the id_after and id_before filters that only match
+// the project with the given ID to the projects URL.
+func withProjectIDFilter(rawURL string, id int) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("id_after", strconv.Itoa(id-1))
+	q.Set("id_before", strconv.Itoa(id+1))
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
 // ExternalServices returns a singleton slice containing the external service.
 func (s GitLabSource) ExternalServices() ExternalServices {
 	return ExternalServices{s.svc}
@@ -126,11 +211,14 @@ func (s GitLabSource) makeRepo(proj *gitlab.Project) *Repo {
 			proj.PathWithNamespace,
 			s.nameTransformations,
 		)),
-		ExternalRepo: gitlab.ExternalRepoSpec(proj, *s.baseURL),
-		Description:  proj.Description,
-		Fork:         proj.ForkedFromProject != nil,
-		Archived:     proj.Archived,
-		Private:      proj.Visibility == "private",
+		ExternalRepo:  gitlab.ExternalRepoSpec(proj, *s.baseURL),
+		Description:   proj.Description,
+		Fork:          proj.ForkedFromProject != nil,
+		Archived:      proj.Archived,
+		Private:       proj.Visibility == "private",
+		Topics:        proj.TagList,
+		Stars:         proj.StarCount,
+		DefaultBranch: proj.DefaultBranch,
 		Sources: map[string]*SourceInfo{
 			urn: {
 				ID:       urn,
@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -213,3 +214,73 @@ func TestGitLabSource_makeRepo(t *testing.T) {
 		})
 	}
 }
+
+func TestGitLabSource_includes(t *testing.T) {
+	var gotURLs []string
+	gitlab.MockListProjects = func(c *gitlab.Client, ctx context.Context, urlStr string) ([]*gitlab.Project, *string, error) {
+		gotURLs = append(gotURLs, urlStr)
+		if strings.HasPrefix(urlStr, "groups/group/projects?") {
+			return []*gitlab.Project{{ProjectCommon: gitlab.ProjectCommon{ID: 42}}}, nil, nil
+		}
+		return nil, nil, nil
+	}
+	defer func() { gitlab.MockListProjects = nil }()
+
+	proj := &gitlab.Project{ProjectCommon: gitlab.ProjectCommon{ID: 42, PathWithNamespace: "group/project"}}
+
+	for _, tc := range []struct {
+		name   string
+		config *schema.GitLabConnection
+		want   bool
+	}{
+		{
+			name:   "project name",
+			config: &schema.GitLabConnection{Projects: []*schema.GitLabProject{{Name: "Group/Project"}}},
+			want:   true,
+		},
+		{
+			name:   "project id",
+			config: &schema.GitLabConnection{Projects: []*schema.GitLabProject{{Id: 42}}},
+			want:   true,
+		},
+		{
+			name:   "other project",
+			config: &schema.GitLabConnection{Projects: []*schema.GitLabProject{{Name: "group/other"}}},
+			want:   false,
+		},
+		{
+			name:   "matching project query",
+			config: &schema.GitLabConnection{ProjectQuery: []string{"none", "groups/group/projects"}},
+			want:   true,
+		},
+		{
+			name:   "other project query",
+			config: &schema.GitLabConnection{ProjectQuery: []string{"?membership=true"}},
+			want:   false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.config.Url = "https://gitlab.com"
+			s, err := newGitLabSource(&ExternalService{Kind: extsvc.KindGitLab}, tc.config, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := s.includes(context.Background(), proj)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	want := []string{
+		"groups/group/projects?id_after=41&id_before=43&per_page=100",
+		"projects?id_after=41&id_before=43&membership=true&per_page=100",
+	}
+	if !reflect.DeepEqual(gotURLs, want) {
+		t.Errorf("listed projects:\n%s", cmp.Diff(want, gotURLs))
+	}
+}
@@ -0,0 +1,185 @@
+package repos
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/ghodss/yaml"
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// maxManifestSize is the maximum size of a manifest.
+const maxManifestSize = 64 * 1024 * 1024
+
+// A ManifestSource yields the repositories listed in a manifest, which is
+// fetched from a URL or read from a file in a repository on every sync. The
+// listed repositories are cloned like the ones of an OtherSource.
+type ManifestSource struct {
+	svc    *ExternalService
+	conn   *schema.ManifestConnection
+	client httpcli.Doer
+}
+
+// NewManifestSource returns a new ManifestSource from the given external service.
+func NewManifestSource(svc *ExternalService, cf *httpcli.Factory) (*ManifestSource, error) {
+	var c schema.ManifestConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, errors.Wrapf(err, "external service id=%d config error", svc.ID)
+	}
+
+	if cf == nil {
+		cf = httpcli.NewExternalHTTPClientFactory()
+	}
+
+	cli, err := cf.Doer()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ManifestSource{svc: svc, conn: &c, client: cli}, nil
+}
+
+// manifest is the document that lists the repositories of a ManifestSource.
+// Both JSON and YAML are accepted.
+type manifest struct {
+	Repos []struct {
+		URL         string `json:"url"`
+		Name        string `json:"name,omitempty"`
+		Description string `json:"description,omitempty"`
+		Private     bool   `json:"private,omitempty"`
+	} `json:"repos"`
+}
+
+// ListRepos returns all repositories listed in the manifest.
+func (s ManifestSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	m, err := s.manifest(ctx)
+	if err != nil {
+		results <- SourceResult{Source: s, Err: err}
+		return
+	}
+
+	urn := s.svc.URN()
+	for i, e := range m.Repos {
+		// Invalid entries are skipped, so that a single one doesn't remove
+		// all the other repositories of the manifest.
+		u, err := url.Parse(e.URL)
+		if err == nil && e.URL == "" {
+			err = errors.New("missing url")
+		}
+		if err != nil {
+			log15.Warn("skipping invalid manifest entry", "externalService", s.svc.ID, "entry", i, "error", err)
+			continue
+		}
+
+		// Each repository is cloned like one of an OtherSource whose clone
+		// base URL is the code host of the repository.
+		base := url.URL{Scheme: u.Scheme, User: u.User, Host: u.Host}
+		other := OtherSource{
+			svc: s.svc,
+			conn: &schema.OtherExternalServiceConnection{
+				Url:                   base.String(),
+				RepositoryPathPattern: s.conn.RepositoryPathPattern,
+			},
+		}
+
+		r, err := other.otherRepoFromCloneURL(urn, u)
+		if err != nil {
+			log15.Warn("skipping invalid manifest entry", "externalService", s.svc.ID, "entry", i, "error", err)
+			continue
+		}
+
+		// The external ID stays derived from the clone URL, so that renaming
+		// a repository in the manifest renames it on Sourcegraph too.
+		if e.Name != "" {
+			r.Name = e.Name
+		}
+		r.Description = e.Description
+		r.Private = e.Private
+
+		results <- SourceResult{Source: s, Repo: r}
+	}
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s ManifestSource) ExternalServices() ExternalServices {
+	return ExternalServices{s.svc}
+}
+
+func (s ManifestSource) manifest(ctx context.Context) (*manifest, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if s.conn.Url != "" {
+		data, err = s.fetchManifest(ctx)
+	} else {
+		data, err = s.readManifest(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// YAML is a superset of JSON, so this decodes both.
+	var m manifest
+	if err = yaml.Unmarshal(data, &m); err != nil {
+		return nil, errors.Wrap(err, "failed to decode manifest")
+	}
+	return &m, nil
+}
+
+func (s ManifestSource) fetchManifest(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequest("GET", s.conn.Url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if s.conn.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.conn.Token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected response status %d when fetching manifest", resp.StatusCode)
+	}
+
+	b, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxManifestSize+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read manifest")
+	}
+	if len(b) > maxManifestSize {
+		return nil, errors.Errorf("manifest is larger than %d bytes", maxManifestSize)
+	}
+	return b, nil
+}
+
+// readManifest reads the manifest from a file in a repository that is cloned
+// on gitserver.
+func (s ManifestSource) readManifest(ctx context.Context) ([]byte, error) {
+	repo := gitserver.Repo{Name: api.RepoName(s.conn.Repository)}
+
+	commit, err := git.ResolveRevision(ctx, repo, nil, s.conn.Revision, &git.ResolveRevisionOptions{NoEnsureRevision: true})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve revision %q of manifest repository %q", s.conn.Revision, s.conn.Repository)
+	}
+
+	b, err := git.ReadFile(ctx, repo, commit, s.conn.Path, maxManifestSize)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read manifest %q from repository %q", s.conn.Path, s.conn.Repository)
+	}
+	return b, nil
+}
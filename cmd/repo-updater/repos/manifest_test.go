@@ -0,0 +1,143 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+func TestManifestSource_ListRepos(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/repos.json":
+			fmt.Fprint(w, `{"repos": [
+				{"url": "https://git.mycorp.com/foo/bar.git", "description": "Bar", "private": true},
+				{"url": "ssh://git@git.mycorp.com/foo/baz", "name": "mycorp/baz"}
+			]}`)
+		case "/repos.yaml":
+			fmt.Fprint(w, "repos:\n- url: https://git.mycorp.com/foo/bar.git\n  description: Bar\n  private: true\n")
+		case "/invalid.json":
+			fmt.Fprint(w, `{"repos": [{"name": "foo"}, {"url": "https://git.mycorp.com/foo/bar.git", "description": "Bar", "private": true}]}`)
+		case "/large.json":
+			_, _ = io.CopyN(w, strings.NewReader(strings.Repeat(" ", maxManifestSize+1)), maxManifestSize+1)
+		default:
+			http.Error(w, r.URL.String()+" not found", http.StatusNotFound)
+		}
+	}))
+	defer s.Close()
+
+	bar := &Repo{
+		Name:        "git.mycorp.com/foo/bar",
+		URI:         "git.mycorp.com/foo/bar",
+		Description: "Bar",
+		Private:     true,
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          "git.mycorp.com/foo/bar",
+			ServiceType: extsvc.TypeOther,
+			ServiceID:   "https://git.mycorp.com",
+		},
+		Sources: map[string]*SourceInfo{
+			"extsvc:manifest:1": {
+				ID:       "extsvc:manifest:1",
+				CloneURL: "https://git.mycorp.com/foo/bar.git",
+			},
+		},
+	}
+	baz := &Repo{
+		Name: "mycorp/baz",
+		URI:  "git.mycorp.com/foo/baz",
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          "git.mycorp.com/foo/baz",
+			ServiceType: extsvc.TypeOther,
+			ServiceID:   "ssh://git@git.mycorp.com",
+		},
+		Sources: map[string]*SourceInfo{
+			"extsvc:manifest:1": {
+				ID:       "extsvc:manifest:1",
+				CloneURL: "ssh://git@git.mycorp.com/foo/baz",
+			},
+		},
+	}
+
+	git.Mocks.ResolveRevision = func(spec string, opt *git.ResolveRevisionOptions) (api.CommitID, error) {
+		if spec != "master" {
+			return "", fmt.Errorf("unexpected revision %q", spec)
+		}
+		return "deadbeef", nil
+	}
+	git.Mocks.ReadFile = func(commit api.CommitID, name string) ([]byte, error) {
+		if commit != "deadbeef" || name != "repos.yaml" {
+			return nil, fmt.Errorf("unexpected file %s:%s", commit, name)
+		}
+		return []byte("repos:\n- url: ssh://git@git.mycorp.com/foo/baz\n  name: mycorp/baz\n"), nil
+	}
+	defer git.ResetMocks()
+
+	for _, tc := range []struct {
+		name   string
+		config string
+		want   []*Repo
+		err    string
+	}{
+		{
+			name:   "json",
+			config: fmt.Sprintf(`{"url": %q, "token": "secret"}`, s.URL+"/repos.json"),
+			want:   []*Repo{bar, baz},
+		},
+		{
+			name:   "yaml",
+			config: fmt.Sprintf(`{"url": %q, "token": "secret"}`, s.URL+"/repos.yaml"),
+			want:   []*Repo{bar},
+		},
+		{
+			name:   "repository",
+			config: `{"repository": "git.mycorp.com/infra/repos", "path": "repos.yaml", "revision": "master"}`,
+			want:   []*Repo{baz},
+		},
+		{
+			name:   "unauthorized",
+			config: fmt.Sprintf(`{"url": %q}`, s.URL+"/repos.json"),
+			err:    "unexpected response status 401 when fetching manifest",
+		},
+		{
+			name:   "invalid entries are skipped",
+			config: fmt.Sprintf(`{"url": %q, "token": "secret"}`, s.URL+"/invalid.json"),
+			want:   []*Repo{bar},
+		},
+		{
+			name:   "too large",
+			config: fmt.Sprintf(`{"url": %q, "token": "secret"}`, s.URL+"/large.json"),
+			err:    "manifest is larger than",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			src, err := NewManifestSource(&ExternalService{ID: 1, Kind: extsvc.KindManifest, Config: tc.config}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			repos, err := listAll(context.Background(), src)
+			if have, want := fmt.Sprint(err), tc.err; (want == "" && err != nil) || !strings.Contains(have, want) {
+				t.Errorf("unexpected error. want=%q have=%q", want, have)
+			}
+
+			if diff := cmp.Diff(tc.want, repos); diff != "" {
+				t.Errorf("unexpected repos (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
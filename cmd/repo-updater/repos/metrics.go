@@ -56,6 +56,11 @@ var (
 		Name: "src_repoupdater_sched_manual_fetch",
 		Help: "Incremented each time the scheduler updates a repository due to user traffic.",
 	})
+	schedUpdateLag = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "src_repoupdater_sched_update_lag_seconds",
+		Help:    "Time from requesting a repository update, such as on push or when its scheduled update is due, until it finished, by update class (none for repositories that belong to no class).",
+		Buckets: []float64{1, 5, 15, 30, 60, 120, 300, 600, 1800, 3600, 7200},
+	}, []string{"class"})
 	schedKnownRepos = promauto.NewGauge(prometheus.GaugeOpts{
 		Name: "src_repoupdater_sched_known_repos",
 		Help: "The number of repositories that are managed by the scheduler.",
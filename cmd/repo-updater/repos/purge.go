@@ -15,8 +15,10 @@ import (
 )
 
 // RunRepositoryPurgeWorker is a worker which deletes repos which are present
-// on gitserver, but not enabled/present in our repos table.
-func RunRepositoryPurgeWorker(ctx context.Context) {
+// on gitserver, but not enabled/present in our repos table. The clones of repos
+// that were deleted within the grace period are kept, so that they can be
+// restored without recloning.
+func RunRepositoryPurgeWorker(ctx context.Context, store Store) {
 	log := log15.Root().New("worker", "repo-purge")
 
 	// Temporary escape hatch if this feature proves to be dangerous
@@ -32,7 +34,7 @@ func RunRepositoryPurgeWorker(ctx context.Context) {
 		// reduce the chance of this happening by only purging at a weird time
 		// to be configuring Sourcegraph.
 		if isSaturdayNight(time.Now()) {
-			err := purge(ctx, log)
+			err := purge(ctx, log, store)
 			if err != nil {
 				log.Error("failed to run repository clone purge", "error", err)
 			}
@@ -41,7 +43,7 @@ func RunRepositoryPurgeWorker(ctx context.Context) {
 	}
 }
 
-func purge(ctx context.Context, log log15.Logger) error {
+func purge(ctx context.Context, log log15.Logger, store Store) error {
 	// If we fetched enabled first we have the following race condition:
 	//
 	// 1. Fetched enabled list without repo X.
@@ -65,15 +67,32 @@ func purge(ctx context.Context, log log15.Logger) error {
 		enabled[protocol.NormalizeRepo(repo)] = struct{}{}
 	}
 
+	deletedList, err := store.ListRepos(ctx, StoreListReposArgs{
+		DeletedSince: time.Now().Add(-GetPurgeGracePeriod()),
+	})
+	if err != nil {
+		return err
+	}
+	deleted := make(map[api.RepoName]struct{}, len(deletedList))
+	for _, repo := range deletedList {
+		deleted[protocol.NormalizeRepo(api.RepoName(repo.OriginalName()))] = struct{}{}
+	}
+
 	success := 0
 	failed := 0
 
-	// remove repositories that are in cloned but not in enabled
+	// remove repositories that are in cloned but not in enabled, unless they
+	// were deleted within the grace period.
+	kept := 0
 	for _, repoStr := range cloned {
 		repo := protocol.NormalizeRepo(api.RepoName(repoStr))
 		if _, ok := enabled[repo]; ok {
 			continue
 		}
+		if _, ok := deleted[repo]; ok {
+			kept++
+			continue
+		}
 
 		// Race condition: A repo can be re-enabled between our listing and
 		// now. This should be very rare, so we ignore it since it will get
@@ -96,7 +115,7 @@ func purge(ctx context.Context, log log15.Logger) error {
 	if success > 0 || failed > 0 {
 		statusLogger = log.Info
 	}
-	statusLogger("repository cloned purge finished", "enabled", len(enabled), "cloned", len(cloned)-success, "removed", success, "failed", failed)
+	statusLogger("repository cloned purge finished", "enabled", len(enabled), "cloned", len(cloned)-success, "removed", success, "failed", failed, "kept", kept)
 
 	return nil
 }
@@ -3,16 +3,19 @@ package repos
 import (
 	"container/heap"
 	"context"
+	"regexp"
 	"sync"
 	"time"
 
 	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	gitserverprotocol "github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/mutablelimiter"
 	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 // schedulerConfig tracks the active scheduler configuration.
@@ -31,6 +34,12 @@ func RunScheduler(ctx context.Context, scheduler *updateScheduler) {
 	conf.Watch(func() {
 		c := conf.Get()
 
+		if classes, err := newUpdateClasses(c.GitUpdateClasses); err != nil {
+			log15.Error("ignoring invalid gitUpdateClasses", "err", err)
+		} else {
+			scheduler.schedule.setClasses(classes)
+		}
+
 		want := schedulerConfig{
 			running:               true,
 			autoGitUpdatesEnabled: !c.DisableAutoGitUpdates,
@@ -79,6 +88,123 @@ const (
 	maxDelay = 8 * time.Hour
 )
 
+// updateClass is a class of repos configured in the gitUpdateClasses site
+// configuration. The scheduled updates of the repos in a class happen at
+// intervals between its MinInterval and MaxInterval and are queued by its
+// Priority.
+//
+// Repos that belong to no class have a nil *updateClass, whose methods return
+// the defaults.
+type updateClass struct {
+	Name        string
+	Pattern     *regexp.Regexp `json:"-"`
+	MinInterval time.Duration
+	MaxInterval time.Duration
+	Priority    int
+}
+
+// newUpdateClasses returns the update classes of the gitUpdateClasses site
+// configuration.
+func newUpdateClasses(cs []*schema.GitUpdateClass) ([]*updateClass, error) {
+	classes := make([]*updateClass, 0, len(cs))
+	for _, c := range cs {
+		class := &updateClass{
+			Name:        c.Name,
+			MinInterval: time.Duration(c.MinIntervalSeconds) * time.Second,
+			MaxInterval: time.Duration(c.MaxIntervalSeconds) * time.Second,
+			Priority:    c.Priority,
+		}
+
+		switch {
+		case class.MinInterval == 0 && class.MaxInterval == 0:
+			class.MinInterval, class.MaxInterval = minDelay, maxDelay
+		case class.MinInterval == 0:
+			class.MinInterval = minDuration(minDelay, class.MaxInterval)
+		case class.MaxInterval == 0:
+			class.MaxInterval = maxDuration(maxDelay, class.MinInterval)
+		case class.MaxInterval < class.MinInterval:
+			return nil, errors.Errorf("update class %q: maxIntervalSeconds is less than minIntervalSeconds", c.Name)
+		}
+
+		if c.RepositoryPattern != "" {
+			p, err := regexp.Compile(c.RepositoryPattern)
+			if err != nil {
+				return nil, errors.Wrapf(err, "update class %q: invalid repositoryPattern", c.Name)
+			}
+			class.Pattern = p
+		}
+
+		classes = append(classes, class)
+	}
+	return classes, nil
+}
+
+// classify returns the first of the classes that the repo belongs to, or nil
+// if it belongs to none.
+func classify(classes []*updateClass, name api.RepoName) *updateClass {
+	for _, c := range classes {
+		if c.Pattern == nil || c.Pattern.MatchString(string(name)) {
+			return c
+		}
+	}
+	return nil
+}
+
+// name returns the name of the class, or the empty string for repos that
+// belong to no class.
+func (c *updateClass) name() string {
+	if c == nil {
+		return ""
+	}
+	return c.Name
+}
+
+// minInterval returns the minimum amount of time between scheduled updates
+// of a repo in the class.
+func (c *updateClass) minInterval() time.Duration {
+	if c == nil {
+		return minDelay
+	}
+	return c.MinInterval
+}
+
+// maxInterval returns the maximum amount of time between scheduled updates
+// of a repo in the class.
+func (c *updateClass) maxInterval() time.Duration {
+	if c == nil {
+		return maxDelay
+	}
+	return c.MaxInterval
+}
+
+// priority returns the priority of the scheduled updates of the repos in the
+// class.
+func (c *updateClass) priority() int {
+	if c == nil {
+		return 0
+	}
+	return c.Priority
+}
+
+// clamp returns the interval limited to the bounds of the class.
+func (c *updateClass) clamp(interval time.Duration) time.Duration {
+	return maxDuration(c.minInterval(), minDuration(interval, c.maxInterval()))
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
+}
+
 // updateScheduler schedules repo update (or clone) requests to gitserver.
 //
 // Repository metadata is synced from configured code hosts and added to the scheduler.
@@ -107,6 +233,11 @@ type configuredRepo2 struct {
 	URL  string
 	ID   api.RepoID
 	Name api.RepoName
+
+	// PreviousName is the name of a renamed repo before it was renamed. It
+	// is only set on queued updates, so that the clone is moved on gitserver
+	// instead of being cloned again under the new name.
+	PreviousName api.RepoName `json:",omitempty"`
 }
 
 // notifyChanBuffer controls the buffer size of notification channels.
@@ -155,7 +286,7 @@ func (s *updateScheduler) runSchedule() {
 		}
 
 		schedAutoFetch.Inc()
-		s.updateQueue.enqueue(repoUpdate.Repo, priorityLow)
+		s.updateQueue.enqueue(repoUpdate.Repo, priorityLow, repoUpdate.Class)
 		repoUpdate.Due = timeNow().Add(repoUpdate.Interval)
 		heap.Fix(s.schedule, 0)
 	}
@@ -188,7 +319,13 @@ func (s *updateScheduler) runUpdateLoop(ctx context.Context) {
 
 			go func(ctx context.Context, repo configuredRepo2, cancel context.CancelFunc) {
 				defer cancel()
-				defer s.updateQueue.remove(repo, true)
+				defer s.updateQueue.finish(repo)
+
+				if repo.PreviousName != "" {
+					if _, err := renameRepo(ctx, repo.PreviousName, repo.Name); err != nil {
+						log15.Warn("error renaming repo", "from", repo.PreviousName, "to", repo.Name, "err", err)
+					}
+				}
 
 				resp, err := requestRepoUpdate(ctx, repo, 1*time.Second)
 				if err != nil {
@@ -211,6 +348,11 @@ var requestRepoUpdate = func(ctx context.Context, repo configuredRepo2, since ti
 	return gitserver.DefaultClient.RequestRepoUpdate(ctx, gitserver.Repo{Name: repo.Name, URL: repo.URL}, since)
 }
 
+// renameRepo sends a request to gitserver to move the clone of a renamed repo.
+var renameRepo = func(ctx context.Context, from, to api.RepoName) (bool, error) {
+	return gitserver.DefaultClient.Rename(ctx, from, to)
+}
+
 // configuredLimiter returns a mutable limiter that is
 // configured with the maximum number of concurrent update
 // requests that repo-updater should send to gitserver.
@@ -259,13 +401,19 @@ func (s *updateScheduler) UpdateFromDiff(diff Diff) {
 func (s *updateScheduler) upsert(r *Repo, enqueue bool) {
 	repo := configuredRepo2FromRepo(r)
 
+	var previousName api.RepoName
+	if scheduled, ok := s.schedule.get(repo.ID); ok && scheduled.Name != repo.Name {
+		previousName = scheduled.Name
+	}
+
 	updated := s.schedule.upsert(repo)
 	log15.Debug("scheduler.schedule.upserted", "repo", r.Name, "updated", updated)
 
 	if !enqueue {
 		return
 	}
-	updated = s.updateQueue.enqueue(repo, priorityLow)
+	repo.PreviousName = previousName
+	updated = s.updateQueue.enqueue(repo, priorityLow, s.schedule.classify(repo.Name))
 	log15.Debug("scheduler.updateQueue.enqueued", "repo", r.Name, "updated", updated)
 }
 
@@ -303,7 +451,7 @@ func (s *updateScheduler) UpdateOnce(id api.RepoID, name api.RepoName, url strin
 		URL:  url,
 	}
 	schedManualFetch.Inc()
-	s.updateQueue.enqueue(repo, priorityHigh)
+	s.updateQueue.enqueue(repo, priorityHigh, s.schedule.classify(name))
 }
 
 // DebugDump returns the state of the update scheduler for debugging.
@@ -364,10 +512,14 @@ func (s *updateScheduler) ScheduleInfo(id api.RepoID) *protocol.RepoUpdateSchedu
 	s.schedule.mu.Lock()
 	if update := s.schedule.index[id]; update != nil {
 		result.Schedule = &protocol.RepoScheduleState{
-			Index:           update.Index,
-			Total:           len(s.schedule.index),
-			IntervalSeconds: int(update.Interval / time.Second),
-			Due:             update.Due,
+			Index:              update.Index,
+			Total:              len(s.schedule.index),
+			IntervalSeconds:    int(update.Interval / time.Second),
+			Due:                update.Due,
+			Class:              update.Class.name(),
+			MinIntervalSeconds: int(update.Class.minInterval() / time.Second),
+			MaxIntervalSeconds: int(update.Class.maxInterval() / time.Second),
+			Priority:           update.Class.priority(),
 		}
 	}
 	s.schedule.mu.Unlock()
@@ -410,11 +562,14 @@ const (
 
 // repoUpdate is a repository that has been queued for an update.
 type repoUpdate struct {
-	Repo     configuredRepo2
-	Priority priority
-	Seq      uint64 // the sequence number of the update
-	Updating bool   // whether the repo has been acquired for update
-	Index    int    `json:"-"` // the index in the heap
+	Repo      configuredRepo2
+	Priority  priority
+	Class     *updateClass `json:",omitempty"` // the update class of the repo; its priority orders updates of the same priority
+	Seq       uint64       // the sequence number of the update
+	Updating  bool         // whether the repo has been acquired for update
+	Requeue   bool         // whether the repo must be updated again once its update finished
+	Requested time.Time    `json:"-"` // when the update was requested, to measure the update lag
+	Index     int          `json:"-"` // the index in the heap
 }
 
 func (q *updateQueue) reset() {
@@ -430,11 +585,16 @@ func (q *updateQueue) reset() {
 // enqueue adds the repo to the queue with the given priority.
 //
 // If the repo is already in the queue and it isn't yet updating,
-// the repo is updated.
+// the repo is updated. If it is updating, a high priority enqueue
+// makes it update once more after the current update finishes, since
+// the current update may miss what the enqueue was requested for (e.g.
+// a push). Any number of such enqueues result in a single update.
 //
 // If the given priority is higher than the one in the queue,
 // the repo's position in the queue is updated accordingly.
-func (q *updateQueue) enqueue(repo configuredRepo2, p priority) (updated bool) {
+//
+// The given update class orders the updates of the same priority.
+func (q *updateQueue) enqueue(repo configuredRepo2, p priority, class *updateClass) (updated bool) {
 	if repo.ID == 0 {
 		panic("repo.id is zero")
 	}
@@ -445,20 +605,38 @@ func (q *updateQueue) enqueue(repo configuredRepo2, p priority) (updated bool) {
 	update := q.index[repo.ID]
 	if update == nil {
 		heap.Push(q, &repoUpdate{
-			Repo:     repo,
-			Priority: p,
+			Repo:      repo,
+			Priority:  p,
+			Class:     class,
+			Requested: timeNow(),
 		})
 		notify(q.notifyEnqueue)
 		return false
 	}
 
+	if repo.PreviousName == "" && repo.Name == update.Repo.Name {
+		// Don't lose a pending rename.
+		repo.PreviousName = update.Repo.PreviousName
+	}
+
 	if update.Updating {
-		return false
+		if p != priorityHigh || update.Requeue {
+			return false
+		}
+		update.Repo = repo
+		update.Class = class
+		update.Requeue = true
+		return true
 	}
 
 	update.Repo = repo
+	reorder := class.priority() != update.Class.priority()
+	update.Class = class
 	if p <= update.Priority {
 		// Repo is already in the queue with at least as good priority.
+		if reorder {
+			heap.Fix(q, update.Index)
+		}
 		return true
 	}
 
@@ -496,6 +674,42 @@ func (q *updateQueue) remove(repo configuredRepo2, updating bool) (removed bool)
 	return false
 }
 
+// finish removes the repo from the queue once its update finished. If the
+// repo was enqueued with high priority while it was updating, it is queued
+// again instead.
+func (q *updateQueue) finish(repo configuredRepo2) {
+	if repo.ID == 0 {
+		panic("repo.id is zero")
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	update := q.index[repo.ID]
+	if update == nil || !update.Updating {
+		return
+	}
+
+	class := update.Class.name()
+	if class == "" {
+		class = "none"
+	}
+	now := timeNow()
+	schedUpdateLag.WithLabelValues(class).Observe(now.Sub(update.Requested).Seconds())
+
+	if !update.Requeue {
+		heap.Remove(q, update.Index)
+		return
+	}
+
+	update.Updating, update.Requeue = false, false
+	update.Priority = priorityHigh
+	update.Requested = now
+	update.Seq = q.nextSeq()
+	heap.Fix(q, update.Index)
+	notify(q.notifyEnqueue)
+}
+
 // acquireNext acquires the next repo for update.
 // The acquired repo must be removed from the queue
 // when the update finishes (independent of success or failure).
@@ -533,6 +747,9 @@ func (q *updateQueue) Less(i, j int) bool {
 		// We want Pop to give us the highest, not lowest, priority so we use greater than here.
 		return qi.Priority > qj.Priority
 	}
+	if pi, pj := qi.Class.priority(), qj.Class.priority(); pi != pj {
+		return pi > pj
+	}
 	// Queue semantics for items with the same priority.
 	return qi.Seq < qj.Seq
 }
@@ -568,6 +785,8 @@ type schedule struct {
 	heap  []*scheduledRepoUpdate // min heap of scheduledRepoUpdates based on their due time.
 	index map[api.RepoID]*scheduledRepoUpdate
 
+	classes []*updateClass // the configured update classes
+
 	// timer sends a value on the wakeup channel when it is time
 	timer  *time.Timer
 	wakeup chan struct{}
@@ -576,6 +795,7 @@ type schedule struct {
 // scheduledRepoUpdate is the update schedule for a single repo.
 type scheduledRepoUpdate struct {
 	Repo     configuredRepo2 // the repo to update
+	Class    *updateClass    `json:",omitempty"` // the update class of the repo, nil if it belongs to none
 	Interval time.Duration   // how regularly the repo is updated
 	Due      time.Time       // the next time that the repo will be enqueued for a update
 	Index    int             `json:"-"` // the index in the heap
@@ -591,14 +811,20 @@ func (s *schedule) upsert(repo configuredRepo2) (updated bool) {
 	defer s.mu.Unlock()
 
 	if update := s.index[repo.ID]; update != nil {
+		if update.Repo.Name != repo.Name {
+			update.Class = classify(s.classes, repo.Name)
+			update.Interval = update.Class.clamp(update.Interval)
+		}
 		update.Repo = repo
 		return true
 	}
 
+	class := classify(s.classes, repo.Name)
 	heap.Push(s, &scheduledRepoUpdate{
 		Repo:     repo,
-		Interval: minDelay,
-		Due:      timeNow().Add(minDelay),
+		Class:    class,
+		Interval: class.minInterval(),
+		Due:      timeNow().Add(class.minInterval()),
 	})
 
 	s.rescheduleTimer()
@@ -606,6 +832,45 @@ func (s *schedule) upsert(repo configuredRepo2) (updated bool) {
 	return false
 }
 
+// get returns the scheduled repo with the given id, if any.
+func (s *schedule) get(id api.RepoID) (configuredRepo2, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if update := s.index[id]; update != nil {
+		return update.Repo, true
+	}
+	return configuredRepo2{}, false
+}
+
+// classify returns the update class of the repo with the given name.
+func (s *schedule) classify(name api.RepoName) *updateClass {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return classify(s.classes, name)
+}
+
+// setClasses sets the update classes and reclassifies the scheduled repos.
+// The intervals of the repos are limited to the bounds of their class, and
+// they are due no later than their interval from now.
+func (s *schedule) setClasses(classes []*updateClass) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.classes = classes
+
+	now := timeNow()
+	for _, update := range s.heap {
+		update.Class = classify(classes, update.Repo.Name)
+		update.Interval = update.Class.clamp(update.Interval)
+		if due := now.Add(update.Interval); update.Due.After(due) {
+			update.Due = due
+		}
+	}
+	heap.Init(s)
+	s.rescheduleTimer()
+}
+
 // updateInterval updates the update interval of a repo in the schedule.
 // It does nothing if the repo is not in the schedule.
 func (s *schedule) updateInterval(repo configuredRepo2, interval time.Duration) {
@@ -615,14 +880,7 @@ func (s *schedule) updateInterval(repo configuredRepo2, interval time.Duration)
 
 	s.mu.Lock()
 	if update := s.index[repo.ID]; update != nil {
-		switch {
-		case interval > maxDelay:
-			update.Interval = maxDelay
-		case interval < minDelay:
-			update.Interval = minDelay
-		default:
-			update.Interval = interval
-		}
+		update.Interval = update.Class.clamp(interval)
 		update.Due = timeNow().Add(update.Interval)
 		log15.Debug("updated repo", "repo", repo.Name, "due", update.Due.Sub(timeNow()))
 		heap.Fix(s, update.Index)
@@ -3,6 +3,7 @@ package repos
 import (
 	"container/heap"
 	"context"
+	"fmt"
 	"reflect"
 	"testing"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	gitserverprotocol "github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/mutablelimiter"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 var defaultTime = time.Date(2000, 1, 1, 1, 1, 1, 1, time.UTC)
@@ -62,9 +64,12 @@ func TestUpdateQueue_enqueue(t *testing.T) {
 	d := configuredRepo2{ID: 4, Name: "d", URL: "d.com"}
 	e := configuredRepo2{ID: 5, Name: "e", URL: "e.com"}
 
+	critical := &updateClass{Name: "critical", MinInterval: minDelay, MaxInterval: maxDelay, Priority: 10}
+
 	type enqueueCall struct {
 		repo     configuredRepo2
 		priority priority
+		class    *updateClass
 	}
 
 	tests := []struct {
@@ -219,6 +224,104 @@ func TestUpdateQueue_enqueue(t *testing.T) {
 			},
 			expectedNotifications: 1,
 		},
+		{
+			name: "pending rename is kept",
+			calls: []*enqueueCall{
+				{repo: configuredRepo2{ID: 1, Name: "a2", URL: "a2.com", PreviousName: "a"}, priority: priorityLow},
+				{repo: a2, priority: priorityHigh},
+			},
+			expectedUpdates: []*repoUpdate{
+				{
+					Repo:     configuredRepo2{ID: 1, Name: "a2", URL: "a2.com", PreviousName: "a"},
+					Priority: priorityHigh,
+					Seq:      2,
+				},
+			},
+			expectedNotifications: 2,
+		},
+		{
+			name: "repo is requeued once if enqueued with high priority while updating",
+			calls: []*enqueueCall{
+				{repo: a, priority: priorityHigh},
+				{repo: a2, priority: priorityHigh},
+				{repo: a, priority: priorityHigh},
+			},
+			acquire: 1,
+			expectedUpdates: []*repoUpdate{
+				{
+					Repo:     a2,
+					Priority: priorityHigh,
+					Updating: true,
+					Requeue:  true,
+					Seq:      1,
+				},
+			},
+			expectedNotifications: 1,
+		},
+		{
+			name: "enqueue low a then low b of higher class priority",
+			calls: []*enqueueCall{
+				{repo: a, priority: priorityLow},
+				{repo: b, priority: priorityLow, class: critical},
+			},
+			expectedUpdates: []*repoUpdate{
+				{
+					Repo:     b,
+					Priority: priorityLow,
+					Class:    critical,
+					Seq:      2,
+				},
+				{
+					Repo:     a,
+					Priority: priorityLow,
+					Seq:      1,
+				},
+			},
+			expectedNotifications: 2,
+		},
+		{
+			name: "enqueue low b of higher class priority then high a",
+			calls: []*enqueueCall{
+				{repo: b, priority: priorityLow, class: critical},
+				{repo: a, priority: priorityHigh},
+			},
+			expectedUpdates: []*repoUpdate{
+				{
+					Repo:     a,
+					Priority: priorityHigh,
+					Seq:      2,
+				},
+				{
+					Repo:     b,
+					Priority: priorityLow,
+					Class:    critical,
+					Seq:      1,
+				},
+			},
+			expectedNotifications: 2,
+		},
+		{
+			name: "class is updated when a queued repo is enqueued again",
+			calls: []*enqueueCall{
+				{repo: a, priority: priorityLow},
+				{repo: b, priority: priorityLow},
+				{repo: b, priority: priorityLow, class: critical},
+			},
+			expectedUpdates: []*repoUpdate{
+				{
+					Repo:     b,
+					Priority: priorityLow,
+					Class:    critical,
+					Seq:      2,
+				},
+				{
+					Repo:     a,
+					Priority: priorityLow,
+					Seq:      1,
+				},
+			},
+			expectedNotifications: 2,
+		},
 		{
 			name: "heap is fixed when priority is bumped",
 			calls: []*enqueueCall{
@@ -273,7 +376,7 @@ func TestUpdateQueue_enqueue(t *testing.T) {
 			s := NewUpdateScheduler()
 
 			for _, call := range test.calls {
-				s.updateQueue.enqueue(call.repo, call.priority)
+				s.updateQueue.enqueue(call.repo, call.priority, call.class)
 				if test.acquire > 0 {
 					s.updateQueue.acquireNext()
 					test.acquire--
@@ -455,6 +558,77 @@ func TestUpdateQueue_remove(t *testing.T) {
 	}
 }
 
+func TestUpdateQueue_finish(t *testing.T) {
+	a := configuredRepo2{ID: 1, Name: "a", URL: "a.com"}
+	b := configuredRepo2{ID: 2, Name: "b", URL: "b.com"}
+
+	tests := []struct {
+		name                  string
+		initialQueue          []*repoUpdate
+		finish                configuredRepo2
+		finalQueue            []*repoUpdate
+		expectedNotifications int
+	}{
+		{
+			name: "finish removes updating",
+			initialQueue: []*repoUpdate{
+				{Repo: a, Seq: 1, Updating: true},
+				{Repo: b, Seq: 2},
+			},
+			finish: a,
+			finalQueue: []*repoUpdate{
+				{Repo: b, Seq: 2},
+			},
+		},
+		{
+			name: "finish doesn't remove not updating",
+			initialQueue: []*repoUpdate{
+				{Repo: a, Seq: 1},
+			},
+			finish: a,
+			finalQueue: []*repoUpdate{
+				{Repo: a, Seq: 1},
+			},
+		},
+		{
+			name: "finish requeues with high priority",
+			initialQueue: []*repoUpdate{
+				{Repo: b, Priority: priorityLow, Seq: 1},
+				{Repo: a, Priority: priorityLow, Seq: 2, Updating: true, Requeue: true},
+			},
+			finish: a,
+			finalQueue: []*repoUpdate{
+				{Repo: a, Priority: priorityHigh, Seq: 3},
+				{Repo: b, Priority: priorityLow, Seq: 1},
+			},
+			expectedNotifications: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, stop := startRecording()
+			defer stop()
+
+			s := NewUpdateScheduler()
+			setupInitialQueue(s, test.initialQueue)
+
+			s.updateQueue.finish(test.finish)
+
+			verifyQueue(t, s, test.finalQueue)
+
+			// Verify notifications.
+			expectedRecording := &recording{}
+			for i := 0; i < test.expectedNotifications; i++ {
+				expectedRecording.notifications = append(expectedRecording.notifications, s.updateQueue.notifyEnqueue)
+			}
+			if !reflect.DeepEqual(expectedRecording, r) {
+				t.Fatalf("\nexpected\n%s\ngot\n%s", spew.Sdump(expectedRecording), spew.Sdump(r))
+			}
+		})
+	}
+}
+
 func TestUpdateQueue_acquireNext(t *testing.T) {
 	a := configuredRepo2{ID: 1, Name: "a", URL: "a.com"}
 	b := configuredRepo2{ID: 2, Name: "b", URL: "b.com"}
@@ -547,6 +721,7 @@ func verifyQueue(t *testing.T, s *updateScheduler, expected []*repoUpdate) {
 	for len(s.updateQueue.heap) > 0 {
 		update := heap.Pop(s.updateQueue).(*repoUpdate)
 		update.Index = 0 // this will always be -1, but easier to set it to 0 to avoid boilerplate in test cases
+		update.Requested = time.Time{}
 		actualQueue = append(actualQueue, update)
 	}
 
@@ -612,6 +787,29 @@ func Test_updateScheduler_UpdateFromDiff(t *testing.T) {
 				{Repo: b, Seq: 2, Updating: false},
 			},
 		},
+		{
+			name: "diff with renamed repo",
+			initialSchedule: []*scheduledRepoUpdate{
+				{Repo: a, Interval: time.Hour, Due: defaultTime.Add(time.Hour)},
+			},
+			diff: Diff{
+				Modified: []*Repo{
+					{
+						ID:   a.ID,
+						Name: "a2",
+						Sources: map[string]*SourceInfo{
+							"a2": {CloneURL: "a2.com"},
+						},
+					},
+				},
+			},
+			finalSchedule: []*scheduledRepoUpdate{
+				{Repo: configuredRepo2{ID: a.ID, Name: "a2", URL: "a2.com"}, Interval: time.Hour, Due: defaultTime.Add(time.Hour)},
+			},
+			finalQueue: []*repoUpdate{
+				{Repo: configuredRepo2{ID: a.ID, Name: "a2", URL: "a2.com", PreviousName: "a"}, Seq: 1},
+			},
+		},
 		{
 			name: "diff with unmodified but partially deleted repos",
 			initialSchedule: []*scheduledRepoUpdate{
@@ -1446,6 +1644,22 @@ func Test_updateQueue_Less(t *testing.T) {
 			},
 			expVal: true,
 		},
+		{
+			name: "class priority",
+			heap: []*repoUpdate{
+				{Seq: 2, Class: &updateClass{Priority: 1}},
+				{Seq: 1},
+			},
+			expVal: true,
+		},
+		{
+			name: "priority before class priority",
+			heap: []*repoUpdate{
+				{Priority: priorityLow, Class: &updateClass{Priority: 1}},
+				{Priority: priorityHigh},
+			},
+			expVal: false,
+		},
 		{
 			name: "seq",
 			heap: []*repoUpdate{
@@ -1465,3 +1679,97 @@ func Test_updateQueue_Less(t *testing.T) {
 		})
 	}
 }
+
+func Test_newUpdateClasses(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  []*schema.GitUpdateClass
+		want    []*updateClass
+		wantErr string
+	}{
+		{
+			name:   "defaults",
+			config: []*schema.GitUpdateClass{{Name: "all"}},
+			want:   []*updateClass{{Name: "all", MinInterval: minDelay, MaxInterval: maxDelay}},
+		},
+		{
+			name:   "min interval above default max interval",
+			config: []*schema.GitUpdateClass{{Name: "forks", MinIntervalSeconds: 86400, Priority: -1}},
+			want:   []*updateClass{{Name: "forks", MinInterval: 24 * time.Hour, MaxInterval: 24 * time.Hour, Priority: -1}},
+		},
+		{
+			name:   "max interval below default min interval",
+			config: []*schema.GitUpdateClass{{Name: "critical", MaxIntervalSeconds: 30, Priority: 1}},
+			want:   []*updateClass{{Name: "critical", MinInterval: 30 * time.Second, MaxInterval: 30 * time.Second, Priority: 1}},
+		},
+		{
+			name:    "max interval below min interval",
+			config:  []*schema.GitUpdateClass{{Name: "bad", MinIntervalSeconds: 60, MaxIntervalSeconds: 30}},
+			wantErr: `update class "bad": maxIntervalSeconds is less than minIntervalSeconds`,
+		},
+		{
+			name:    "invalid pattern",
+			config:  []*schema.GitUpdateClass{{Name: "bad", RepositoryPattern: "("}},
+			wantErr: `update class "bad": invalid repositoryPattern: error parsing regexp: missing closing ): ` + "`(`",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			have, err := newUpdateClasses(test.config)
+			if have, want := fmt.Sprint(err), fmt.Sprint(test.wantErr); test.wantErr != "" && have != want {
+				t.Fatalf("have err %q, want %q", have, want)
+			} else if test.wantErr == "" && err != nil {
+				t.Fatal(err)
+			}
+			if test.wantErr == "" && !reflect.DeepEqual(have, test.want) {
+				t.Fatalf("\nexpected\n%s\ngot\n%s", spew.Sdump(test.want), spew.Sdump(have))
+			}
+		})
+	}
+}
+
+func TestSchedule_setClasses(t *testing.T) {
+	_, stop := startRecording()
+	defer stop()
+
+	a := configuredRepo2{ID: 1, Name: "github.com/org/critical", URL: "a.com"}
+	b := configuredRepo2{ID: 2, Name: "github.com/forks/b", URL: "b.com"}
+	c := configuredRepo2{ID: 3, Name: "github.com/org/c", URL: "c.com"}
+
+	classes, err := newUpdateClasses([]*schema.GitUpdateClass{
+		{Name: "critical", RepositoryPattern: "/critical$", MaxIntervalSeconds: 30, Priority: 1},
+		{Name: "forks", RepositoryPattern: "^github\\.com/forks/", MinIntervalSeconds: 86400},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	critical, forks := classes[0], classes[1]
+
+	s := NewUpdateScheduler()
+	setupInitialSchedule(s, []*scheduledRepoUpdate{
+		{Repo: a, Interval: time.Hour, Due: defaultTime.Add(time.Hour)},
+		{Repo: b, Interval: time.Hour, Due: defaultTime.Add(time.Hour)},
+		{Repo: c, Interval: time.Hour, Due: defaultTime.Add(time.Hour / 2)},
+	})
+
+	s.schedule.setClasses(classes)
+
+	// New repos are scheduled at the minimum interval of their class.
+	d := configuredRepo2{ID: 4, Name: "github.com/forks/d", URL: "d.com"}
+	s.schedule.upsert(d)
+
+	// Update intervals are limited to the bounds of the class.
+	s.schedule.updateInterval(a, time.Minute)
+
+	if info := s.ScheduleInfo(a.ID).Schedule; info.Class != "critical" || info.MinIntervalSeconds != 30 || info.MaxIntervalSeconds != 30 || info.Priority != 1 {
+		t.Fatalf("unexpected schedule info: %+v", info)
+	}
+
+	verifySchedule(t, s, []*scheduledRepoUpdate{
+		{Repo: a, Class: critical, Interval: 30 * time.Second, Due: defaultTime.Add(30 * time.Second)},
+		{Repo: c, Interval: time.Hour, Due: defaultTime.Add(time.Hour / 2)},
+		{Repo: b, Class: forks, Interval: 24 * time.Hour, Due: defaultTime.Add(time.Hour)},
+		{Repo: d, Class: forks, Interval: 24 * time.Hour, Due: defaultTime.Add(24 * time.Hour)},
+	})
+}
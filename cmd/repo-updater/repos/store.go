@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbutil"
@@ -16,6 +17,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitolite"
@@ -49,6 +51,9 @@ type StoreListReposArgs struct {
 	PerPage int64
 	// Only include private repositories.
 	PrivateOnly bool
+	// DeletedSince, when non-zero, lists repos that were deleted at or after
+	// the given time instead of the ones that aren't deleted.
+	DeletedSince time.Time
 
 	// UseOr decides between ANDing or ORing the predicates together.
 	UseOr bool
@@ -330,12 +335,16 @@ SELECT
   archived,
   fork,
   private,
+  topics,
+  stars,
+  default_branch,
+  restored_at,
   sources,
   metadata
 FROM repo
 WHERE id > %s
 AND %s
-AND deleted_at IS NULL
+AND %s
 ORDER BY id ASC LIMIT %s
 `
 
@@ -392,11 +401,17 @@ func listReposQuery(args StoreListReposArgs) paginatedQuery {
 		predQ = sqlf.Join(preds, "\n AND ")
 	}
 
+	deleted := sqlf.Sprintf("deleted_at IS NULL")
+	if !args.DeletedSince.IsZero() {
+		deleted = sqlf.Sprintf("deleted_at >= %s", args.DeletedSince.UTC())
+	}
+
 	return func(cursor, limit int64) *sqlf.Query {
 		return sqlf.Sprintf(
 			listReposQueryFmtstr,
 			cursor,
 			sqlf.Sprintf("(%s)", predQ),
+			deleted,
 			limit,
 		)
 	}
@@ -576,6 +591,10 @@ func batchReposQuery(fmtstr string, repos []*Repo) (_ *sqlf.Query, err error) {
 		Archived            bool            `json:"archived"`
 		Fork                bool            `json:"fork"`
 		Private             bool            `json:"private"`
+		Topics              []string        `json:"topics"`
+		Stars               int             `json:"stars"`
+		DefaultBranch       *string         `json:"default_branch,omitempty"`
+		RestoredAt          *time.Time      `json:"restored_at,omitempty"`
 		Sources             json.RawMessage `json:"sources"`
 		Metadata            json.RawMessage `json:"metadata"`
 	}
@@ -592,6 +611,11 @@ func batchReposQuery(fmtstr string, repos []*Repo) (_ *sqlf.Query, err error) {
 			return nil, errors.Wrapf(err, "batchReposQuery: metadata marshalling failed")
 		}
 
+		topics := r.Topics
+		if topics == nil {
+			topics = []string{}
+		}
+
 		records = append(records, record{
 			ID:                  r.ID,
 			Name:                r.Name,
@@ -607,6 +631,10 @@ func batchReposQuery(fmtstr string, repos []*Repo) (_ *sqlf.Query, err error) {
 			Archived:            r.Archived,
 			Fork:                r.Fork,
 			Private:             r.Private,
+			Topics:              topics,
+			Stars:               r.Stars,
+			DefaultBranch:       nullStringColumn(r.DefaultBranch),
+			RestoredAt:          nullTimeColumn(r.RestoredAt.UTC()),
 			Sources:             sources,
 			Metadata:            metadata,
 		})
@@ -651,6 +679,10 @@ WITH batch AS (
       archived              boolean,
       fork                  boolean,
       private               boolean,
+      topics                text[],
+      stars                 integer,
+      default_branch        text,
+      restored_at           timestamptz,
       sources               jsonb,
       metadata              jsonb
     )
@@ -658,7 +690,22 @@ WITH batch AS (
   WITH ORDINALITY
 )`
 
-var updateReposQuery = batchReposQueryFmtstr + `
+var updateReposQuery = batchReposQueryFmtstr + `,
+-- Renamed repos are recorded in repo_redirects so that their old names keep
+-- resolving to them.
+redirects AS (
+  INSERT INTO repo_redirects (name, repo_id)
+  SELECT repo.name, repo.id
+  FROM repo
+  JOIN batch
+  ON repo.external_service_type = batch.external_service_type
+  AND repo.external_service_id = batch.external_service_id
+  AND repo.external_id = batch.external_id
+  WHERE repo.deleted_at IS NULL
+  AND batch.deleted_at IS NULL
+  AND repo.name <> batch.name
+  ON CONFLICT (name) DO UPDATE SET repo_id = excluded.repo_id, created_at = now()
+)
 UPDATE repo
 SET
   name                  = batch.name,
@@ -674,6 +721,10 @@ SET
   archived              = batch.archived,
   fork                  = batch.fork,
   private               = batch.private,
+  topics                = batch.topics,
+  stars                 = batch.stars,
+  default_branch        = batch.default_branch,
+  restored_at           = batch.restored_at,
   sources               = batch.sources,
   metadata              = batch.metadata
 FROM batch
@@ -711,6 +762,10 @@ INSERT INTO repo (
   archived,
   fork,
   private,
+  topics,
+  stars,
+  default_branch,
+  restored_at,
   sources,
   metadata
 )
@@ -728,6 +783,10 @@ SELECT
   archived,
   fork,
   private,
+  topics,
+  stars,
+  default_branch,
+  restored_at,
   sources,
   metadata
 FROM batch
@@ -829,6 +888,10 @@ func scanRepo(r *Repo, s scanner) error {
 		&r.Archived,
 		&r.Fork,
 		&r.Private,
+		pq.Array(&r.Topics),
+		&r.Stars,
+		&dbutil.NullString{S: &r.DefaultBranch},
+		&dbutil.NullTime{Time: &r.RestoredAt},
 		&sources,
 		&metadata,
 	)
@@ -853,6 +916,8 @@ func scanRepo(r *Repo, s scanner) error {
 		r.Metadata = new(bitbucketserver.Repo)
 	case extsvc.TypeBitbucketCloud:
 		r.Metadata = new(bitbucketcloud.Repo)
+	case extsvc.TypeGerrit:
+		r.Metadata = new(gerrit.Project)
 	case extsvc.TypeAWSCodeCommit:
 		r.Metadata = new(awscodecommit.Repository)
 	case extsvc.TypeGitolite:
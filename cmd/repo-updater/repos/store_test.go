@@ -879,11 +879,12 @@ func testStoreListRepos(store repos.Store) func(*testing.T) {
 	}
 
 	type testCase struct {
-		name   string
-		args   func(stored repos.Repos) repos.StoreListReposArgs
-		stored repos.Repos
-		repos  repos.ReposAssertion
-		err    error
+		name    string
+		args    func(stored repos.Repos) repos.StoreListReposArgs
+		stored  repos.Repos
+		deleted func(stored repos.Repos) repos.Repos
+		repos   repos.ReposAssertion
+		err     error
 	}
 
 	var testCases []testCase
@@ -924,6 +925,30 @@ func testStoreListRepos(store repos.Store) func(*testing.T) {
 		})
 	}
 
+	testCases = append(testCases, testCase{
+		name:   "returns repos deleted since the given time",
+		stored: repositories,
+		deleted: func(stored repos.Repos) repos.Repos {
+			return repos.Repos{
+				stored[0].With(repos.Opt.RepoDeletedAt(now.Add(-time.Hour))),
+				stored[1].With(repos.Opt.RepoDeletedAt(now)),
+			}
+		},
+		args: func(repos.Repos) repos.StoreListReposArgs {
+			return repos.StoreListReposArgs{DeletedSince: now.Add(-time.Minute)}
+		},
+		repos: func(t testing.TB, rs repos.Repos) {
+			t.Helper()
+			var names []string
+			for _, r := range rs {
+				names = append(names, r.OriginalName())
+			}
+			if have, want := names, []string{gitlab.Name}; !reflect.DeepEqual(have, want) {
+				t.Errorf("names:\nhave: %q\nwant: %q", have, want)
+			}
+		},
+	})
+
 	testCases = append(testCases, testCase{
 		name:   "returns repos in ascending order by id",
 		stored: mkRepos(7, repositories...),
@@ -1015,6 +1040,12 @@ func testStoreListRepos(store repos.Store) func(*testing.T) {
 					t.Fatalf("failed to setup store: %v", err)
 				}
 
+				if tc.deleted != nil {
+					if err := tx.UpsertRepos(ctx, tc.deleted(stored)...); err != nil {
+						t.Fatalf("failed to delete repos: %v", err)
+					}
+				}
+
 				var args repos.StoreListReposArgs
 				if tc.args != nil {
 					args = tc.args(stored)
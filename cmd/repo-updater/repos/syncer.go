@@ -140,6 +140,7 @@ func (s *Syncer) Sync(ctx context.Context) (err error) {
 	}
 
 	diff = NewDiff(sourced, stored)
+	s.keepRestored(&diff)
 	upserts := s.upserts(diff)
 
 	if err = store.UpsertRepos(ctx, upserts...); err != nil {
@@ -153,6 +154,72 @@ func (s *Syncer) Sync(ctx context.Context) (err error) {
 	return nil
 }
 
+// Preview returns the diff that syncing the given external service would apply
+// to the stored repos, without storing anything. The external service doesn't
+// need to be stored, so that configuration changes can be previewed before
+// they are saved. Stored repos that the external service doesn't yield are
+// only part of the diff if they were sourced by it: they are deleted, unless
+// other external services yield them too.
+func (s *Syncer) Preview(ctx context.Context, svc *ExternalService) (Diff, error) {
+	srcs, err := s.Sourcer(svc)
+	if err != nil {
+		return Diff{}, errors.Wrap(err, "syncer.preview.sourcer")
+	}
+
+	// Partial results would be reported as deletions, so any source error
+	// fails the preview.
+	sourced, err := listAll(ctx, srcs)
+	if err != nil {
+		return Diff{}, errors.Wrap(err, "syncer.preview.sourced")
+	}
+
+	stored, err := s.Store.ListRepos(ctx, StoreListReposArgs{})
+	if err != nil {
+		return Diff{}, errors.Wrap(err, "syncer.preview.store.list-repos")
+	}
+
+	// The diff updates the stored repos in place, which must not be visible
+	// to stores that don't return copies.
+	stored = Repos(stored).Clone()
+
+	// Sourced repos keep the sources of other external services, like they
+	// would when syncing all of them.
+	urn := svc.URN()
+	byExternalRepo := make(map[api.ExternalRepoSpec]*Repo, len(stored))
+	for _, r := range stored {
+		byExternalRepo[r.ExternalRepo] = r
+	}
+	for _, r := range sourced {
+		if old := byExternalRepo[r.ExternalRepo]; old != nil {
+			for id, src := range old.Sources {
+				if id != urn {
+					r.Sources[id] = src
+				}
+			}
+		}
+	}
+
+	diff := NewDiff(sourced, stored)
+
+	deleted := diff.Deleted
+	diff.Deleted = nil
+	for _, r := range deleted {
+		if _, ok := r.Sources[urn]; !ok {
+			continue
+		}
+		if len(r.Sources) > 1 {
+			delete(r.Sources, urn)
+			diff.Modified = append(diff.Modified, r)
+		} else {
+			diff.Deleted = append(diff.Deleted, r)
+		}
+	}
+
+	diff.Sort()
+
+	return diff, nil
+}
+
 // SyncSubset runs the syncer on a subset of the stored repositories. It will
 // only sync the repositories with the same name or external service spec as
 // sourcedSubset repositories.
@@ -211,7 +278,17 @@ func (s *Syncer) syncSubset(ctx context.Context, insertOnly bool, sourcedSubset
 		return Diff{}, nil
 	}
 
-	diff = NewDiff(sourcedSubset, storedSubset)
+	// Sourced repos without any sources aren't yielded by any external service
+	// anymore, so they're left out to have their stored counterparts deleted.
+	live := make([]*Repo, 0, len(sourcedSubset))
+	for _, r := range sourcedSubset {
+		if len(r.Sources) > 0 {
+			live = append(live, r)
+		}
+	}
+
+	diff = NewDiff(live, storedSubset)
+	s.keepRestored(&diff)
 	upserts := s.upserts(diff)
 
 	if err = store.UpsertRepos(ctx, upserts...); err != nil {
@@ -225,6 +302,29 @@ func (s *Syncer) syncSubset(ctx context.Context, insertOnly bool, sourcedSubset
 	return diff, nil
 }
 
+// keepRestored moves the repos that the diff deletes but that were restored
+// within the purge grace period to its unmodified repos, so that they aren't
+// deleted again before the configuration that removed them is fixed.
+func (s *Syncer) keepRestored(diff *Diff) {
+	var since time.Time
+	deleted := diff.Deleted[:0]
+	for _, r := range diff.Deleted {
+		if r.RestoredAt.IsZero() {
+			deleted = append(deleted, r)
+			continue
+		}
+		if since.IsZero() {
+			since = s.Now().Add(-GetPurgeGracePeriod())
+		}
+		if r.RestoredAt.After(since) {
+			diff.Unmodified = append(diff.Unmodified, r)
+		} else {
+			deleted = append(deleted, r)
+		}
+	}
+	diff.Deleted = deleted
+}
+
 func (s *Syncer) upserts(diff Diff) []*Repo {
 	now := s.Now()
 	upserts := make([]*Repo, 0, len(diff.Added)+len(diff.Deleted)+len(diff.Modified))
@@ -665,6 +665,11 @@ func testSyncSubset(s repos.Store) func(*testing.T) {
 		},
 		assert: repos.Assert.ReposEqual(repo.With(
 			repos.Opt.RepoCreatedAt(clock.Time(2)))),
+	}, {
+		name:    "delete without sources",
+		sourced: repos.Repos{repo.With(repos.Opt.RepoSources())},
+		stored:  repos.Repos{repo.With(repos.Opt.RepoCreatedAt(clock.Time(2)))},
+		assert:  repos.Assert.ReposEqual(),
 	}}
 
 	return func(t *testing.T) {
@@ -712,6 +717,123 @@ func testSyncSubset(s repos.Store) func(*testing.T) {
 	}
 }
 
+func TestSyncer_SyncRepoEvents(t *testing.T) {
+	t.Parallel()
+
+	clock := repos.NewFakeClock(time.Now(), time.Second)
+
+	svc := &repos.ExternalService{ID: 1, Kind: extsvc.KindGitHub, Config: `{"url": "https://github.com"}`}
+	other := &repos.ExternalService{ID: 2, Kind: extsvc.KindGitHub, Config: `{"url": "https://github.com"}`}
+
+	repo := func(id, name string, srcs ...string) *repos.Repo {
+		return (&repos.Repo{
+			Name:     name,
+			Metadata: &github.Repository{},
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          id,
+				ServiceID:   "https://github.com/",
+				ServiceType: extsvc.TypeGitHub,
+			},
+		}).With(repos.Opt.RepoSources(srcs...))
+	}
+
+	event := func(action, id string) repos.RepoEvent {
+		return repos.RepoEvent{
+			Action:       action,
+			ExternalRepo: api.ExternalRepoSpec{ID: id, ServiceType: extsvc.TypeGitHub},
+		}
+	}
+
+	for _, tc := range []struct {
+		name    string
+		sourced repos.Repos
+		stored  repos.Repos
+		events  []repos.RepoEvent
+		want    repos.Repos
+	}{
+		{
+			name:    "created",
+			sourced: repos.Repos{repo("a", "github.com/org/a")},
+			events:  []repos.RepoEvent{event(repos.RepoCreated, "a")},
+			want:    repos.Repos{repo("a", "github.com/org/a", svc.URN())},
+		},
+		{
+			name:    "renamed keeps other sources",
+			sourced: repos.Repos{repo("a", "github.com/org/b")},
+			stored:  repos.Repos{repo("a", "github.com/org/a", svc.URN(), other.URN())},
+			events:  []repos.RepoEvent{event(repos.RepoRenamed, "a")},
+			want:    repos.Repos{repo("a", "github.com/org/b", svc.URN(), other.URN())},
+		},
+		{
+			name:   "deleted",
+			stored: repos.Repos{repo("a", "github.com/org/a", svc.URN()), repo("b", "github.com/org/b", svc.URN())},
+			events: []repos.RepoEvent{event(repos.RepoDeleted, "a")},
+			want:   repos.Repos{repo("b", "github.com/org/b", svc.URN())},
+		},
+		{
+			name:   "deleted but yielded by other external service",
+			stored: repos.Repos{repo("a", "github.com/org/a", svc.URN(), other.URN())},
+			events: []repos.RepoEvent{event(repos.RepoDeleted, "a")},
+			want:   repos.Repos{repo("a", "github.com/org/a", other.URN())},
+		},
+		{
+			name:   "not yielded anymore",
+			stored: repos.Repos{repo("a", "github.com/org/a", svc.URN())},
+			events: []repos.RepoEvent{event(repos.RepoArchived, "a")},
+			want:   repos.Repos{},
+		},
+		{
+			name:   "unknown deleted repo",
+			events: []repos.RepoEvent{event(repos.RepoDeleted, "a")},
+			want:   repos.Repos{},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			st := new(repos.FakeStore)
+			if err := st.UpsertRepos(ctx, tc.stored.Clone()...); err != nil {
+				t.Fatalf("failed to prepare store: %v", err)
+			}
+
+			syncer := &repos.Syncer{
+				Store:   st,
+				Sourcer: repos.NewFakeSourcer(nil, repos.NewFakeSource(svc, nil, tc.sourced.Clone()...)),
+				Now:     clock.Now,
+			}
+			if err := syncer.SyncRepoEvents(ctx, svc, tc.events...); err != nil {
+				t.Fatal(err)
+			}
+
+			have, err := st.ListRepos(ctx, repos.StoreListReposArgs{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			type repo struct {
+				Name    string
+				Sources []string
+			}
+			summarize := func(rs repos.Repos) (s []repo) {
+				for _, r := range rs {
+					var srcs []string
+					for id := range r.Sources {
+						srcs = append(srcs, id)
+					}
+					sort.Strings(srcs)
+					s = append(s, repo{Name: r.Name, Sources: srcs})
+				}
+				sort.Slice(s, func(i, j int) bool { return s[i].Name < s[j].Name })
+				return s
+			}
+			if diff := cmp.Diff(summarize(tc.want), summarize(have)); diff != "" {
+				t.Errorf("unexpected repos (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestDiff(t *testing.T) {
 	t.Parallel()
 
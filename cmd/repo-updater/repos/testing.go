@@ -60,6 +60,21 @@ func (s FakeSource) ListRepos(ctx context.Context, results chan SourceResult) {
 	}
 }
 
+// repoForEvent returns the Repo that FakeSource was instantiated with whose
+// external ID matches the event, as well as the error, if any.
+func (s FakeSource) repoForEvent(ctx context.Context, ev RepoEvent) (*Repo, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	for _, r := range s.repos {
+		if r.ExternalRepo.ID == ev.ExternalRepo.ID {
+			return r.With(Opt.RepoSources(s.svc.URN())), nil
+		}
+	}
+	return nil, nil
+}
+
 // ExternalServices returns a singleton slice containing the external service.
 func (s FakeSource) ExternalServices() ExternalServices {
 	return ExternalServices{s.svc}
@@ -242,7 +257,11 @@ func (s FakeStore) ListRepos(ctx context.Context, args StoreListReposArgs) ([]*R
 			continue
 		}
 
-		if r.IsDeleted() {
+		if args.DeletedSince.IsZero() && r.IsDeleted() {
+			continue
+		}
+
+		if !args.DeletedSince.IsZero() && (!r.IsDeleted() || r.DeletedAt.Before(args.DeletedSince)) {
 			continue
 		}
 
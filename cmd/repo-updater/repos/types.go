@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/url"
 	"reflect"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -20,6 +21,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitolite"
 	"github.com/sourcegraph/sourcegraph/internal/jsonc"
 	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
@@ -113,6 +115,8 @@ func (e ExternalService) BaseURL() (*url.URL, error) {
 		rawURL = c.Url
 	case *schema.GitoliteConnection:
 		rawURL = c.Host
+	case *schema.GerritConnection:
+		rawURL = c.Url
 	case *schema.PhabricatorConnection:
 		rawURL = c.Url
 	case *schema.OtherExternalServiceConnection:
@@ -143,6 +147,8 @@ func (e *ExternalService) Exclude(rs ...*Repo) error {
 		return e.excludeAWSCodeCommitRepos(rs...)
 	case extsvc.KindGitolite:
 		return e.excludeGitoliteRepos(rs...)
+	case extsvc.KindGerrit:
+		return e.excludeGerritRepos(rs...)
 	case extsvc.KindOther:
 		return e.excludeOtherRepos(rs...)
 	default:
@@ -345,6 +351,34 @@ func (e *ExternalService) excludeGitoliteRepos(rs ...*Repo) error {
 	})
 }
 
+// excludeGerritRepos changes the configuration of a Gerrit external service to exclude the
+// given repos from being synced.
+func (e *ExternalService) excludeGerritRepos(rs ...*Repo) error {
+	if len(rs) == 0 {
+		return nil
+	}
+
+	return e.config(extsvc.KindGerrit, func(v interface{}) (string, interface{}, error) {
+		c := v.(*schema.GerritConnection)
+		set := make(map[string]bool, len(c.Exclude))
+		for _, ex := range c.Exclude {
+			if ex.Name != "" {
+				set[ex.Name] = true
+			}
+		}
+
+		for _, r := range rs {
+			project, ok := r.Metadata.(*gerrit.Project)
+			if ok && project.Name != "" && !set[project.Name] {
+				c.Exclude = append(c.Exclude, &schema.ExcludedGerritProject{Name: project.Name})
+				set[project.Name] = true
+			}
+		}
+
+		return "exclude", c.Exclude, nil
+	})
+}
+
 // excludeGithubRepos changes the configuration of a Github external service to exclude the
 // given repos from being synced.
 func (e *ExternalService) excludeGithubRepos(rs ...*Repo) error {
@@ -489,6 +523,10 @@ func (e ExternalService) schema() string {
 		return schema.GitLabSchemaJSON
 	case extsvc.KindGitolite:
 		return schema.GitoliteSchemaJSON
+	case extsvc.KindGerrit:
+		return schema.GerritSchemaJSON
+	case extsvc.KindManifest:
+		return schema.ManifestSchemaJSON
 	case extsvc.KindPhabricator:
 		return schema.PhabricatorSchemaJSON
 	case extsvc.KindOther:
@@ -572,12 +610,23 @@ type Repo struct {
 	Archived bool
 	// Private is whether the repository is private.
 	Private bool
+	// Topics are the topics, tags or labels of this repository on its code host.
+	Topics []string
+	// Stars is the number of stars of this repository on its code host.
+	Stars int
+	// DefaultBranch is the name of the default branch of this repository on
+	// its code host, if known.
+	DefaultBranch string
 	// CreatedAt is when this repository was created on Sourcegraph.
 	CreatedAt time.Time
 	// UpdatedAt is when this repository's metadata was last updated on Sourcegraph.
 	UpdatedAt time.Time
 	// DeletedAt is when this repository was soft-deleted from Sourcegraph.
 	DeletedAt time.Time
+	// RestoredAt is when this repository was last restored after it was
+	// deleted. Restored repositories aren't deleted by syncs for the purge
+	// grace period after they were restored.
+	RestoredAt time.Time
 	// ExternalRepo identifies this repository by its ID on the external service where it resides (and the external
 	// service itself).
 	ExternalRepo api.ExternalRepoSpec
@@ -633,6 +682,16 @@ func (r *Repo) ExternalServiceIDs() []int64 {
 // IsDeleted returns true if the repo is deleted.
 func (r *Repo) IsDeleted() bool { return !r.DeletedAt.IsZero() }
 
+// deletedNamePrefix matches the prefix that deleteReposQuery adds to the names
+// of soft-deleted repos.
+var deletedNamePrefix = regexp.MustCompile(`^DELETED-[0-9.]+-`)
+
+// OriginalName returns the name the repo had before it was deleted. The names
+// of repos that aren't deleted are returned as is.
+func (r *Repo) OriginalName() string {
+	return deletedNamePrefix.ReplaceAllLiteralString(r.Name, "")
+}
+
 // Update updates Repo r with the fields from the given newer Repo n,
 // returning true if modified.
 func (r *Repo) Update(n *Repo) (modified bool) {
@@ -669,6 +728,18 @@ func (r *Repo) Update(n *Repo) (modified bool) {
 		r.Private, modified = n.Private, true
 	}
 
+	if !equalStrings(r.Topics, n.Topics) {
+		r.Topics, modified = n.Topics, true
+	}
+
+	if r.Stars != n.Stars {
+		r.Stars, modified = n.Stars, true
+	}
+
+	if r.DefaultBranch != n.DefaultBranch {
+		r.DefaultBranch, modified = n.DefaultBranch, true
+	}
+
 	if !reflect.DeepEqual(r.Sources, n.Sources) {
 		r.Sources, modified = n.Sources, true
 	}
@@ -771,6 +842,20 @@ func sortedSliceLess(a, b []string) bool {
 	return true
 }
 
+// equalStrings returns true if a and b contain the same strings in the same
+// order. A nil slice is equal to an empty one.
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // pick deterministically chooses between a and b a repo to keep and
 // discard. It is used when resolving conflicts on sourced repositories.
 func pick(a *Repo, b *Repo) (keep, discard *Repo) {
@@ -13,6 +13,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitolite"
 	"github.com/sourcegraph/sourcegraph/internal/jsonc"
 	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
@@ -96,6 +97,19 @@ func TestExternalService_Exclude(t *testing.T) {
 		UpdatedAt: now,
 	}
 
+	gerritService := ExternalService{
+		Kind:        extsvc.KindGerrit,
+		DisplayName: "Gerrit",
+		Config: `{
+			// Some comment
+			"url": "https://gerrit.mycorp.com",
+			"username": "admin",
+			"password": "secret"
+		}`,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
 	otherService := ExternalService{
 		Kind:        extsvc.KindOther,
 		DisplayName: "Other code hosts",
@@ -181,6 +195,9 @@ func TestExternalService_Exclude(t *testing.T) {
 		{
 			Metadata: &gitolite.Repo{Name: "foo"},
 		},
+		{
+			Metadata: &gerrit.Project{ID: "platform%2Fbuild", Name: "platform/build"},
+		},
 	}
 
 	var testCases []testCase
@@ -251,6 +268,18 @@ func TestExternalService_Exclude(t *testing.T) {
 					]
 				}`)
 			}),
+			gerritService.With(func(e *ExternalService) {
+				e.Config = formatJSON(t, `
+				{
+					// Some comment
+					"url": "https://gerrit.mycorp.com",
+					"username": "admin",
+					"password": "secret",
+					"exclude": [
+						{"name": "platform/build"}
+					]
+				}`)
+			}),
 			&otherService,
 		}
 
@@ -324,6 +353,18 @@ func TestExternalService_Exclude(t *testing.T) {
 					]
 				}`)
 			}),
+			gerritService.With(func(e *ExternalService) {
+				e.Config = formatJSON(t, `
+				{
+					// Some comment
+					"url": "https://gerrit.mycorp.com",
+					"username": "admin",
+					"password": "secret",
+					"exclude": [
+						{"pattern": "^device/.*"}
+					]
+				}`)
+			}),
 			otherService.With(func(e *ExternalService) {
 				e.Config = formatJSON(t, `
 				{
@@ -412,6 +453,19 @@ func TestExternalService_Exclude(t *testing.T) {
 						]
 					}`)
 				}),
+				gerritService.With(func(e *ExternalService) {
+					e.Config = formatJSON(t, `
+					{
+						// Some comment
+						"url": "https://gerrit.mycorp.com",
+						"username": "admin",
+						"password": "secret",
+						"exclude": [
+							{"pattern": "^device/.*"},
+							{"name": "platform/build"}
+						]
+					}`)
+				}),
 				otherService.With(func(e *ExternalService) {
 					e.Config = formatJSON(t, `
 					{
@@ -481,6 +535,19 @@ func TestReposNamesSummary(t *testing.T) {
 // Our uses of pick happen from iterating through a map. So we can't guarantee
 // that we test both pick(a, b) and pick(b, a) without writing this specific
 // test.
+func TestRepo_OriginalName(t *testing.T) {
+	for name, want := range map[string]string{
+		"github.com/foo/bar":                           "github.com/foo/bar",
+		"DELETED-1588000000.123456-github.com/foo/bar": "github.com/foo/bar",
+		"DELETED-1588000000-github.com/foo/bar":        "github.com/foo/bar",
+		"DELETED-github.com/foo/bar":                   "DELETED-github.com/foo/bar",
+	} {
+		if have := (&Repo{Name: name}).OriginalName(); have != want {
+			t.Errorf("%q: have %q, want %q", name, have, want)
+		}
+	}
+}
+
 func TestPick(t *testing.T) {
 	eid := func(id string) api.ExternalRepoSpec {
 		return api.ExternalRepoSpec{
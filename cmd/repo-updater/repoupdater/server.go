@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -77,9 +78,14 @@ func (s *Server) Handler() http.Handler {
 	mux.HandleFunc("/enqueue-repo-update", s.handleEnqueueRepoUpdate)
 	mux.HandleFunc("/exclude-repo", s.handleExcludeRepo)
 	mux.HandleFunc("/sync-external-service", s.handleExternalServiceSync)
+	mux.HandleFunc("/external-service-dry-run", s.handleExternalServiceDryRun)
+	mux.HandleFunc("/restore-repo", s.handleRestoreRepo)
 	mux.HandleFunc("/status-messages", s.handleStatusMessages)
 	mux.HandleFunc("/enqueue-changeset-sync", s.handleEnqueueChangesetSync)
 	mux.HandleFunc("/schedule-perms-sync", s.handleSchedulePermsSync)
+	mux.HandleFunc("/webhooks/github", s.handleGitHubWebhook)
+	mux.HandleFunc("/webhooks/gitlab", s.handleGitLabWebhook)
+	mux.HandleFunc("/webhooks/bitbucket-server", s.handleBitbucketServerWebhook)
 	return mux
 }
 
@@ -177,6 +183,70 @@ func (s *Server) handleExcludeRepo(w http.ResponseWriter, r *http.Request) {
 	respond(w, http.StatusOK, resp)
 }
 
+// handleRestoreRepo restores a repository that was deleted within the purge
+// grace period, so its clone is still on gitserver. Syncs keep the restored
+// repository for another grace period, after which it is deleted again unless
+// an external service yields it by then.
+func (s *Server) handleRestoreRepo(w http.ResponseWriter, r *http.Request) {
+	var req protocol.RestoreRepoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond(w, http.StatusBadRequest, err)
+		return
+	}
+
+	resp, status, err := s.restoreRepo(r.Context(), req.Repo)
+	if err != nil {
+		respond(w, status, err)
+		return
+	}
+	respond(w, status, resp)
+}
+
+func (s *Server) restoreRepo(ctx context.Context, name api.RepoName) (*protocol.RestoreRepoResponse, int, error) {
+	rs, err := s.Store.ListRepos(ctx, repos.StoreListReposArgs{Names: []string{string(name)}})
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "store.list-repos")
+	}
+	if len(rs) > 0 {
+		return nil, http.StatusConflict, errors.Errorf("repo %q is not deleted", name)
+	}
+
+	now := time.Now()
+	deleted, err := s.Store.ListRepos(ctx, repos.StoreListReposArgs{
+		DeletedSince: now.Add(-repos.GetPurgeGracePeriod()),
+	})
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "store.list-repos")
+	}
+
+	// The same name may have been deleted more than once, in which case the
+	// most recently deleted repo is restored.
+	var repo *repos.Repo
+	for _, r := range deleted {
+		if strings.EqualFold(r.OriginalName(), string(name)) && (repo == nil || r.DeletedAt.After(repo.DeletedAt)) {
+			repo = r
+		}
+	}
+	if repo == nil {
+		return nil, http.StatusNotFound, errors.Errorf("repo %q was not deleted within the purge grace period", name)
+	}
+
+	repo.Name = repo.OriginalName()
+	repo.UpdatedAt, repo.DeletedAt, repo.RestoredAt = now, time.Time{}, now
+	if err = s.Store.UpsertRepos(ctx, repo); err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "store.upsert-repos")
+	}
+
+	var url string
+	if urls := repo.CloneURLs(); len(urls) > 0 {
+		url = urls[0]
+	}
+	s.Scheduler.UpdateOnce(repo.ID, api.RepoName(repo.Name), url)
+
+	log15.Info("server.restore-repo", "repo", repo.Name, "id", repo.ID)
+	return &protocol.RestoreRepoResponse{ID: repo.ID, Name: repo.Name}, http.StatusOK, nil
+}
+
 // TODO(tsenart): Reuse this function in all handlers.
 func respond(w http.ResponseWriter, code int, v interface{}) {
 	switch val := v.(type) {
@@ -363,6 +433,49 @@ func (s *Server) handleExternalServiceSync(w http.ResponseWriter, r *http.Reques
 	})
 }
 
+// handleExternalServiceDryRun lists the repositories that syncing the external
+// service in the request would add, modify and delete, without storing
+// anything.
+func (s *Server) handleExternalServiceDryRun(w http.ResponseWriter, r *http.Request) {
+	var req protocol.ExternalServiceDryRunRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond(w, http.StatusBadRequest, err)
+		return
+	}
+
+	diff, err := s.Syncer.Preview(r.Context(), &repos.ExternalService{
+		ID:          req.ExternalService.ID,
+		Kind:        req.ExternalService.Kind,
+		DisplayName: req.ExternalService.DisplayName,
+		Config:      req.ExternalService.Config,
+	})
+	if err != nil {
+		respond(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respond(w, http.StatusOK, &protocol.ExternalServiceDryRunResult{
+		Added:           sampleRepoNames(diff.Added, req.SampleSize),
+		Modified:        sampleRepoNames(diff.Modified, req.SampleSize),
+		Deleted:         sampleRepoNames(diff.Deleted, req.SampleSize),
+		AddedCount:      len(diff.Added),
+		ModifiedCount:   len(diff.Modified),
+		DeletedCount:    len(diff.Deleted),
+		UnmodifiedCount: len(diff.Unmodified),
+	})
+}
+
+// sampleRepoNames returns the sorted names of the given repos, at most n of
+// them if n is positive.
+func sampleRepoNames(rs repos.Repos, n int) []string {
+	names := rs.Names()
+	sort.Strings(names)
+	if n > 0 && len(names) > n {
+		names = names[:n]
+	}
+	return names
+}
+
 func externalServiceValidate(ctx context.Context, req *protocol.ExternalServiceSyncRequest) error {
 	if req.ExternalService.DeletedAt != nil {
 		// We don't need to check deleted services.
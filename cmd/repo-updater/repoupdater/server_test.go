@@ -21,6 +21,7 @@ import (
 	"github.com/opentracing/opentracing-go"
 	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
@@ -30,6 +31,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/jsonc"
 	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
 	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 func TestServer_handleRepoLookup(t *testing.T) {
@@ -450,6 +452,226 @@ func TestServer_EnqueueRepoUpdate(t *testing.T) {
 	}
 }
 
+func TestServer_RestoreRepo(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{RepoPurgeGracePeriod: 24}})
+	defer conf.Mock(nil)
+
+	repo := repos.Repo{
+		Name: "github.com/foo/bar",
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          "bar",
+			ServiceType: extsvc.TypeGitHub,
+			ServiceID:   "http://github.com",
+		},
+		Metadata: new(github.Repository),
+		Sources: map[string]*repos.SourceInfo{
+			"extsvc:123": {
+				ID:       "extsvc:123",
+				CloneURL: "https://secret-token@github.com/foo/bar",
+			},
+		},
+	}
+
+	now := time.Now()
+
+	for _, tc := range []struct {
+		name      string
+		deletedAt time.Time
+		err       string
+		updated   []string
+	}{
+		{
+			name:      "deleted within grace period",
+			deletedAt: now.Add(-time.Hour),
+			updated:   []string{"github.com/foo/bar https://secret-token@github.com/foo/bar"},
+		},
+		{
+			name:      "deleted before grace period",
+			deletedAt: now.Add(-48 * time.Hour),
+			err:       `repo "github.com/foo/bar" was not deleted within the purge grace period`,
+		},
+		{
+			name: "not deleted",
+			err:  `repo "github.com/foo/bar" is not deleted`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			store := new(repos.FakeStore)
+			stored := repo.Clone()
+			must(store.UpsertRepos(ctx, stored))
+			if !tc.deletedAt.IsZero() {
+				// Deleted repos keep their sources in the database.
+				deleted := stored.Clone()
+				deleted.DeletedAt = tc.deletedAt
+				must(store.UpsertRepos(ctx, deleted))
+			}
+
+			scheduler := &fakeScheduler{}
+			s := &Server{Store: store, Scheduler: scheduler}
+			srv := httptest.NewServer(s.Handler())
+			defer srv.Close()
+			cli := repoupdater.Client{URL: srv.URL}
+
+			if tc.err == "" {
+				tc.err = "<nil>"
+			}
+
+			res, err := cli.RestoreRepo(ctx, api.RepoName(repo.Name))
+			if have, want := fmt.Sprint(err), tc.err; have != want {
+				t.Fatalf("have err: %q, want: %q", have, want)
+			}
+
+			if diff := cmp.Diff(tc.updated, scheduler.updated); diff != "" {
+				t.Errorf("unexpected updates (-want +got):\n%s", diff)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if have, want := res, (&protocol.RestoreRepoResponse{ID: stored.ID, Name: repo.Name}); !reflect.DeepEqual(have, want) {
+				t.Errorf("response: %s", cmp.Diff(have, want))
+			}
+
+			rs, err := store.ListRepos(ctx, repos.StoreListReposArgs{Names: []string{repo.Name}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rs) != 1 {
+				t.Fatalf("restored repo not listed: %v", repos.Repos(rs).Names())
+			}
+
+			// Syncs keep the restored repo for the grace period, even though
+			// no external service yields it.
+			for _, sc := range []struct {
+				now  time.Time
+				kept bool
+			}{
+				{now: now.Add(time.Hour), kept: true},
+				{now: now.Add(48 * time.Hour), kept: false},
+			} {
+				syncer := &repos.Syncer{
+					Store:            store,
+					Sourcer:          repos.NewFakeSourcer(nil),
+					DisableStreaming: true,
+					Now:              func() time.Time { return sc.now },
+				}
+				if err := syncer.Sync(ctx); err != nil {
+					t.Fatal(err)
+				}
+
+				rs, err := store.ListRepos(ctx, repos.StoreListReposArgs{Names: []string{repo.Name}})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if kept := len(rs) == 1; kept != sc.kept {
+					t.Errorf("sync at %s: restored repo kept: %t, want %t", sc.now.Sub(now), kept, sc.kept)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_ExternalServiceDryRun(t *testing.T) {
+	ctx := context.Background()
+
+	githubService := &repos.ExternalService{
+		Kind:   extsvc.KindGitHub,
+		Config: `{"url": "https://github.com", "token": "abc"}`,
+	}
+	gitlabService := &repos.ExternalService{
+		Kind:   extsvc.KindGitLab,
+		Config: `{"url": "https://gitlab.com", "token": "abc"}`,
+	}
+
+	store := new(repos.FakeStore)
+	must(store.UpsertExternalServices(ctx, githubService, gitlabService))
+
+	repo := func(name string, svcs ...*repos.ExternalService) *repos.Repo {
+		r := &repos.Repo{
+			Name: name,
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          name,
+				ServiceType: extsvc.TypeGitHub,
+				ServiceID:   "https://github.com/",
+			},
+		}
+		urns := make([]string, 0, len(svcs))
+		for _, svc := range svcs {
+			urns = append(urns, svc.URN())
+		}
+		return r.With(repos.Opt.RepoSources(urns...))
+	}
+
+	kept := repo("github.com/foo/kept", githubService)
+	removed := repo("github.com/foo/removed", githubService)
+	shared := repo("github.com/foo/shared", githubService, gitlabService)
+	other := repo("github.com/foo/other", gitlabService)
+	must(store.UpsertRepos(ctx, kept, removed, shared, other))
+
+	added1 := repo("github.com/foo/added1", githubService)
+	added2 := repo("github.com/foo/added2", githubService)
+
+	s := &Server{
+		Store: store,
+		Syncer: &repos.Syncer{
+			Store: store,
+			Sourcer: func(svcs ...*repos.ExternalService) (repos.Sources, error) {
+				return repos.Sources{repos.NewFakeSource(svcs[0], nil, kept.Clone(), added2.Clone(), added1.Clone())}, nil
+			},
+			Now: time.Now,
+		},
+	}
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+	cli := repoupdater.Client{URL: srv.URL}
+
+	svc := api.ExternalService{
+		ID:     githubService.ID,
+		Kind:   githubService.Kind,
+		Config: `{"url": "https://github.com", "token": "abc", "exclude": [{"name": "foo/removed"}]}`,
+	}
+
+	res, err := cli.ExternalServiceDryRun(ctx, svc, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &protocol.ExternalServiceDryRunResult{
+		Added:           []string{added1.Name, added2.Name},
+		Modified:        []string{shared.Name},
+		Deleted:         []string{removed.Name},
+		AddedCount:      2,
+		ModifiedCount:   1,
+		DeletedCount:    1,
+		UnmodifiedCount: 1,
+	}
+	if diff := cmp.Diff(want, res); diff != "" {
+		t.Errorf("unexpected dry run result (-want +got):\n%s", diff)
+	}
+
+	res, err = cli.ExternalServiceDryRun(ctx, svc, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want.Added = want.Added[:1]
+	if diff := cmp.Diff(want, res); diff != "" {
+		t.Errorf("unexpected sampled dry run result (-want +got):\n%s", diff)
+	}
+
+	rs, err := store.ListRepos(ctx, repos.StoreListReposArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := len(rs), 4; have != want {
+		t.Errorf("dry run changed the store: have %d repos, want %d", have, want)
+	}
+}
+
 func TestServer_RepoExternalServices(t *testing.T) {
 	service1 := &repos.ExternalService{
 		ID:          1,
@@ -1135,9 +1357,13 @@ func (s *fakeRepoSource) GetRepo(context.Context, string) (*repos.Repo, error) {
 	return s.repo.Clone(), s.err
 }
 
-type fakeScheduler struct{}
+type fakeScheduler struct {
+	updated []string
+}
 
-func (s *fakeScheduler) UpdateOnce(_ api.RepoID, _ api.RepoName, _ string) {}
+func (s *fakeScheduler) UpdateOnce(_ api.RepoID, name api.RepoName, url string) {
+	s.updated = append(s.updated, string(name)+" "+url)
+}
 func (s *fakeScheduler) ScheduleInfo(id api.RepoID) *protocol.RepoUpdateSchedulerInfoResult {
 	return &protocol.RepoUpdateSchedulerInfoResult{}
 }
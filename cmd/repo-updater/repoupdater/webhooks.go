@@ -0,0 +1,321 @@
+package repoupdater
+
+import (
+	"crypto/subtle"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	gh "github.com/google/go-github/v28/github"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// errUnauthorizedWebhook is returned when none of the configured webhook
+// secrets authenticates a webhook request.
+var errUnauthorizedWebhook = errors.New("webhook request could not be authenticated")
+
+// handleGitHubWebhook applies the repository events of GitHub organization
+// webhooks and schedules an immediate update of repositories on push events.
+// Other events are ignored.
+func (s *Server) handleGitHubWebhook(w http.ResponseWriter, r *http.Request) {
+	payload, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		respond(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	sig := r.Header.Get("X-Hub-Signature")
+	svc, status, err := s.webhookExternalService(r, extsvc.KindGitHub, func(c interface{}) bool {
+		for _, hook := range c.(*schema.GitHubConnection).Webhooks {
+			if hook.Secret != "" && gh.ValidateSignature(sig, payload, []byte(hook.Secret)) == nil {
+				return true
+			}
+		}
+		return false
+	})
+	if err != nil {
+		respond(w, status, err)
+		return
+	}
+
+	e, err := gh.ParseWebHook(gh.WebHookType(r), payload)
+	if err != nil {
+		respond(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if pe, ok := e.(*gh.PushEvent); ok && pe.GetRepo() != nil {
+		s.updatePushedRepo(w, r, svc, api.ExternalRepoSpec{
+			ID:          pe.GetRepo().GetNodeID(),
+			ServiceType: extsvc.TypeGitHub,
+		})
+		return
+	}
+
+	re, ok := e.(*gh.RepositoryEvent)
+	if !ok || re.GetRepo() == nil {
+		respond(w, http.StatusOK, nil) // Nothing to do
+		return
+	}
+
+	var action string
+	switch re.GetAction() {
+	case "created":
+		action = repos.RepoCreated
+	case "renamed":
+		action = repos.RepoRenamed
+	case "transferred":
+		action = repos.RepoTransferred
+	case "deleted":
+		action = repos.RepoDeleted
+	case "archived":
+		action = repos.RepoArchived
+	case "unarchived":
+		action = repos.RepoUnarchived
+	case "publicized", "privatized":
+		action = repos.RepoVisibilityChanged
+	default:
+		action = repos.RepoEdited
+	}
+
+	s.syncRepoEvent(w, r, svc, repos.RepoEvent{
+		Action: action,
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          re.GetRepo().GetNodeID(),
+			ServiceType: extsvc.TypeGitHub,
+		},
+		Name: re.GetRepo().GetFullName(),
+	})
+}
+
+// handleGitLabWebhook applies the project events of GitLab system hooks and
+// schedules an immediate update of projects on push events, which are sent by
+// both system hooks and project or group push hooks.
+func (s *Server) handleGitLabWebhook(w http.ResponseWriter, r *http.Request) {
+	payload, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		respond(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	token := []byte(gitlab.WebhookToken(r))
+	svc, status, err := s.webhookExternalService(r, extsvc.KindGitLab, func(c interface{}) bool {
+		for _, hook := range c.(*schema.GitLabConnection).Webhooks {
+			if hook.Secret != "" && subtle.ConstantTimeCompare(token, []byte(hook.Secret)) == 1 {
+				return true
+			}
+		}
+		return false
+	})
+	if err != nil {
+		respond(w, status, err)
+		return
+	}
+
+	eventType := gitlab.WebhookEventType(r)
+	switch eventType {
+	case "System Hook", "Push Hook", "Tag Push Hook":
+	default:
+		respond(w, http.StatusOK, nil) // Nothing to do
+		return
+	}
+
+	e, err := gitlab.ParseWebhookEvent(eventType, payload)
+	if err == gitlab.ErrIgnoredEvent {
+		respond(w, http.StatusOK, nil) // Nothing to do
+		return
+	} else if err != nil {
+		respond(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if push, ok := e.(*gitlab.PushEvent); ok {
+		s.updatePushedRepo(w, r, svc, api.ExternalRepoSpec{
+			ID:          strconv.Itoa(push.ProjectID),
+			ServiceType: extsvc.TypeGitLab,
+		})
+		return
+	}
+
+	pe := e.(*gitlab.ProjectEvent)
+
+	var action string
+	switch pe.EventName {
+	case "project_create":
+		action = repos.RepoCreated
+	case "project_rename":
+		action = repos.RepoRenamed
+	case "project_transfer":
+		action = repos.RepoTransferred
+	case "project_destroy":
+		action = repos.RepoDeleted
+	default:
+		action = repos.RepoEdited
+	}
+
+	s.syncRepoEvent(w, r, svc, repos.RepoEvent{
+		Action: action,
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          strconv.Itoa(pe.ProjectID),
+			ServiceType: extsvc.TypeGitLab,
+		},
+		Name: pe.PathWithNamespace,
+	})
+}
+
+// handleBitbucketServerWebhook applies the repository events of Bitbucket
+// Server webhooks and schedules an immediate update of repositories on push
+// events. Bitbucket Server doesn't send events for created or deleted
+// repositories, so those are only picked up by the periodic full sync.
+func (s *Server) handleBitbucketServerWebhook(w http.ResponseWriter, r *http.Request) {
+	payload, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		respond(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	sig := r.Header.Get("X-Hub-Signature")
+	svc, status, err := s.webhookExternalService(r, extsvc.KindBitbucketServer, func(c interface{}) bool {
+		secret := c.(*schema.BitbucketServerConnection).WebhookSecret()
+		return secret != "" && gh.ValidateSignature(sig, payload, []byte(secret)) == nil
+	})
+	if err != nil {
+		respond(w, status, err)
+		return
+	}
+
+	eventType := bitbucketserver.WebhookEventType(r)
+	switch eventType {
+	case "repo:modified", "repo:refs_changed":
+	default:
+		respond(w, http.StatusOK, nil) // Nothing to do
+		return
+	}
+
+	e, err := bitbucketserver.ParseWebhookEvent(eventType, payload)
+	if err != nil {
+		respond(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if rc, ok := e.(*bitbucketserver.RepoRefsChangedEvent); ok {
+		if rc.Repository == nil {
+			respond(w, http.StatusBadRequest, errors.New("repo:refs_changed event without repository"))
+			return
+		}
+		s.updatePushedRepo(w, r, svc, api.ExternalRepoSpec{
+			ID:          strconv.Itoa(rc.Repository.ID),
+			ServiceType: extsvc.TypeBitbucketServer,
+		})
+		return
+	}
+
+	me := e.(*bitbucketserver.RepoModifiedEvent)
+	if me.Old == nil || me.New == nil || me.New.Project == nil {
+		respond(w, http.StatusBadRequest, errors.New("repo:modified event without repository"))
+		return
+	}
+
+	action := repos.RepoEdited
+	switch {
+	case me.Old.Project != nil && me.Old.Project.Key != me.New.Project.Key:
+		action = repos.RepoTransferred
+	case me.Old.Slug != me.New.Slug:
+		action = repos.RepoRenamed
+	}
+
+	s.syncRepoEvent(w, r, svc, repos.RepoEvent{
+		Action: action,
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          strconv.Itoa(me.New.ID),
+			ServiceType: extsvc.TypeBitbucketServer,
+		},
+		Name: me.New.Project.Key + "/" + me.New.Slug,
+	})
+}
+
+// webhookExternalService returns the external service of the given kind whose
+// configuration authenticates the webhook request. If the request has an
+// external service ID parameter, only that external service is considered.
+func (s *Server) webhookExternalService(r *http.Request, kind string, authenticates func(config interface{}) bool) (*repos.ExternalService, int, error) {
+	args := repos.StoreListExternalServicesArgs{Kinds: []string{kind}}
+
+	// Webhooks that were set up before the external service ID was part of
+	// the URL don't send it, in which case all external services are tried.
+	if rawID := r.FormValue(extsvc.IDParam); rawID != "" {
+		id, err := strconv.ParseInt(rawID, 10, 64)
+		if err != nil {
+			return nil, http.StatusBadRequest, errors.Wrap(err, "invalid external service id")
+		}
+		args.IDs = append(args.IDs, id)
+	}
+
+	es, err := s.Store.ListExternalServices(r.Context(), args)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// 🚨 SECURITY: Only accept requests that are authenticated by one of the
+	// configured webhook secrets.
+	for _, e := range es {
+		c, err := e.Configuration()
+		if err != nil {
+			continue
+		}
+		if authenticates(c) {
+			return e, http.StatusOK, nil
+		}
+	}
+
+	return nil, http.StatusUnauthorized, errUnauthorizedWebhook
+}
+
+func (s *Server) syncRepoEvent(w http.ResponseWriter, r *http.Request, svc *repos.ExternalService, ev repos.RepoEvent) {
+	if err := s.Syncer.SyncRepoEvents(r.Context(), svc, ev); err != nil {
+		respond(w, http.StatusInternalServerError, err)
+		return
+	}
+	respond(w, http.StatusOK, nil)
+}
+
+// updatePushedRepo schedules an immediate update of the stored repository
+// that was pushed to. Repositories that aren't stored are ignored. Pushes
+// received while the repository is being updated result in a single
+// follow-up update, so bursts of pushes don't cause a fetch for each one.
+func (s *Server) updatePushedRepo(w http.ResponseWriter, r *http.Request, svc *repos.ExternalService, spec api.ExternalRepoSpec) {
+	baseURL, err := svc.BaseURL()
+	if err != nil {
+		respond(w, http.StatusInternalServerError, err)
+		return
+	}
+	spec.ServiceID = baseURL.String()
+
+	rs, err := s.Store.ListRepos(r.Context(), repos.StoreListReposArgs{ExternalRepos: []api.ExternalRepoSpec{spec}})
+	if err != nil {
+		respond(w, http.StatusInternalServerError, errors.Wrap(err, "store.list-repos"))
+		return
+	}
+
+	if len(rs) != 1 {
+		respond(w, http.StatusOK, nil) // Nothing to do
+		return
+	}
+
+	repo := rs[0]
+
+	var url string
+	if src := repo.Sources[svc.URN()]; src != nil && src.CloneURL != "" {
+		url = src.CloneURL
+	} else if urls := repo.CloneURLs(); len(urls) > 0 {
+		url = urls[0]
+	}
+
+	s.Scheduler.UpdateOnce(repo.ID, api.RepoName(repo.Name), url)
+	respond(w, http.StatusOK, nil)
+}
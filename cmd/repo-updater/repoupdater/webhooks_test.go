@@ -0,0 +1,296 @@
+package repoupdater
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+)
+
+func TestServer_Webhooks(t *testing.T) {
+	githubService := &repos.ExternalService{
+		Kind:   extsvc.KindGitHub,
+		Config: `{"url": "https://github.com", "token": "abc", "webhooks": [{"org": "org", "secret": "secret"}]}`,
+	}
+	gitlabService := &repos.ExternalService{
+		Kind:   extsvc.KindGitLab,
+		Config: `{"url": "https://gitlab.com", "token": "abc", "webhooks": [{"secret": "secret"}]}`,
+	}
+	bitbucketServerService := &repos.ExternalService{
+		Kind:   extsvc.KindBitbucketServer,
+		Config: `{"url": "https://bitbucket.example.com", "token": "abc", "username": "admin", "plugin": {"webhooks": {"secret": "secret"}}}`,
+	}
+
+	repo := func(serviceType, serviceID, id, name string) *repos.Repo {
+		return &repos.Repo{
+			Name: name,
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          id,
+				ServiceType: serviceType,
+				ServiceID:   serviceID,
+			},
+		}
+	}
+
+	sign := func(secret, payload string) string {
+		mac := hmac.New(sha1.New, []byte(secret))
+		mac.Write([]byte(payload))
+		return "sha1=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	githubRenamed := `{"action": "renamed", "repository": {"node_id": "MDEw", "full_name": "org/new"}}`
+	gitlabDestroyed := `{"event_name": "project_destroy", "project_id": 42, "path_with_namespace": "group/project"}`
+	githubPushed := `{"ref": "refs/heads/master", "repository": {"node_id": "MDEw", "full_name": "org/repo"}}`
+	gitlabPushed := `{"object_kind": "push", "ref": "refs/heads/master", "project_id": 42, "project": {"path_with_namespace": "group/project"}}`
+	bitbucketServerRefsChanged := `{
+		"repository": {"id": 7, "slug": "repo", "project": {"key": "PROJ"}},
+		"changes": [{"refId": "refs/heads/master", "type": "UPDATE"}]
+	}`
+	bitbucketServerModified := `{
+		"old": {"id": 7, "slug": "old", "project": {"key": "PROJ"}},
+		"new": {"id": 7, "slug": "new", "project": {"key": "PROJ"}}
+	}`
+
+	for _, tc := range []struct {
+		name    string
+		path    string
+		header  map[string]string
+		payload string
+		svc     *repos.ExternalService
+		sourced *repos.Repo
+		stored  *repos.Repo
+		code    int
+		want    []string
+		updated []string
+	}{
+		{
+			name: "github repository renamed",
+			path: "/webhooks/github",
+			header: map[string]string{
+				"X-GitHub-Event":  "repository",
+				"X-Hub-Signature": sign("secret", githubRenamed),
+			},
+			payload: githubRenamed,
+			svc:     githubService,
+			sourced: repo(extsvc.TypeGitHub, "https://github.com/", "MDEw", "github.com/org/new"),
+			stored:  repo(extsvc.TypeGitHub, "https://github.com/", "MDEw", "github.com/org/old"),
+			code:    http.StatusOK,
+			want:    []string{"github.com/org/new"},
+		},
+		{
+			name: "github invalid signature",
+			path: "/webhooks/github",
+			header: map[string]string{
+				"X-GitHub-Event":  "repository",
+				"X-Hub-Signature": sign("wrong", githubRenamed),
+			},
+			payload: githubRenamed,
+			svc:     githubService,
+			stored:  repo(extsvc.TypeGitHub, "https://github.com/", "MDEw", "github.com/org/old"),
+			code:    http.StatusUnauthorized,
+			want:    []string{"github.com/org/old"},
+		},
+		{
+			name: "github other event",
+			path: "/webhooks/github",
+			header: map[string]string{
+				"X-GitHub-Event":  "star",
+				"X-Hub-Signature": sign("secret", `{"action": "created"}`),
+			},
+			payload: `{"action": "created"}`,
+			svc:     githubService,
+			stored:  repo(extsvc.TypeGitHub, "https://github.com/", "MDEw", "github.com/org/old"),
+			code:    http.StatusOK,
+			want:    []string{"github.com/org/old"},
+		},
+		{
+			name: "github push",
+			path: "/webhooks/github",
+			header: map[string]string{
+				"X-GitHub-Event":  "push",
+				"X-Hub-Signature": sign("secret", githubPushed),
+			},
+			payload: githubPushed,
+			svc:     githubService,
+			stored:  repo(extsvc.TypeGitHub, "https://github.com/", "MDEw", "github.com/org/repo"),
+			code:    http.StatusOK,
+			want:    []string{"github.com/org/repo"},
+			updated: []string{"github.com/org/repo https://github.com/org/repo.git"},
+		},
+		{
+			name: "github push to unknown repository",
+			path: "/webhooks/github",
+			header: map[string]string{
+				"X-GitHub-Event":  "push",
+				"X-Hub-Signature": sign("secret", githubPushed),
+			},
+			payload: githubPushed,
+			svc:     githubService,
+			stored:  repo(extsvc.TypeGitHub, "https://github.com/", "MDEx", "github.com/org/other"),
+			code:    http.StatusOK,
+			want:    []string{"github.com/org/other"},
+		},
+		{
+			name: "github push invalid signature",
+			path: "/webhooks/github",
+			header: map[string]string{
+				"X-GitHub-Event":  "push",
+				"X-Hub-Signature": sign("wrong", githubPushed),
+			},
+			payload: githubPushed,
+			svc:     githubService,
+			stored:  repo(extsvc.TypeGitHub, "https://github.com/", "MDEw", "github.com/org/repo"),
+			code:    http.StatusUnauthorized,
+			want:    []string{"github.com/org/repo"},
+		},
+		{
+			name: "gitlab push hook",
+			path: "/webhooks/gitlab",
+			header: map[string]string{
+				"X-Gitlab-Event": "Push Hook",
+				"X-Gitlab-Token": "secret",
+			},
+			payload: gitlabPushed,
+			svc:     gitlabService,
+			stored:  repo(extsvc.TypeGitLab, "https://gitlab.com/", "42", "gitlab.com/group/project"),
+			code:    http.StatusOK,
+			want:    []string{"gitlab.com/group/project"},
+			updated: []string{"gitlab.com/group/project https://gitlab.com/group/project.git"},
+		},
+		{
+			name: "gitlab project destroyed",
+			path: "/webhooks/gitlab",
+			header: map[string]string{
+				"X-Gitlab-Event": "System Hook",
+				"X-Gitlab-Token": "secret",
+			},
+			payload: gitlabDestroyed,
+			svc:     gitlabService,
+			stored:  repo(extsvc.TypeGitLab, "https://gitlab.com/", "42", "gitlab.com/group/project"),
+			code:    http.StatusOK,
+			want:    []string{},
+		},
+		{
+			name: "gitlab other system hook event",
+			path: "/webhooks/gitlab",
+			header: map[string]string{
+				"X-Gitlab-Event": "System Hook",
+				"X-Gitlab-Token": "secret",
+			},
+			payload: `{"event_name": "user_create", "user_id": 1}`,
+			svc:     gitlabService,
+			stored:  repo(extsvc.TypeGitLab, "https://gitlab.com/", "42", "gitlab.com/group/project"),
+			code:    http.StatusOK,
+			want:    []string{"gitlab.com/group/project"},
+		},
+		{
+			name: "gitlab invalid token",
+			path: "/webhooks/gitlab",
+			header: map[string]string{
+				"X-Gitlab-Event": "System Hook",
+				"X-Gitlab-Token": "wrong",
+			},
+			payload: gitlabDestroyed,
+			svc:     gitlabService,
+			stored:  repo(extsvc.TypeGitLab, "https://gitlab.com/", "42", "gitlab.com/group/project"),
+			code:    http.StatusUnauthorized,
+			want:    []string{"gitlab.com/group/project"},
+		},
+		{
+			name: "bitbucket server repository modified",
+			path: "/webhooks/bitbucket-server",
+			header: map[string]string{
+				"X-Event-Key":     "repo:modified",
+				"X-Hub-Signature": sign("secret", bitbucketServerModified),
+			},
+			payload: bitbucketServerModified,
+			svc:     bitbucketServerService,
+			sourced: repo(extsvc.TypeBitbucketServer, "https://bitbucket.example.com/", "7", "bitbucket.example.com/PROJ/new"),
+			stored:  repo(extsvc.TypeBitbucketServer, "https://bitbucket.example.com/", "7", "bitbucket.example.com/PROJ/old"),
+			code:    http.StatusOK,
+			want:    []string{"bitbucket.example.com/PROJ/new"},
+		},
+		{
+			name: "bitbucket server refs changed",
+			path: "/webhooks/bitbucket-server",
+			header: map[string]string{
+				"X-Event-Key":     "repo:refs_changed",
+				"X-Hub-Signature": sign("secret", bitbucketServerRefsChanged),
+			},
+			payload: bitbucketServerRefsChanged,
+			svc:     bitbucketServerService,
+			stored:  repo(extsvc.TypeBitbucketServer, "https://bitbucket.example.com/", "7", "bitbucket.example.com/PROJ/repo"),
+			code:    http.StatusOK,
+			want:    []string{"bitbucket.example.com/PROJ/repo"},
+			updated: []string{"bitbucket.example.com/PROJ/repo https://bitbucket.example.com/PROJ/repo.git"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			store := new(repos.FakeStore)
+			svc := tc.svc.Clone()
+			if err := store.UpsertExternalServices(ctx, svc); err != nil {
+				t.Fatal(err)
+			}
+
+			stored := tc.stored.With(repos.Opt.RepoSources(svc.URN()))
+			stored.Sources[svc.URN()].CloneURL = "https://" + stored.Name + ".git"
+			if err := store.UpsertRepos(ctx, stored); err != nil {
+				t.Fatal(err)
+			}
+
+			var sourced []*repos.Repo
+			if tc.sourced != nil {
+				sourced = append(sourced, tc.sourced.With(repos.Opt.RepoSources(svc.URN())))
+			}
+
+			scheduler := &fakeScheduler{}
+			s := &Server{
+				Store:     store,
+				Scheduler: scheduler,
+				Syncer: &repos.Syncer{
+					Store:   store,
+					Sourcer: repos.NewFakeSourcer(nil, repos.NewFakeSource(svc, nil, sourced...)),
+					Now:     time.Now,
+				},
+			}
+
+			req := httptest.NewRequest("POST", tc.path, strings.NewReader(tc.payload))
+			for k, v := range tc.header {
+				req.Header.Set(k, v)
+			}
+
+			rec := httptest.NewRecorder()
+			s.Handler().ServeHTTP(rec, req)
+
+			if have, want := rec.Code, tc.code; have != want {
+				t.Fatalf("have status %d, want %d: %s", have, want, rec.Body.String())
+			}
+
+			rs, err := store.ListRepos(ctx, repos.StoreListReposArgs{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.want, repos.Repos(rs).Names()); diff != "" {
+				t.Errorf("unexpected repos (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.updated, scheduler.updated); diff != "" {
+				t.Errorf("unexpected updates (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -190,7 +190,7 @@ func Main(enterpriseInit EnterpriseInit) {
 
 	if !envvar.SourcegraphDotComMode() {
 		// git-server repos purging thread
-		go repos.RunRepositoryPurgeWorker(ctx)
+		go repos.RunRepositoryPurgeWorker(ctx, store)
 	}
 
 	// Git fetches scheduler
@@ -25,6 +25,11 @@ type Request struct {
 
 	PatternInfo
 
+	// Submodules are the submodules of Commit whose contents are searched
+	// along with it. Matches in a submodule have a path prefixed with the
+	// submodule path.
+	Submodules []Submodule
+
 	// The amount of time to wait for a repo archive to fetch.
 	// It is parsed with time.ParseDuration.
 	//
@@ -47,6 +52,19 @@ type Request struct {
 // GitserverRepo returns the repository information necessary to perform gitserver requests.
 func (r Request) GitserverRepo() gitserver.Repo { return gitserver.Repo{Name: r.Repo} }
 
+// Submodule is a Git submodule whose contents are searched under its path.
+type Submodule struct {
+	// Path is the path of the submodule relative to the repository root.
+	Path string
+
+	// Repo is the name of the repository containing the submodule commit.
+	Repo api.RepoName
+
+	// Commit is the commit of Repo pinned by the submodule. It is required
+	// to be resolved.
+	Commit api.CommitID
+}
+
 // PatternInfo describes a search request on a repo. Most of the fields
 // are based on PatternInfo used in vscode.
 type PatternInfo struct {
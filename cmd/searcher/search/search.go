@@ -204,7 +204,7 @@ func (s *Service) search(ctx context.Context, p *protocol.Request) (matches []pr
 	defer cancel()
 
 	getZf := func() (string, *store.ZipFile, error) {
-		path, err := s.Store.PrepareZip(prepareCtx, p.GitserverRepo(), p.Commit)
+		path, err := s.Store.PrepareZipWithSubmodules(prepareCtx, p.GitserverRepo(), p.Commit, storeSubmodules(p.Submodules))
 		if err != nil {
 			return "", nil, err
 		}
@@ -243,12 +243,30 @@ func validateParams(p *protocol.Request) error {
 	if len(p.Commit) != 40 {
 		return errors.Errorf("Commit must be resolved (Commit=%q)", p.Commit)
 	}
+	for _, sm := range p.Submodules {
+		if sm.Path == "" || sm.Repo == "" || len(sm.Commit) != 40 {
+			return errors.Errorf("Submodules must have a path, repo and resolved commit (Submodule=%+v)", sm)
+		}
+	}
 	if p.Pattern == "" && p.ExcludePattern == "" && len(p.IncludePatterns) == 0 {
 		return errors.New("At least one of pattern and include/exclude pattners must be non-empty")
 	}
 	return nil
 }
 
+// storeSubmodules converts the submodules of a request to the submodules of
+// its archive.
+func storeSubmodules(submodules []protocol.Submodule) []store.Submodule {
+	if len(submodules) == 0 {
+		return nil
+	}
+	sms := make([]store.Submodule, len(submodules))
+	for i, sm := range submodules {
+		sms[i] = store.Submodule{Path: sm.Path, Repo: sm.Repo, Commit: sm.Commit}
+	}
+	return sms
+}
+
 const megabyte = float64(1000 * 1000)
 
 var (
@@ -277,6 +277,17 @@ func TestSearch_badrequest(t *testing.T) {
 			},
 		},
 
+		// Non-absolute submodule commit
+		{
+			Repo:   "foo",
+			URL:    "u",
+			Commit: "deadbeefdeadbeefdeadbeefdeadbeefdeadbeef",
+			PatternInfo: protocol.PatternInfo{
+				Pattern: "test",
+			},
+			Submodules: []protocol.Submodule{{Path: "vendor/bar", Repo: "bar", Commit: "HEAD"}},
+		},
+
 		// Bad include glob
 		{
 			Repo:   "foo",
@@ -378,6 +389,11 @@ func doSearch(u string, p *protocol.Request) ([]protocol.FileMatch, error) {
 	if p.PatternMatchesPath {
 		form.Set("PatternMatchesPath", "true")
 	}
+	for i, sm := range p.Submodules {
+		form.Set(fmt.Sprintf("Submodules.%d.Path", i), sm.Path)
+		form.Set(fmt.Sprintf("Submodules.%d.Repo", i), string(sm.Repo))
+		form.Set(fmt.Sprintf("Submodules.%d.Commit", i), string(sm.Commit))
+	}
 	resp, err := http.PostForm(u, form)
 	if err != nil {
 		return nil, err
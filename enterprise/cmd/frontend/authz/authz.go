@@ -18,6 +18,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	edb "github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/authz/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/authz/gerrit"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/authz/github"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/authz/gitlab"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/licensing"
@@ -73,6 +74,20 @@ func Init(d dbutil.DB, clock func() time.Time) {
 			}
 		}
 
+		gerrits, err := db.ExternalServices.ListGerritConnections(ctx)
+		if err != nil {
+			return []*graphqlbackend.Alert{{
+				TypeValue:    graphqlbackend.AlertTypeError,
+				MessageValue: fmt.Sprintf("Unable to fetch Gerrit external services: %s", err),
+			}}
+		}
+		for _, g := range gerrits {
+			if g.Authorization != nil {
+				authzTypes = append(authzTypes, "Gerrit")
+				break
+			}
+		}
+
 		if len(authzTypes) > 0 {
 			return []*graphqlbackend.Alert{{
 				TypeValue:    graphqlbackend.AlertTypeError,
@@ -144,6 +159,7 @@ type ExternalServicesStore interface {
 	ListGitLabConnections(context.Context) ([]*types.GitLabConnection, error)
 	ListGitHubConnections(context.Context) ([]*types.GitHubConnection, error)
 	ListBitbucketServerConnections(context.Context) ([]*types.BitbucketServerConnection, error)
+	ListGerritConnections(context.Context) ([]*types.GerritConnection, error)
 }
 
 // ProvidersFromConfig returns the set of permission-related providers derived from the site config.
@@ -196,6 +212,15 @@ func ProvidersFromConfig(
 		warnings = append(warnings, bbsWarnings...)
 	}
 
+	if gerritConns, err := s.ListGerritConnections(ctx); err != nil {
+		seriousProblems = append(seriousProblems, fmt.Sprintf("Could not load Gerrit external service configs: %s", err))
+	} else {
+		gerritProviders, gerritProblems, gerritWarnings := gerrit.NewAuthzProviders(gerritConns)
+		providers = append(providers, gerritProviders...)
+		seriousProblems = append(seriousProblems, gerritProblems...)
+		warnings = append(warnings, gerritWarnings...)
+	}
+
 	// 🚨 SECURITY: Warn the admin when both code host authz provider and the permissions user mapping are configured.
 	if cfg.SiteConfiguration.PermissionsUserMapping != nil &&
 		cfg.SiteConfiguration.PermissionsUserMapping.Enabled && len(providers) > 0 {
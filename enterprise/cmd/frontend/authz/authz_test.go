@@ -566,6 +566,7 @@ type fakeStore struct {
 	gitlabs          []*schema.GitLabConnection
 	githubs          []*schema.GitHubConnection
 	bitbucketServers []*schema.BitbucketServerConnection
+	gerrits          []*schema.GerritConnection
 }
 
 func (s fakeStore) ListGitHubConnections(context.Context) ([]*types.GitHubConnection, error) {
@@ -591,3 +592,11 @@ func (s fakeStore) ListBitbucketServerConnections(context.Context) ([]*types.Bit
 	}
 	return conns, nil
 }
+
+func (s fakeStore) ListGerritConnections(context.Context) ([]*types.GerritConnection, error) {
+	conns := make([]*types.GerritConnection, 0, len(s.gerrits))
+	for _, g := range s.gerrits {
+		conns = append(conns, &types.GerritConnection{GerritConnection: g})
+	}
+	return conns, nil
+}
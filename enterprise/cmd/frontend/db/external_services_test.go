@@ -389,6 +389,47 @@ func TestExternalServices_ValidateConfig(t *testing.T) {
 			config: `{"exclude": [{"pattern": "["}]}`,
 			assert: includes(`exclude.0.pattern: Does not match format 'regex'`),
 		},
+		{
+			kind: extsvc.KindGerrit,
+			desc: "valid with url, username, password, projects",
+			config: `
+			{
+				"url": "https://gerrit.example.org",
+				"username": "admin",
+				"password": "secret",
+				"projects": ["platform/build"],
+				"projectQuery": ["none"]
+			}`,
+			assert: equals("<nil>"),
+		},
+		{
+			kind:   extsvc.KindGerrit,
+			desc:   "without url, username and password",
+			config: `{}`,
+			assert: includes(
+				"url is required",
+				"username is required",
+				"password is required",
+			),
+		},
+		{
+			kind:   extsvc.KindGerrit,
+			desc:   "invalid exclude pattern",
+			config: `{"exclude": [{"pattern": "["}]}`,
+			assert: includes(`exclude.0.pattern: Does not match format 'regex'`),
+		},
+		{
+			kind: extsvc.KindGerrit,
+			desc: "invalid authorization ttl",
+			config: `
+			{
+				"url": "https://gerrit.example.org",
+				"username": "admin",
+				"password": "secret",
+				"authorization": {"ttl": "forever"}
+			}`,
+			assert: includes(`authorization.ttl: time: invalid duration "forever"`),
+		},
 		{
 			kind: extsvc.KindBitbucketServer,
 			desc: "valid with url, username, token, repositoryQuery",
@@ -1256,7 +1297,7 @@ func TestExternalServices_ValidateConfig(t *testing.T) {
 			kind:   extsvc.KindOther,
 			desc:   "with invalid scheme URL",
 			config: `{"url": "badscheme://github.com/", "repos": ["my/repo"]}`,
-			assert: includes(`url: Does not match pattern '^(git|ssh|https?)://'`),
+			assert: includes(`url: Does not match pattern '^(git|ssh|https?|perforce(\+ssl)?|hg\+(https?|ssh))://'`),
 		},
 		{
 			kind:   extsvc.KindOther,
@@ -0,0 +1,111 @@
+package gerrit
+
+import (
+	"sort"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+)
+
+// readSections are the ref patterns whose read permission grants access to the code of a
+// project. Read permissions on more specific refs (such as a single branch) are ignored.
+var readSections = []string{"refs/*", "refs/heads/*"}
+
+// canRead reports whether an account in the given groups can read the given project, walking
+// the project's inheritance chain in access. The closest project with a read rule matching
+// one of the groups decides, with DENY taking precedence over ALLOW on the same project. An
+// exclusive read permission without a matching ALLOW rule hides inherited rules. A BLOCK rule
+// for one of the groups anywhere in the chain always denies access.
+func canRead(access map[string]*gerrit.ProjectAccess, project string, groups map[string]bool) bool {
+	allowed, decided := false, false
+
+	seen := make(map[string]bool)
+	for name := project; name != "" && !seen[name]; {
+		seen[name] = true
+
+		a, ok := access[name]
+		if !ok {
+			break
+		}
+
+		var allow, deny, exclusive bool
+		for _, perm := range readPermissions(a) {
+			exclusive = exclusive || perm.Exclusive
+			for group, rule := range perm.Rules {
+				if !groups[group] {
+					continue
+				}
+
+				switch rule.Action {
+				case gerrit.ActionBlock:
+					return false
+				case gerrit.ActionDeny:
+					deny = true
+				case gerrit.ActionAllow:
+					allow = true
+				}
+			}
+		}
+
+		if !decided {
+			switch {
+			case deny:
+				allowed, decided = false, true
+			case allow:
+				allowed, decided = true, true
+			case exclusive:
+				allowed, decided = false, true
+			}
+		}
+
+		// Even once decided, we keep walking the chain to look for BLOCK rules.
+		name = parentOf(a)
+	}
+
+	return allowed
+}
+
+// allowedGroups returns the sorted UUIDs of the groups that have an ALLOW read rule on the
+// given project or on one of the projects it inherits from.
+func allowedGroups(access map[string]*gerrit.ProjectAccess, project string) []string {
+	set := make(map[string]bool)
+
+	seen := make(map[string]bool)
+	for name := project; name != "" && !seen[name]; {
+		seen[name] = true
+
+		a, ok := access[name]
+		if !ok {
+			break
+		}
+
+		for _, perm := range readPermissions(a) {
+			for group, rule := range perm.Rules {
+				if rule.Action == gerrit.ActionAllow {
+					set[group] = true
+				}
+			}
+		}
+
+		name = parentOf(a)
+	}
+
+	groups := make([]string, 0, len(set))
+	for group := range set {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	return groups
+}
+
+// readPermissions returns the read permissions of the readSections defined on a project.
+func readPermissions(a *gerrit.ProjectAccess) []*gerrit.Permission {
+	var perms []*gerrit.Permission
+	for _, ref := range readSections {
+		if section, ok := a.Local[ref]; ok && section != nil {
+			if perm, ok := section.Permissions["read"]; ok && perm != nil {
+				perms = append(perms, perm)
+			}
+		}
+	}
+	return perms
+}
@@ -0,0 +1,374 @@
+package gerrit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+	"github.com/sourcegraph/sourcegraph/internal/rcache"
+)
+
+// The system groups that Gerrit implicitly puts accounts in. They are not returned by the
+// account groups endpoint and their members cannot be listed.
+const (
+	anonymousUsers  = "global:Anonymous-Users"
+	registeredUsers = "global:Registered-Users"
+)
+
+// accessBatchSize is the maximum number of projects whose access rights are requested at once.
+const accessBatchSize = 100
+
+// client describes the subset of the Gerrit API used by Provider.
+type client interface {
+	GetAccount(ctx context.Context, username string) (*gerrit.Account, error)
+	ListAccountGroups(ctx context.Context, accountID int64) ([]*gerrit.Group, error)
+	ListGroupMembers(ctx context.Context, groupID string) ([]*gerrit.Account, error)
+	ListProjects(ctx context.Context, query string) (map[string]*gerrit.Project, error)
+	ListAccess(ctx context.Context, projects ...string) (map[string]*gerrit.ProjectAccess, error)
+}
+
+// cache describes the shape of the cache that Provider uses internally.
+type cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, b []byte)
+}
+
+// Provider implements authz.Provider for Gerrit repository permissions. Users are identified
+// by username, and can read a project if one of their groups is allowed to read it.
+type Provider struct {
+	urn      string
+	client   client
+	codeHost *extsvc.CodeHost
+	cache    cache
+}
+
+func NewProvider(urn string, baseURL *url.URL, cli client, cacheTTL time.Duration, mockCache cache) *Provider {
+	p := &Provider{
+		urn:      urn,
+		client:   cli,
+		codeHost: extsvc.NewCodeHost(baseURL, extsvc.TypeGerrit),
+		cache:    mockCache,
+	}
+
+	// Note: this will use the same underlying Redis instance and key namespace for every instance
+	// of Provider, so that different instances, even in different processes, share cache entries.
+	if p.cache == nil {
+		p.cache = rcache.NewWithTTL(fmt.Sprintf("gerritAuthz:%s", baseURL.String()), int(math.Ceil(cacheTTL.Seconds())))
+	}
+	return p
+}
+
+var _ authz.Provider = (*Provider)(nil)
+
+// RepoPerms implements the authz.Provider interface. It evaluates the read permissions of the
+// given repositories' projects against the groups of the user, both of which are cached.
+func (p *Provider) RepoPerms(ctx context.Context, account *extsvc.Account, repos []*types.Repo) ([]authz.RepoPerms, error) {
+	if len(repos) == 0 {
+		return nil, nil
+	}
+
+	groups, err := p.accountGroups(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(repos))
+	for _, r := range repos {
+		names = append(names, projectName(r.ExternalRepo.ID))
+	}
+
+	access, err := p.projectAccess(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	perms := make([]authz.RepoPerms, 0, len(repos))
+	for i, r := range repos {
+		if canRead(access, names[i], groups) {
+			perms = append(perms, authz.RepoPerms{Repo: r, Perms: authz.Read})
+		}
+	}
+	return perms, nil
+}
+
+// FetchAccount implements the authz.Provider interface. It looks up the Gerrit account with
+// the same username as the user.
+func (p *Provider) FetchAccount(ctx context.Context, user *types.User, _ []*extsvc.Account) (*extsvc.Account, error) {
+	if user == nil {
+		return nil, nil
+	}
+
+	gerritAccount, err := p.client.GetAccount(ctx, user.Username)
+	if gerrit.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	accountData, err := json.Marshal(gerritAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &extsvc.Account{
+		UserID: user.ID,
+		AccountSpec: extsvc.AccountSpec{
+			ServiceType: p.codeHost.ServiceType,
+			ServiceID:   p.codeHost.ServiceID,
+			AccountID:   strconv.FormatInt(gerritAccount.ID, 10),
+		},
+		AccountData: extsvc.AccountData{
+			Data: (*json.RawMessage)(&accountData),
+		},
+	}, nil
+}
+
+// FetchUserPerms returns a list of project IDs (on code host) that the given account
+// has read access on the code host. The project ID has the same value as it would be
+// used as api.ExternalRepoSpec.ID. All projects are considered private.
+func (p *Provider) FetchUserPerms(ctx context.Context, account *extsvc.Account) ([]extsvc.RepoID, error) {
+	if account == nil {
+		return nil, errors.New("no account provided")
+	} else if !extsvc.IsHostOfAccount(p.codeHost, account) {
+		return nil, fmt.Errorf("not a code host of the account: want %q but have %q",
+			account.AccountSpec.ServiceID, p.codeHost.ServiceID)
+	}
+
+	groups, err := p.groups(ctx, account.AccountID)
+	if err != nil {
+		return nil, errors.Wrap(err, "list account groups")
+	}
+
+	projects, err := p.client.ListProjects(ctx, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "list projects")
+	}
+
+	names := make([]string, 0, len(projects))
+	for name := range projects {
+		names = append(names, name)
+	}
+
+	access, err := p.projectAccess(ctx, names)
+	if err != nil {
+		return nil, errors.Wrap(err, "list project access")
+	}
+
+	repoIDs := make([]extsvc.RepoID, 0, len(projects))
+	for name, project := range projects {
+		if canRead(access, name, groups) {
+			repoIDs = append(repoIDs, extsvc.RepoID(project.ID))
+		}
+	}
+	return repoIDs, nil
+}
+
+// FetchRepoPerms returns a list of account IDs (on code host) who have read access to
+// the given project on the code host. The account ID has the same value as it would
+// be used as extsvc.Account.AccountID.
+//
+// Candidates are the members of the groups that are allowed to read the project. The
+// members of the system groups (such as "Registered Users") cannot be listed, so access
+// granted through them is not included.
+func (p *Provider) FetchRepoPerms(ctx context.Context, repo *extsvc.Repository) ([]extsvc.AccountID, error) {
+	if repo == nil {
+		return nil, errors.New("no repository provided")
+	} else if !extsvc.IsHostOfRepo(p.codeHost, &repo.ExternalRepoSpec) {
+		return nil, fmt.Errorf("not a code host of the repository: want %q but have %q",
+			repo.ServiceID, p.codeHost.ServiceID)
+	}
+
+	name := projectName(repo.ID)
+	access, err := p.projectAccess(ctx, []string{name})
+	if err != nil {
+		return nil, errors.Wrap(err, "list project access")
+	}
+
+	seen := make(map[string]bool)
+	accountIDs := make([]extsvc.AccountID, 0, 100)
+	for _, group := range allowedGroups(access, name) {
+		if group == anonymousUsers || group == registeredUsers {
+			continue
+		}
+
+		members, err := p.client.ListGroupMembers(ctx, group)
+		if err != nil {
+			return accountIDs, errors.Wrapf(err, "list members of group %q", group)
+		}
+
+		for _, m := range members {
+			id := strconv.FormatInt(m.ID, 10)
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			// Membership in an allowed group is not enough: another group of the
+			// account may be denied or blocked.
+			groups, err := p.groups(ctx, id)
+			if err != nil {
+				return accountIDs, errors.Wrapf(err, "list groups of account %s", id)
+			}
+			if canRead(access, name, groups) {
+				accountIDs = append(accountIDs, extsvc.AccountID(id))
+			}
+		}
+	}
+
+	return accountIDs, nil
+}
+
+func (p *Provider) URN() string {
+	return p.urn
+}
+
+func (p *Provider) ServiceID() string {
+	return p.codeHost.ServiceID
+}
+
+func (p *Provider) ServiceType() string {
+	return p.codeHost.ServiceType
+}
+
+func (p *Provider) Validate() (problems []string) {
+	return nil
+}
+
+// accountGroups returns the set of group UUIDs of the given account, which may be nil for
+// users without a Gerrit account.
+func (p *Provider) accountGroups(ctx context.Context, account *extsvc.Account) (map[string]bool, error) {
+	if account == nil {
+		return map[string]bool{anonymousUsers: true}, nil
+	}
+	return p.groups(ctx, account.AccountID)
+}
+
+// groups returns the set of group UUIDs of the account with the given ID, including the
+// system groups every account belongs to. It consults and updates the cache.
+func (p *Provider) groups(ctx context.Context, accountID string) (map[string]bool, error) {
+	key := "g:" + accountID
+
+	var ids []string
+	if b, ok := p.cache.Get(key); ok {
+		if err := json.Unmarshal(b, &ids); err != nil {
+			return nil, err
+		}
+	} else {
+		id, err := strconv.ParseInt(accountID, 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid Gerrit account ID %q", accountID)
+		}
+
+		groups, err := p.client.ListAccountGroups(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = make([]string, 0, len(groups))
+		for _, g := range groups {
+			ids = append(ids, g.ID)
+		}
+
+		b, err := json.Marshal(ids)
+		if err != nil {
+			return nil, err
+		}
+		p.cache.Set(key, b)
+	}
+
+	set := map[string]bool{anonymousUsers: true, registeredUsers: true}
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set, nil
+}
+
+// projectAccess returns the access rights of the given projects and of all the projects they
+// inherit from, keyed by project name. Projects that do not exist or are not visible to the
+// client are missing from the result. It consults and updates the cache.
+func (p *Provider) projectAccess(ctx context.Context, names []string) (map[string]*gerrit.ProjectAccess, error) {
+	access := make(map[string]*gerrit.ProjectAccess)
+	seen := make(map[string]bool)
+
+	for pending := names; len(pending) > 0; {
+		var missing []string
+		for _, name := range pending {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			if b, ok := p.cache.Get("a:" + name); ok {
+				var a gerrit.ProjectAccess
+				if err := json.Unmarshal(b, &a); err != nil {
+					return nil, err
+				}
+				access[name] = &a
+				continue
+			}
+			missing = append(missing, name)
+		}
+
+		for i := 0; i < len(missing); i += accessBatchSize {
+			j := i + accessBatchSize
+			if j > len(missing) {
+				j = len(missing)
+			}
+
+			batch, err := p.client.ListAccess(ctx, missing[i:j]...)
+			if err != nil {
+				return nil, err
+			}
+
+			for name, a := range batch {
+				b, err := json.Marshal(a)
+				if err != nil {
+					return nil, err
+				}
+				p.cache.Set("a:"+name, b)
+				access[name] = a
+			}
+		}
+
+		// Continue with the parents we haven't looked at yet.
+		pending = nil
+		for _, a := range access {
+			if parent := parentOf(a); parent != "" && !seen[parent] {
+				pending = append(pending, parent)
+			}
+		}
+	}
+
+	return access, nil
+}
+
+// projectName returns the name of the project with the given ID, which is the URL-encoded
+// name.
+func projectName(id string) string {
+	if name, err := url.PathUnescape(id); err == nil {
+		return name
+	}
+	return id
+}
+
+// parentOf returns the name of the project the given access rights are inherited from, or
+// the empty string for the root project.
+func parentOf(a *gerrit.ProjectAccess) string {
+	if a.InheritsFrom == nil {
+		return ""
+	}
+	if a.InheritsFrom.Name != "" {
+		return a.InheritsFrom.Name
+	}
+	return projectName(a.InheritsFrom.ID)
+}
@@ -0,0 +1,293 @@
+package gerrit
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+)
+
+func read(exclusive bool, rules map[string]string) *gerrit.AccessSection {
+	perm := &gerrit.Permission{Exclusive: exclusive, Rules: map[string]*gerrit.PermissionRule{}}
+	for group, action := range rules {
+		perm.Rules[group] = &gerrit.PermissionRule{Action: action}
+	}
+	return &gerrit.AccessSection{Permissions: map[string]*gerrit.Permission{"read": perm}}
+}
+
+func access(parent string, sections map[string]*gerrit.AccessSection) *gerrit.ProjectAccess {
+	a := &gerrit.ProjectAccess{Local: sections}
+	if parent != "" {
+		a.InheritsFrom = &gerrit.Project{ID: url.PathEscape(parent), Name: parent}
+	}
+	return a
+}
+
+// testAccess is a project hierarchy of the following shape:
+//
+//	All-Projects (registered users can read, "contractors" are blocked)
+//	├── platform (only "platform-devs" can read, exclusively)
+//	│   └── platform/build (no rules of its own)
+//	├── kernel ("kernel-devs" are denied)
+//	└── secret/project (exclusive without any matching rule)
+var testAccess = map[string]*gerrit.ProjectAccess{
+	"All-Projects": access("", map[string]*gerrit.AccessSection{
+		"refs/*": read(false, map[string]string{
+			registeredUsers: gerrit.ActionAllow,
+			"contractors":   gerrit.ActionBlock,
+		}),
+	}),
+	"platform": access("All-Projects", map[string]*gerrit.AccessSection{
+		"refs/heads/*": read(true, map[string]string{"platform-devs": gerrit.ActionAllow}),
+	}),
+	"platform/build": access("platform", nil),
+	"kernel": access("All-Projects", map[string]*gerrit.AccessSection{
+		"refs/*": read(false, map[string]string{
+			"kernel-devs": gerrit.ActionDeny,
+			"kernel-ops":  gerrit.ActionAllow,
+		}),
+	}),
+	"secret/project": access("All-Projects", map[string]*gerrit.AccessSection{
+		"refs/*": read(true, nil),
+	}),
+}
+
+func TestCanRead(t *testing.T) {
+	for _, tc := range []struct {
+		project string
+		groups  []string
+		want    bool
+	}{
+		{"All-Projects", []string{anonymousUsers}, false},
+		{"All-Projects", []string{anonymousUsers, registeredUsers}, true},
+		{"All-Projects", []string{registeredUsers, "contractors"}, false},
+		{"platform/build", []string{registeredUsers}, false},
+		{"platform/build", []string{registeredUsers, "platform-devs"}, true},
+		{"platform/build", []string{registeredUsers, "platform-devs", "contractors"}, false},
+		{"kernel", []string{registeredUsers}, true},
+		{"kernel", []string{registeredUsers, "kernel-devs"}, false},
+		{"kernel", []string{registeredUsers, "kernel-devs", "kernel-ops"}, false},
+		{"secret/project", []string{registeredUsers}, false},
+		{"missing", []string{registeredUsers}, false},
+	} {
+		groups := map[string]bool{}
+		for _, g := range tc.groups {
+			groups[g] = true
+		}
+
+		if have := canRead(testAccess, tc.project, groups); have != tc.want {
+			t.Errorf("canRead(%q, %v): want %v, have %v", tc.project, tc.groups, tc.want, have)
+		}
+	}
+}
+
+type mockClient struct {
+	accounts map[string]*gerrit.Account
+	groups   map[int64][]string
+	members  map[string][]int64
+	access   map[string]*gerrit.ProjectAccess
+
+	listAccessCalls [][]string
+}
+
+func (c *mockClient) GetAccount(ctx context.Context, username string) (*gerrit.Account, error) {
+	if a, ok := c.accounts[username]; ok {
+		return a, nil
+	}
+	return nil, errors.Errorf("account %q not found", username)
+}
+
+func (c *mockClient) ListAccountGroups(ctx context.Context, accountID int64) ([]*gerrit.Group, error) {
+	var groups []*gerrit.Group
+	for _, id := range c.groups[accountID] {
+		groups = append(groups, &gerrit.Group{ID: id})
+	}
+	return groups, nil
+}
+
+func (c *mockClient) ListGroupMembers(ctx context.Context, groupID string) ([]*gerrit.Account, error) {
+	var members []*gerrit.Account
+	for _, id := range c.members[groupID] {
+		members = append(members, &gerrit.Account{ID: id})
+	}
+	return members, nil
+}
+
+func (c *mockClient) ListProjects(ctx context.Context, query string) (map[string]*gerrit.Project, error) {
+	projects := map[string]*gerrit.Project{}
+	for name := range c.access {
+		projects[name] = &gerrit.Project{ID: url.PathEscape(name), Name: name}
+	}
+	return projects, nil
+}
+
+func (c *mockClient) ListAccess(ctx context.Context, projects ...string) (map[string]*gerrit.ProjectAccess, error) {
+	sorted := append([]string(nil), projects...)
+	sort.Strings(sorted)
+	c.listAccessCalls = append(c.listAccessCalls, sorted)
+
+	access := map[string]*gerrit.ProjectAccess{}
+	for _, name := range projects {
+		if a, ok := c.access[name]; ok {
+			access[name] = a
+		}
+	}
+	return access, nil
+}
+
+type mapCache map[string][]byte
+
+func (c mapCache) Get(key string) ([]byte, bool) {
+	b, ok := c[key]
+	return b, ok
+}
+
+func (c mapCache) Set(key string, b []byte) {
+	c[key] = b
+}
+
+func newTestProvider(cli *mockClient) *Provider {
+	baseURL, _ := url.Parse("https://gerrit.example.com/")
+	return NewProvider("extsvc:gerrit:1", baseURL, cli, 0, mapCache{})
+}
+
+func newTestClient() *mockClient {
+	return &mockClient{
+		accounts: map[string]*gerrit.Account{
+			"alice": {ID: 1000, Username: "alice"},
+		},
+		groups: map[int64][]string{
+			1000: {"platform-devs"},
+			1001: {"platform-devs", "contractors"},
+			1002: {"kernel-devs"},
+		},
+		members: map[string][]int64{
+			"platform-devs": {1000, 1001},
+		},
+		access: testAccess,
+	}
+}
+
+func TestProvider_RepoPerms(t *testing.T) {
+	cli := newTestClient()
+	p := newTestProvider(cli)
+
+	repo := func(name string) *types.Repo {
+		return &types.Repo{
+			Name: api.RepoName("gerrit.example.com/" + name),
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          url.PathEscape(name),
+				ServiceType: extsvc.TypeGerrit,
+				ServiceID:   "https://gerrit.example.com/",
+			},
+		}
+	}
+	repos := []*types.Repo{repo("platform/build"), repo("kernel"), repo("secret/project")}
+
+	account := &extsvc.Account{AccountSpec: extsvc.AccountSpec{
+		ServiceType: extsvc.TypeGerrit,
+		ServiceID:   "https://gerrit.example.com/",
+		AccountID:   "1000",
+	}}
+
+	for i := 0; i < 2; i++ {
+		perms, err := p.RepoPerms(context.Background(), account, repos)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []authz.RepoPerms{
+			{Repo: repos[0], Perms: authz.Read},
+			{Repo: repos[1], Perms: authz.Read},
+		}
+		if diff := cmp.Diff(want, perms); diff != "" {
+			t.Errorf("unexpected perms (-want +got):\n%s", diff)
+		}
+	}
+
+	// The second call must be served from the cache.
+	wantCalls := [][]string{
+		{"kernel", "platform/build", "secret/project"},
+		{"All-Projects", "platform"},
+	}
+	if diff := cmp.Diff(wantCalls, cli.listAccessCalls); diff != "" {
+		t.Errorf("unexpected ListAccess calls (-want +got):\n%s", diff)
+	}
+
+	// Anonymous users can't read anything.
+	perms, err := p.RepoPerms(context.Background(), nil, repos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(perms) != 0 {
+		t.Errorf("expected no perms for anonymous users, got %v", perms)
+	}
+}
+
+func TestProvider_FetchAccount(t *testing.T) {
+	p := newTestProvider(newTestClient())
+
+	account, err := p.FetchAccount(context.Background(), &types.User{ID: 1, Username: "alice"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := extsvc.AccountSpec{
+		ServiceType: extsvc.TypeGerrit,
+		ServiceID:   "https://gerrit.example.com/",
+		AccountID:   "1000",
+	}
+	if diff := cmp.Diff(want, account.AccountSpec); diff != "" {
+		t.Errorf("unexpected account (-want +got):\n%s", diff)
+	}
+}
+
+func TestProvider_FetchUserPerms(t *testing.T) {
+	p := newTestProvider(newTestClient())
+
+	ids, err := p.FetchUserPerms(context.Background(), &extsvc.Account{AccountSpec: extsvc.AccountSpec{
+		ServiceType: extsvc.TypeGerrit,
+		ServiceID:   "https://gerrit.example.com/",
+		AccountID:   "1002",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	want := []extsvc.RepoID{"All-Projects"}
+	if diff := cmp.Diff(want, ids); diff != "" {
+		t.Errorf("unexpected repo IDs (-want +got):\n%s", diff)
+	}
+}
+
+func TestProvider_FetchRepoPerms(t *testing.T) {
+	p := newTestProvider(newTestClient())
+
+	ids, err := p.FetchRepoPerms(context.Background(), &extsvc.Repository{
+		URI: "gerrit.example.com/platform/build",
+		ExternalRepoSpec: api.ExternalRepoSpec{
+			ID:          "platform%2Fbuild",
+			ServiceType: extsvc.TypeGerrit,
+			ServiceID:   "https://gerrit.example.com/",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Account 1001 is a member of "platform-devs", but also blocked as a contractor.
+	want := []extsvc.AccountID{"1000"}
+	if diff := cmp.Diff(want, ids); diff != "" {
+		t.Errorf("unexpected account IDs (-want +got):\n%s", diff)
+	}
+}
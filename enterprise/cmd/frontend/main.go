@@ -191,6 +191,8 @@ func initCodeIntel(enterpriseServices *enterprise.Services) {
 	enterpriseServices.NewCodeIntelUploadHandler = func(internal bool) http.Handler {
 		return codeintelhttpapi.NewUploadHandler(store, bundleManagerClient, internal)
 	}
+
+	enterpriseServices.NewCodeIntelValidateHandler = codeintelhttpapi.NewValidateHandler
 }
 
 type usersStore struct{}
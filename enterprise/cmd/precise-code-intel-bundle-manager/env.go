@@ -16,6 +16,16 @@ var (
 	rawMaxUploadAge        = env.Get("PRECISE_CODE_INTEL_MAX_UPLOAD_AGE", "24h", "The maximum time an upload can sit on disk.")
 	rawMaxUploadPartAge    = env.Get("PRECISE_CODE_INTEL_MAX_UPLOAD_PART_AGE", "2h", "The maximum time an upload part file can sit on disk.")
 	rawMaxDatabasePartAge  = env.Get("PRECISE_CODE_INTEL_MAX_DATABASE_PART_AGE", "2h", "The maximum time a database part file can sit on disk.")
+	rawRetentionInterval   = env.Get("PRECISE_CODE_INTEL_RETENTION_INTERVAL", "1h", "Interval between applications of the code intel retention policies.")
+
+	rawBundleStore              = env.Get("PRECISE_CODE_INTEL_BUNDLE_STORE", "", "Where to persist uploads and converted bundles: empty (bundle dir only), local, or s3. If set, the bundle dir becomes a cache.")
+	rawBundleStoreDir           = env.Get("PRECISE_CODE_INTEL_BUNDLE_STORE_DIR", "", "Root dir of the local bundle store (e.g. a volume shared by all replicas).")
+	rawBundleStoreS3Bucket      = env.Get("PRECISE_CODE_INTEL_BUNDLE_STORE_S3_BUCKET", "", "Bucket of the S3 bundle store.")
+	rawBundleStoreS3Endpoint    = env.Get("PRECISE_CODE_INTEL_BUNDLE_STORE_S3_ENDPOINT", "", "Endpoint of an S3-compatible service (e.g. MinIO or GCS) to use instead of AWS.")
+	rawBundleStoreS3Region      = env.Get("PRECISE_CODE_INTEL_BUNDLE_STORE_S3_REGION", "us-east-1", "Region of the S3 bundle store bucket.")
+	rawBundleStoreS3AccessKeyID = env.Get("PRECISE_CODE_INTEL_BUNDLE_STORE_S3_ACCESS_KEY_ID", "", "Access key of the S3 bundle store. Defaults to the AWS credential chain.")
+	rawBundleStoreS3SecretKey   = env.Get("PRECISE_CODE_INTEL_BUNDLE_STORE_S3_SECRET_ACCESS_KEY", "", "Secret key of the S3 bundle store.")
+	rawBundleStoreS3PathStyle   = env.Get("PRECISE_CODE_INTEL_BUNDLE_STORE_S3_FORCE_PATH_STYLE", "false", "Address the S3 bundle store bucket by path (required by most self-hosted services).")
 )
 
 // mustGet returns the non-empty version of the given raw value fatally logs on failure.
@@ -48,6 +58,16 @@ func mustParsePercent(rawValue, name string) int {
 	return p
 }
 
+// mustParseBool returns the boolean version of the given raw value fatally logs on failure.
+func mustParseBool(rawValue, name string) bool {
+	b, err := strconv.ParseBool(rawValue)
+	if err != nil {
+		log.Fatalf("invalid bool %q for %s: %s", rawValue, name, err)
+	}
+
+	return b
+}
+
 // mustParseInterval returns the interval version of the given raw value fatally logs on failure.
 func mustParseInterval(rawValue, name string) time.Duration {
 	d, err := time.ParseDuration(rawValue)
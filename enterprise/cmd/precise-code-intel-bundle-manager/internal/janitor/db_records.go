@@ -11,7 +11,7 @@ import (
 )
 
 // removeProcessedUploadsWithoutBundleFile removes all processed upload records
-// that do not have a corresponding bundle file on disk.
+// that do not have a corresponding bundle file on disk or in the bundle store.
 func (j *Janitor) removeProcessedUploadsWithoutBundleFile() error {
 	ctx := context.Background()
 
@@ -21,7 +21,18 @@ func (j *Janitor) removeProcessedUploadsWithoutBundleFile() error {
 		return errors.Wrap(err, "store.GetDumpIDs")
 	}
 
+	var storedIDs map[int]struct{}
+	if j.bundleStore != nil {
+		if storedIDs, err = j.storedDatabaseIDs(); err != nil {
+			return err
+		}
+	}
+
 	for _, id := range ids {
+		if _, ok := storedIDs[id]; ok {
+			continue
+		}
+
 		exists, err := paths.PathExists(paths.DBDir(j.bundleDir, int64(id)))
 		if err != nil {
 			return errors.Wrap(err, "paths.PathExists")
@@ -2,9 +2,12 @@ package janitor
 
 import (
 	"context"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"syscall"
+	"time"
 
 	"github.com/inconshreveable/log15"
 	"github.com/pkg/errors"
@@ -12,7 +15,8 @@ import (
 )
 
 // freeSpace determines the space available on the device containing the bundle directory,
-// then calls cleanOldBundles to free enough space to get back below the disk usage threshold.
+// then calls evictBundles to free enough space to get back below the disk usage threshold.
+// If the bundle directory is a cache of a bundle store, evictCachedBundles is called instead.
 func (j *Janitor) freeSpace() error {
 	var fs syscall.Statfs_t
 	if err := syscall.Statfs(j.bundleDir, &fs); err != nil {
@@ -26,9 +30,102 @@ func (j *Janitor) freeSpace() error {
 		return nil
 	}
 
+	if j.bundleStore != nil {
+		return j.evictCachedBundles(desiredFreeBytes - freeBytes)
+	}
+
 	return j.evictBundles(desiredFreeBytes - freeBytes)
 }
 
+// evictCachedBundles deletes the least recently used bundle directories, upload files, and
+// upload part files from the filesystem until at least bytesToFree, or there are no more
+// cached files. The files remain in the bundle store and are read through again on their
+// next use. Upload files that were written too recently to be certain they were pushed to
+// the store are not evicted.
+func (j *Janitor) evictCachedBundles(bytesToFree uint64) error {
+	candidates, err := j.cachedFiles()
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(candidates, func(i, k int) bool {
+		return candidates[i].modTime.Before(candidates[k].modTime)
+	})
+
+	for _, candidate := range candidates {
+		if bytesToFree == 0 {
+			break
+		}
+
+		size, err := sizeOf(candidate.path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return err
+		}
+
+		if !j.remove(candidate.path) {
+			continue
+		}
+
+		log15.Debug("Removed cached bundle file", "path", candidate.path)
+		j.metrics.CachedBundleFilesEvicted.Inc()
+
+		if size >= bytesToFree {
+			break
+		}
+
+		bytesToFree -= size
+	}
+
+	return nil
+}
+
+type cachedFile struct {
+	path    string
+	modTime time.Time
+}
+
+// cachedFiles returns the bundle directories, upload files, and upload part files that
+// can be evicted from the filesystem.
+func (j *Janitor) cachedFiles() ([]cachedFile, error) {
+	dirs := []struct {
+		dir    string
+		minAge time.Duration
+	}{
+		{paths.DBsDir(j.bundleDir), 0},
+		{paths.UploadsDir(j.bundleDir), MinimumUploadAge},
+		{paths.UploadPartsDir(j.bundleDir), MinimumUploadAge},
+	}
+
+	var candidates []cachedFile
+	for _, dir := range dirs {
+		fileInfos, err := ioutil.ReadDir(dir.dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		for _, fileInfo := range fileInfos {
+			if time.Since(fileInfo.ModTime()) <= dir.minAge {
+				continue
+			}
+
+			candidates = append(candidates, cachedFile{
+				path:    filepath.Join(dir.dir, fileInfo.Name()),
+				modTime: fileInfo.ModTime(),
+			})
+		}
+	}
+
+	return candidates, nil
+}
+
 // evictBundles removes completed upload recors from the database and then deletes the
 // associated bundle file from the filesystem until at least bytesToFree, or there are
 // no more prunable bundles.
@@ -3,8 +3,10 @@ package janitor
 import (
 	"context"
 	"fmt"
+	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	storemocks "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/store/mocks"
@@ -140,3 +142,100 @@ func TestEvictBundlesNoBundleFile(t *testing.T) {
 		t.Fatalf("unexpected error evicting bundles: %s", err)
 	}
 }
+
+func TestEvictCachedBundles(t *testing.T) {
+	bundleDir := testRoot(t)
+	now := time.Now()
+
+	for id := 1; id <= 10; id++ {
+		path := filepath.Join(bundleDir, "dbs", fmt.Sprintf("%d", id), "sqlite.db")
+		if err := makeFileWithSize(path, 20); err != nil {
+			t.Fatalf("unexpected error creating file %s: %s", path, err)
+		}
+
+		// Lower identifiers were used more recently
+		mtime := now.Add(-time.Duration(id) * time.Minute)
+		if err := os.Chtimes(filepath.Dir(path), mtime, mtime); err != nil {
+			t.Fatalf("unexpected error touching directory: %s", err)
+		}
+	}
+
+	mockStore := storemocks.NewMockStore()
+
+	j := &Janitor{
+		store:     mockStore,
+		bundleDir: bundleDir,
+		metrics:   NewJanitorMetrics(metrics.TestRegisterer),
+	}
+
+	if err := j.evictCachedBundles(70); err != nil {
+		t.Fatalf("unexpected error evicting cached bundles: %s", err)
+	}
+
+	names, err := getFilenames(filepath.Join(bundleDir, "dbs"))
+	if err != nil {
+		t.Fatalf("unexpected error listing directory: %s", err)
+	}
+
+	expected := []string{"1/sqlite.db", "2/sqlite.db", "3/sqlite.db", "4/sqlite.db", "5/sqlite.db", "6/sqlite.db"}
+	if diff := cmp.Diff(expected, names); diff != "" {
+		t.Errorf("unexpected directory contents (-want +got):\n%s", diff)
+	}
+
+	if len(mockStore.DeleteOldestDumpFunc.History()) != 0 {
+		t.Errorf("unexpected call to DeleteOldestDump")
+	}
+}
+
+func TestEvictCachedBundlesIncludesUploads(t *testing.T) {
+	bundleDir := testRoot(t)
+	now := time.Now()
+
+	files := []struct {
+		path string
+		age  time.Duration
+	}{
+		{filepath.Join(bundleDir, "dbs", "1", "sqlite.db"), 1 * time.Minute},
+		{filepath.Join(bundleDir, "dbs", "2", "sqlite.db"), 4 * time.Minute},
+		{filepath.Join(bundleDir, "uploads", "3.gz"), 2 * time.Minute},
+		{filepath.Join(bundleDir, "uploads", "4.gz"), 5 * time.Minute},
+		{filepath.Join(bundleDir, "upload-parts", "5.0.gz"), 3 * time.Minute},
+		{filepath.Join(bundleDir, "uploads", "6.gz"), 0}, // possibly not yet pushed
+	}
+
+	for _, file := range files {
+		if err := makeFileWithSize(file.path, 20); err != nil {
+			t.Fatalf("unexpected error creating file %s: %s", file.path, err)
+		}
+
+		mtime := now.Add(-file.age)
+		if err := os.Chtimes(file.path, mtime, mtime); err != nil {
+			t.Fatalf("unexpected error touching file: %s", err)
+		}
+		if filepath.Base(file.path) == "sqlite.db" {
+			if err := os.Chtimes(filepath.Dir(file.path), mtime, mtime); err != nil {
+				t.Fatalf("unexpected error touching directory: %s", err)
+			}
+		}
+	}
+
+	j := &Janitor{
+		store:     storemocks.NewMockStore(),
+		bundleDir: bundleDir,
+		metrics:   NewJanitorMetrics(metrics.TestRegisterer),
+	}
+
+	if err := j.evictCachedBundles(80); err != nil {
+		t.Fatalf("unexpected error evicting cached bundles: %s", err)
+	}
+
+	names, err := getFilenames(bundleDir)
+	if err != nil {
+		t.Fatalf("unexpected error listing directory: %s", err)
+	}
+
+	expected := []string{"dbs/1/sqlite.db", "uploads/6.gz"}
+	if diff := cmp.Diff(expected, names); diff != "" {
+		t.Errorf("unexpected directory contents (-want +got):\n%s", diff)
+	}
+}
@@ -7,40 +7,56 @@ import (
 
 	"github.com/inconshreveable/log15"
 	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/gitserver"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/retention"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/store"
+	"github.com/sourcegraph/sourcegraph/internal/objectstorage"
 )
 
 type Janitor struct {
 	store              store.Store
 	bundleDir          string
+	bundleStore        objectstorage.Store
 	desiredPercentFree int
 	janitorInterval    time.Duration
 	maxUploadAge       time.Duration
 	maxUploadPartAge   time.Duration
 	maxDatabasePartAge time.Duration
+	retentionInterval  time.Duration
+	retentionEvaluator *retention.Evaluator
+	lastRetentionRun   time.Time
 	metrics            JanitorMetrics
 	done               chan struct{}
 	once               sync.Once
 }
 
+// New creates a new janitor. If bundleStore is non-nil, the bundle directory is treated as a
+// cache of the bundle store: disk pressure evicts cached bundles instead of dump records, and
+// the contents of the bundle store are cleaned up alongside the bundle directory. Retention
+// policies from the site configuration are applied at most once per retention interval.
 func New(
 	store store.Store,
 	bundleDir string,
+	bundleStore objectstorage.Store,
 	desiredPercentFree int,
 	janitorInterval time.Duration,
 	maxUploadAge time.Duration,
 	maxUploadPartAge time.Duration,
 	maxDatabasePartAge time.Duration,
+	retentionInterval time.Duration,
 	metrics JanitorMetrics,
 ) *Janitor {
 	return &Janitor{
 		store:              store,
 		bundleDir:          bundleDir,
+		bundleStore:        bundleStore,
 		desiredPercentFree: desiredPercentFree,
 		janitorInterval:    janitorInterval,
 		maxUploadAge:       maxUploadAge,
 		maxUploadPartAge:   maxUploadPartAge,
 		maxDatabasePartAge: maxDatabasePartAge,
+		retentionInterval:  retentionInterval,
+		retentionEvaluator: retention.NewEvaluator(store, gitserver.DefaultClient),
 		metrics:            metrics,
 		done:               make(chan struct{}),
 	}
@@ -91,6 +107,14 @@ func (j *Janitor) run() error {
 		return errors.Wrap(err, "janitor.removeOrphanedBundleFiles")
 	}
 
+	if err := j.removeOldStoredFiles(); err != nil {
+		return errors.Wrap(err, "janitor.removeOldStoredFiles")
+	}
+
+	if err := j.removeOrphanedStoredFiles(); err != nil {
+		return errors.Wrap(err, "janitor.removeOrphanedStoredFiles")
+	}
+
 	if err := j.freeSpace(); err != nil {
 		return errors.Wrap(err, "janitor.freeSpace")
 	}
@@ -99,6 +123,10 @@ func (j *Janitor) run() error {
 		return errors.Wrap(err, "janitor.removeProcessedUploadsWithoutBundle")
 	}
 
+	if err := j.applyRetentionPolicies(); err != nil {
+		return errors.Wrap(err, "janitor.applyRetentionPolicies")
+	}
+
 	return nil
 }
 
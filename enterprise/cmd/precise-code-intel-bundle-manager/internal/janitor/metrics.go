@@ -7,7 +7,9 @@ type JanitorMetrics struct {
 	PartFilesRemoved          prometheus.Counter
 	OrphanedFilesRemoved      prometheus.Counter
 	EvictedBundleFilesRemoved prometheus.Counter
+	CachedBundleFilesEvicted  prometheus.Counter
 	UploadRecordsRemoved      prometheus.Counter
+	UploadRecordsExpired      prometheus.Counter
 	Errors                    prometheus.Counter
 }
 
@@ -36,12 +38,24 @@ func NewJanitorMetrics(r prometheus.Registerer) JanitorMetrics {
 	})
 	r.MustRegister(evictedBundleFilesRemoved)
 
+	cachedBundleFilesEvicted := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "src_bundle_manager_janitor_cached_bundle_files_evicted_total",
+		Help: "Total number of cached bundle files removed (due to disk pressure, remaining in the bundle store)",
+	})
+	r.MustRegister(cachedBundleFilesEvicted)
+
 	uploadRecordsRemoved := prometheus.NewCounter(prometheus.CounterOpts{
 		Name: "src_bundle_manager_janitor_upload_records_removed_total",
 		Help: "Total number of processed upload records removed (with no corresponding bundle file)",
 	})
 	r.MustRegister(uploadRecordsRemoved)
 
+	uploadRecordsExpired := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "src_bundle_manager_janitor_upload_records_expired_total",
+		Help: "Total number of completed upload records removed (expired by a retention policy)",
+	})
+	r.MustRegister(uploadRecordsExpired)
+
 	errors := prometheus.NewCounter(prometheus.CounterOpts{
 		Name: "src_bundle_manager_janitor_errors_total",
 		Help: "Total number of errors when running the janitor",
@@ -53,7 +67,9 @@ func NewJanitorMetrics(r prometheus.Registerer) JanitorMetrics {
 		PartFilesRemoved:          partFilesRemoved,
 		OrphanedFilesRemoved:      orphanedFilesRemoved,
 		EvictedBundleFilesRemoved: evictedBundleFilesRemoved,
+		CachedBundleFilesEvicted:  cachedBundleFilesEvicted,
 		UploadRecordsRemoved:      uploadRecordsRemoved,
+		UploadRecordsExpired:      uploadRecordsExpired,
 		Errors:                    errors,
 	}
 }
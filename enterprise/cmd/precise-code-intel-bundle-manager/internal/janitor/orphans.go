@@ -59,16 +59,9 @@ func (j *Janitor) removeOrphans(pathsByID map[int]string, onRemove func(id int,
 		ids = append(ids, id)
 	}
 
-	states := map[int]string{}
-	for _, batch := range batchIntSlice(ids, GetStateBatchSize) {
-		batchStates, err := j.store.GetStates(context.Background(), batch)
-		if err != nil {
-			return errors.Wrap(err, "store.GetStates")
-		}
-
-		for k, v := range batchStates {
-			states[k] = v
-		}
+	states, err := j.getStates(ids)
+	if err != nil {
+		return err
 	}
 
 	for id, path := range pathsByID {
@@ -82,6 +75,24 @@ func (j *Janitor) removeOrphans(pathsByID map[int]string, onRemove func(id int,
 	return nil
 }
 
+// getStates returns the state of each upload record with the given identifiers. Identifiers
+// without a corresponding record are absent from the resulting map.
+func (j *Janitor) getStates(ids []int) (map[int]string, error) {
+	states := map[int]string{}
+	for _, batch := range batchIntSlice(ids, GetStateBatchSize) {
+		batchStates, err := j.store.GetStates(context.Background(), batch)
+		if err != nil {
+			return nil, errors.Wrap(err, "store.GetStates")
+		}
+
+		for k, v := range batchStates {
+			states[k] = v
+		}
+	}
+
+	return states, nil
+}
+
 // uploadPathsByID returns map of bundle ids to their upload file on disk.
 func (j *Janitor) uploadPathsByID() (map[int]string, error) {
 	fileInfos, err := ioutil.ReadDir(paths.UploadsDir(j.bundleDir))
@@ -0,0 +1,72 @@
+package janitor
+
+import (
+	"context"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/retention"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+)
+
+// applyRetentionPolicies removes the completed uploads that are expired by the retention
+// policy of their repository. Policies are evaluated at most once per retention interval
+// as each repository with uploads requires several requests to gitserver.
+func (j *Janitor) applyRetentionPolicies() error {
+	if time.Since(j.lastRetentionRun) < j.retentionInterval {
+		return nil
+	}
+
+	policies, err := retention.NewPolicies(conf.Get().CodeIntelRetentionPolicies)
+	if err != nil || len(policies) == 0 {
+		return err
+	}
+
+	ctx := context.Background()
+
+	uploads, err := retention.CompletedUploads(ctx, j.store, 0)
+	if err != nil {
+		return err
+	}
+
+	repositoryIDs := map[int]struct{}{}
+	for _, upload := range uploads {
+		repositoryIDs[upload.RepositoryID] = struct{}{}
+	}
+
+	for repositoryID := range repositoryIDs {
+		repositoryName, err := j.store.RepoName(ctx, repositoryID)
+		if err != nil {
+			return errors.Wrap(err, "store.RepoName")
+		}
+
+		policy, ok := retention.PolicyForRepository(policies, repositoryName)
+		if !ok {
+			continue
+		}
+
+		expirations, err := j.retentionEvaluator.ExpirationsForRemoval(ctx, repositoryID, policy, time.Now())
+		if err != nil {
+			// Do not block the remaining repositories on a single unreachable repository
+			j.metrics.Errors.Inc()
+			log15.Error("Failed to evaluate retention policy", "repository", repositoryName, "err", err)
+			continue
+		}
+
+		for _, expiration := range expirations {
+			deleted, err := j.store.DeleteUploadByID(ctx, expiration.Upload.ID, j.getTipCommit)
+			if err != nil {
+				return errors.Wrap(err, "store.DeleteUploadByID")
+			}
+
+			if deleted {
+				log15.Debug("Removed upload record expired by retention policy", "id", expiration.Upload.ID, "reason", expiration.Reason)
+				j.metrics.UploadRecordsExpired.Inc()
+			}
+		}
+	}
+
+	j.lastRetentionRun = time.Now()
+	return nil
+}
@@ -0,0 +1,86 @@
+package janitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/gitserver"
+	gitservermocks "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/gitserver/mocks"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/retention"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/store"
+	storemocks "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/store/mocks"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestApplyRetentionPolicies(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		CodeIntelRetentionPolicies: []*schema.CodeIntelRetentionPolicy{
+			{RepositoryPattern: "^github.com/foo/", KeepMostRecentPerBranch: 1},
+		},
+	}})
+	defer conf.Mock(nil)
+
+	now := time.Now()
+	uploads := []store.Upload{
+		{ID: 1, RepositoryID: 50, Commit: "a", UploadedAt: now.Add(-time.Hour * 3)},
+		{ID: 2, RepositoryID: 50, Commit: "b", UploadedAt: now.Add(-time.Hour * 2)},
+		{ID: 3, RepositoryID: 51, Commit: "a", UploadedAt: now.Add(-time.Hour * 3)},
+		{ID: 4, RepositoryID: 51, Commit: "b", UploadedAt: now.Add(-time.Hour * 2)},
+	}
+
+	mockStore := storemocks.NewMockStore()
+	mockStore.GetUploadsFunc.SetDefaultHook(func(ctx context.Context, opts store.GetUploadsOptions) ([]store.Upload, int, error) {
+		var filtered []store.Upload
+		for _, upload := range uploads {
+			if opts.RepositoryID == 0 || opts.RepositoryID == upload.RepositoryID {
+				filtered = append(filtered, upload)
+			}
+		}
+		return filtered, len(filtered), nil
+	})
+	mockStore.RepoNameFunc.SetDefaultHook(func(ctx context.Context, repositoryID int) (string, error) {
+		if repositoryID == 50 {
+			return "github.com/foo/bar", nil
+		}
+		return "github.com/baz/bonk", nil
+	})
+	mockStore.GetCommitGraphFunc.SetDefaultReturn(map[string][]string{"a": nil, "b": {"a"}}, nil)
+	mockStore.DeleteUploadByIDFunc.SetDefaultReturn(true, nil)
+
+	mockGitserverClient := gitservermocks.NewMockClient()
+	mockGitserverClient.GetRefsFunc.SetDefaultReturn(gitserver.Refs{
+		DefaultBranch: "master",
+		Branches:      map[string]string{"master": "b"},
+	}, nil)
+
+	j := &Janitor{
+		store:              mockStore,
+		retentionInterval:  time.Hour,
+		retentionEvaluator: retention.NewEvaluator(mockStore, mockGitserverClient),
+		metrics:            NewJanitorMetrics(metrics.TestRegisterer),
+	}
+
+	if err := j.applyRetentionPolicies(); err != nil {
+		t.Fatalf("unexpected error applying retention policies: %s", err)
+	}
+
+	var ids []int
+	for _, call := range mockStore.DeleteUploadByIDFunc.History() {
+		ids = append(ids, call.Arg1)
+	}
+	if diff := cmp.Diff([]int{1}, ids); diff != "" {
+		t.Errorf("unexpected deleted upload ids (-want +got):\n%s", diff)
+	}
+
+	// Policies are not re-applied within the retention interval
+	if err := j.applyRetentionPolicies(); err != nil {
+		t.Fatalf("unexpected error applying retention policies: %s", err)
+	}
+	if len(mockStore.DeleteUploadByIDFunc.History()) != 1 {
+		t.Errorf("unexpected number of DeleteUploadByID calls. want=%d have=%d", 1, len(mockStore.DeleteUploadByIDFunc.History()))
+	}
+}
@@ -0,0 +1,156 @@
+package janitor
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/precise-code-intel-bundle-manager/internal/paths"
+)
+
+// removeOldStoredFiles removes all upload and part objects from the bundle store that are
+// older than their configured maximum age. This mirrors removeOldUploadFiles, removeOldUploadPartFiles,
+// and removeOldDatabasePartFiles for objects that are not (or no longer) cached on disk. This
+// method is a no-op if the janitor is not configured with a bundle store.
+func (j *Janitor) removeOldStoredFiles() error {
+	if j.bundleStore == nil {
+		return nil
+	}
+
+	prefixes := []struct {
+		dir      string
+		maxAge   time.Duration
+		onRemove func()
+	}{
+		{paths.UploadsDir(j.bundleDir), j.maxUploadAge, j.metrics.UploadFilesRemoved.Inc},
+		{paths.UploadPartsDir(j.bundleDir), j.maxUploadPartAge, j.metrics.PartFilesRemoved.Inc},
+		{paths.DBPartsDir(j.bundleDir), j.maxDatabasePartAge, j.metrics.PartFilesRemoved.Inc},
+	}
+
+	for _, prefix := range prefixes {
+		objects, err := j.bundleStore.List(context.Background(), paths.StorageKey(j.bundleDir, prefix.dir)+"/")
+		if err != nil {
+			return errors.Wrap(err, "bundleStore.List")
+		}
+
+		for _, object := range objects {
+			age := time.Since(object.LastModified)
+			if age <= prefix.maxAge {
+				continue
+			}
+
+			if j.removeObject(object.Key) {
+				log15.Debug("Removed old stored file", "key", object.Key, "age", age)
+				prefix.onRemove()
+			}
+		}
+	}
+
+	return nil
+}
+
+// removeOrphanedStoredFiles removes any upload or bundle object from the bundle store that
+// is associated with an errored (or missing) entry in the database. This method is a no-op
+// if the janitor is not configured with a bundle store.
+func (j *Janitor) removeOrphanedStoredFiles() error {
+	if j.bundleStore == nil {
+		return nil
+	}
+
+	uploadKeysByID, err := j.storedKeysByID(paths.UploadsDir(j.bundleDir), MinimumUploadAge)
+	if err != nil {
+		return err
+	}
+
+	databaseKeysByID, err := j.storedKeysByID(paths.DBsDir(j.bundleDir), 0)
+	if err != nil {
+		return err
+	}
+
+	var ids []int
+	for id := range uploadKeysByID {
+		ids = append(ids, id)
+	}
+	for id := range databaseKeysByID {
+		ids = append(ids, id)
+	}
+
+	states, err := j.getStates(ids)
+	if err != nil {
+		return err
+	}
+
+	for _, keysByID := range []map[int][]string{uploadKeysByID, databaseKeysByID} {
+		for id, keys := range keysByID {
+			if state, exists := states[id]; exists && state != "errored" {
+				continue
+			}
+
+			for _, key := range keys {
+				if j.removeObject(key) {
+					log15.Debug("Removed orphaned stored file", "id", id, "key", key)
+					j.metrics.OrphanedFilesRemoved.Inc()
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// storedDatabaseIDs returns the set of bundle identifiers with a database in the bundle store.
+func (j *Janitor) storedDatabaseIDs() (map[int]struct{}, error) {
+	keysByID, err := j.storedKeysByID(paths.DBsDir(j.bundleDir), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[int]struct{}, len(keysByID))
+	for id := range keysByID {
+		ids[id] = struct{}{}
+	}
+
+	return ids, nil
+}
+
+// storedKeysByID returns a map of bundle ids to the keys of objects in the bundle store that
+// mirror files within the given directory. Objects younger than minAge are skipped.
+func (j *Janitor) storedKeysByID(dir string, minAge time.Duration) (map[int][]string, error) {
+	prefix := paths.StorageKey(j.bundleDir, dir) + "/"
+
+	objects, err := j.bundleStore.List(context.Background(), prefix)
+	if err != nil {
+		return nil, errors.Wrap(err, "bundleStore.List")
+	}
+
+	keysByID := map[int][]string{}
+	for _, object := range objects {
+		if time.Since(object.LastModified) <= minAge {
+			continue
+		}
+
+		// Keys are of the form `uploads/{id}.gz` or `dbs/{id}/sqlite.db`
+		name := strings.SplitN(strings.TrimPrefix(object.Key, prefix), "/", 2)[0]
+		if id, err := strconv.Atoi(strings.Split(name, ".")[0]); err == nil {
+			keysByID[id] = append(keysByID[id], object.Key)
+		}
+	}
+
+	return keysByID, nil
+}
+
+// removeObject deletes the object with the given key from the bundle store. Returns a boolean
+// indicating success. If unsuccessful, the key and error will be logged and the error counter
+// will be incremented.
+func (j *Janitor) removeObject(key string) bool {
+	if err := j.bundleStore.Delete(context.Background(), key); err != nil {
+		j.metrics.Errors.Inc()
+		log15.Error("Failed to remove stored file", "key", key, "err", err)
+		return false
+	}
+
+	return true
+}
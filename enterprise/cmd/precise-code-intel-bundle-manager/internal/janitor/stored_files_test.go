@@ -0,0 +1,75 @@
+package janitor
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	storemocks "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/store/mocks"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/objectstorage"
+)
+
+func TestRemoveOrphanedStoredFiles(t *testing.T) {
+	bundleDir := testRoot(t)
+	storeDir := testRoot(t)
+
+	for id := 1; id <= 6; id++ {
+		for _, path := range []string{
+			filepath.Join(storeDir, "uploads", fmt.Sprintf("%d.gz", id)),
+			filepath.Join(storeDir, "dbs", fmt.Sprintf("%d", id), "sqlite.db"),
+		} {
+			if err := makeFile(path, time.Now().Local().Add(-2*time.Minute)); err != nil {
+				t.Fatalf("unexpected error creating file %s: %s", path, err)
+			}
+		}
+	}
+
+	bundleStore, err := objectstorage.NewLocalStore(storeDir)
+	if err != nil {
+		t.Fatalf("unexpected error creating bundle store: %s", err)
+	}
+
+	mockStore := storemocks.NewMockStore()
+	mockStore.GetStatesFunc.SetDefaultHook(func(ctx context.Context, ids []int) (map[int]string, error) {
+		return map[int]string{
+			1: "completed",
+			2: "queued",
+			3: "processing",
+			4: "errored",
+		}, nil
+	})
+
+	j := &Janitor{
+		store:       mockStore,
+		bundleDir:   bundleDir,
+		bundleStore: bundleStore,
+		metrics:     NewJanitorMetrics(metrics.TestRegisterer),
+	}
+
+	if err := j.removeOrphanedStoredFiles(); err != nil {
+		t.Fatalf("unexpected error removing orphaned stored files: %s", err)
+	}
+
+	names, err := getFilenames(storeDir)
+	if err != nil {
+		t.Fatalf("unexpected error listing directory: %s", err)
+	}
+	sort.Strings(names)
+
+	expectedNames := []string{
+		"dbs/1/sqlite.db",
+		"dbs/2/sqlite.db",
+		"dbs/3/sqlite.db",
+		"uploads/1.gz",
+		"uploads/2.gz",
+		"uploads/3.gz",
+	}
+	if diff := cmp.Diff(expectedNames, names); diff != "" {
+		t.Errorf("unexpected directory contents (-want +got):\n%s", diff)
+	}
+}
@@ -80,3 +80,14 @@ func DBPartFilename(bundleDir string, id, index int64) string {
 func MigrationMarkerFilename(bundleDir string, version int) string {
 	return filepath.Join(bundleDir, migrationMarkersDir, fmt.Sprintf("v%d", version))
 }
+
+// StorageKey returns the key of the object in a bundle store that mirrors the given path
+// within the bundle dir.
+func StorageKey(bundleDir, path string) string {
+	rel, err := filepath.Rel(bundleDir, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+
+	return filepath.ToSlash(rel)
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
 
 	"github.com/gorilla/mux"
 	"github.com/hashicorp/go-multierror"
@@ -51,7 +52,19 @@ func (s *Server) handler() http.Handler {
 
 // GET /uploads/{id:[0-9]+}
 func (s *Server) handleGetUpload(w http.ResponseWriter, r *http.Request) {
-	file, err := os.Open(paths.UploadFilename(s.bundleDir, idFromRequest(r)))
+	filename := paths.UploadFilename(s.bundleDir, idFromRequest(r))
+	if err := s.fetchFile(r.Context(), filename); err != nil {
+		if os.IsNotExist(err) {
+			http.Error(w, "Upload not found.", http.StatusNotFound)
+			return
+		}
+
+		log15.Error("Failed to fetch upload file", "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	file, err := os.Open(filename)
 	if err != nil {
 		http.Error(w, "Upload not found.", http.StatusNotFound)
 		return
@@ -90,17 +103,33 @@ func (s *Server) handlePostUploadPart(w http.ResponseWriter, r *http.Request) {
 
 // POST /uploads/{id:[0-9]+}/stitch
 func (s *Server) handlePostUploadStitch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	id := idFromRequest(r)
 	filename := paths.UploadFilename(s.bundleDir, id)
 	makePartFilename := func(index int) string {
 		return paths.UploadPartFilename(s.bundleDir, id, int64(index))
 	}
 
+	numParts, err := s.fetchParts(ctx, makePartFilename)
+	if err != nil {
+		log15.Error("Failed to fetch multipart upload parts", "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	if err := codeintelutils.StitchFiles(filename, makePartFilename, true); err != nil {
 		log15.Error("Failed to stitch multipart upload", "err", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+
+	if err := s.pushFile(ctx, filename); err != nil {
+		log15.Error("Failed to store stitched upload", "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.deleteParts(ctx, makePartFilename, numParts)
 }
 
 // DELETE /uploads/{id:[0-9]+}
@@ -119,12 +148,20 @@ func (s *Server) handlePostDatabasePart(w http.ResponseWriter, r *http.Request)
 
 // POST /dbs/{id:[0-9]+}/stitch
 func (s *Server) handlePostDatabaseStitch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	id := idFromRequest(r)
 	dirname := paths.DBDir(s.bundleDir, id)
 	makePartFilename := func(index int) string {
 		return paths.DBPartFilename(s.bundleDir, id, int64(index))
 	}
 
+	numParts, err := s.fetchParts(ctx, makePartFilename)
+	if err != nil {
+		log15.Error("Failed to fetch multipart database parts", "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	stitchedReader, err := codeintelutils.StitchFilesReader(makePartFilename, false)
 	if err != nil {
 		log15.Error("Failed to stitch multipart database", "err", err)
@@ -138,6 +175,14 @@ func (s *Server) handlePostDatabaseStitch(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if err := s.pushFile(ctx, paths.SQLiteDBFilename(s.bundleDir, id)); err != nil {
+		log15.Error("Failed to store database", "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.deleteParts(ctx, makePartFilename, numParts)
+
 	// Once we have a database, we no longer need the upload file
 	s.deleteUpload(w, r)
 }
@@ -282,19 +327,53 @@ func (s *Server) handlePackageInformation(w http.ResponseWriter, r *http.Request
 }
 
 // doUpload writes the HTTP request body to the path determined by the given
-// makeFilename function.
+// makeFilename function. If the server is configured with a store, the file is
+// also written to the store.
 func (s *Server) doUpload(w http.ResponseWriter, r *http.Request, makeFilename func(bundleDir string, id int64) string) bool {
-	if err := writeToFile(makeFilename(s.bundleDir, idFromRequest(r)), r.Body); err != nil {
+	filename := makeFilename(s.bundleDir, idFromRequest(r))
+
+	if err := writeToFile(filename, r.Body); err != nil {
 		log15.Error("Failed to write payload", "err", err)
 		http.Error(w, fmt.Sprintf("failed to write payload: %s", err.Error()), http.StatusInternalServerError)
 		return false
 	}
 
+	if err := s.pushFile(r.Context(), filename); err != nil {
+		log15.Error("Failed to store payload", "err", err)
+		http.Error(w, fmt.Sprintf("failed to store payload: %s", err.Error()), http.StatusInternalServerError)
+		return false
+	}
+
 	return true
 }
 
+// fetchParts ensures that all part files with the given naming scheme exist on disk and
+// returns the number of parts. Parts may have been received by another replica, in which
+// case they are read through from the store.
+func (s *Server) fetchParts(ctx context.Context, makePartFilename func(index int) string) (int, error) {
+	for index := 0; ; index++ {
+		if err := s.fetchFile(ctx, makePartFilename(index)); err != nil {
+			if os.IsNotExist(err) {
+				return index, nil
+			}
+
+			return 0, err
+		}
+	}
+}
+
+// deleteParts removes the given number of part files with the given naming scheme from
+// disk and from the store.
+func (s *Server) deleteParts(ctx context.Context, makePartFilename func(index int) string, numParts int) {
+	for index := 0; index < numParts; index++ {
+		if err := s.deleteFile(ctx, makePartFilename(index)); err != nil {
+			log15.Warn("Failed to delete part file", "err", err)
+		}
+	}
+}
+
 func writeToFile(filename string, r io.Reader) (err error) {
-	targetFile, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE, 0666)
+	targetFile, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
 	if err != nil {
 		return err
 	}
@@ -312,7 +391,7 @@ func writeToFile(filename string, r io.Reader) (err error) {
 }
 
 func (s *Server) deleteUpload(w http.ResponseWriter, r *http.Request) {
-	if err := os.Remove(paths.UploadFilename(s.bundleDir, idFromRequest(r))); err != nil {
+	if err := s.deleteFile(r.Context(), paths.UploadFilename(s.bundleDir, idFromRequest(r))); err != nil {
 		log15.Warn("Failed to delete upload file", "err", err)
 	}
 }
@@ -354,6 +433,13 @@ func (s *Server) dbQueryErr(w http.ResponseWriter, r *http.Request, handler dbQu
 		span.Finish()
 	}()
 
+	// If the database is not cached on disk, read it through from the store. Unknown
+	// databases are reported by the reader cache below.
+	if err := s.fetchFile(ctx, filename); err != nil && !os.IsNotExist(err) {
+		return pkgerrors.Wrap(err, "fetchFile")
+	}
+	s.touchDir(filepath.Dir(filename))
+
 	return s.readerCache.WithReader(ctx, filename, func(reader persistence.Reader) error {
 		db, err := database.OpenDatabase(ctx, filename, persistence.NewObserved(reader, s.observationContext))
 		if err != nil {
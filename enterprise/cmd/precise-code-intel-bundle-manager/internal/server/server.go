@@ -11,22 +11,29 @@ import (
 	"github.com/inconshreveable/log15"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/bundles/persistence/cache"
 	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/objectstorage"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
+	"golang.org/x/sync/singleflight"
 )
 
 const Port = 3187
 
 type Server struct {
 	bundleDir          string
+	bundleStore        objectstorage.Store
 	readerCache        cache.ReaderCache
 	observationContext *observation.Context
 	server             *http.Server
+	fetchGroup         singleflight.Group
 	once               sync.Once
 }
 
+// New creates a new server. If bundleStore is non-nil, uploads and bundles are persisted to the
+// store and the bundle directory acts as a read-through cache of its contents.
 func New(
 	bundleDir string,
+	bundleStore objectstorage.Store,
 	readerCache cache.ReaderCache,
 	observationContext *observation.Context,
 ) *Server {
@@ -37,6 +44,7 @@ func New(
 
 	s := &Server{
 		bundleDir:          bundleDir,
+		bundleStore:        bundleStore,
 		readerCache:        readerCache,
 		observationContext: observationContext,
 	}
@@ -0,0 +1,121 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/precise-code-intel-bundle-manager/internal/paths"
+	"github.com/sourcegraph/sourcegraph/internal/objectstorage"
+)
+
+// pushFile copies the file at the given path within the bundle directory into the
+// store. This method is a no-op if the server is not configured with a store.
+func (s *Server) pushFile(ctx context.Context, path string) error {
+	if s.bundleStore == nil {
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return s.bundleStore.Upload(ctx, s.storageKey(path), f)
+}
+
+// fetchFile ensures that the file at the given path within the bundle directory exists,
+// reading it through from the store if it is not already cached on disk. If the file is
+// neither on disk nor in the store, an error satisfying os.IsNotExist is returned.
+func (s *Server) fetchFile(ctx context.Context, path string) error {
+	exists, err := paths.PathExists(path)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+	if s.bundleStore == nil {
+		return os.ErrNotExist
+	}
+
+	// Concurrent requests for the same missing file share a single download. The
+	// download must not be canceled with the request that happened to start it, so
+	// it uses a detached context and each request only stops waiting for it.
+	ch := s.fetchGroup.DoChan(path, func() (interface{}, error) {
+		return nil, s.fetchFileFromStore(context.Background(), path)
+	})
+
+	select {
+	case res := <-ch:
+		return res.Err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Server) fetchFileFromStore(ctx context.Context, path string) (err error) {
+	rc, err := s.bundleStore.Get(ctx, s.storageKey(path))
+	if err != nil {
+		if err == objectstorage.ErrNotExist {
+			return os.ErrNotExist
+		}
+
+		return errors.Wrap(err, "store.Get")
+	}
+	defer rc.Close()
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so that a partial download is never observed
+	// by a concurrent reader or mistaken for a cached copy on a later request.
+	tempPath := path + ".tmp"
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tempPath)
+		}
+	}()
+
+	if err := writeToFile(tempPath, rc); err != nil {
+		return err
+	}
+
+	return os.Rename(tempPath, path)
+}
+
+// deleteFile removes the file at the given path within the bundle directory from disk
+// and from the store.
+func (s *Server) deleteFile(ctx context.Context, path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if s.bundleStore == nil {
+		return nil
+	}
+
+	return s.bundleStore.Delete(ctx, s.storageKey(path))
+}
+
+// touchDir updates the modification time of the given directory. When the bundle directory
+// acts as a cache in front of a store, the janitor evicts the least recently touched bundles
+// first.
+func (s *Server) touchDir(path string) {
+	if s.bundleStore == nil {
+		return
+	}
+
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+}
+
+// storageKey returns the key of the object in the store that mirrors the file at the
+// given path within the bundle directory.
+func (s *Server) storageKey(path string) string {
+	return paths.StorageKey(s.bundleDir, path)
+}
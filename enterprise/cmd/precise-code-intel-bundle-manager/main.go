@@ -19,6 +19,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/debugserver"
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/objectstorage"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/sqliteutil"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
@@ -40,6 +41,7 @@ func main() {
 		maxUploadAge        = mustParseInterval(rawMaxUploadAge, "PRECISE_CODE_INTEL_MAX_UPLOAD_AGE")
 		maxUploadPartAge    = mustParseInterval(rawMaxUploadPartAge, "PRECISE_CODE_INTEL_MAX_UPLOAD_PART_AGE")
 		maxDatabasePartAge  = mustParseInterval(rawMaxDatabasePartAge, "PRECISE_CODE_INTEL_MAX_DATABASE_PART_AGE")
+		retentionInterval   = mustParseInterval(rawRetentionInterval, "PRECISE_CODE_INTEL_RETENTION_INTERVAL")
 	)
 
 	readerCache, err := sqlitereader.NewReaderCache(readerDataCacheSize)
@@ -66,11 +68,12 @@ func main() {
 	}
 
 	store := store.NewObserved(mustInitializeStore(), observationContext)
+	bundleStore := mustInitializeBundleStore()
 	metrics.MustRegisterDiskMonitor(bundleDir)
 
-	server := server.New(bundleDir, readerCache, observationContext)
+	server := server.New(bundleDir, bundleStore, readerCache, observationContext)
 	janitorMetrics := janitor.NewJanitorMetrics(prometheus.DefaultRegisterer)
-	janitor := janitor.New(store, bundleDir, desiredPercentFree, janitorInterval, maxUploadAge, maxUploadPartAge, maxDatabasePartAge, janitorMetrics)
+	janitor := janitor.New(store, bundleDir, bundleStore, desiredPercentFree, janitorInterval, maxUploadAge, maxUploadPartAge, maxDatabasePartAge, retentionInterval, janitorMetrics)
 
 	go server.Start()
 	go janitor.Run()
@@ -106,3 +109,39 @@ func mustInitializeStore() store.Store {
 
 	return store
 }
+
+// mustInitializeBundleStore returns the configured bundle store, or nil if uploads and bundles
+// should only be kept in the bundle directory.
+func mustInitializeBundleStore() objectstorage.Store {
+	var (
+		bundleStore objectstorage.Store
+		err         error
+	)
+
+	switch rawBundleStore {
+	case "":
+		return nil
+
+	case "local":
+		bundleStore, err = objectstorage.NewLocalStore(mustGet(rawBundleStoreDir, "PRECISE_CODE_INTEL_BUNDLE_STORE_DIR"))
+
+	case "s3":
+		bundleStore, err = objectstorage.NewS3Store(objectstorage.S3Config{
+			Bucket:          mustGet(rawBundleStoreS3Bucket, "PRECISE_CODE_INTEL_BUNDLE_STORE_S3_BUCKET"),
+			Endpoint:        rawBundleStoreS3Endpoint,
+			Region:          rawBundleStoreS3Region,
+			AccessKeyID:     rawBundleStoreS3AccessKeyID,
+			SecretAccessKey: rawBundleStoreS3SecretKey,
+			ForcePathStyle:  mustParseBool(rawBundleStoreS3PathStyle, "PRECISE_CODE_INTEL_BUNDLE_STORE_S3_FORCE_PATH_STYLE"),
+		})
+
+	default:
+		log.Fatalf("invalid value %q for PRECISE_CODE_INTEL_BUNDLE_STORE: must be one of local, s3", rawBundleStore)
+	}
+
+	if err != nil {
+		log.Fatalf("failed to initialize bundle store: %s", err)
+	}
+
+	return bundleStore
+}
@@ -3,8 +3,8 @@ package correlation
 import (
 	"sort"
 
-	"github.com/sourcegraph/sourcegraph/enterprise/cmd/precise-code-intel-worker/internal/correlation/datastructures"
-	"github.com/sourcegraph/sourcegraph/enterprise/cmd/precise-code-intel-worker/internal/correlation/lsif"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/datastructures"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/lsif"
 )
 
 // canonicalize deduplicates data in the raw correlation state and collapses range,
@@ -8,10 +8,10 @@ import (
 	"strings"
 
 	"github.com/inconshreveable/log15"
-	"github.com/sourcegraph/sourcegraph/enterprise/cmd/precise-code-intel-worker/internal/correlation/datastructures"
-	"github.com/sourcegraph/sourcegraph/enterprise/cmd/precise-code-intel-worker/internal/correlation/lsif"
-	"github.com/sourcegraph/sourcegraph/enterprise/cmd/precise-code-intel-worker/internal/correlation/lsif/jsonlines"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/precise-code-intel-worker/internal/existence"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/datastructures"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/lsif"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/lsif/jsonlines"
 )
 
 // Correlate reads LSIF data from the given reader and returns a correlation state object with
@@ -1,8 +1,8 @@
 package correlation
 
 import (
-	"github.com/sourcegraph/sourcegraph/enterprise/cmd/precise-code-intel-worker/internal/correlation/datastructures"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/precise-code-intel-worker/internal/existence"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/datastructures"
 )
 
 // prune removes references to documents in the given correlation state that do not exist in
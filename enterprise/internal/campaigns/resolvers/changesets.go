@@ -324,6 +324,14 @@ func (r *changesetResolver) CheckState(ctx context.Context) (*campaigns.Changese
 	return &state, nil
 }
 
+func (r *changesetResolver) SignatureState() *campaigns.ChangesetSignatureState {
+	state := r.Changeset.SignatureState()
+	if state == campaigns.ChangesetSignatureStateUnknown {
+		return nil
+	}
+	return &state
+}
+
 func (r *changesetResolver) Labels(ctx context.Context) ([]graphqlbackend.ChangesetLabelResolver, error) {
 	// Only GitHub supports labels on pull requests so don't make a DB call unless we need to
 	if _, ok := r.Changeset.Metadata.(*github.PullRequest); !ok {
@@ -12,11 +12,13 @@ import (
 	"github.com/inconshreveable/log15"
 	"github.com/opentracing/opentracing-go/log"
 	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/campaigns"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
@@ -178,6 +180,11 @@ func ExecChangesetJob(
 		ensureUniqueRef = false
 	}
 
+	authorName, authorEmail, err := commitAuthor(ctx, c.AuthorID)
+	if err != nil {
+		return errors.Wrap(err, "getting commit author")
+	}
+
 	ref, err := opts.GitClient.CreateCommitFromPatch(ctx, protocol.CreateCommitFromPatchRequest{
 		Repo:       api.RepoName(repo.Name),
 		BaseCommit: patch.Rev,
@@ -188,8 +195,8 @@ func ExecChangesetJob(
 		UniqueRef: ensureUniqueRef,
 		CommitInfo: protocol.PatchCommitInfo{
 			Message:     c.Name,
-			AuthorName:  "Sourcegraph Bot",
-			AuthorEmail: "campaigns@sourcegraph.com",
+			AuthorName:  authorName,
+			AuthorEmail: authorEmail,
 			Date:        job.CreatedAt,
 		},
 		// We use unified diffs, not git diffs, which means they're missing the
@@ -351,3 +358,35 @@ func ExecChangesetJob(
 	runFinalUpdate(ctx, opts.Store)
 	return err
 }
+
+// commitAuthor returns the author of the commits created for the changesets
+// of a campaign: the author of the campaign if their primary email is
+// verified, and the Sourcegraph bot otherwise. The committer is left to
+// gitserver, which commits signed commits as the owner of the signing key.
+func commitAuthor(ctx context.Context, userID int32) (name, email string, err error) {
+	const (
+		botName  = "Sourcegraph Bot"
+		botEmail = "campaigns@sourcegraph.com"
+	)
+
+	email, verified, err := db.UserEmails.GetPrimaryEmail(ctx, userID)
+	if errcode.IsNotFound(err) || (err == nil && !verified) {
+		return botName, botEmail, nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	user, err := db.Users.GetByID(ctx, userID)
+	if errcode.IsNotFound(err) {
+		return botName, botEmail, nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+	name = user.DisplayName
+	if name == "" {
+		name = user.Username
+	}
+	return name, email, nil
+}
@@ -8,6 +8,8 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
 	cmpgn "github.com/sourcegraph/sourcegraph/internal/campaigns"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
@@ -181,6 +183,47 @@ func TestExecChangesetJob(t *testing.T) {
 	}
 }
 
+func TestCommitAuthor(t *testing.T) {
+	defer func() {
+		db.Mocks.Users = db.MockUsers{}
+		db.Mocks.UserEmails = db.MockUserEmails{}
+	}()
+	db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id, Username: "alice"}, nil
+	}
+
+	for _, tc := range []struct {
+		name      string
+		email     string
+		verified  bool
+		err       error
+		wantName  string
+		wantEmail string
+	}{
+		{name: "verified", email: "alice@example.com", verified: true, wantName: "alice", wantEmail: "alice@example.com"},
+		{name: "unverified", email: "alice@example.com", wantName: "Sourcegraph Bot", wantEmail: "campaigns@sourcegraph.com"},
+		{name: "no email", err: notFoundErr{}, wantName: "Sourcegraph Bot", wantEmail: "campaigns@sourcegraph.com"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			db.Mocks.UserEmails.GetPrimaryEmail = func(ctx context.Context, id int32) (string, bool, error) {
+				return tc.email, tc.verified, tc.err
+			}
+			name, email, err := commitAuthor(context.Background(), 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if name != tc.wantName || email != tc.wantEmail {
+				t.Errorf("got author %q <%s>, want %q <%s>", name, email, tc.wantName, tc.wantEmail)
+			}
+		})
+	}
+}
+
+type notFoundErr struct{}
+
+func (notFoundErr) Error() string  { return "not found" }
+func (notFoundErr) NotFound() bool { return true }
+
 const testDiff = `diff --git foobar.c foobar.c
 index d75b080..cf04b5b 100644
 --- foobar.c
@@ -29,6 +29,8 @@ type Cursor struct {
 	SkipDumpsWhenBatching  int                   // same-repo/remote-repo
 	SkipDumpsInBatch       int                   // same-repo/remote-repo
 	SkipResultsInDump      int                   // same-repo/remote-repo
+	VersionMatch           VersionMatch          // common
+	Versions               []string              // remote-repo
 }
 
 // EncodeCursor returns an encoding of the given cursor suitable for a URL.
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/bundles/client"
 	bundles "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/bundles/client"
 	bundlemocks "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/bundles/client/mocks"
@@ -118,16 +119,16 @@ func setMockStoreSameRepoPager(t *testing.T, mockStore *storemocks.MockStore, ex
 	})
 }
 
-func setMockStorePackageReferencePager(t *testing.T, mockStore *storemocks.MockStore, expectedScheme, expectedName, expectedVersion string, expectedRepositoryID, expectedLimit int, totalCount int, pager store.ReferencePager) {
-	mockStore.PackageReferencePagerFunc.SetDefaultHook(func(ctx context.Context, scheme, name, version string, repositoryID, limit int) (int, store.ReferencePager, error) {
+func setMockStorePackageReferencePager(t *testing.T, mockStore *storemocks.MockStore, expectedScheme, expectedName string, expectedVersions []string, expectedRepositoryID, expectedLimit int, totalCount int, pager store.ReferencePager) {
+	mockStore.PackageReferencePagerFunc.SetDefaultHook(func(ctx context.Context, scheme, name string, versions []string, repositoryID, limit int) (int, store.ReferencePager, error) {
 		if scheme != expectedScheme {
 			t.Errorf("unexpected scheme for PackageReferencePager. want=%s have=%s", expectedScheme, scheme)
 		}
 		if name != expectedName {
 			t.Errorf("unexpected name for PackageReferencePager. want=%s have=%s", expectedName, name)
 		}
-		if version != expectedVersion {
-			t.Errorf("unexpected version for PackageReferencePager. want=%s have=%s", expectedVersion, version)
+		if diff := cmp.Diff(expectedVersions, versions); diff != "" {
+			t.Errorf("unexpected versions for PackageReferencePager (-want +got):\n%s", diff)
 		}
 		if repositoryID != expectedRepositoryID {
 			t.Errorf("unexpected repository id for PackageReferencePager. want=%d have=%d", expectedRepositoryID, repositoryID)
@@ -112,25 +112,27 @@ func (s *ReferencePageResolver) handleSameDumpCursor(ctx context.Context, cursor
 
 	if newOffset := cursor.SkipResults + s.limit; newOffset <= len(locations) {
 		newCursor := Cursor{
-			Phase:       cursor.Phase,
-			DumpID:      cursor.DumpID,
-			Path:        cursor.Path,
-			Line:        cursor.Line,
-			Character:   cursor.Character,
-			Monikers:    cursor.Monikers,
-			SkipResults: newOffset,
+			Phase:        cursor.Phase,
+			DumpID:       cursor.DumpID,
+			Path:         cursor.Path,
+			Line:         cursor.Line,
+			Character:    cursor.Character,
+			Monikers:     cursor.Monikers,
+			SkipResults:  newOffset,
+			VersionMatch: cursor.VersionMatch,
 		}
 		return resolvedLocations, newCursor, true, nil
 	}
 
 	newCursor := Cursor{
-		Phase:       "same-dump-monikers",
-		DumpID:      cursor.DumpID,
-		Path:        cursor.Path,
-		Line:        cursor.Line,
-		Character:   cursor.Character,
-		Monikers:    cursor.Monikers,
-		SkipResults: 0,
+		Phase:        "same-dump-monikers",
+		DumpID:       cursor.DumpID,
+		Path:         cursor.Path,
+		Line:         cursor.Line,
+		Character:    cursor.Character,
+		Monikers:     cursor.Monikers,
+		SkipResults:  0,
+		VersionMatch: cursor.VersionMatch,
 	}
 	return resolvedLocations, newCursor, true, nil
 }
@@ -195,23 +197,25 @@ func (s *ReferencePageResolver) handleSameDumpMonikersCursor(ctx context.Context
 
 	if newOffset := cursor.SkipResults + s.limit; newOffset <= totalCount {
 		newCursor := Cursor{
-			Phase:       cursor.Phase,
-			DumpID:      cursor.DumpID,
-			Path:        cursor.Path,
-			Line:        cursor.Line,
-			Character:   cursor.Character,
-			Monikers:    cursor.Monikers,
-			SkipResults: newOffset,
+			Phase:        cursor.Phase,
+			DumpID:       cursor.DumpID,
+			Path:         cursor.Path,
+			Line:         cursor.Line,
+			Character:    cursor.Character,
+			Monikers:     cursor.Monikers,
+			SkipResults:  newOffset,
+			VersionMatch: cursor.VersionMatch,
 		}
 		return resolvedLocations, newCursor, true, nil
 	}
 
 	newCursor := Cursor{
-		DumpID:      cursor.DumpID,
-		Phase:       "definition-monikers",
-		Path:        cursor.Path,
-		Monikers:    cursor.Monikers,
-		SkipResults: 0,
+		DumpID:       cursor.DumpID,
+		Phase:        "definition-monikers",
+		Path:         cursor.Path,
+		Monikers:     cursor.Monikers,
+		SkipResults:  0,
+		VersionMatch: cursor.VersionMatch,
 	}
 	return resolvedLocations, newCursor, true, nil
 }
@@ -246,6 +250,7 @@ func (s *ReferencePageResolver) handleDefinitionMonikersCursor(ctx context.Conte
 			SkipDumpsWhenBatching:  0,
 			SkipDumpsInBatch:       0,
 			SkipResultsInDump:      0,
+			VersionMatch:           cursor.VersionMatch,
 		}
 		break
 	}
@@ -265,11 +270,12 @@ func (s *ReferencePageResolver) handleDefinitionMonikersCursor(ctx context.Conte
 
 		if newOffset := cursor.SkipResults + len(locations); newOffset < count {
 			newCursor := Cursor{
-				Phase:       cursor.Phase,
-				DumpID:      cursor.DumpID,
-				Path:        cursor.Path,
-				Monikers:    cursor.Monikers,
-				SkipResults: newOffset,
+				Phase:        cursor.Phase,
+				DumpID:       cursor.DumpID,
+				Path:         cursor.Path,
+				Monikers:     cursor.Monikers,
+				SkipResults:  newOffset,
+				VersionMatch: cursor.VersionMatch,
 			}
 			return locations, newCursor, true, nil
 		}
@@ -305,13 +311,28 @@ func (s *ReferencePageResolver) handleSameRepoCursor(ctx context.Context, cursor
 		SkipDumpsWhenBatching:  0,
 		SkipDumpsInBatch:       0,
 		SkipResultsInDump:      0,
+		VersionMatch:           cursor.VersionMatch,
 	}
 	return locations, newCursor, true, nil
 }
 
 func (s *ReferencePageResolver) handleRemoteRepoCursor(ctx context.Context, cursor Cursor) ([]ResolvedLocation, Cursor, bool, error) {
+	versions := cursor.Versions
+	if versions == nil {
+		var err error
+		if versions, err = s.resolveVersions(ctx, cursor); err != nil {
+			return nil, Cursor{}, false, err
+		}
+
+		// Store the matched versions in the cursor so that subsequent pages of a range match
+		// are drawn from the same result set even if new versions are referenced meanwhile.
+		if len(versions) != 1 || versions[0] != cursor.Version {
+			cursor.Versions = versions
+		}
+	}
+
 	return s.resolveLocationsViaReferencePager(ctx, cursor, func(ctx context.Context) (int, store.ReferencePager, error) {
-		totalCount, pager, err := s.store.PackageReferencePager(ctx, cursor.Scheme, cursor.Name, cursor.Version, s.repositoryID, s.remoteDumpLimit)
+		totalCount, pager, err := s.store.PackageReferencePager(ctx, cursor.Scheme, cursor.Name, versions, s.repositoryID, s.remoteDumpLimit)
 		if err != nil {
 			return 0, nil, pkgerrors.Wrap(err, "store.PackageReferencePager")
 		}
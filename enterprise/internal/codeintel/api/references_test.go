@@ -570,7 +570,7 @@ func TestHandleRemoteRepoCursor(t *testing.T) {
 
 	setMockStoreGetDumpByID(t, mockStore, map[int]store.Dump{42: testDump1, 50: testDump2, 51: testDump3, 52: testDump4})
 	setMockBundleManagerClientBundleClient(t, mockBundleManagerClient, map[int]bundles.BundleClient{50: mockBundleClient1, 51: mockBundleClient2, 52: mockBundleClient3})
-	setMockStorePackageReferencePager(t, mockStore, "gomod", "leftpad", "0.1.0", 100, 5, 3, mockReferencePager)
+	setMockStorePackageReferencePager(t, mockStore, "gomod", "leftpad", []string{"0.1.0"}, 100, 5, 3, mockReferencePager)
 	setMockReferencePagerPageFromOffset(t, mockReferencePager, 0, []types.PackageReference{
 		{DumpID: 50, Filter: readTestFilter(t, "normal", "1")},
 		{DumpID: 51, Filter: readTestFilter(t, "normal", "1")},
@@ -696,7 +696,7 @@ func TestHandleRemoteRepoCursorMultipleDumpBatches(t *testing.T) {
 
 	setMockStoreGetDumpByID(t, mockStore, map[int]store.Dump{42: testDump1, 50: testDump2, 51: testDump3, 52: testDump4})
 	setMockBundleManagerClientBundleClient(t, mockBundleManagerClient, map[int]bundles.BundleClient{51: mockBundleClient})
-	setMockStorePackageReferencePager(t, mockStore, "gomod", "leftpad", "0.1.0", 100, 2, 3, mockReferencePager)
+	setMockStorePackageReferencePager(t, mockStore, "gomod", "leftpad", []string{"0.1.0"}, 100, 2, 3, mockReferencePager)
 	setMockReferencePagerPageFromOffset(t, mockReferencePager, 0, []types.PackageReference{
 		{DumpID: 50, Filter: readTestFilter(t, "normal", "1")},
 		{DumpID: 51, Filter: readTestFilter(t, "normal", "1")},
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/semver"
+	pkgerrors "github.com/pkg/errors"
+)
+
+const (
+	// VersionMatchExact matches only the version of the package that defines the symbol.
+	VersionMatchExact = "exact"
+
+	// VersionMatchAny matches every referenced version of the package.
+	VersionMatchAny = "any"
+
+	// VersionMatchSemver matches every referenced version of the package that satisfies a
+	// semver range.
+	VersionMatchSemver = "semver"
+)
+
+// VersionMatch determines which versions of a package are searched for references from
+// other repositories.
+type VersionMatch struct {
+	// Mode is one of VersionMatchExact, VersionMatchAny, or VersionMatchSemver. An empty
+	// mode is equivalent to VersionMatchExact.
+	Mode string
+
+	// Constraint is the semver range (e.g. ">= 1.2, < 2") used by VersionMatchSemver. If
+	// empty, the versions compatible with the defining version (^version) are matched.
+	Constraint string
+}
+
+// Validate returns an error if the version match has an unknown mode or an invalid constraint.
+func (m VersionMatch) Validate() error {
+	switch m.Mode {
+	case "", VersionMatchExact, VersionMatchAny:
+		return nil
+
+	case VersionMatchSemver:
+		if m.Constraint == "" {
+			return nil
+		}
+
+		if _, err := semver.NewConstraint(m.Constraint); err != nil {
+			return pkgerrors.Wrapf(err, "invalid version constraint %q", m.Constraint)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("unknown version match mode %q", m.Mode)
+}
+
+// resolveVersions returns the versions of the package with the given scheme and name that are
+// matched by the cursor's version match relative to the cursor's version.
+func (s *ReferencePageResolver) resolveVersions(ctx context.Context, cursor Cursor) ([]string, error) {
+	if cursor.VersionMatch.Mode == "" || cursor.VersionMatch.Mode == VersionMatchExact {
+		return []string{cursor.Version}, nil
+	}
+
+	packageVersions, err := s.store.GetPackageVersions(ctx, cursor.Scheme, cursor.Name)
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "store.GetPackageVersions")
+	}
+
+	matches, err := versionMatcher(cursor.VersionMatch, cursor.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := []string{}
+	for _, packageVersion := range packageVersions {
+		if len(packageVersion.ReferencingDumpIDs) > 0 && matches(packageVersion.Version) {
+			versions = append(versions, packageVersion.Version)
+		}
+	}
+
+	return versions, nil
+}
+
+// versionMatcher returns a function that determines if a version is matched by the given version
+// match relative to the given defining version.
+func versionMatcher(versionMatch VersionMatch, definingVersion string) (func(version string) bool, error) {
+	exact := func(version string) bool { return version == definingVersion }
+
+	switch versionMatch.Mode {
+	case VersionMatchAny:
+		return func(version string) bool { return true }, nil
+
+	case VersionMatchSemver:
+		rawConstraint := versionMatch.Constraint
+		if rawConstraint == "" {
+			if _, err := semver.NewVersion(definingVersion); err != nil {
+				// Not a semantic version, there are no compatible versions
+				return exact, nil
+			}
+
+			rawConstraint = "^" + definingVersion
+		}
+
+		constraint, err := semver.NewConstraint(rawConstraint)
+		if err != nil {
+			return nil, pkgerrors.Wrapf(err, "invalid version constraint %q", rawConstraint)
+		}
+
+		return func(version string) bool {
+			v, err := semver.NewVersion(version)
+			if err != nil {
+				return exact(version)
+			}
+
+			return constraint.Check(v)
+		}, nil
+	}
+
+	return exact, nil
+}
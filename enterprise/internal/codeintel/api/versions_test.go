@@ -0,0 +1,68 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/store"
+	storemocks "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/store/mocks"
+)
+
+func TestResolveVersions(t *testing.T) {
+	mockStore := storemocks.NewMockStore()
+	mockStore.GetPackageVersionsFunc.SetDefaultReturn([]store.PackageVersion{
+		{Version: "0.1.0", ProvidingDumpIDs: []int{42}, ReferencingDumpIDs: []int{50}},
+		{Version: "0.1.3", ReferencingDumpIDs: []int{51}},
+		{Version: "0.2.0", ProvidingDumpIDs: []int{43}},
+		{Version: "1.4.0", ReferencingDumpIDs: []int{52}},
+		{Version: "master", ReferencingDumpIDs: []int{53}},
+	}, nil)
+
+	rpr := &ReferencePageResolver{store: mockStore}
+
+	testCases := []struct {
+		versionMatch VersionMatch
+		expected     []string
+	}{
+		{VersionMatch{}, []string{"0.1.0"}},
+		{VersionMatch{Mode: VersionMatchExact}, []string{"0.1.0"}},
+		{VersionMatch{Mode: VersionMatchAny}, []string{"0.1.0", "0.1.3", "1.4.0", "master"}},
+		{VersionMatch{Mode: VersionMatchSemver}, []string{"0.1.0", "0.1.3"}},
+		{VersionMatch{Mode: VersionMatchSemver, Constraint: ">= 0.1.1"}, []string{"0.1.3", "1.4.0"}},
+	}
+
+	for _, testCase := range testCases {
+		name := testCase.versionMatch.Mode + " " + testCase.versionMatch.Constraint
+
+		t.Run(name, func(t *testing.T) {
+			versions, err := rpr.resolveVersions(context.Background(), Cursor{
+				Scheme:       "gomod",
+				Name:         "leftpad",
+				Version:      "0.1.0",
+				VersionMatch: testCase.versionMatch,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error resolving versions: %s", err)
+			}
+
+			if diff := cmp.Diff(testCase.expected, versions); diff != "" {
+				t.Errorf("unexpected versions (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestVersionMatchValidate(t *testing.T) {
+	for _, versionMatch := range []VersionMatch{{}, {Mode: VersionMatchAny}, {Mode: VersionMatchSemver, Constraint: "~1.2"}} {
+		if err := versionMatch.Validate(); err != nil {
+			t.Errorf("unexpected error validating %v: %s", versionMatch, err)
+		}
+	}
+
+	for _, versionMatch := range []VersionMatch{{Mode: "fuzzy"}, {Mode: VersionMatchSemver, Constraint: "not a range"}} {
+		if err := versionMatch.Validate(); err == nil {
+			t.Errorf("expected error validating %v", versionMatch)
+		}
+	}
+}
@@ -32,6 +32,13 @@ type Client interface {
 	// or not the tag was attached directly to the commit. If no tags exist at or before this commit, the
 	// tag is an empty string.
 	Tags(ctx context.Context, store store.Store, repositoryID int, commit string) (string, bool, error)
+
+	// GetRefs returns the branches and tags of the given repository.
+	GetRefs(ctx context.Context, store store.Store, repositoryID int) (Refs, error)
+
+	// IsAncestor determines whether the given commit is an ancestor of (or equal to) the given
+	// descendant commit.
+	IsAncestor(ctx context.Context, store store.Store, repositoryID int, commit, descendant string) (bool, error)
 }
 
 type defaultClient struct{}
@@ -61,3 +68,11 @@ func (c *defaultClient) FileExists(ctx context.Context, store store.Store, repos
 func (c *defaultClient) Tags(ctx context.Context, store store.Store, repositoryID int, commit string) (string, bool, error) {
 	return Tags(ctx, store, repositoryID, commit)
 }
+
+func (c *defaultClient) GetRefs(ctx context.Context, store store.Store, repositoryID int) (Refs, error) {
+	return GetRefs(ctx, store, repositoryID)
+}
+
+func (c *defaultClient) IsAncestor(ctx context.Context, store store.Store, repositoryID int, commit, descendant string) (bool, error) {
+	return IsAncestor(ctx, store, repositoryID, commit, descendant)
+}
@@ -27,9 +27,15 @@ type MockClient struct {
 	// FileExistsFunc is an instance of a mock function object controlling
 	// the behavior of the method FileExists.
 	FileExistsFunc *ClientFileExistsFunc
+	// GetRefsFunc is an instance of a mock function object controlling the
+	// behavior of the method GetRefs.
+	GetRefsFunc *ClientGetRefsFunc
 	// HeadFunc is an instance of a mock function object controlling the
 	// behavior of the method Head.
 	HeadFunc *ClientHeadFunc
+	// IsAncestorFunc is an instance of a mock function object controlling
+	// the behavior of the method IsAncestor.
+	IsAncestorFunc *ClientIsAncestorFunc
 	// TagsFunc is an instance of a mock function object controlling the
 	// behavior of the method Tags.
 	TagsFunc *ClientTagsFunc
@@ -59,11 +65,21 @@ func NewMockClient() *MockClient {
 				return false, nil
 			},
 		},
+		GetRefsFunc: &ClientGetRefsFunc{
+			defaultHook: func(context.Context, store.Store, int) (gitserver.Refs, error) {
+				return gitserver.Refs{}, nil
+			},
+		},
 		HeadFunc: &ClientHeadFunc{
 			defaultHook: func(context.Context, store.Store, int) (string, error) {
 				return "", nil
 			},
 		},
+		IsAncestorFunc: &ClientIsAncestorFunc{
+			defaultHook: func(context.Context, store.Store, int, string, string) (bool, error) {
+				return false, nil
+			},
+		},
 		TagsFunc: &ClientTagsFunc{
 			defaultHook: func(context.Context, store.Store, int, string) (string, bool, error) {
 				return "", false, nil
@@ -88,9 +104,15 @@ func NewMockClientFrom(i gitserver.Client) *MockClient {
 		FileExistsFunc: &ClientFileExistsFunc{
 			defaultHook: i.FileExists,
 		},
+		GetRefsFunc: &ClientGetRefsFunc{
+			defaultHook: i.GetRefs,
+		},
 		HeadFunc: &ClientHeadFunc{
 			defaultHook: i.Head,
 		},
+		IsAncestorFunc: &ClientIsAncestorFunc{
+			defaultHook: i.IsAncestor,
+		},
 		TagsFunc: &ClientTagsFunc{
 			defaultHook: i.Tags,
 		},
@@ -560,6 +582,117 @@ func (c ClientFileExistsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// ClientGetRefsFunc describes the behavior when the GetRefs method of the
+// parent MockClient instance is invoked.
+type ClientGetRefsFunc struct {
+	defaultHook func(context.Context, store.Store, int) (gitserver.Refs, error)
+	hooks       []func(context.Context, store.Store, int) (gitserver.Refs, error)
+	history     []ClientGetRefsFuncCall
+	mutex       sync.Mutex
+}
+
+// GetRefs delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockClient) GetRefs(v0 context.Context, v1 store.Store, v2 int) (gitserver.Refs, error) {
+	r0, r1 := m.GetRefsFunc.nextHook()(v0, v1, v2)
+	m.GetRefsFunc.appendCall(ClientGetRefsFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetRefs method of
+// the parent MockClient instance is invoked and the hook queue is empty.
+func (f *ClientGetRefsFunc) SetDefaultHook(hook func(context.Context, store.Store, int) (gitserver.Refs, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetRefs method of the parent MockClient instance inovkes the hook at the
+// front of the queue and discards it. After the queue is empty, the default
+// hook function is invoked for any future action.
+func (f *ClientGetRefsFunc) PushHook(hook func(context.Context, store.Store, int) (gitserver.Refs, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *ClientGetRefsFunc) SetDefaultReturn(r0 gitserver.Refs, r1 error) {
+	f.SetDefaultHook(func(context.Context, store.Store, int) (gitserver.Refs, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *ClientGetRefsFunc) PushReturn(r0 gitserver.Refs, r1 error) {
+	f.PushHook(func(context.Context, store.Store, int) (gitserver.Refs, error) {
+		return r0, r1
+	})
+}
+
+func (f *ClientGetRefsFunc) nextHook() func(context.Context, store.Store, int) (gitserver.Refs, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ClientGetRefsFunc) appendCall(r0 ClientGetRefsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ClientGetRefsFuncCall objects describing
+// the invocations of this function.
+func (f *ClientGetRefsFunc) History() []ClientGetRefsFuncCall {
+	f.mutex.Lock()
+	history := make([]ClientGetRefsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ClientGetRefsFuncCall is an object that describes an invocation of method
+// GetRefs on an instance of MockClient.
+type ClientGetRefsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 store.Store
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 gitserver.Refs
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ClientGetRefsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ClientGetRefsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // ClientHeadFunc describes the behavior when the Head method of the parent
 // MockClient instance is invoked.
 type ClientHeadFunc struct {
@@ -671,6 +804,123 @@ func (c ClientHeadFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// ClientIsAncestorFunc describes the behavior when the IsAncestor method of
+// the parent MockClient instance is invoked.
+type ClientIsAncestorFunc struct {
+	defaultHook func(context.Context, store.Store, int, string, string) (bool, error)
+	hooks       []func(context.Context, store.Store, int, string, string) (bool, error)
+	history     []ClientIsAncestorFuncCall
+	mutex       sync.Mutex
+}
+
+// IsAncestor delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockClient) IsAncestor(v0 context.Context, v1 store.Store, v2 int, v3 string, v4 string) (bool, error) {
+	r0, r1 := m.IsAncestorFunc.nextHook()(v0, v1, v2, v3, v4)
+	m.IsAncestorFunc.appendCall(ClientIsAncestorFuncCall{v0, v1, v2, v3, v4, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the IsAncestor method of
+// the parent MockClient instance is invoked and the hook queue is empty.
+func (f *ClientIsAncestorFunc) SetDefaultHook(hook func(context.Context, store.Store, int, string, string) (bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// IsAncestor method of the parent MockClient instance inovkes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *ClientIsAncestorFunc) PushHook(hook func(context.Context, store.Store, int, string, string) (bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *ClientIsAncestorFunc) SetDefaultReturn(r0 bool, r1 error) {
+	f.SetDefaultHook(func(context.Context, store.Store, int, string, string) (bool, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *ClientIsAncestorFunc) PushReturn(r0 bool, r1 error) {
+	f.PushHook(func(context.Context, store.Store, int, string, string) (bool, error) {
+		return r0, r1
+	})
+}
+
+func (f *ClientIsAncestorFunc) nextHook() func(context.Context, store.Store, int, string, string) (bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ClientIsAncestorFunc) appendCall(r0 ClientIsAncestorFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ClientIsAncestorFuncCall objects describing
+// the invocations of this function.
+func (f *ClientIsAncestorFunc) History() []ClientIsAncestorFuncCall {
+	f.mutex.Lock()
+	history := make([]ClientIsAncestorFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ClientIsAncestorFuncCall is an object that describes an invocation of
+// method IsAncestor on an instance of MockClient.
+type ClientIsAncestorFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 store.Store
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 bool
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ClientIsAncestorFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ClientIsAncestorFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // ClientTagsFunc describes the behavior when the Tags method of the parent
 // MockClient instance is invoked.
 type ClientTagsFunc struct {
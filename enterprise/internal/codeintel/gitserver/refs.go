@@ -0,0 +1,106 @@
+package gitserver
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/store"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+)
+
+// Refs describes the branches and tags of a repository.
+type Refs struct {
+	// DefaultBranch is the name of the branch referenced by HEAD.
+	DefaultBranch string
+
+	// Branches maps branch names to the commit at the tip of the branch.
+	Branches map[string]string
+
+	// Tags maps commits to the names of the tags pointing at that commit.
+	Tags map[string][]string
+}
+
+// GetRefs returns the branches and tags of the given repository.
+func GetRefs(ctx context.Context, store store.Store, repositoryID int) (Refs, error) {
+	defaultBranch, err := execGitCommand(ctx, store, repositoryID, "symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return Refs{}, err
+	}
+
+	out, err := execGitCommand(ctx, store, repositoryID, "for-each-ref", "--format=%(refname) %(objectname) %(*objectname)", "refs/heads", "refs/tags")
+	if err != nil {
+		return Refs{}, err
+	}
+
+	refs := parseRefs(strings.Split(out, "\n"))
+	refs.DefaultBranch = defaultBranch
+	return refs, nil
+}
+
+// parseRefs converts the output of git for-each-ref into a Refs value. Annotated tags are
+// resolved to the commit they point at.
+func parseRefs(lines []string) Refs {
+	refs := Refs{
+		Branches: map[string]string{},
+		Tags:     map[string][]string{},
+	}
+
+	for _, line := range lines {
+		parts := strings.Fields(line)
+		if len(parts) < 2 {
+			continue
+		}
+
+		refname, commit := parts[0], parts[1]
+		if len(parts) > 2 {
+			// Use the peeled object of annotated tags
+			commit = parts[2]
+		}
+
+		if name := strings.TrimPrefix(refname, "refs/heads/"); name != refname {
+			refs.Branches[name] = commit
+		} else if name := strings.TrimPrefix(refname, "refs/tags/"); name != refname {
+			refs.Tags[commit] = append(refs.Tags[commit], name)
+		}
+	}
+
+	return refs
+}
+
+// IsAncestor determines whether the given commit is an ancestor of (or equal to) the given
+// descendant commit. Commits that are unknown to the repository, for example because they
+// were force-pushed away, are not ancestors of any commit.
+func IsAncestor(ctx context.Context, store store.Store, repositoryID int, commit, descendant string) (bool, error) {
+	repo, err := repositoryIDToRepo(ctx, store, repositoryID)
+	if err != nil {
+		return false, err
+	}
+
+	cmd := gitserver.DefaultClient.Command("git", "merge-base", "--is-ancestor", commit, descendant)
+	cmd.Repo = repo
+	_, stderr, err := cmd.DividedOutput(ctx)
+	if err == nil {
+		return true, nil
+	}
+
+	// git merge-base --is-ancestor exits with status 1 if the commit is not an ancestor,
+	// and with status 128 if either commit does not exist.
+	if cmd.ExitStatus == 1 || (cmd.ExitStatus == 128 && isUnknownCommitError(string(stderr))) {
+		return false, nil
+	}
+
+	return false, errors.Wrap(err, "gitserver.Command")
+}
+
+// isUnknownCommitError returns true if the given stderr output of a git command reports
+// that a commit does not exist.
+func isUnknownCommitError(stderr string) bool {
+	for _, message := range []string{"Not a valid commit name", "Not a valid object name", "bad object"} {
+		if strings.Contains(stderr, message) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,158 @@
+package gitserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	storemocks "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/store/mocks"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+func TestParseRefs(t *testing.T) {
+	lines := []string{
+		"refs/heads/main 9ad62c7ec68e377b41a8b8dd846e573b76634172 ",
+		"refs/heads/feature/x 683cafd122632142bda6e36563f5719e5b0fa37d ",
+		"refs/tags/v1.0.0 1afa9c06d8bb8b2c5746e539ed4eb80c23b21db3 ",
+		"refs/tags/v1.0.1 02f41985f46b400b7a673c3dfb6bab8fd1ac6a6d 9ad62c7ec68e377b41a8b8dd846e573b76634172",
+		"refs/tags/latest 9ad62c7ec68e377b41a8b8dd846e573b76634172 ",
+		"",
+	}
+
+	expected := Refs{
+		Branches: map[string]string{
+			"main":      "9ad62c7ec68e377b41a8b8dd846e573b76634172",
+			"feature/x": "683cafd122632142bda6e36563f5719e5b0fa37d",
+		},
+		Tags: map[string][]string{
+			"1afa9c06d8bb8b2c5746e539ed4eb80c23b21db3": {"v1.0.0"},
+			"9ad62c7ec68e377b41a8b8dd846e573b76634172": {"v1.0.1", "latest"},
+		},
+	}
+
+	if diff := cmp.Diff(expected, parseRefs(lines)); diff != "" {
+		t.Errorf("unexpected refs (-want +got):\n%s", diff)
+	}
+}
+
+func TestIsAncestor(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir, err := ioutil.TempDir("", "codeintel-gitserver")
+	if err != nil {
+		t.Fatalf("unexpected error creating temp directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	runGit(t, dir, "init")
+	runGit(t, dir, "commit", "--allow-empty", "-m", "a")
+	a := runGit(t, dir, "rev-parse", "HEAD")
+	runGit(t, dir, "commit", "--allow-empty", "-m", "b")
+	b := runGit(t, dir, "rev-parse", "HEAD")
+	runGit(t, dir, "checkout", "--orphan", "unrelated")
+	runGit(t, dir, "commit", "--allow-empty", "-m", "c")
+	c := runGit(t, dir, "rev-parse", "HEAD")
+
+	setupFakeGitserver(t, dir)
+
+	mockStore := storemocks.NewMockStore()
+	mockStore.RepoNameFunc.SetDefaultReturn("github.com/test/test", nil)
+
+	testCases := []struct {
+		name       string
+		commit     string
+		descendant string
+		expected   bool
+	}{
+		{"ancestor", a, b, true},
+		{"same commit", b, b, true},
+		{"descendant", b, a, false},
+		{"unrelated", c, b, false},
+		{"unknown", "deadbeefdeadbeefdeadbeefdeadbeefdeadbeef", b, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			isAncestor, err := IsAncestor(context.Background(), mockStore, 42, testCase.commit, testCase.descendant)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if isAncestor != testCase.expected {
+				t.Errorf("unexpected result. want=%v have=%v", testCase.expected, isAncestor)
+			}
+		})
+	}
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=a", "GIT_AUTHOR_EMAIL=a@a.com", "GIT_COMMITTER_NAME=a", "GIT_COMMITTER_EMAIL=a@a.com",
+		"GIT_CONFIG_NOSYSTEM=1", "HOME="+dir,
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("unexpected error running git %s: %s\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// setupFakeGitserver replaces the default gitserver client with one that runs git commands
+// in the given directory and reports the result the same way gitserver does.
+func setupFakeGitserver(t *testing.T, dir string) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req protocol.ExecRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var stdout, stderr bytes.Buffer
+		cmd := exec.Command("git", req.Args...)
+		cmd.Dir = dir
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		runErr := cmd.Run()
+
+		execError, exitStatus := "", 0
+		if runErr != nil {
+			execError = runErr.Error()
+			if exitErr, ok := runErr.(*exec.ExitError); ok {
+				exitStatus = exitErr.ExitCode()
+			}
+		}
+
+		w.Header().Set("Trailer", "X-Exec-Error")
+		w.Header().Add("Trailer", "X-Exec-Exit-Status")
+		w.Header().Add("Trailer", "X-Exec-Stderr")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(stdout.Bytes())
+		w.Header().Set("X-Exec-Error", execError)
+		w.Header().Set("X-Exec-Exit-Status", strconv.Itoa(exitStatus))
+		w.Header().Set("X-Exec-Stderr", stderr.String())
+	}))
+
+	client := gitserver.NewClient(http.DefaultClient)
+	client.Addrs = func(ctx context.Context) []string { return []string{strings.TrimPrefix(ts.URL, "http://")} }
+
+	defaultClient := gitserver.DefaultClient
+	gitserver.DefaultClient = client
+
+	t.Cleanup(func() {
+		gitserver.DefaultClient = defaultClient
+		ts.Close()
+	})
+}
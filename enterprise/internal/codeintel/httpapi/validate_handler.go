@@ -0,0 +1,94 @@
+package httpapi
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/inconshreveable/log15"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/lsif/validation"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+)
+
+// maxValidatePayloadSize is the maximum size of the (possibly gzipped) body of a
+// validation request.
+const maxValidatePayloadSize = 500 * 1000 * 1000 // 500MB
+
+type ValidateHandler struct {
+	internal       bool
+	maxPayloadSize int64
+}
+
+func NewValidateHandler(internal bool) http.Handler {
+	handler := &ValidateHandler{
+		internal:       internal,
+		maxPayloadSize: maxValidatePayloadSize,
+	}
+
+	return http.HandlerFunc(handler.handleValidate)
+}
+
+// gzipMagic is the header that begins every gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// POST /validate
+//
+// handleValidate runs the structural checks of the validation package over the LSIF dump in
+// the request body and writes the resulting report. The body may be gzipped (as sent by src-cli)
+// or raw. Nothing is persisted.
+func (h *ValidateHandler) handleValidate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// 🚨 SECURITY: Validation is expensive for large dumps, so we only allow authenticated
+	// users to hit the external endpoint.
+	if !h.internal && !actor.FromContext(ctx).IsAuthenticated() {
+		http.Error(w, "must be authenticated to validate LSIF uploads", http.StatusUnauthorized)
+		return
+	}
+
+	body, err := decompressIfGzipped(http.MaxBytesReader(w, r.Body, h.maxPayloadSize))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read payload: %s", err.Error()), payloadErrorStatus(err))
+		return
+	}
+
+	report, err := validation.Validate(ctx, body)
+	if err != nil {
+		log15.Error("Failed to validate payload", "error", err)
+		http.Error(w, fmt.Sprintf("failed to validate payload: %s", err.Error()), payloadErrorStatus(err))
+		return
+	}
+
+	writeJSON(w, report)
+}
+
+// payloadErrorStatus returns the status code of the response to a request whose body could
+// not be read with the given error.
+func payloadErrorStatus(err error) int {
+	// http.MaxBytesReader returns an untyped error once the limit is exceeded
+	if err.Error() == "http: request body too large" {
+		return http.StatusRequestEntityTooLarge
+	}
+
+	return http.StatusBadRequest
+}
+
+// decompressIfGzipped returns a reader that produces the decompressed content of r if it
+// begins with a gzip header, and the content of r unchanged otherwise.
+func decompressIfGzipped(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+
+	header, err := br.Peek(len(gzipMagic))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	if !bytes.Equal(header, gzipMagic) {
+		return br, nil
+	}
+
+	return gzip.NewReader(br)
+}
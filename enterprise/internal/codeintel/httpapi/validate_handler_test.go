@@ -0,0 +1,98 @@
+package httpapi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/lsif/validation"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+)
+
+const testValidateDump = `{"id": "1", "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///"}
+{"id": "2", "type": "vertex", "label": "document", "uri": "file:///main.go"}
+{"id": "3", "type": "vertex", "label": "range", "start": {"line": 1, "character": 2}, "end": {"line": 1, "character": 5}}
+{"id": "4", "type": "edge", "label": "contains", "outV": "2", "inVs": ["3", "5"]}
+`
+
+func TestHandleValidate(t *testing.T) {
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	if _, err := gzipWriter.Write([]byte(testValidateDump)); err != nil {
+		t.Fatalf("unexpected error writing payload: %s", err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		t.Fatalf("unexpected error closing gzip writer: %s", err)
+	}
+
+	for name, body := range map[string][]byte{"raw": []byte(testValidateDump), "gzip": buf.Bytes()} {
+		t.Run(name, func(t *testing.T) {
+			r, err := http.NewRequest("POST", "http://test.com/validate", bytes.NewReader(body))
+			if err != nil {
+				t.Fatalf("unexpected error constructing request: %s", err)
+			}
+
+			w := httptest.NewRecorder()
+			h := &ValidateHandler{internal: true, maxPayloadSize: maxValidatePayloadSize}
+			h.handleValidate(w, r)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("unexpected status code. want=%d have=%d", http.StatusOK, w.Code)
+			}
+
+			var report validation.Report
+			if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+				t.Fatalf("unexpected error unmarshalling report: %s", err)
+			}
+
+			if report.Valid {
+				t.Errorf("expected report to be invalid")
+			}
+			if len(report.Issues) != 1 || report.Issues[0].Check != "dangling-edge" {
+				t.Errorf("unexpected issues: %v", report.Issues)
+			}
+		})
+	}
+}
+
+func TestHandleValidateTooLarge(t *testing.T) {
+	r, err := http.NewRequest("POST", "http://test.com/validate", strings.NewReader(testValidateDump))
+	if err != nil {
+		t.Fatalf("unexpected error constructing request: %s", err)
+	}
+
+	w := httptest.NewRecorder()
+	h := &ValidateHandler{internal: true, maxPayloadSize: int64(len(testValidateDump) / 2)}
+	h.handleValidate(w, r)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("unexpected status code. want=%d have=%d", http.StatusRequestEntityTooLarge, w.Code)
+	}
+}
+
+func TestHandleValidateUnauthenticated(t *testing.T) {
+	r, err := http.NewRequest("POST", "http://test.com/validate", strings.NewReader(testValidateDump))
+	if err != nil {
+		t.Fatalf("unexpected error constructing request: %s", err)
+	}
+
+	w := httptest.NewRecorder()
+	h := &ValidateHandler{internal: false, maxPayloadSize: maxValidatePayloadSize}
+	h.handleValidate(w, r)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("unexpected status code. want=%d have=%d", http.StatusUnauthorized, w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	r, _ = http.NewRequest("POST", "http://test.com/validate", strings.NewReader(testValidateDump))
+	h.handleValidate(w, r.WithContext(actor.WithActor(r.Context(), &actor.Actor{UID: 1})))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("unexpected status code. want=%d have=%d", http.StatusOK, w.Code)
+	}
+}
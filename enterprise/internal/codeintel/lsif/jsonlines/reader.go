@@ -4,8 +4,8 @@ import (
 	"context"
 	"io"
 
-	"github.com/sourcegraph/sourcegraph/enterprise/cmd/precise-code-intel-worker/internal/correlation/lsif"
-	"github.com/sourcegraph/sourcegraph/enterprise/cmd/precise-code-intel-worker/internal/correlation/lsif/lines"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/lsif"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/lsif/lines"
 )
 
 // Read reads the given content as line-separated JSON objects representing a single LSIF vertex or
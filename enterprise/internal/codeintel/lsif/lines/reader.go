@@ -8,7 +8,7 @@ import (
 	"runtime"
 	"sync"
 
-	"github.com/sourcegraph/sourcegraph/enterprise/cmd/precise-code-intel-worker/internal/correlation/lsif"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/lsif"
 )
 
 // LineBufferSize is the maximum size of the buffer used to read each line of a raw LSIF index. Lines in
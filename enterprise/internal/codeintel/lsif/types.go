@@ -1,6 +1,6 @@
 package lsif
 
-import "github.com/sourcegraph/sourcegraph/enterprise/cmd/precise-code-intel-worker/internal/correlation/datastructures"
+import "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/datastructures"
 
 type Element struct {
 	ID      string
@@ -0,0 +1,449 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/lsif"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/lsif/jsonlines"
+)
+
+// MaxIssues is the maximum number of issues included in a report.
+const MaxIssues = 1000
+
+const (
+	// SeverityError indicates an issue that causes the upload to fail processing
+	// or to produce incorrect results.
+	SeverityError = "error"
+
+	// SeverityWarning indicates an issue that degrades the code intelligence
+	// provided by the upload.
+	SeverityWarning = "warning"
+)
+
+// Issue is a single problem found in an LSIF dump.
+type Issue struct {
+	// Check is the name of the check that found the issue.
+	Check string `json:"check"`
+
+	// Severity is either SeverityError or SeverityWarning.
+	Severity string `json:"severity"`
+
+	// Element is the one-based index of the offending element in the dump (counting
+	// only non-empty lines). Zero if the issue does not concern a particular element.
+	Element int `json:"element,omitempty"`
+
+	// ElementID is the identifier of the offending element.
+	ElementID string `json:"elementId,omitempty"`
+
+	// Message is a human-readable description of the issue.
+	Message string `json:"message"`
+}
+
+// Report is the result of validating an LSIF dump.
+type Report struct {
+	// Valid is true if no issue has error severity.
+	Valid bool `json:"valid"`
+
+	// LSIFVersion is the version declared by the metaData vertex.
+	LSIFVersion string `json:"lsifVersion,omitempty"`
+
+	NumVertices int `json:"numVertices"`
+	NumEdges    int `json:"numEdges"`
+
+	// Issues is the list of issues found, ordered by element.
+	Issues []Issue `json:"issues"`
+
+	// Truncated is true if more than MaxIssues issues were found, in which case only the
+	// MaxIssues issues of the earliest elements are included.
+	Truncated bool `json:"truncated"`
+}
+
+// Validate reads an LSIF dump with the same reader used to process uploads and checks its
+// structure. Unlike processing, validation does not stop at the first problem, so the report
+// lists every issue found (up to MaxIssues). Validate does not persist any data. An error is
+// returned only if the given reader cannot be read.
+func Validate(ctx context.Context, r io.Reader) (Report, error) {
+	reader := &errorRecordingReader{r: r}
+
+	ctx, cancel := context.WithCancel(ctx)
+	ch := jsonlines.Read(ctx, reader)
+	defer func() {
+		cancel()
+
+		for range ch {
+			// drain whatever is in the channel to help out GC
+		}
+	}()
+
+	v := newValidator()
+
+	i := 0
+	for pair := range ch {
+		i++
+
+		if pair.Err != nil {
+			if err := reader.Err(); err != nil && pair.Err == err {
+				return Report{}, pair.Err
+			}
+
+			v.addIssue(i, pair.Element.ID, SeverityError, "malformed-element", "could not parse element: %s", pair.Err)
+			continue
+		}
+
+		v.visit(i, pair.Element)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return Report{}, err
+	}
+
+	return v.report(i), nil
+}
+
+// errorRecordingReader records the first non-EOF error returned by the underlying reader so
+// that read failures can be distinguished from malformed input.
+type errorRecordingReader struct {
+	r   io.Reader
+	mu  sync.Mutex
+	err error
+}
+
+func (r *errorRecordingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if err != nil && err != io.EOF {
+		r.mu.Lock()
+		if r.err == nil {
+			r.err = err
+		}
+		r.mu.Unlock()
+	}
+
+	return n, err
+}
+
+// Err returns the first error returned by the underlying reader.
+func (r *errorRecordingReader) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// elementRef identifies an element by its position in the dump and its identifier.
+type elementRef struct {
+	index int
+	id    string
+}
+
+// itemRange is a range referenced by an item edge, along with the document the item edge
+// declares the range to belong to.
+type itemRange struct {
+	edge     elementRef
+	document string
+	rangeID  string
+}
+
+type validator struct {
+	lsifVersion string
+	numVertices int
+	numEdges    int
+	issues      []Issue
+
+	// vertices maps vertex identifiers to their label.
+	vertices map[string]string
+
+	// vertexIndexes maps vertex identifiers to the index of the vertex in the dump.
+	vertexIndexes map[string]int
+
+	// ranges contains the ranges in the order they were defined.
+	ranges []elementRef
+
+	// containedBy maps range identifiers to the documents that contain them.
+	containedBy map[string][]string
+
+	// pendingEndpoints are edge endpoints that were not yet defined when the edge was read.
+	pendingEndpoints []itemRange
+
+	// itemRanges are the ranges referenced by item edges with a document property.
+	itemRanges []itemRange
+
+	// monikers contains the import and export monikers in the order they were defined.
+	monikers []elementRef
+
+	// packageInformationEdges maps moniker identifiers to the package information attached to them.
+	packageInformationEdges map[string]string
+
+	// typedEdges are the edges whose endpoints are restricted by edgeEndpointLabels.
+	typedEdges []typedEdge
+}
+
+type typedEdge struct {
+	edge  elementRef
+	label string
+	outV  string
+	inV   string
+}
+
+// edgeEndpointLabels maps edge labels to the vertex labels permitted as their outV and inV.
+var edgeEndpointLabels = map[string]struct{ outV, inV []string }{
+	"moniker":            {outV: []string{"range", "resultSet"}, inV: []string{"moniker"}},
+	"packageInformation": {outV: []string{"moniker"}, inV: []string{"packageInformation"}},
+}
+
+func newValidator() *validator {
+	return &validator{
+		vertices:                map[string]string{},
+		vertexIndexes:           map[string]int{},
+		containedBy:             map[string][]string{},
+		packageInformationEdges: map[string]string{},
+	}
+}
+
+func (v *validator) addIssue(index int, id, severity, check, format string, args ...interface{}) {
+	v.issues = append(v.issues, Issue{
+		Check:     check,
+		Severity:  severity,
+		Element:   index,
+		ElementID: id,
+		Message:   fmt.Sprintf(format, args...),
+	})
+}
+
+// visit updates the state of the validator with the given element and reports issues that
+// can be detected without looking at the remainder of the dump.
+func (v *validator) visit(index int, element lsif.Element) {
+	if index == 1 && (element.Type != "vertex" || element.Label != "metaData") {
+		v.addIssue(index, element.ID, SeverityError, "missing-metadata", "the first element must be a metaData vertex")
+	}
+
+	switch element.Type {
+	case "vertex":
+		v.visitVertex(index, element)
+	case "edge":
+		v.visitEdge(index, element)
+	default:
+		v.addIssue(index, element.ID, SeverityError, "unknown-element-type", "unknown element type %q", element.Type)
+	}
+}
+
+func (v *validator) visitVertex(index int, element lsif.Element) {
+	v.numVertices++
+
+	if _, ok := v.vertices[element.ID]; ok {
+		v.addIssue(index, element.ID, SeverityError, "duplicate-vertex", "vertex %s is defined more than once", element.ID)
+	}
+	v.vertices[element.ID] = element.Label
+	v.vertexIndexes[element.ID] = index
+
+	switch payload := element.Payload.(type) {
+	case lsif.MetaData:
+		v.lsifVersion = payload.Version
+
+	case lsif.Range:
+		v.ranges = append(v.ranges, elementRef{index, element.ID})
+
+		if payload.StartLine < 0 || payload.StartCharacter < 0 || payload.EndLine < 0 || payload.EndCharacter < 0 {
+			v.addIssue(index, element.ID, SeverityError, "invalid-range", "range has a negative position")
+		} else if payload.StartLine > payload.EndLine || (payload.StartLine == payload.EndLine && payload.StartCharacter > payload.EndCharacter) {
+			v.addIssue(index, element.ID, SeverityError, "invalid-range", "range ends before it starts")
+		}
+
+	case lsif.Moniker:
+		if payload.Kind == "import" || payload.Kind == "export" {
+			v.monikers = append(v.monikers, elementRef{index, element.ID})
+		}
+
+	case lsif.PackageInformation:
+		if payload.Name == "" || payload.Version == "" {
+			v.addIssue(index, element.ID, SeverityWarning, "incomplete-package-information", "packageInformation vertex has no name or version")
+		}
+	}
+}
+
+func (v *validator) visitEdge(index int, element lsif.Element) {
+	v.numEdges++
+
+	edge, ok := element.Payload.(lsif.Edge)
+	if !ok {
+		v.addIssue(index, element.ID, SeverityError, "malformed-element", "could not parse edge")
+		return
+	}
+
+	ref := elementRef{index, element.ID}
+
+	if edge.OutV == "" {
+		v.addIssue(index, element.ID, SeverityError, "malformed-edge", "edge has no outV")
+	}
+	if edge.InV == "" && len(edge.InVs) == 0 {
+		v.addIssue(index, element.ID, SeverityError, "malformed-edge", "edge has neither inV nor inVs")
+	}
+
+	endpoints := []string{edge.OutV, edge.InV, edge.Document}
+	endpoints = append(endpoints, edge.InVs...)
+	for _, endpoint := range endpoints {
+		if _, ok := v.vertices[endpoint]; endpoint != "" && !ok {
+			v.pendingEndpoints = append(v.pendingEndpoints, itemRange{edge: ref, rangeID: endpoint})
+		}
+	}
+
+	switch element.Label {
+	case "contains":
+		if v.vertices[edge.OutV] == "document" {
+			for _, inV := range edge.InVs {
+				v.containedBy[inV] = append(v.containedBy[inV], edge.OutV)
+			}
+		}
+
+	case "item":
+		if edge.Document != "" {
+			for _, inV := range edge.InVs {
+				v.itemRanges = append(v.itemRanges, itemRange{edge: ref, document: edge.Document, rangeID: inV})
+			}
+		}
+
+	case "packageInformation":
+		v.packageInformationEdges[edge.OutV] = edge.InV
+	}
+
+	if _, ok := edgeEndpointLabels[element.Label]; ok {
+		v.typedEdges = append(v.typedEdges, typedEdge{ref, element.Label, edge.OutV, edge.InV})
+	}
+}
+
+// report runs the checks that require the entire dump and returns the final report.
+func (v *validator) report(numElements int) Report {
+	if numElements == 0 {
+		v.addIssue(0, "", SeverityError, "missing-metadata", "the dump is empty")
+	}
+
+	v.checkEndpoints()
+	v.checkContains()
+	v.checkItemDocuments()
+	v.checkMonikers()
+	sortIssues(v.issues)
+
+	valid := true
+	for _, issue := range v.issues {
+		if issue.Severity == SeverityError {
+			valid = false
+			break
+		}
+	}
+
+	// Issues are only truncated once they are sorted, so that the issues found after reading
+	// the entire dump aren't dropped in favor of the ones of later elements.
+	issues := v.issues
+	truncated := len(issues) > MaxIssues
+	if truncated {
+		issues = issues[:MaxIssues]
+	}
+	if issues == nil {
+		issues = []Issue{}
+	}
+
+	return Report{
+		Valid:       valid,
+		LSIFVersion: v.lsifVersion,
+		NumVertices: v.numVertices,
+		NumEdges:    v.numEdges,
+		Issues:      issues,
+		Truncated:   truncated,
+	}
+}
+
+// checkEndpoints reports edges that refer to vertices that are never defined (dangling edges)
+// and edges that refer to vertices defined later in the dump. The latter are rejected during
+// processing, which requires vertices to be emitted before the edges that refer to them.
+func (v *validator) checkEndpoints() {
+	for _, pending := range v.pendingEndpoints {
+		if index, ok := v.vertexIndexes[pending.rangeID]; ok {
+			v.addIssue(pending.edge.index, pending.edge.id, SeverityError, "forward-reference", "edge refers to vertex %s which is defined later (element %d)", pending.rangeID, index)
+		} else {
+			v.addIssue(pending.edge.index, pending.edge.id, SeverityError, "dangling-edge", "edge refers to unknown vertex %s", pending.rangeID)
+		}
+	}
+}
+
+// checkContains reports ranges that do not belong to exactly one document.
+func (v *validator) checkContains() {
+	for _, r := range v.ranges {
+		switch documents := v.containedBy[r.id]; len(documents) {
+		case 0:
+			v.addIssue(r.index, r.id, SeverityError, "missing-contains-edge", "range is not contained by any document")
+		case 1:
+		default:
+			v.addIssue(r.index, r.id, SeverityError, "range-in-multiple-documents", "range is contained by multiple documents (%s and %s)", documents[0], documents[1])
+		}
+	}
+}
+
+// checkItemDocuments reports ranges referenced by an item edge that are not contained by the
+// document the item edge declares.
+func (v *validator) checkItemDocuments() {
+	for _, item := range v.itemRanges {
+		if v.vertices[item.rangeID] != "range" {
+			continue
+		}
+
+		documents := v.containedBy[item.rangeID]
+		if len(documents) == 0 {
+			// Already reported as a missing contains edge
+			continue
+		}
+
+		found := false
+		for _, document := range documents {
+			if document == item.document {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			v.addIssue(item.edge.index, item.edge.id, SeverityError, "range-outside-document", "range %s is not contained by document %s", item.rangeID, item.document)
+		}
+	}
+}
+
+// checkMonikers reports moniker and packageInformation edges that connect the wrong kinds of
+// vertices. Import and export monikers without package information are reported as well, as
+// they cannot be used for cross-repository navigation.
+func (v *validator) checkMonikers() {
+	for _, edge := range v.typedEdges {
+		labels := edgeEndpointLabels[edge.label]
+
+		if label, ok := v.vertices[edge.outV]; ok && !contains(labels.outV, label) {
+			v.addIssue(edge.edge.index, edge.edge.id, SeverityError, "invalid-edge-endpoint", "%s edge starts at a %s vertex (expected %s)", edge.label, label, strings.Join(labels.outV, " or "))
+		}
+		if label, ok := v.vertices[edge.inV]; ok && !contains(labels.inV, label) {
+			v.addIssue(edge.edge.index, edge.edge.id, SeverityError, "invalid-edge-endpoint", "%s edge ends at a %s vertex (expected %s)", edge.label, label, strings.Join(labels.inV, " or "))
+		}
+	}
+
+	for _, moniker := range v.monikers {
+		if _, ok := v.packageInformationEdges[moniker.id]; !ok {
+			v.addIssue(moniker.index, moniker.id, SeverityWarning, "missing-package-information", "import or export moniker has no packageInformation edge")
+		}
+	}
+}
+
+// sortIssues orders issues by the index of the element they concern.
+func sortIssues(issues []Issue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Element < issues[j].Element
+	})
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,186 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValidateValid(t *testing.T) {
+	report := validate(t, []string{
+		`{"id": "1", "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///"}`,
+		`{"id": "2", "type": "vertex", "label": "document", "uri": "file:///main.go"}`,
+		`{"id": "3", "type": "vertex", "label": "range", "start": {"line": 1, "character": 2}, "end": {"line": 1, "character": 5}}`,
+		`{"id": "4", "type": "edge", "label": "contains", "outV": "2", "inVs": ["3"]}`,
+		`{"id": "5", "type": "vertex", "label": "definitionResult"}`,
+		`{"id": "6", "type": "edge", "label": "item", "outV": "5", "inVs": ["3"], "document": "2"}`,
+		`{"id": "7", "type": "vertex", "label": "moniker", "kind": "export", "scheme": "gomod", "identifier": "pkg:Foo"}`,
+		`{"id": "8", "type": "vertex", "label": "packageInformation", "name": "pkg", "version": "v1.0.0"}`,
+		`{"id": "9", "type": "edge", "label": "packageInformation", "outV": "7", "inV": "8"}`,
+		`{"id": "10", "type": "edge", "label": "moniker", "outV": "3", "inV": "7"}`,
+	})
+
+	expected := Report{
+		Valid:       true,
+		LSIFVersion: "0.4.3",
+		NumVertices: 6,
+		NumEdges:    4,
+		Issues:      []Issue{},
+	}
+	if diff := cmp.Diff(expected, report); diff != "" {
+		t.Errorf("unexpected report (-want +got):\n%s", diff)
+	}
+}
+
+func TestValidateIssues(t *testing.T) {
+	report := validate(t, []string{
+		`{"id": "1", "type": "vertex", "label": "document", "uri": "file:///main.go"}`,
+		`{"id": "2", "type": "vertex", "label": "document", "uri": "file:///util.go"}`,
+		`{"id": "3", "type": "vertex", "label": "range", "start": {"line": 4, "character": 2}, "end": {"line": 1, "character": 5}}`,
+		`{"id": "4", "type": "vertex", "label": "range", "start": {"line": 1, "character": 2}, "end": {"line": 1, "character": 5}}`,
+		`{"id": "5", "type": "edge", "label": "contains", "outV": "1", "inVs": ["3"]}`,
+		`{"id": "6", "type": "vertex", "label": "definitionResult"}`,
+		`{"id": "7", "type": "edge", "label": "item", "outV": "6", "inVs": ["3"], "document": "2"}`,
+		`{"id": "8", "type": "edge", "label": "next", "outV": "3", "inV": "42"}`,
+		`{"id": "9", "type": "edge", "label": "next", "outV": "4", "inV": "11"}`,
+		`not json`,
+		`{"id": "11", "type": "vertex", "label": "resultSet"}`,
+		`{"id": "12", "type": "vertex", "label": "moniker", "kind": "import", "scheme": "gomod", "identifier": "pkg:Foo"}`,
+		`{"id": "13", "type": "edge", "label": "packageInformation", "outV": "3", "inV": "12"}`,
+	})
+
+	expected := Report{
+		Valid:       false,
+		NumVertices: 7,
+		NumEdges:    5,
+		Issues: []Issue{
+			{Check: "missing-metadata", Severity: SeverityError, Element: 1, ElementID: "1", Message: "the first element must be a metaData vertex"},
+			{Check: "invalid-range", Severity: SeverityError, Element: 3, ElementID: "3", Message: "range ends before it starts"},
+			{Check: "missing-contains-edge", Severity: SeverityError, Element: 4, ElementID: "4", Message: "range is not contained by any document"},
+			{Check: "range-outside-document", Severity: SeverityError, Element: 7, ElementID: "7", Message: "range 3 is not contained by document 2"},
+			{Check: "dangling-edge", Severity: SeverityError, Element: 8, ElementID: "8", Message: "edge refers to unknown vertex 42"},
+			{Check: "forward-reference", Severity: SeverityError, Element: 9, ElementID: "9", Message: "edge refers to vertex 11 which is defined later (element 11)"},
+			{Check: "malformed-element", Severity: SeverityError, Element: 10, Message: "could not parse element: skipThreeBytes: expect ull, error found in #2 byte of ...|not json|..., bigger context ...|not json|..."},
+			{Check: "missing-package-information", Severity: SeverityWarning, Element: 12, ElementID: "12", Message: "import or export moniker has no packageInformation edge"},
+			{Check: "invalid-edge-endpoint", Severity: SeverityError, Element: 13, ElementID: "13", Message: "packageInformation edge starts at a range vertex (expected moniker)"},
+			{Check: "invalid-edge-endpoint", Severity: SeverityError, Element: 13, ElementID: "13", Message: "packageInformation edge ends at a moniker vertex (expected packageInformation)"},
+		},
+	}
+	if diff := cmp.Diff(expected, report); diff != "" {
+		t.Errorf("unexpected report (-want +got):\n%s", diff)
+	}
+}
+
+func TestValidateWarningsOnly(t *testing.T) {
+	report := validate(t, []string{
+		`{"id": "1", "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///"}`,
+		`{"id": "2", "type": "vertex", "label": "packageInformation", "name": "pkg"}`,
+	})
+
+	if !report.Valid {
+		t.Errorf("expected report to be valid")
+	}
+	if len(report.Issues) != 1 || report.Issues[0].Check != "incomplete-package-information" {
+		t.Errorf("unexpected issues: %v", report.Issues)
+	}
+}
+
+func TestValidateEmpty(t *testing.T) {
+	report := validate(t, nil)
+
+	if report.Valid {
+		t.Errorf("expected report to be invalid")
+	}
+	if len(report.Issues) != 1 || report.Issues[0].Check != "missing-metadata" {
+		t.Errorf("unexpected issues: %v", report.Issues)
+	}
+}
+
+func TestValidateTruncated(t *testing.T) {
+	lines := []string{`{"id": "1", "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///"}`}
+	for i := 0; i < MaxIssues+10; i++ {
+		lines = append(lines, `{"id": "2", "type": "edge", "label": "next", "outV": "1", "inV": "42"}`)
+	}
+
+	report := validate(t, lines)
+
+	if !report.Truncated {
+		t.Errorf("expected report to be truncated")
+	}
+	if len(report.Issues) != MaxIssues {
+		t.Errorf("unexpected number of issues. want=%d have=%d", MaxIssues, len(report.Issues))
+	}
+}
+
+func TestValidateTruncatedKeepsEarliestIssues(t *testing.T) {
+	lines := []string{
+		`{"id": "1", "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///"}`,
+		`{"id": "2", "type": "edge", "label": "next", "outV": "1", "inV": "42"}`,
+	}
+	for i := 0; i < MaxIssues+10; i++ {
+		lines = append(lines, `not json`)
+	}
+
+	report := validate(t, lines)
+
+	if !report.Truncated {
+		t.Errorf("expected report to be truncated")
+	}
+	if len(report.Issues) != MaxIssues {
+		t.Fatalf("unexpected number of issues. want=%d have=%d", MaxIssues, len(report.Issues))
+	}
+	if report.Issues[0].Check != "dangling-edge" {
+		t.Errorf("expected the dangling edge of the second element to be reported first, have %v", report.Issues[0])
+	}
+}
+
+func TestValidateMultipleDocuments(t *testing.T) {
+	report := validate(t, []string{
+		`{"id": "1", "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///"}`,
+		`{"id": "2", "type": "vertex", "label": "document", "uri": "file:///main.go"}`,
+		`{"id": "3", "type": "vertex", "label": "document", "uri": "file:///util.go"}`,
+		`{"id": "4", "type": "vertex", "label": "range", "start": {"line": 1, "character": 2}, "end": {"line": 1, "character": 5}}`,
+		`{"id": "5", "type": "edge", "label": "contains", "outV": "2", "inVs": ["4"]}`,
+		`{"id": "6", "type": "edge", "label": "contains", "outV": "3", "inVs": ["4"]}`,
+	})
+
+	expected := []Issue{
+		{Check: "range-in-multiple-documents", Severity: SeverityError, Element: 4, ElementID: "4", Message: "range is contained by multiple documents (2 and 3)"},
+	}
+	if diff := cmp.Diff(expected, report.Issues); diff != "" {
+		t.Errorf("unexpected issues (-want +got):\n%s", diff)
+	}
+}
+
+func TestValidateReadError(t *testing.T) {
+	readErr := errors.New("uh-oh")
+	r := io.MultiReader(
+		strings.NewReader(`{"id": "1", "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///"}`+"\n"),
+		&errorReader{readErr},
+	)
+
+	if _, err := Validate(context.Background(), r); err != readErr {
+		t.Errorf("unexpected error. want=%q have=%q", readErr, err)
+	}
+}
+
+func validate(t *testing.T, lines []string) Report {
+	report, err := Validate(context.Background(), strings.NewReader(strings.Join(lines, "\n")))
+	if err != nil {
+		t.Fatalf("unexpected error validating dump: %s", err)
+	}
+
+	return report
+}
+
+type errorReader struct {
+	err error
+}
+
+func (r *errorReader) Read(p []byte) (int, error) {
+	return 0, r.err
+}
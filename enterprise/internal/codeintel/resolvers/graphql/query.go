@@ -2,9 +2,11 @@ package graphql
 
 import (
 	"context"
+	"strings"
 
 	"github.com/pkg/errors"
 	gql "github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	codeintelapi "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/api"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/resolvers"
 )
 
@@ -58,7 +60,15 @@ func (r *QueryResolver) References(ctx context.Context, args *gql.LSIFPagedQuery
 		return nil, err
 	}
 
-	locations, cursor, err := r.resolver.References(ctx, int(args.Line), int(args.Character), limit, cursor)
+	versionMatch := codeintelapi.VersionMatch{
+		Mode:       strings.ToLower(derefString(args.VersionMatch, "")),
+		Constraint: derefString(args.VersionConstraint, ""),
+	}
+	if err := versionMatch.Validate(); err != nil {
+		return nil, err
+	}
+
+	locations, cursor, err := r.resolver.References(ctx, int(args.Line), int(args.Character), limit, cursor, versionMatch)
 	if err != nil {
 		return nil, err
 	}
@@ -132,6 +132,30 @@ func (r *Resolver) GitBlobLSIFData(ctx context.Context, args *gql.GitBlobLSIFDat
 	return NewQueryResolver(resolver, r.locationResolver), nil
 }
 
+func (r *Resolver) LSIFUploadRetentionPreview(ctx context.Context, id graphql.ID) ([]gql.LSIFUploadExpirationResolver, error) {
+	// 🚨 SECURITY: Only site admins may preview the removal of LSIF data
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	repositoryID, err := resolveRepositoryID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	expirations, err := r.resolver.UploadExpirations(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]gql.LSIFUploadExpirationResolver, 0, len(expirations))
+	for _, expiration := range expirations {
+		resolvers = append(resolvers, NewUploadExpirationResolver(expiration, r.locationResolver))
+	}
+
+	return resolvers, nil
+}
+
 // makeGetUploadsOptions translates the given GraphQL arguments into options defined by the
 // store.GetUploads operations.
 func makeGetUploadsOptions(ctx context.Context, args *gql.LSIFRepositoryUploadsQueryArgs) (store.GetUploadsOptions, error) {
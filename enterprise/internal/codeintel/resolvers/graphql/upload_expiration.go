@@ -0,0 +1,26 @@
+package graphql
+
+import (
+	gql "github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/retention"
+)
+
+type UploadExpirationResolver struct {
+	expiration       retention.Expiration
+	locationResolver *CachedLocationResolver
+}
+
+func NewUploadExpirationResolver(expiration retention.Expiration, locationResolver *CachedLocationResolver) gql.LSIFUploadExpirationResolver {
+	return &UploadExpirationResolver{
+		expiration:       expiration,
+		locationResolver: locationResolver,
+	}
+}
+
+func (r *UploadExpirationResolver) Upload() gql.LSIFUploadResolver {
+	return NewUploadResolver(r.expiration.Upload, r.locationResolver)
+}
+
+func (r *UploadExpirationResolver) Reason() string {
+	return r.expiration.Reason.String()
+}
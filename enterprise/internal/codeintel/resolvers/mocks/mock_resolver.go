@@ -6,6 +6,7 @@ import (
 	"context"
 	graphqlbackend "github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	resolvers "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/resolvers"
+	retention "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/retention"
 	store "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/store"
 	"sync"
 )
@@ -36,6 +37,9 @@ type MockResolver struct {
 	// UploadConnectionResolverFunc is an instance of a mock function object
 	// controlling the behavior of the method UploadConnectionResolver.
 	UploadConnectionResolverFunc *ResolverUploadConnectionResolverFunc
+	// UploadExpirationsFunc is an instance of a mock function object
+	// controlling the behavior of the method UploadExpirations.
+	UploadExpirationsFunc *ResolverUploadExpirationsFunc
 }
 
 // NewMockResolver creates a new mock of the Resolver interface. All methods
@@ -77,6 +81,11 @@ func NewMockResolver() *MockResolver {
 				return nil
 			},
 		},
+		UploadExpirationsFunc: &ResolverUploadExpirationsFunc{
+			defaultHook: func(context.Context, int) ([]retention.Expiration, error) {
+				return nil, nil
+			},
+		},
 	}
 }
 
@@ -105,6 +114,9 @@ func NewMockResolverFrom(i resolvers.Resolver) *MockResolver {
 		UploadConnectionResolverFunc: &ResolverUploadConnectionResolverFunc{
 			defaultHook: i.UploadConnectionResolver,
 		},
+		UploadExpirationsFunc: &ResolverUploadExpirationsFunc{
+			defaultHook: i.UploadExpirations,
+		},
 	}
 }
 
@@ -864,3 +876,112 @@ func (c ResolverUploadConnectionResolverFuncCall) Args() []interface{} {
 func (c ResolverUploadConnectionResolverFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
+
+// ResolverUploadExpirationsFunc describes the behavior when the
+// UploadExpirations method of the parent MockResolver instance is invoked.
+type ResolverUploadExpirationsFunc struct {
+	defaultHook func(context.Context, int) ([]retention.Expiration, error)
+	hooks       []func(context.Context, int) ([]retention.Expiration, error)
+	history     []ResolverUploadExpirationsFuncCall
+	mutex       sync.Mutex
+}
+
+// UploadExpirations delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockResolver) UploadExpirations(v0 context.Context, v1 int) ([]retention.Expiration, error) {
+	r0, r1 := m.UploadExpirationsFunc.nextHook()(v0, v1)
+	m.UploadExpirationsFunc.appendCall(ResolverUploadExpirationsFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the UploadExpirations
+// method of the parent MockResolver instance is invoked and the hook queue
+// is empty.
+func (f *ResolverUploadExpirationsFunc) SetDefaultHook(hook func(context.Context, int) ([]retention.Expiration, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// UploadExpirations method of the parent MockResolver instance inovkes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *ResolverUploadExpirationsFunc) PushHook(hook func(context.Context, int) ([]retention.Expiration, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *ResolverUploadExpirationsFunc) SetDefaultReturn(r0 []retention.Expiration, r1 error) {
+	f.SetDefaultHook(func(context.Context, int) ([]retention.Expiration, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *ResolverUploadExpirationsFunc) PushReturn(r0 []retention.Expiration, r1 error) {
+	f.PushHook(func(context.Context, int) ([]retention.Expiration, error) {
+		return r0, r1
+	})
+}
+
+func (f *ResolverUploadExpirationsFunc) nextHook() func(context.Context, int) ([]retention.Expiration, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ResolverUploadExpirationsFunc) appendCall(r0 ResolverUploadExpirationsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ResolverUploadExpirationsFuncCall objects
+// describing the invocations of this function.
+func (f *ResolverUploadExpirationsFunc) History() []ResolverUploadExpirationsFuncCall {
+	f.mutex.Lock()
+	history := make([]ResolverUploadExpirationsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ResolverUploadExpirationsFuncCall is an object that describes an
+// invocation of method UploadExpirations on an instance of MockResolver.
+type ResolverUploadExpirationsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []retention.Expiration
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ResolverUploadExpirationsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ResolverUploadExpirationsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
@@ -1,10 +1,10 @@
 package resolvers
 
 import (
-	"bytes"
 	"context"
-	"io/ioutil"
+	"io"
 	"strings"
+	"sync"
 
 	"github.com/sourcegraph/go-diff/diff"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
@@ -37,6 +37,17 @@ type PositionAdjuster interface {
 type positionAdjuster struct {
 	repo   *types.Repo
 	commit string
+
+	// hunks caches the result of readHunks. A query commonly adjusts many ranges of the
+	// same file (e.g. references), which would otherwise require a diff per range.
+	hunksMu sync.Mutex
+	hunks   map[hunksKey][]*diff.Hunk
+}
+
+type hunksKey struct {
+	sourceCommit string
+	targetCommit string
+	path         string
 }
 
 // NewPositionAdjuster creates a new PositionAdjuster with the given repository and source commit.
@@ -44,6 +55,7 @@ func NewPositionAdjuster(repo *types.Repo, commit string) PositionAdjuster {
 	return &positionAdjuster{
 		repo:   repo,
 		commit: commit,
+		hunks:  map[hunksKey][]*diff.Hunk{},
 	}
 }
 
@@ -83,7 +95,8 @@ func (p *positionAdjuster) AdjustRange(ctx context.Context, commit, path string,
 
 // readHunks returns a position-ordered slice of changes (additions or deletions) of the
 // given path between the given source and target commits. If revese is true, then the
-// source and target commits are swapped.
+// source and target commits are swapped. Results are cached for the lifetime of the
+// position adjuster.
 func (p *positionAdjuster) readHunks(ctx context.Context, repo *types.Repo, sourceCommit, targetCommit, path string, reverse bool) ([]*diff.Hunk, error) {
 	if sourceCommit == targetCommit {
 		return nil, nil
@@ -92,31 +105,58 @@ func (p *positionAdjuster) readHunks(ctx context.Context, repo *types.Repo, sour
 		sourceCommit, targetCommit = targetCommit, sourceCommit
 	}
 
-	cachedRepo, err := backend.CachedGitRepo(ctx, repo)
+	key := hunksKey{sourceCommit: sourceCommit, targetCommit: targetCommit, path: path}
+
+	p.hunksMu.Lock()
+	hunks, ok := p.hunks[key]
+	p.hunksMu.Unlock()
+	if ok {
+		return hunks, nil
+	}
+
+	hunks, err := readHunks(ctx, repo, sourceCommit, targetCommit, path)
 	if err != nil {
 		return nil, err
 	}
 
-	// TODO(efritz) - cache diff results
-	reader, err := git.ExecReader(ctx, *cachedRepo, []string{"diff", sourceCommit, targetCommit, "--", path})
+	p.hunksMu.Lock()
+	p.hunks[key] = hunks
+	p.hunksMu.Unlock()
+
+	return hunks, nil
+}
+
+// readHunks requests the diff of the given path between the source and target commits from
+// gitserver. The commits are compared directly as the source commit may be either an ancestor
+// or a descendant of the target commit.
+func readHunks(ctx context.Context, repo *types.Repo, sourceCommit, targetCommit, path string) ([]*diff.Hunk, error) {
+	cachedRepo, err := backend.CachedGitRepo(ctx, repo)
 	if err != nil {
 		return nil, err
 	}
-	defer reader.Close()
 
-	output, err := ioutil.ReadAll(reader)
+	iter, err := git.Diff(ctx, git.DiffOptions{
+		Repo:   *cachedRepo,
+		Base:   sourceCommit,
+		Head:   targetCommit,
+		Direct: true,
+		Paths:  []string{path},
+	})
 	if err != nil {
 		return nil, err
 	}
-	if len(output) == 0 {
-		return nil, nil
-	}
+	defer iter.Close()
 
-	diff, err := diff.NewFileDiffReader(bytes.NewReader(output)).Read()
+	fileDiff, err := iter.Next()
 	if err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+
 		return nil, err
 	}
-	return diff.Hunks, nil
+
+	return fileDiff.Hunks, nil
 }
 
 // adjustPosition translates the given position by adjusting the line number based on the
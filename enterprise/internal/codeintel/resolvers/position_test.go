@@ -35,7 +35,7 @@ func TestAdjustPosition(t *testing.T) {
 		git.Mocks.ExecReader = nil
 	})
 	git.Mocks.ExecReader = func(args []string) (reader io.ReadCloser, err error) {
-		expectedArgs := []string{"diff", "deadbeef1", "deadbeef2", "--", "/foo/bar.go"}
+		expectedArgs := diffArgs("deadbeef1", "deadbeef2", "/foo/bar.go")
 		if diff := cmp.Diff(expectedArgs, args); diff != "" {
 			t.Errorf("unexpected exec reader args (-want +got):\n%s", diff)
 		}
@@ -96,7 +96,7 @@ func TestAdjustPositionReverse(t *testing.T) {
 		git.Mocks.ExecReader = nil
 	})
 	git.Mocks.ExecReader = func(args []string) (reader io.ReadCloser, err error) {
-		expectedArgs := []string{"diff", "deadbeef2", "deadbeef1", "--", "/foo/bar.go"}
+		expectedArgs := diffArgs("deadbeef2", "deadbeef1", "/foo/bar.go")
 		if diff := cmp.Diff(expectedArgs, args); diff != "" {
 			t.Errorf("unexpected exec reader args (-want +got):\n%s", diff)
 		}
@@ -130,7 +130,7 @@ func TestAdjustRange(t *testing.T) {
 		git.Mocks.ExecReader = nil
 	})
 	git.Mocks.ExecReader = func(args []string) (reader io.ReadCloser, err error) {
-		expectedArgs := []string{"diff", "deadbeef1", "deadbeef2", "--", "/foo/bar.go"}
+		expectedArgs := diffArgs("deadbeef1", "deadbeef2", "/foo/bar.go")
 		if diff := cmp.Diff(expectedArgs, args); diff != "" {
 			t.Errorf("unexpected exec reader args (-want +got):\n%s", diff)
 		}
@@ -200,7 +200,7 @@ func TestAdjustRangeReverse(t *testing.T) {
 		git.Mocks.ExecReader = nil
 	})
 	git.Mocks.ExecReader = func(args []string) (reader io.ReadCloser, err error) {
-		expectedArgs := []string{"diff", "deadbeef2", "deadbeef1", "--", "/foo/bar.go"}
+		expectedArgs := diffArgs("deadbeef2", "deadbeef1", "/foo/bar.go")
 		if diff := cmp.Diff(expectedArgs, args); diff != "" {
 			t.Errorf("unexpected exec reader args (-want +got):\n%s", diff)
 		}
@@ -235,6 +235,50 @@ func TestAdjustRangeReverse(t *testing.T) {
 	}
 }
 
+func TestAdjustRangeCachesDiff(t *testing.T) {
+	t.Cleanup(func() {
+		git.Mocks.ExecReader = nil
+	})
+
+	calls := 0
+	git.Mocks.ExecReader = func(args []string) (reader io.ReadCloser, err error) {
+		calls++
+		return ioutil.NopCloser(bytes.NewReader([]byte(hugoDiff))), nil
+	}
+
+	rIn := bundles.Range{
+		Start: bundles.Position{Line: 302, Character: 15},
+		End:   bundles.Position{Line: 305, Character: 20},
+	}
+
+	adjuster := NewPositionAdjuster(&types.Repo{ID: 50}, "deadbeef1")
+	for i := 0; i < 3; i++ {
+		if _, _, _, err := adjuster.AdjustRange(context.Background(), "deadbeef2", "/foo/bar.go", rIn, true); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if _, _, _, err := adjuster.AdjustRange(context.Background(), "deadbeef2", "/foo/baz.go", rIn, true); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if calls != 2 {
+		t.Errorf("unexpected number of diffs. want=%d have=%d", 2, calls)
+	}
+}
+
+func diffArgs(sourceCommit, targetCommit, path string) []string {
+	return []string{
+		"diff",
+		"--find-renames",
+		"--full-index",
+		"--inter-hunk-context=3",
+		"--no-prefix",
+		sourceCommit + ".." + targetCommit,
+		"--",
+		path,
+	}
+}
+
 type adjustPositionTestCase struct {
 	diff         string // The git diff output
 	diffName     string // The git diff output name
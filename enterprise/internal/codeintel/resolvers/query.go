@@ -35,7 +35,7 @@ type AdjustedDiagnostic struct {
 // API.
 type QueryResolver interface {
 	Definitions(ctx context.Context, line, character int) ([]AdjustedLocation, error)
-	References(ctx context.Context, line, character, limit int, rawCursor string) ([]AdjustedLocation, string, error)
+	References(ctx context.Context, line, character, limit int, rawCursor string, versionMatch codeintelapi.VersionMatch) ([]AdjustedLocation, string, error)
 	Hover(ctx context.Context, line, character int) (string, bundles.Range, bool, error)
 	Diagnostics(ctx context.Context, limit int) ([]AdjustedDiagnostic, int, error)
 }
@@ -108,8 +108,10 @@ func (r *queryResolver) Definitions(ctx context.Context, line, character int) ([
 
 // References returns the list of source locations that reference the symbol at the given position.
 // This may include references from other dumps and repositories. If there are multiple bundles
-// associated with this resolver, results from all bundles will be concatenated and returned.
-func (r *queryResolver) References(ctx context.Context, line, character, limit int, rawCursor string) ([]AdjustedLocation, string, error) {
+// associated with this resolver, results from all bundles will be concatenated and returned. The
+// given version match determines which versions of a package are searched in other repositories
+// and is only consulted on the first page of results.
+func (r *queryResolver) References(ctx context.Context, line, character, limit int, rawCursor string, versionMatch codeintelapi.VersionMatch) ([]AdjustedLocation, string, error) {
 	position := bundles.Position{Line: line, Character: character}
 
 	// Decode a map of upload ids to the next url that serves
@@ -150,6 +152,9 @@ func (r *queryResolver) References(ctx context.Context, line, character, limit i
 		if err != nil {
 			return nil, "", err
 		}
+		if rawCursor == "" {
+			cursor.VersionMatch = versionMatch
+		}
 
 		locations, newCursor, hasNewCursor, err := r.codeIntelAPI.References(ctx, r.repositoryID, r.commit, limit, cursor)
 		if err != nil {
@@ -251,10 +256,13 @@ func (r *queryResolver) Diagnostics(ctx context.Context, limit int) ([]AdjustedD
 			End:   client.Position{Line: allDiagnostics[i].Diagnostic.EndLine, Character: allDiagnostics[i].Diagnostic.EndCharacter},
 		}
 
-		adjustedCommit, adjustedRange, err := r.adjustRange(ctx, allDiagnostics[i].Dump.RepositoryID, allDiagnostics[i].Dump.Commit, allDiagnostics[i].Diagnostic.Path, clientRange)
+		adjustedCommit, adjustedRange, ok, err := r.adjustRange(ctx, allDiagnostics[i].Dump.RepositoryID, allDiagnostics[i].Dump.Commit, allDiagnostics[i].Diagnostic.Path, clientRange)
 		if err != nil {
 			return nil, 0, err
 		}
+		if !ok {
+			continue
+		}
 
 		adjustedDiagnostics = append(adjustedDiagnostics, AdjustedDiagnostic{
 			Diagnostic:     allDiagnostics[i].Diagnostic,
@@ -268,14 +276,17 @@ func (r *queryResolver) Diagnostics(ctx context.Context, limit int) ([]AdjustedD
 }
 
 // adjustLocations translates a list of resolved locations (relative to the indexed commit) into a list of
-// equivalent locations in the requested commit.
+// equivalent locations in the requested commit. Locations that fall within a changed hunk are dropped.
 func (r *queryResolver) adjustLocations(ctx context.Context, locations []codeintelapi.ResolvedLocation) ([]AdjustedLocation, error) {
 	adjustedLocations := make([]AdjustedLocation, 0, len(locations))
 	for i := range locations {
-		adjustedCommit, adjustedRange, err := r.adjustRange(ctx, locations[i].Dump.RepositoryID, locations[i].Dump.Commit, locations[i].Path, locations[i].Range)
+		adjustedCommit, adjustedRange, ok, err := r.adjustRange(ctx, locations[i].Dump.RepositoryID, locations[i].Dump.Commit, locations[i].Path, locations[i].Range)
 		if err != nil {
 			return nil, err
 		}
+		if !ok {
+			continue
+		}
 
 		adjustedLocations = append(adjustedLocations, AdjustedLocation{
 			Dump:           locations[i].Dump,
@@ -288,20 +299,21 @@ func (r *queryResolver) adjustLocations(ctx context.Context, locations []codeint
 	return adjustedLocations, nil
 }
 
-// adjustRange translates a range (relative to the indexed commit) into an equivalent range in the requested commit.
-func (r *queryResolver) adjustRange(ctx context.Context, repositoryID int, commit, path string, rx bundles.Range) (string, bundles.Range, error) {
+// adjustRange translates a range (relative to the indexed commit) into an equivalent range in the requested
+// commit. This method returns false if the range intersects a hunk that changed between the indexed commit
+// and the requested commit, in which case we have no confidence that the range is still accurate.
+func (r *queryResolver) adjustRange(ctx context.Context, repositoryID int, commit, path string, rx bundles.Range) (string, bundles.Range, bool, error) {
 	if repositoryID != r.repositoryID {
 		// No diffs exist for translation between repos
-		return commit, rx, nil
+		return commit, rx, true, nil
 	}
 
-	if _, adjustedRange, ok, err := r.positionAdjuster.AdjustRange(ctx, commit, path, rx, true); err != nil {
-		return "", bundles.Range{}, err
-	} else if ok {
-		return r.commit, adjustedRange, nil
+	_, adjustedRange, ok, err := r.positionAdjuster.AdjustRange(ctx, commit, path, rx, true)
+	if err != nil || !ok {
+		return "", bundles.Range{}, false, err
 	}
 
-	return commit, rx, nil
+	return r.commit, adjustedRange, true, nil
 }
 
 // readCursor decodes a cursor into a map from upload ids to URLs that serves the next page of results.
@@ -117,6 +117,95 @@ func TestDefinitions(t *testing.T) {
 	}
 }
 
+func TestDefinitionsDropsEditedRanges(t *testing.T) {
+	mockStore := storemocks.NewMockStore()
+	mockBundleManagerClient := bundlemocks.NewMockBundleManagerClient()
+	mockCodeIntelAPI := apimocks.NewMockCodeIntelAPI()
+	mockPositionAdjuster := NewMockPositionAdjuster()
+
+	mockPositionAdjuster.AdjustPositionFunc.SetDefaultReturn("", bundles.Position{Line: 20, Character: 15}, true, nil)
+
+	mockCodeIntelAPI.DefinitionsFunc.SetDefaultReturn([]codeintelapi.ResolvedLocation{
+		{
+			Dump: store.Dump{ID: 42, RepositoryID: 50, Commit: "deadbeef1"},
+			Path: "p1.go",
+			Range: bundles.Range{
+				Start: bundles.Position{Line: 11, Character: 12},
+				End:   bundles.Position{Line: 13, Character: 14},
+			},
+		},
+		{
+			Dump: store.Dump{ID: 42, RepositoryID: 50, Commit: "deadbeef1"},
+			Path: "p2.go",
+			Range: bundles.Range{
+				Start: bundles.Position{Line: 21, Character: 22},
+				End:   bundles.Position{Line: 23, Character: 24},
+			},
+		},
+		{
+			Dump: store.Dump{ID: 51, RepositoryID: 51, Commit: "deadbeef3"},
+			Path: "p3.go",
+			Range: bundles.Range{
+				Start: bundles.Position{Line: 31, Character: 32},
+				End:   bundles.Position{Line: 33, Character: 34},
+			},
+		},
+	}, nil)
+
+	// range in p1.go was edited between deadbeef1 and deadbeef2
+	mockPositionAdjuster.AdjustRangeFunc.SetDefaultHook(func(ctx context.Context, commit, path string, r bundles.Range, reverse bool) (string, bundles.Range, bool, error) {
+		if path == "p1.go" {
+			return "", bundles.Range{}, false, nil
+		}
+
+		return path, bundles.Range{
+			Start: bundles.Position{Line: r.Start.Line + 1, Character: r.Start.Character},
+			End:   bundles.Position{Line: r.End.Line + 1, Character: r.End.Character},
+		}, true, nil
+	})
+
+	queryResolver := NewQueryResolver(
+		mockStore,
+		mockBundleManagerClient,
+		mockCodeIntelAPI,
+		mockPositionAdjuster,
+		50,
+		"deadbeef2",
+		"/foo/bar.go",
+		[]store.Dump{{ID: 42, RepositoryID: 50, Commit: "deadbeef1"}},
+	)
+
+	definitions, err := queryResolver.Definitions(context.Background(), 10, 15)
+	if err != nil {
+		t.Fatalf("unexpected error resolving definitions: %s", err)
+	}
+
+	expectedDefinitions := []AdjustedLocation{
+		{
+			Dump:           store.Dump{ID: 42, RepositoryID: 50, Commit: "deadbeef1"},
+			Path:           "p2.go",
+			AdjustedCommit: "deadbeef2",
+			AdjustedRange: bundles.Range{
+				Start: bundles.Position{Line: 22, Character: 22},
+				End:   bundles.Position{Line: 24, Character: 24},
+			},
+		},
+		{
+			// locations in other repositories are not adjusted
+			Dump:           store.Dump{ID: 51, RepositoryID: 51, Commit: "deadbeef3"},
+			Path:           "p3.go",
+			AdjustedCommit: "deadbeef3",
+			AdjustedRange: bundles.Range{
+				Start: bundles.Position{Line: 31, Character: 32},
+				End:   bundles.Position{Line: 33, Character: 34},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedDefinitions, definitions); diff != "" {
+		t.Errorf("unexpected definitions (-want +got):\n%s", diff)
+	}
+}
+
 func TestReferences(t *testing.T) {
 	mockStore := storemocks.NewMockStore()
 	mockBundleManagerClient := bundlemocks.NewMockBundleManagerClient()
@@ -220,7 +309,7 @@ func TestReferences(t *testing.T) {
 		t.Fatalf("unexpected error creating cursor: %s", err)
 	}
 
-	references, nextCursor, err := queryResolver.References(context.Background(), 10, 15, 3, cursor)
+	references, nextCursor, err := queryResolver.References(context.Background(), 10, 15, 3, cursor, codeintelapi.VersionMatch{})
 	if err != nil {
 		t.Fatalf("unexpected error resolving references: %s", err)
 	}
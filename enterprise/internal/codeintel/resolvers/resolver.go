@@ -2,13 +2,16 @@ package resolvers
 
 import (
 	"context"
+	"time"
 
 	"github.com/pkg/errors"
 	gql "github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	codeintelapi "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/api"
 	bundles "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/bundles/client"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/gitserver"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/retention"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/store"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 )
 
 // Resolver is the main interface to code intel-related operations exposed to the GraphQL API.
@@ -24,6 +27,7 @@ type Resolver interface {
 	DeleteUploadByID(ctx context.Context, uploadID int) error
 	DeleteIndexByID(ctx context.Context, id int) error
 	QueryResolver(ctx context.Context, args *gql.GitBlobLSIFDataArgs) (QueryResolver, error)
+	UploadExpirations(ctx context.Context, repositoryID int) ([]retention.Expiration, error)
 }
 
 type resolver struct {
@@ -95,6 +99,27 @@ func (r *resolver) QueryResolver(ctx context.Context, args *gql.GitBlobLSIFDataA
 	), nil
 }
 
+// UploadExpirations returns the completed uploads of the given repository that would be removed
+// by the retention policy applicable to the repository. No data is removed by this method.
+func (r *resolver) UploadExpirations(ctx context.Context, repositoryID int) ([]retention.Expiration, error) {
+	policies, err := retention.NewPolicies(conf.Get().CodeIntelRetentionPolicies)
+	if err != nil {
+		return nil, err
+	}
+
+	repositoryName, err := r.store.RepoName(ctx, repositoryID)
+	if err != nil {
+		return nil, errors.Wrap(err, "store.RepoName")
+	}
+
+	policy, ok := retention.PolicyForRepository(policies, repositoryName)
+	if !ok {
+		return nil, nil
+	}
+
+	return retention.NewEvaluator(r.store, gitserver.DefaultClient).Expirations(ctx, repositoryID, policy, time.Now())
+}
+
 // getTipCommit returns the head of the default branch for the given repository. This
 // is used to recalculate the set of visible dumps for a repository on dump deletion.
 func (r *resolver) getTipCommit(ctx context.Context, repositoryID int) (string, error) {
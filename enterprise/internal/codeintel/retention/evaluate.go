@@ -0,0 +1,151 @@
+package retention
+
+import (
+	"sort"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/store"
+)
+
+// Topology describes the git topology of a repository against which a policy is evaluated.
+type Topology struct {
+	// DefaultBranch is the name of the repository's default branch.
+	DefaultBranch string
+
+	// Branches maps branch names to the commit at the tip of the branch.
+	Branches map[string]string
+
+	// Tags maps commits to the names of the tags pointing at that commit.
+	Tags map[string][]string
+
+	// Graph maps commits to their parent commits. This is the graph maintained by
+	// store.UpdateCommits and may only be a subset of the repository's history.
+	Graph map[string][]string
+}
+
+// Reason describes why an upload is removed by a retention policy.
+type Reason int
+
+const (
+	// ReasonNotRecent indicates that the upload is not among the most recent uploads
+	// of any branch containing its commit.
+	ReasonNotRecent Reason = iota
+
+	// ReasonExpired indicates that the commit of the upload is not on the default branch
+	// and that the upload is older than the maximum age of such uploads.
+	ReasonExpired
+)
+
+func (r Reason) String() string {
+	switch r {
+	case ReasonNotRecent:
+		return "not among the most recent uploads of any branch"
+	case ReasonExpired:
+		return "not on the default branch and older than the maximum age"
+	}
+
+	return ""
+}
+
+// Expiration is an upload removed by a retention policy.
+type Expiration struct {
+	Upload store.Upload
+	Reason Reason
+}
+
+// Evaluate returns the uploads that are removed by the given policy. Uploads visible at the
+// tip of the default branch are never removed. The given uploads are expected to be the
+// completed uploads of a single repository.
+func Evaluate(policy Policy, uploads []store.Upload, topology Topology, now time.Time) []Expiration {
+	commitsByBranch := make(map[string]map[string]struct{}, len(topology.Branches))
+	for branch, tip := range topology.Branches {
+		commitsByBranch[branch] = ancestors(topology.Graph, tip)
+	}
+
+	recent := mostRecentPerBranch(uploads, commitsByBranch, policy.KeepMostRecentPerBranch)
+	defaultBranchCommits := commitsByBranch[topology.DefaultBranch]
+
+	var expirations []Expiration
+	for _, upload := range uploads {
+		if upload.VisibleAtTip {
+			continue
+		}
+
+		if policy.KeepTaggedCommits && len(topology.Tags[upload.Commit]) > 0 {
+			continue
+		}
+
+		if _, ok := defaultBranchCommits[upload.Commit]; !ok && policy.NonDefaultBranchMaxAge > 0 {
+			if now.Sub(upload.UploadedAt) > policy.NonDefaultBranchMaxAge {
+				expirations = append(expirations, Expiration{Upload: upload, Reason: ReasonExpired})
+				continue
+			}
+		}
+
+		if _, ok := recent[upload.ID]; !ok && policy.KeepMostRecentPerBranch > 0 {
+			expirations = append(expirations, Expiration{Upload: upload, Reason: ReasonNotRecent})
+		}
+	}
+
+	return expirations
+}
+
+// mostRecentPerBranch returns the identifiers of the n most recent uploads of each branch.
+// Uploads for distinct roots and indexers are counted separately so that every project of
+// a repository keeps n uploads per branch.
+func mostRecentPerBranch(uploads []store.Upload, commitsByBranch map[string]map[string]struct{}, n int) map[int]struct{} {
+	if n <= 0 {
+		return nil
+	}
+
+	sorted := make([]store.Upload, len(uploads))
+	copy(sorted, uploads)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].UploadedAt.After(sorted[j].UploadedAt)
+	})
+
+	type projectKey struct {
+		root    string
+		indexer string
+	}
+
+	recent := map[int]struct{}{}
+	for _, commits := range commitsByBranch {
+		counts := map[projectKey]int{}
+
+		for _, upload := range sorted {
+			if _, ok := commits[upload.Commit]; !ok {
+				continue
+			}
+
+			key := projectKey{upload.Root, upload.Indexer}
+			if counts[key] < n {
+				counts[key]++
+				recent[upload.ID] = struct{}{}
+			}
+		}
+	}
+
+	return recent
+}
+
+// ancestors returns the set of commits reachable from the given commit in the given graph,
+// including the commit itself.
+func ancestors(graph map[string][]string, commit string) map[string]struct{} {
+	seen := map[string]struct{}{commit: {}}
+	frontier := []string{commit}
+
+	for len(frontier) > 0 {
+		current := frontier[len(frontier)-1]
+		frontier = frontier[:len(frontier)-1]
+
+		for _, parent := range graph[current] {
+			if _, ok := seen[parent]; !ok {
+				seen[parent] = struct{}{}
+				frontier = append(frontier, parent)
+			}
+		}
+	}
+
+	return seen
+}
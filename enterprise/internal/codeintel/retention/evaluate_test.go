@@ -0,0 +1,109 @@
+package retention
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/store"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestEvaluate(t *testing.T) {
+	now := time.Unix(1587396557, 0).UTC()
+	day := 24 * time.Hour
+
+	// a <- b <- c (master)
+	//       \
+	//        d <- e (feature)
+	topology := Topology{
+		DefaultBranch: "master",
+		Branches:      map[string]string{"master": "c", "feature": "e"},
+		Tags:          map[string][]string{"a": {"v1.0.0"}},
+		Graph: map[string][]string{
+			"a": nil,
+			"b": {"a"},
+			"c": {"b"},
+			"d": {"b"},
+			"e": {"d"},
+		},
+	}
+
+	uploads := []store.Upload{
+		{ID: 1, Commit: "a", UploadedAt: now.Add(-50 * day)},
+		{ID: 2, Commit: "b", UploadedAt: now.Add(-40 * day)},
+		{ID: 3, Commit: "c", UploadedAt: now.Add(-30 * day), VisibleAtTip: true},
+		{ID: 4, Commit: "d", UploadedAt: now.Add(-20 * day)},
+		{ID: 5, Commit: "e", UploadedAt: now.Add(-2 * day)},
+		{ID: 6, Commit: "b", UploadedAt: now.Add(-35 * day), Root: "sub/"},
+	}
+
+	testCases := []struct {
+		name     string
+		policy   Policy
+		expected []Expiration
+	}{
+		{
+			name:     "empty policy",
+			policy:   Policy{},
+			expected: nil,
+		},
+		{
+			name:   "most recent per branch",
+			policy: Policy{KeepMostRecentPerBranch: 1},
+			expected: []Expiration{
+				{Upload: uploads[0], Reason: ReasonNotRecent},
+				{Upload: uploads[1], Reason: ReasonNotRecent},
+				{Upload: uploads[3], Reason: ReasonNotRecent},
+			},
+		},
+		{
+			name:   "keep tagged commits",
+			policy: Policy{KeepMostRecentPerBranch: 1, KeepTaggedCommits: true},
+			expected: []Expiration{
+				{Upload: uploads[1], Reason: ReasonNotRecent},
+				{Upload: uploads[3], Reason: ReasonNotRecent},
+			},
+		},
+		{
+			name:   "non-default branch max age",
+			policy: Policy{NonDefaultBranchMaxAge: 7 * day},
+			expected: []Expiration{
+				{Upload: uploads[3], Reason: ReasonExpired},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			expirations := Evaluate(testCase.policy, uploads, topology, now)
+			if diff := cmp.Diff(testCase.expected, expirations); diff != "" {
+				t.Errorf("unexpected expirations (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPolicyForRepository(t *testing.T) {
+	policies, err := NewPolicies([]*schema.CodeIntelRetentionPolicy{
+		{RepositoryPattern: "^github.com/foo/", KeepMostRecentPerBranch: 5},
+		{KeepMostRecentPerBranch: 10, NonDefaultBranchMaxAgeDays: 30},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating policies: %s", err)
+	}
+
+	if policy, ok := PolicyForRepository(policies, "github.com/foo/bar"); !ok || policy.KeepMostRecentPerBranch != 5 {
+		t.Errorf("unexpected policy for github.com/foo/bar. want=%d have=%d", 5, policy.KeepMostRecentPerBranch)
+	}
+
+	if policy, ok := PolicyForRepository(policies, "github.com/baz/bonk"); !ok || policy.NonDefaultBranchMaxAge != 30*24*time.Hour {
+		t.Errorf("unexpected policy for github.com/baz/bonk. want=%s have=%s", 30*24*time.Hour, policy.NonDefaultBranchMaxAge)
+	}
+}
+
+func TestNewPoliciesInvalidPattern(t *testing.T) {
+	if _, err := NewPolicies([]*schema.CodeIntelRetentionPolicy{{RepositoryPattern: "("}}); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
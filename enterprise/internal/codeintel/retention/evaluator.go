@@ -0,0 +1,207 @@
+package retention
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/gitserver"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/store"
+)
+
+// UploadsPageSize is the number of uploads requested from the store at once.
+const UploadsPageSize = 1000
+
+// Evaluator determines the uploads removed by a retention policy using the refs of a
+// repository and the commit graph maintained by store.UpdateCommits.
+type Evaluator struct {
+	store           store.Store
+	gitserverClient gitserver.Client
+}
+
+// NewEvaluator creates a new evaluator with the given store and gitserver client.
+func NewEvaluator(store store.Store, gitserverClient gitserver.Client) *Evaluator {
+	return &Evaluator{
+		store:           store,
+		gitserverClient: gitserverClient,
+	}
+}
+
+// Expirations returns the completed uploads of the given repository that are removed by the
+// given policy. This method does not write to the store, so it can be used to preview a policy.
+func (e *Evaluator) Expirations(ctx context.Context, repositoryID int, policy Policy, now time.Time) ([]Expiration, error) {
+	return e.expirations(ctx, repositoryID, policy, now, false)
+}
+
+// ExpirationsForRemoval is like Expirations, but also stores the commits near branch tips that
+// were missing from the commit graph, so that later evaluations need fewer gitserver requests.
+// It is used right before the returned uploads are removed.
+func (e *Evaluator) ExpirationsForRemoval(ctx context.Context, repositoryID int, policy Policy, now time.Time) ([]Expiration, error) {
+	return e.expirations(ctx, repositoryID, policy, now, true)
+}
+
+func (e *Evaluator) expirations(ctx context.Context, repositoryID int, policy Policy, now time.Time, updateCommits bool) ([]Expiration, error) {
+	uploads, err := CompletedUploads(ctx, e.store, repositoryID)
+	if err != nil || len(uploads) == 0 {
+		return nil, err
+	}
+
+	topology, err := e.topology(ctx, repositoryID, updateCommits)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.confirm(ctx, repositoryID, policy, uploads, topology, Evaluate(policy, uploads, topology, now))
+}
+
+// topology returns the refs and commit graph of the given repository. Branch tips that are not
+// yet part of the commit graph are added to it, along with their nearest ancestors. These
+// commits are only written to the store if updateCommits is set.
+func (e *Evaluator) topology(ctx context.Context, repositoryID int, updateCommits bool) (Topology, error) {
+	refs, err := e.gitserverClient.GetRefs(ctx, e.store, repositoryID)
+	if err != nil {
+		return Topology{}, errors.Wrap(err, "gitserver.GetRefs")
+	}
+
+	graph, err := e.store.GetCommitGraph(ctx, repositoryID)
+	if err != nil {
+		return Topology{}, errors.Wrap(err, "store.GetCommitGraph")
+	}
+
+	for _, tip := range refs.Branches {
+		if _, ok := graph[tip]; ok {
+			continue
+		}
+
+		commits, err := e.gitserverClient.CommitsNear(ctx, e.store, repositoryID, tip)
+		if err != nil {
+			return Topology{}, errors.Wrap(err, "gitserver.CommitsNear")
+		}
+
+		if updateCommits {
+			if err := e.store.UpdateCommits(ctx, repositoryID, commits); err != nil {
+				return Topology{}, errors.Wrap(err, "store.UpdateCommits")
+			}
+		}
+
+		for commit, parents := range commits {
+			graph[commit] = append(graph[commit], parents...)
+		}
+	}
+
+	return Topology{
+		DefaultBranch: refs.DefaultBranch,
+		Branches:      refs.Branches,
+		Tags:          refs.Tags,
+		Graph:         graph,
+	}, nil
+}
+
+// confirm removes the expirations that gitserver contradicts. The commit graph only contains
+// the nearest ancestors of each upload and branch tip, so a commit deep in the history of a
+// branch may not be reachable from its tip within the graph. Such an upload is believed to be
+// off the branch, and may wrongly be expired as off the default branch, or as not among the
+// most recent uploads of the branch.
+func (e *Evaluator) confirm(ctx context.Context, repositoryID int, policy Policy, uploads []store.Upload, topology Topology, expirations []Expiration) ([]Expiration, error) {
+	commitsByBranch := make(map[string]map[string]struct{}, len(topology.Branches))
+	for branch, tip := range topology.Branches {
+		commitsByBranch[branch] = ancestors(topology.Graph, tip)
+	}
+
+	filtered := expirations[:0]
+	for _, expiration := range expirations {
+		var keep bool
+		var err error
+
+		switch expiration.Reason {
+		case ReasonExpired:
+			keep, err = e.onDefaultBranch(ctx, repositoryID, topology, expiration.Upload)
+		case ReasonNotRecent:
+			keep, err = e.possiblyRecent(ctx, repositoryID, policy, uploads, topology, commitsByBranch, expiration.Upload)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if !keep {
+			filtered = append(filtered, expiration)
+		}
+	}
+
+	return filtered, nil
+}
+
+// onDefaultBranch returns true if the commit of the given upload is an ancestor of the tip of
+// the default branch according to gitserver.
+func (e *Evaluator) onDefaultBranch(ctx context.Context, repositoryID int, topology Topology, upload store.Upload) (bool, error) {
+	tip, ok := topology.Branches[topology.DefaultBranch]
+	if !ok {
+		return false, nil
+	}
+
+	onDefaultBranch, err := e.gitserverClient.IsAncestor(ctx, e.store, repositoryID, upload.Commit, tip)
+	if err != nil {
+		return false, errors.Wrap(err, "gitserver.IsAncestor")
+	}
+
+	return onDefaultBranch, nil
+}
+
+// possiblyRecent returns true if the given upload may be among the most recent uploads of a
+// branch that it is not known to be on within the commit graph. The uploads known to be on a
+// branch are a subset of the uploads actually on it, so an upload with at least n newer known
+// uploads of its project on a branch is certainly not among the n most recent uploads of that
+// branch. Otherwise, gitserver decides whether the upload is on the branch, and the upload is
+// kept if it is.
+func (e *Evaluator) possiblyRecent(ctx context.Context, repositoryID int, policy Policy, uploads []store.Upload, topology Topology, commitsByBranch map[string]map[string]struct{}, upload store.Upload) (bool, error) {
+	for branch, commits := range commitsByBranch {
+		if _, ok := commits[upload.Commit]; ok {
+			// The evaluation within the graph is exact for uploads known to be on the branch
+			continue
+		}
+
+		newer := 0
+		for _, other := range uploads {
+			if _, ok := commits[other.Commit]; ok && other.Root == upload.Root && other.Indexer == upload.Indexer && other.UploadedAt.After(upload.UploadedAt) {
+				newer++
+			}
+		}
+		if newer >= policy.KeepMostRecentPerBranch {
+			continue
+		}
+
+		onBranch, err := e.gitserverClient.IsAncestor(ctx, e.store, repositoryID, upload.Commit, topology.Branches[branch])
+		if err != nil {
+			return false, errors.Wrap(err, "gitserver.IsAncestor")
+		}
+		if onBranch {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// CompletedUploads returns all completed uploads of the given repository. If the repository
+// identifier is zero, the completed uploads of all repositories are returned.
+func CompletedUploads(ctx context.Context, s store.Store, repositoryID int) ([]store.Upload, error) {
+	var allUploads []store.Upload
+	for {
+		uploads, totalCount, err := s.GetUploads(ctx, store.GetUploadsOptions{
+			RepositoryID: repositoryID,
+			State:        "completed",
+			Limit:        UploadsPageSize,
+			Offset:       len(allUploads),
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "store.GetUploads")
+		}
+
+		allUploads = append(allUploads, uploads...)
+		if len(uploads) == 0 || len(allUploads) >= totalCount {
+			break
+		}
+	}
+
+	return allUploads, nil
+}
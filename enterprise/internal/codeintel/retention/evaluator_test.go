@@ -0,0 +1,112 @@
+package retention
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/gitserver"
+	gitservermocks "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/gitserver/mocks"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/store"
+	storemocks "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/store/mocks"
+)
+
+func TestEvaluatorExpirations(t *testing.T) {
+	now := time.Unix(1587396557, 0).UTC()
+	day := 24 * time.Hour
+
+	uploads := []store.Upload{
+		{ID: 1, Commit: "a", UploadedAt: now.Add(-50 * day)},
+		{ID: 2, Commit: "d", UploadedAt: now.Add(-40 * day)},
+		{ID: 3, Commit: "c", UploadedAt: now.Add(-1 * day), VisibleAtTip: true},
+	}
+
+	mockStore := storemocks.NewMockStore()
+	mockStore.GetUploadsFunc.SetDefaultReturn(uploads, len(uploads), nil)
+	// The tip of the feature branch is not yet part of the commit graph
+	mockStore.GetCommitGraphFunc.SetDefaultHook(func(ctx context.Context, repositoryID int) (map[string][]string, error) {
+		return map[string][]string{"a": nil, "c": {"b"}}, nil
+	})
+
+	mockGitserverClient := gitservermocks.NewMockClient()
+	mockGitserverClient.GetRefsFunc.SetDefaultReturn(gitserver.Refs{
+		DefaultBranch: "master",
+		Branches:      map[string]string{"master": "c", "feature": "e"},
+	}, nil)
+	mockGitserverClient.CommitsNearFunc.SetDefaultReturn(map[string][]string{"e": {"d"}, "d": {"b"}}, nil)
+	// Commit a is on the default branch but not reachable within the commit graph
+	mockGitserverClient.IsAncestorFunc.SetDefaultHook(func(ctx context.Context, store store.Store, repositoryID int, commit, descendant string) (bool, error) {
+		return commit == "a", nil
+	})
+
+	expirations, err := NewEvaluator(mockStore, mockGitserverClient).Expirations(context.Background(), 42, Policy{NonDefaultBranchMaxAge: 30 * day}, now)
+	if err != nil {
+		t.Fatalf("unexpected error getting expirations: %s", err)
+	}
+
+	expected := []Expiration{{Upload: uploads[1], Reason: ReasonExpired}}
+	if diff := cmp.Diff(expected, expirations); diff != "" {
+		t.Errorf("unexpected expirations (-want +got):\n%s", diff)
+	}
+
+	// Previews must not write to the store
+	if calls := mockStore.UpdateCommitsFunc.History(); len(calls) != 0 {
+		t.Errorf("unexpected number of UpdateCommits calls. want=%d have=%d", 0, len(calls))
+	}
+
+	expirations, err = NewEvaluator(mockStore, mockGitserverClient).ExpirationsForRemoval(context.Background(), 42, Policy{NonDefaultBranchMaxAge: 30 * day}, now)
+	if err != nil {
+		t.Fatalf("unexpected error getting expirations: %s", err)
+	}
+
+	if diff := cmp.Diff(expected, expirations); diff != "" {
+		t.Errorf("unexpected expirations (-want +got):\n%s", diff)
+	}
+
+	if calls := mockStore.UpdateCommitsFunc.History(); len(calls) != 1 {
+		t.Errorf("unexpected number of UpdateCommits calls. want=%d have=%d", 1, len(calls))
+	}
+}
+
+func TestEvaluatorExpirationsConfirmsNotRecent(t *testing.T) {
+	now := time.Unix(1587396557, 0).UTC()
+	day := 24 * time.Hour
+
+	uploads := []store.Upload{
+		{ID: 1, Commit: "a", UploadedAt: now.Add(-3 * day)},
+		{ID: 2, Commit: "b", UploadedAt: now.Add(-2 * day)},
+		{ID: 3, Commit: "x", UploadedAt: now.Add(-1 * day)},
+		{ID: 4, Commit: "c", UploadedAt: now.Add(-1 * day), VisibleAtTip: true},
+	}
+
+	mockStore := storemocks.NewMockStore()
+	mockStore.GetUploadsFunc.SetDefaultReturn(uploads, len(uploads), nil)
+	// Commits a and x are on the default branch, but x is not reachable within the commit graph
+	mockStore.GetCommitGraphFunc.SetDefaultReturn(map[string][]string{"a": nil, "b": {"a"}, "c": {"b"}, "x": nil}, nil)
+
+	mockGitserverClient := gitservermocks.NewMockClient()
+	mockGitserverClient.GetRefsFunc.SetDefaultReturn(gitserver.Refs{
+		DefaultBranch: "master",
+		Branches:      map[string]string{"master": "c"},
+	}, nil)
+	mockGitserverClient.IsAncestorFunc.SetDefaultHook(func(ctx context.Context, store store.Store, repositoryID int, commit, descendant string) (bool, error) {
+		return commit == "x", nil
+	})
+
+	expirations, err := NewEvaluator(mockStore, mockGitserverClient).Expirations(context.Background(), 42, Policy{KeepMostRecentPerBranch: 2}, now)
+	if err != nil {
+		t.Fatalf("unexpected error getting expirations: %s", err)
+	}
+
+	// Upload 3 is among the two most recent uploads of the default branch according to gitserver.
+	// Upload 1 is known to have two newer uploads on the branch, so gitserver is not asked.
+	expected := []Expiration{{Upload: uploads[0], Reason: ReasonNotRecent}}
+	if diff := cmp.Diff(expected, expirations); diff != "" {
+		t.Errorf("unexpected expirations (-want +got):\n%s", diff)
+	}
+
+	if calls := mockGitserverClient.IsAncestorFunc.History(); len(calls) != 1 || calls[0].Arg3 != "x" {
+		t.Errorf("unexpected IsAncestor calls: %v", calls)
+	}
+}
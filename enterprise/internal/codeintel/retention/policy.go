@@ -0,0 +1,62 @@
+package retention
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// Policy determines which completed uploads of a repository are removed.
+type Policy struct {
+	// RepositoryPattern matches the names of the repositories to which this policy
+	// applies. A nil pattern matches all repositories.
+	RepositoryPattern *regexp.Regexp
+
+	// KeepMostRecentPerBranch is the number of most recent uploads kept for each
+	// branch. A zero value keeps all uploads regardless of their recency.
+	KeepMostRecentPerBranch int
+
+	// KeepTaggedCommits retains uploads of tagged commits forever.
+	KeepTaggedCommits bool
+
+	// NonDefaultBranchMaxAge is the age after which uploads of commits that are not
+	// on the default branch are removed. A zero value disables expiration.
+	NonDefaultBranchMaxAge time.Duration
+}
+
+// NewPolicies converts the retention policies from the site configuration.
+func NewPolicies(configs []*schema.CodeIntelRetentionPolicy) ([]Policy, error) {
+	policies := make([]Policy, 0, len(configs))
+	for _, config := range configs {
+		var pattern *regexp.Regexp
+		if config.RepositoryPattern != "" {
+			var err error
+			if pattern, err = regexp.Compile(config.RepositoryPattern); err != nil {
+				return nil, errors.Wrapf(err, "invalid repository pattern %q", config.RepositoryPattern)
+			}
+		}
+
+		policies = append(policies, Policy{
+			RepositoryPattern:       pattern,
+			KeepMostRecentPerBranch: config.KeepMostRecentPerBranch,
+			KeepTaggedCommits:       config.KeepTaggedCommits,
+			NonDefaultBranchMaxAge:  time.Duration(config.NonDefaultBranchMaxAgeDays) * 24 * time.Hour,
+		})
+	}
+
+	return policies, nil
+}
+
+// PolicyForRepository returns the first policy that applies to the repository with the
+// given name and a flag indicating whether any policy applies.
+func PolicyForRepository(policies []Policy, repositoryName string) (Policy, bool) {
+	for _, policy := range policies {
+		if policy.RepositoryPattern == nil || policy.RepositoryPattern.MatchString(repositoryName) {
+			return policy, true
+		}
+	}
+
+	return Policy{}, false
+}
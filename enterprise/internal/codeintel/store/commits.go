@@ -47,6 +47,16 @@ func (s *store) HasCommit(ctx context.Context, repositoryID int, commit string)
 	return count > 0, err
 }
 
+// GetCommitGraph returns a map from commits to parent commits of all commits known for the
+// given repository.
+func (s *store) GetCommitGraph(ctx context.Context, repositoryID int) (map[string][]string, error) {
+	return scanCommits(s.query(ctx, sqlf.Sprintf(`
+		SELECT "commit", parent_commit
+		FROM lsif_commits
+		WHERE repository_id = %s
+	`, repositoryID)))
+}
+
 // UpdateCommits upserts commits/parent-commit relations for the given repository ID.
 func (s *store) UpdateCommits(ctx context.Context, repositoryID int, commits map[string][]string) error {
 	if len(commits) == 0 {
@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"fmt"
+	"sort"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -48,6 +49,47 @@ func TestHasCommit(t *testing.T) {
 	}
 }
 
+func TestGetCommitGraph(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	store := testStore()
+
+	if err := store.UpdateCommits(context.Background(), 50, map[string][]string{
+		makeCommit(1): {},
+		makeCommit(2): {makeCommit(1)},
+		makeCommit(3): {makeCommit(1)},
+		makeCommit(4): {makeCommit(2), makeCommit(3)},
+	}); err != nil {
+		t.Fatalf("unexpected error updating commits: %s", err)
+	}
+
+	if err := store.UpdateCommits(context.Background(), 51, map[string][]string{
+		makeCommit(5): {makeCommit(4)},
+	}); err != nil {
+		t.Fatalf("unexpected error updating commits: %s", err)
+	}
+
+	graph, err := store.GetCommitGraph(context.Background(), 50)
+	if err != nil {
+		t.Fatalf("unexpected error getting commit graph: %s", err)
+	}
+	for _, parents := range graph {
+		sort.Strings(parents)
+	}
+
+	expectedGraph := map[string][]string{
+		makeCommit(1): nil,
+		makeCommit(2): {makeCommit(1)},
+		makeCommit(3): {makeCommit(1)},
+		makeCommit(4): {makeCommit(2), makeCommit(3)},
+	}
+	if diff := cmp.Diff(expectedGraph, graph); diff != "" {
+		t.Errorf("unexpected commit graph (-want +got):\n%s", diff)
+	}
+}
+
 func TestUpdateCommits(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
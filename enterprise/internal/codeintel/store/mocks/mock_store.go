@@ -42,6 +42,9 @@ type MockStore struct {
 	// FindClosestDumpsFunc is an instance of a mock function object
 	// controlling the behavior of the method FindClosestDumps.
 	FindClosestDumpsFunc *StoreFindClosestDumpsFunc
+	// GetCommitGraphFunc is an instance of a mock function object
+	// controlling the behavior of the method GetCommitGraph.
+	GetCommitGraphFunc *StoreGetCommitGraphFunc
 	// GetDumpByIDFunc is an instance of a mock function object controlling
 	// the behavior of the method GetDumpByID.
 	GetDumpByIDFunc *StoreGetDumpByIDFunc
@@ -57,6 +60,9 @@ type MockStore struct {
 	// GetPackageFunc is an instance of a mock function object controlling
 	// the behavior of the method GetPackage.
 	GetPackageFunc *StoreGetPackageFunc
+	// GetPackageVersionsFunc is an instance of a mock function object
+	// controlling the behavior of the method GetPackageVersions.
+	GetPackageVersionsFunc *StoreGetPackageVersionsFunc
 	// GetStatesFunc is an instance of a mock function object controlling
 	// the behavior of the method GetStates.
 	GetStatesFunc *StoreGetStatesFunc
@@ -203,6 +209,11 @@ func NewMockStore() *MockStore {
 				return nil, nil
 			},
 		},
+		GetCommitGraphFunc: &StoreGetCommitGraphFunc{
+			defaultHook: func(context.Context, int) (map[string][]string, error) {
+				return nil, nil
+			},
+		},
 		GetDumpByIDFunc: &StoreGetDumpByIDFunc{
 			defaultHook: func(context.Context, int) (store.Dump, bool, error) {
 				return store.Dump{}, false, nil
@@ -228,6 +239,11 @@ func NewMockStore() *MockStore {
 				return store.Dump{}, false, nil
 			},
 		},
+		GetPackageVersionsFunc: &StoreGetPackageVersionsFunc{
+			defaultHook: func(context.Context, string, string) ([]store.PackageVersion, error) {
+				return nil, nil
+			},
+		},
 		GetStatesFunc: &StoreGetStatesFunc{
 			defaultHook: func(context.Context, []int) (map[int]string, error) {
 				return nil, nil
@@ -299,7 +315,7 @@ func NewMockStore() *MockStore {
 			},
 		},
 		PackageReferencePagerFunc: &StorePackageReferencePagerFunc{
-			defaultHook: func(context.Context, string, string, string, int, int) (int, store.ReferencePager, error) {
+			defaultHook: func(context.Context, string, string, []string, int, int) (int, store.ReferencePager, error) {
 				return 0, nil, nil
 			},
 		},
@@ -417,6 +433,9 @@ func NewMockStoreFrom(i store.Store) *MockStore {
 		FindClosestDumpsFunc: &StoreFindClosestDumpsFunc{
 			defaultHook: i.FindClosestDumps,
 		},
+		GetCommitGraphFunc: &StoreGetCommitGraphFunc{
+			defaultHook: i.GetCommitGraph,
+		},
 		GetDumpByIDFunc: &StoreGetDumpByIDFunc{
 			defaultHook: i.GetDumpByID,
 		},
@@ -432,6 +451,9 @@ func NewMockStoreFrom(i store.Store) *MockStore {
 		GetPackageFunc: &StoreGetPackageFunc{
 			defaultHook: i.GetPackage,
 		},
+		GetPackageVersionsFunc: &StoreGetPackageVersionsFunc{
+			defaultHook: i.GetPackageVersions,
+		},
 		GetStatesFunc: &StoreGetStatesFunc{
 			defaultHook: i.GetStates,
 		},
@@ -1524,6 +1546,115 @@ func (c StoreFindClosestDumpsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// StoreGetCommitGraphFunc describes the behavior when the GetCommitGraph
+// method of the parent MockStore instance is invoked.
+type StoreGetCommitGraphFunc struct {
+	defaultHook func(context.Context, int) (map[string][]string, error)
+	hooks       []func(context.Context, int) (map[string][]string, error)
+	history     []StoreGetCommitGraphFuncCall
+	mutex       sync.Mutex
+}
+
+// GetCommitGraph delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockStore) GetCommitGraph(v0 context.Context, v1 int) (map[string][]string, error) {
+	r0, r1 := m.GetCommitGraphFunc.nextHook()(v0, v1)
+	m.GetCommitGraphFunc.appendCall(StoreGetCommitGraphFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetCommitGraph
+// method of the parent MockStore instance is invoked and the hook queue is
+// empty.
+func (f *StoreGetCommitGraphFunc) SetDefaultHook(hook func(context.Context, int) (map[string][]string, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetCommitGraph method of the parent MockStore instance inovkes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *StoreGetCommitGraphFunc) PushHook(hook func(context.Context, int) (map[string][]string, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *StoreGetCommitGraphFunc) SetDefaultReturn(r0 map[string][]string, r1 error) {
+	f.SetDefaultHook(func(context.Context, int) (map[string][]string, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *StoreGetCommitGraphFunc) PushReturn(r0 map[string][]string, r1 error) {
+	f.PushHook(func(context.Context, int) (map[string][]string, error) {
+		return r0, r1
+	})
+}
+
+func (f *StoreGetCommitGraphFunc) nextHook() func(context.Context, int) (map[string][]string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreGetCommitGraphFunc) appendCall(r0 StoreGetCommitGraphFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreGetCommitGraphFuncCall objects
+// describing the invocations of this function.
+func (f *StoreGetCommitGraphFunc) History() []StoreGetCommitGraphFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreGetCommitGraphFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreGetCommitGraphFuncCall is an object that describes an invocation of
+// method GetCommitGraph on an instance of MockStore.
+type StoreGetCommitGraphFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 map[string][]string
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreGetCommitGraphFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreGetCommitGraphFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // StoreGetDumpByIDFunc describes the behavior when the GetDumpByID method
 // of the parent MockStore instance is invoked.
 type StoreGetDumpByIDFunc struct {
@@ -2079,6 +2210,118 @@ func (c StoreGetPackageFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
+// StoreGetPackageVersionsFunc describes the behavior when the
+// GetPackageVersions method of the parent MockStore instance is invoked.
+type StoreGetPackageVersionsFunc struct {
+	defaultHook func(context.Context, string, string) ([]store.PackageVersion, error)
+	hooks       []func(context.Context, string, string) ([]store.PackageVersion, error)
+	history     []StoreGetPackageVersionsFuncCall
+	mutex       sync.Mutex
+}
+
+// GetPackageVersions delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockStore) GetPackageVersions(v0 context.Context, v1 string, v2 string) ([]store.PackageVersion, error) {
+	r0, r1 := m.GetPackageVersionsFunc.nextHook()(v0, v1, v2)
+	m.GetPackageVersionsFunc.appendCall(StoreGetPackageVersionsFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetPackageVersions
+// method of the parent MockStore instance is invoked and the hook queue is
+// empty.
+func (f *StoreGetPackageVersionsFunc) SetDefaultHook(hook func(context.Context, string, string) ([]store.PackageVersion, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetPackageVersions method of the parent MockStore instance inovkes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *StoreGetPackageVersionsFunc) PushHook(hook func(context.Context, string, string) ([]store.PackageVersion, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *StoreGetPackageVersionsFunc) SetDefaultReturn(r0 []store.PackageVersion, r1 error) {
+	f.SetDefaultHook(func(context.Context, string, string) ([]store.PackageVersion, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *StoreGetPackageVersionsFunc) PushReturn(r0 []store.PackageVersion, r1 error) {
+	f.PushHook(func(context.Context, string, string) ([]store.PackageVersion, error) {
+		return r0, r1
+	})
+}
+
+func (f *StoreGetPackageVersionsFunc) nextHook() func(context.Context, string, string) ([]store.PackageVersion, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreGetPackageVersionsFunc) appendCall(r0 StoreGetPackageVersionsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreGetPackageVersionsFuncCall objects
+// describing the invocations of this function.
+func (f *StoreGetPackageVersionsFunc) History() []StoreGetPackageVersionsFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreGetPackageVersionsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreGetPackageVersionsFuncCall is an object that describes an invocation
+// of method GetPackageVersions on an instance of MockStore.
+type StoreGetPackageVersionsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 string
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []store.PackageVersion
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreGetPackageVersionsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreGetPackageVersionsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // StoreGetStatesFunc describes the behavior when the GetStates method of
 // the parent MockStore instance is invoked.
 type StoreGetStatesFunc struct {
@@ -3598,15 +3841,15 @@ func (c StoreMarkQueuedFuncCall) Results() []interface{} {
 // StorePackageReferencePagerFunc describes the behavior when the
 // PackageReferencePager method of the parent MockStore instance is invoked.
 type StorePackageReferencePagerFunc struct {
-	defaultHook func(context.Context, string, string, string, int, int) (int, store.ReferencePager, error)
-	hooks       []func(context.Context, string, string, string, int, int) (int, store.ReferencePager, error)
+	defaultHook func(context.Context, string, string, []string, int, int) (int, store.ReferencePager, error)
+	hooks       []func(context.Context, string, string, []string, int, int) (int, store.ReferencePager, error)
 	history     []StorePackageReferencePagerFuncCall
 	mutex       sync.Mutex
 }
 
 // PackageReferencePager delegates to the next hook function in the queue
 // and stores the parameter and result values of this invocation.
-func (m *MockStore) PackageReferencePager(v0 context.Context, v1 string, v2 string, v3 string, v4 int, v5 int) (int, store.ReferencePager, error) {
+func (m *MockStore) PackageReferencePager(v0 context.Context, v1 string, v2 string, v3 []string, v4 int, v5 int) (int, store.ReferencePager, error) {
 	r0, r1, r2 := m.PackageReferencePagerFunc.nextHook()(v0, v1, v2, v3, v4, v5)
 	m.PackageReferencePagerFunc.appendCall(StorePackageReferencePagerFuncCall{v0, v1, v2, v3, v4, v5, r0, r1, r2})
 	return r0, r1, r2
@@ -3615,7 +3858,7 @@ func (m *MockStore) PackageReferencePager(v0 context.Context, v1 string, v2 stri
 // SetDefaultHook sets function that is called when the
 // PackageReferencePager method of the parent MockStore instance is invoked
 // and the hook queue is empty.
-func (f *StorePackageReferencePagerFunc) SetDefaultHook(hook func(context.Context, string, string, string, int, int) (int, store.ReferencePager, error)) {
+func (f *StorePackageReferencePagerFunc) SetDefaultHook(hook func(context.Context, string, string, []string, int, int) (int, store.ReferencePager, error)) {
 	f.defaultHook = hook
 }
 
@@ -3623,7 +3866,7 @@ func (f *StorePackageReferencePagerFunc) SetDefaultHook(hook func(context.Contex
 // PackageReferencePager method of the parent MockStore instance inovkes the
 // hook at the front of the queue and discards it. After the queue is empty,
 // the default hook function is invoked for any future action.
-func (f *StorePackageReferencePagerFunc) PushHook(hook func(context.Context, string, string, string, int, int) (int, store.ReferencePager, error)) {
+func (f *StorePackageReferencePagerFunc) PushHook(hook func(context.Context, string, string, []string, int, int) (int, store.ReferencePager, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -3632,7 +3875,7 @@ func (f *StorePackageReferencePagerFunc) PushHook(hook func(context.Context, str
 // SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
 // the given values.
 func (f *StorePackageReferencePagerFunc) SetDefaultReturn(r0 int, r1 store.ReferencePager, r2 error) {
-	f.SetDefaultHook(func(context.Context, string, string, string, int, int) (int, store.ReferencePager, error) {
+	f.SetDefaultHook(func(context.Context, string, string, []string, int, int) (int, store.ReferencePager, error) {
 		return r0, r1, r2
 	})
 }
@@ -3640,12 +3883,12 @@ func (f *StorePackageReferencePagerFunc) SetDefaultReturn(r0 int, r1 store.Refer
 // PushReturn calls PushDefaultHook with a function that returns the given
 // values.
 func (f *StorePackageReferencePagerFunc) PushReturn(r0 int, r1 store.ReferencePager, r2 error) {
-	f.PushHook(func(context.Context, string, string, string, int, int) (int, store.ReferencePager, error) {
+	f.PushHook(func(context.Context, string, string, []string, int, int) (int, store.ReferencePager, error) {
 		return r0, r1, r2
 	})
 }
 
-func (f *StorePackageReferencePagerFunc) nextHook() func(context.Context, string, string, string, int, int) (int, store.ReferencePager, error) {
+func (f *StorePackageReferencePagerFunc) nextHook() func(context.Context, string, string, []string, int, int) (int, store.ReferencePager, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -3689,7 +3932,7 @@ type StorePackageReferencePagerFuncCall struct {
 	Arg2 string
 	// Arg3 is the value of the 4th argument passed to this method
 	// invocation.
-	Arg3 string
+	Arg3 []string
 	// Arg4 is the value of the 5th argument passed to this method
 	// invocation.
 	Arg4 int
@@ -39,7 +39,9 @@ type ObservedStore struct {
 	sameRepoPagerOperation             *observation.Operation
 	updatePackageReferencesOperation   *observation.Operation
 	packageReferencePagerOperation     *observation.Operation
+	getPackageVersionsOperation        *observation.Operation
 	hasCommitOperation                 *observation.Operation
+	getCommitGraphOperation            *observation.Operation
 	updateCommitsOperation             *observation.Operation
 	indexableRepositoriesOperation     *observation.Operation
 	updateIndexableRepositoryOperation *observation.Operation
@@ -206,11 +208,21 @@ func NewObserved(store Store, observationContext *observation.Context) Store {
 			MetricLabels: []string{"package_reference_pager"},
 			Metrics:      metrics,
 		}),
+		getPackageVersionsOperation: observationContext.Operation(observation.Op{
+			Name:         "store.GetPackageVersions",
+			MetricLabels: []string{"get_package_versions"},
+			Metrics:      metrics,
+		}),
 		hasCommitOperation: observationContext.Operation(observation.Op{
 			Name:         "store.HasCommit",
 			MetricLabels: []string{"has_commit"},
 			Metrics:      metrics,
 		}),
+		getCommitGraphOperation: observationContext.Operation(observation.Op{
+			Name:         "store.GetCommitGraph",
+			MetricLabels: []string{"get_commit_graph"},
+			Metrics:      metrics,
+		}),
 		updateCommitsOperation: observationContext.Operation(observation.Op{
 			Name:         "store.UpdateCommits",
 			MetricLabels: []string{"update_commits"},
@@ -329,7 +341,9 @@ func (s *ObservedStore) wrap(other Store) Store {
 		sameRepoPagerOperation:             s.sameRepoPagerOperation,
 		updatePackageReferencesOperation:   s.updatePackageReferencesOperation,
 		packageReferencePagerOperation:     s.packageReferencePagerOperation,
+		getPackageVersionsOperation:        s.getPackageVersionsOperation,
 		hasCommitOperation:                 s.hasCommitOperation,
+		getCommitGraphOperation:            s.getCommitGraphOperation,
 		updateCommitsOperation:             s.updateCommitsOperation,
 		indexableRepositoriesOperation:     s.indexableRepositoriesOperation,
 		updateIndexableRepositoryOperation: s.updateIndexableRepositoryOperation,
@@ -551,10 +565,17 @@ func (s *ObservedStore) UpdatePackageReferences(ctx context.Context, packageRefe
 }
 
 // PackageReferencePager calls into the inner store and registers the observed results.
-func (s *ObservedStore) PackageReferencePager(ctx context.Context, scheme, name, version string, repositoryID, limit int) (_ int, _ ReferencePager, err error) {
+func (s *ObservedStore) PackageReferencePager(ctx context.Context, scheme, name string, versions []string, repositoryID, limit int) (_ int, _ ReferencePager, err error) {
 	ctx, endObservation := s.packageReferencePagerOperation.With(ctx, &err, observation.Args{})
 	defer endObservation(1, observation.Args{})
-	return s.store.PackageReferencePager(ctx, scheme, name, version, repositoryID, limit)
+	return s.store.PackageReferencePager(ctx, scheme, name, versions, repositoryID, limit)
+}
+
+// GetPackageVersions calls into the inner store and registers the observed results.
+func (s *ObservedStore) GetPackageVersions(ctx context.Context, scheme, name string) (_ []PackageVersion, err error) {
+	ctx, endObservation := s.getPackageVersionsOperation.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+	return s.store.GetPackageVersions(ctx, scheme, name)
 }
 
 // HasCommit calls into the inner store and registers the observed results.
@@ -564,6 +585,13 @@ func (s *ObservedStore) HasCommit(ctx context.Context, repositoryID int, commit
 	return s.store.HasCommit(ctx, repositoryID, commit)
 }
 
+// GetCommitGraph calls into the inner store and registers the observed results.
+func (s *ObservedStore) GetCommitGraph(ctx context.Context, repositoryID int) (_ map[string][]string, err error) {
+	ctx, endObservation := s.getCommitGraphOperation.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+	return s.store.GetCommitGraph(ctx, repositoryID)
+}
+
 // UpdateCommits calls into the inner store and registers the observed results.
 func (s *ObservedStore) UpdateCommits(ctx context.Context, repositoryID int, commits map[string][]string) (err error) {
 	ctx, endObservation := s.updateCommitsOperation.With(ctx, &err, observation.Args{})
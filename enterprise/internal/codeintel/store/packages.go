@@ -2,11 +2,51 @@ package store
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/keegancsmith/sqlf"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/bundles/types"
 )
 
+// PackageVersion is a node of the package version graph. It pairs a version of a package with the
+// dumps that provide that version and the dumps that reference that version.
+type PackageVersion struct {
+	Version            string
+	ProvidingDumpIDs   []int
+	ReferencingDumpIDs []int
+}
+
+// scanPackageVersions scans a slice of package versions from the return value of `*store.query`. The
+// rows are expected to be ordered by version.
+func scanPackageVersions(rows *sql.Rows, queryErr error) (_ []PackageVersion, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = closeRows(rows, err) }()
+
+	var versions []PackageVersion
+	for rows.Next() {
+		var version string
+		var dumpID int
+		var providing bool
+		if err := rows.Scan(&version, &dumpID, &providing); err != nil {
+			return nil, err
+		}
+
+		if len(versions) == 0 || versions[len(versions)-1].Version != version {
+			versions = append(versions, PackageVersion{Version: version})
+		}
+
+		if last := &versions[len(versions)-1]; providing {
+			last.ProvidingDumpIDs = append(last.ProvidingDumpIDs, dumpID)
+		} else {
+			last.ReferencingDumpIDs = append(last.ReferencingDumpIDs, dumpID)
+		}
+	}
+
+	return versions, nil
+}
+
 // GetPackage returns the dump that provides the package with the given scheme, name, and version and a flag indicating its existence.
 func (s *store) GetPackage(ctx context.Context, scheme, name, version string) (Dump, bool, error) {
 	return scanFirstDump(s.query(ctx, sqlf.Sprintf(`
@@ -45,3 +85,18 @@ func (s *store) UpdatePackages(ctx context.Context, packages []types.Package) (e
 
 	return s.queryForEffect(ctx, sqlf.Sprintf(`INSERT INTO lsif_packages (dump_id, scheme, name, version) VALUES %s`, sqlf.Join(values, ",")))
 }
+
+// GetPackageVersions returns the versions of the package with the given scheme and name along with the dumps that provide each
+// version and the dumps that reference each version. Only referencing dumps visible at the tip of their repository's default branch
+// are included. The resulting versions are ordered by version.
+func (s *store) GetPackageVersions(ctx context.Context, scheme, name string) ([]PackageVersion, error) {
+	return scanPackageVersions(s.query(ctx, sqlf.Sprintf(`
+		SELECT p.version, p.dump_id, true FROM lsif_packages p
+		WHERE p.scheme = %s AND p.name = %s
+		UNION ALL
+		SELECT r.version, r.dump_id, false FROM lsif_references r
+		JOIN lsif_dumps d ON r.dump_id = d.id
+		WHERE r.scheme = %s AND r.name = %s AND d.visible_at_tip = true
+		ORDER BY 1, 2
+	`, scheme, name, scheme, name)))
+}
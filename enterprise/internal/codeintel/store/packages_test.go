@@ -127,3 +127,48 @@ func TestUpdatePackagesEmpty(t *testing.T) {
 		t.Errorf("unexpected package count. want=%d have=%d", 0, count)
 	}
 }
+
+func TestGetPackageVersions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	store := testStore()
+
+	insertUploads(t, dbconn.Global,
+		Upload{ID: 1, Commit: makeCommit(1), VisibleAtTip: true, RepositoryID: 50},
+		Upload{ID: 2, Commit: makeCommit(2), VisibleAtTip: true, RepositoryID: 50},
+		Upload{ID: 3, Commit: makeCommit(3), VisibleAtTip: true, RepositoryID: 51},
+		Upload{ID: 4, Commit: makeCommit(4), VisibleAtTip: true, RepositoryID: 52},
+		Upload{ID: 5, Commit: makeCommit(5), VisibleAtTip: false, RepositoryID: 53},
+	)
+
+	if err := store.UpdatePackages(context.Background(), []types.Package{
+		{DumpID: 1, Scheme: "gomod", Name: "leftpad", Version: "0.1.0"},
+		{DumpID: 2, Scheme: "gomod", Name: "leftpad", Version: "0.2.0"},
+		{DumpID: 2, Scheme: "gomod", Name: "rightpad", Version: "0.2.0"},
+	}); err != nil {
+		t.Fatalf("unexpected error updating packages: %s", err)
+	}
+
+	insertPackageReferences(t, store, []types.PackageReference{
+		{DumpID: 3, Scheme: "gomod", Name: "leftpad", Version: "0.1.0", Filter: []byte("f3")},
+		{DumpID: 4, Scheme: "gomod", Name: "leftpad", Version: "0.1.0", Filter: []byte("f4")},
+		{DumpID: 4, Scheme: "gomod", Name: "leftpad", Version: "0.3.0", Filter: []byte("f4")},
+		{DumpID: 5, Scheme: "gomod", Name: "leftpad", Version: "0.2.0", Filter: []byte("f5")},
+	})
+
+	versions, err := store.GetPackageVersions(context.Background(), "gomod", "leftpad")
+	if err != nil {
+		t.Fatalf("unexpected error getting package versions: %s", err)
+	}
+
+	expected := []PackageVersion{
+		{Version: "0.1.0", ProvidingDumpIDs: []int{1}, ReferencingDumpIDs: []int{3, 4}},
+		{Version: "0.2.0", ProvidingDumpIDs: []int{2}},
+		{Version: "0.3.0", ReferencingDumpIDs: []int{4}},
+	}
+	if diff := cmp.Diff(expected, versions); diff != "" {
+		t.Errorf("unexpected package versions (-want +got):\n%s", diff)
+	}
+}
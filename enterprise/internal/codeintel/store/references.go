@@ -88,9 +88,9 @@ func (s *store) SameRepoPager(ctx context.Context, repositoryID int, commit, sch
 }
 
 // PackageReferencePager returns a ReferencePager for dumps that belong to a remote repository (distinct from the given repository id)
-// and reference the package with the given scheme, name, and version. All resulting dumps are visible at the tip of their repository's
-// default branch.
-func (s *store) PackageReferencePager(ctx context.Context, scheme, name, version string, repositoryID, limit int) (_ int, _ ReferencePager, err error) {
+// and reference the package with the given scheme, name, and one of the given versions. All resulting dumps are visible at the tip of
+// their repository's default branch.
+func (s *store) PackageReferencePager(ctx context.Context, scheme, name string, versions []string, repositoryID, limit int) (_ int, _ ReferencePager, err error) {
 	tx, started, err := s.transact(ctx)
 	if err != nil {
 		return 0, nil, err
@@ -101,10 +101,19 @@ func (s *store) PackageReferencePager(ctx context.Context, scheme, name, version
 		done = tx.Done
 	}
 
+	if len(versions) == 0 {
+		return 0, newReferencePager(noopPageFromOffsetFn, done), nil
+	}
+
+	var versionQueries []*sqlf.Query
+	for _, version := range versions {
+		versionQueries = append(versionQueries, sqlf.Sprintf("%s", version))
+	}
+
 	conds := []*sqlf.Query{
 		sqlf.Sprintf("r.scheme = %s", scheme),
 		sqlf.Sprintf("r.name = %s", name),
-		sqlf.Sprintf("r.version = %s", version),
+		sqlf.Sprintf("r.version IN (%s)", sqlf.Join(versionQueries, ", ")),
 		sqlf.Sprintf("d.repository_id != %s", repositoryID),
 		sqlf.Sprintf("d.visible_at_tip = true"),
 	}
@@ -222,7 +222,7 @@ func TestPackageReferencePager(t *testing.T) {
 		{DumpID: 6, Scheme: "gomod", Name: "leftpad", Version: "0.1.0", Filter: []byte("f6")},
 	}, expected...))
 
-	totalCount, pager, err := store.PackageReferencePager(context.Background(), "gomod", "leftpad", "0.1.0", 50, 5)
+	totalCount, pager, err := store.PackageReferencePager(context.Background(), "gomod", "leftpad", []string{"0.1.0"}, 50, 5)
 	if err != nil {
 		t.Fatalf("unexpected error getting pager: %s", err)
 	}
@@ -246,7 +246,7 @@ func TestPackageReferencePagerEmpty(t *testing.T) {
 	dbtesting.SetupGlobalTestDB(t)
 	store := testStore()
 
-	totalCount, pager, err := store.PackageReferencePager(context.Background(), "gomod", "leftpad", "0.1.0", 50, 5)
+	totalCount, pager, err := store.PackageReferencePager(context.Background(), "gomod", "leftpad", []string{"0.1.0"}, 50, 5)
 	if err != nil {
 		t.Fatalf("unexpected error getting pager: %s", err)
 	}
@@ -289,7 +289,7 @@ func TestPackageReferencePagerPages(t *testing.T) {
 	}
 	insertPackageReferences(t, store, expected)
 
-	totalCount, pager, err := store.PackageReferencePager(context.Background(), "gomod", "leftpad", "0.1.0", 50, 3)
+	totalCount, pager, err := store.PackageReferencePager(context.Background(), "gomod", "leftpad", []string{"0.1.0"}, 50, 3)
 	if err != nil {
 		t.Fatalf("unexpected error getting pager: %s", err)
 	}
@@ -116,13 +116,21 @@ type Store interface {
 	UpdatePackageReferences(ctx context.Context, packageReferences []types.PackageReference) error
 
 	// PackageReferencePager returns a ReferencePager for dumps that belong to a remote repository (distinct from the given repository id)
-	// and reference the package with the given scheme, name, and version. All resulting dumps are visible at the tip of their repository's
-	// default branch.
-	PackageReferencePager(ctx context.Context, scheme, name, version string, repositoryID, limit int) (int, ReferencePager, error)
+	// and reference the package with the given scheme, name, and one of the given versions. All resulting dumps are visible at the tip of
+	// their repository's default branch.
+	PackageReferencePager(ctx context.Context, scheme, name string, versions []string, repositoryID, limit int) (int, ReferencePager, error)
+
+	// GetPackageVersions returns the versions of the package with the given scheme and name along with the dumps that provide each
+	// version and the dumps that reference each version. Only referencing dumps visible at the tip of their repository's default branch
+	// are included. The resulting versions are ordered by version.
+	GetPackageVersions(ctx context.Context, scheme, name string) ([]PackageVersion, error)
 
 	// HasCommit determines if the given commit is known for the given repository.
 	HasCommit(ctx context.Context, repositoryID int, commit string) (bool, error)
 
+	// GetCommitGraph returns a map from commits to parent commits of all commits known for the given repository.
+	GetCommitGraph(ctx context.Context, repositoryID int) (map[string][]string, error)
+
 	// UpdateCommits upserts commits/parent-commit relations for the given repository ID.
 	UpdateCommits(ctx context.Context, repositoryID int, commits map[string][]string) error
 
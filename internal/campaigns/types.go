@@ -290,6 +290,16 @@ func (s ChangesetCheckState) Valid() bool {
 	}
 }
 
+// ChangesetSignatureState constants.
+type ChangesetSignatureState string
+
+const (
+	ChangesetSignatureStateUnknown    ChangesetSignatureState = "UNKNOWN"
+	ChangesetSignatureStateUnsigned   ChangesetSignatureState = "UNSIGNED"
+	ChangesetSignatureStateVerified   ChangesetSignatureState = "VERIFIED"
+	ChangesetSignatureStateUnverified ChangesetSignatureState = "UNVERIFIED"
+)
+
 // A ChangesetJob is the creation of a Changeset on an external host from a
 // local Patch for a given Campaign.
 type ChangesetJob struct {
@@ -492,6 +502,25 @@ func (c *Changeset) URL() (s string, err error) {
 	}
 }
 
+// SignatureState returns whether the code host verified the signature of the
+// head commit of the Changeset. It is ChangesetSignatureStateUnknown for code
+// hosts that don't verify signatures.
+func (c *Changeset) SignatureState() ChangesetSignatureState {
+	pr, ok := c.Metadata.(*github.PullRequest)
+	if !ok || len(pr.Commits.Nodes) == 0 {
+		return ChangesetSignatureStateUnknown
+	}
+	// We only request the most recent commit.
+	switch sig := pr.Commits.Nodes[0].Commit.Signature; {
+	case sig == nil:
+		return ChangesetSignatureStateUnsigned
+	case sig.IsValid && sig.State == "VALID":
+		return ChangesetSignatureStateVerified
+	default:
+		return ChangesetSignatureStateUnverified
+	}
+}
+
 // Changesets is a slice of *Changesets.
 type Changesets []*Changeset
 
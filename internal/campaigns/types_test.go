@@ -296,3 +296,32 @@ func TestChangesetEvents(t *testing.T) {
 		})
 	}
 }
+
+func TestChangesetSignatureState(t *testing.T) {
+	prWithSignature := func(sig *github.GitSignature) *github.PullRequest {
+		var commit github.CommitWithChecks
+		commit.Commit.Signature = sig
+		pr := &github.PullRequest{}
+		pr.Commits.Nodes = []github.CommitWithChecks{commit}
+		return pr
+	}
+
+	for _, tc := range []struct {
+		name     string
+		metadata interface{}
+		want     ChangesetSignatureState
+	}{
+		{"github unsigned", prWithSignature(nil), ChangesetSignatureStateUnsigned},
+		{"github verified", prWithSignature(&github.GitSignature{IsValid: true, State: "VALID"}), ChangesetSignatureStateVerified},
+		{"github unknown key", prWithSignature(&github.GitSignature{State: "UNKNOWN_KEY"}), ChangesetSignatureStateUnverified},
+		{"github no commits", &github.PullRequest{}, ChangesetSignatureStateUnknown},
+		{"bitbucket server", &bitbucketserver.PullRequest{}, ChangesetSignatureStateUnknown},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &Changeset{Metadata: tc.metadata}
+			if have := c.SignatureState(); have != tc.want {
+				t.Errorf("have signature state %q, want %q", have, tc.want)
+			}
+		})
+	}
+}
@@ -14,6 +14,11 @@ type ServiceConnections struct {
 	// PostgresDSN is the PostgreSQL DB data source name.
 	// eg: "postgres://sg@pgsql/sourcegraph?sslmode=false"
 	PostgresDSN string `json:"postgresDSN"`
+
+	// GitServerPlacements are the addresses of the gitserver instances of
+	// repositories that were moved off the gitserver their name hashes to,
+	// by normalized repository name (see protocol.NormalizeRepo).
+	GitServerPlacements map[string]string `json:"gitServerPlacements,omitempty"`
 }
 
 // RawUnified is the unparsed variant of conf.Unified.
@@ -233,6 +233,13 @@ type Repo struct {
 	Parent      *Repo  `json:"parent"`
 	IsPrivate   bool   `json:"is_private"`
 	Links       Links  `json:"links"`
+	Language    string `json:"language,omitempty"`
+	MainBranch  *Ref   `json:"mainbranch,omitempty"`
+}
+
+// Ref is a branch or tag of a repository.
+type Ref struct {
+	Name string `json:"name"`
 }
 
 type Links struct {
@@ -28,6 +28,12 @@ func ParseWebhookEvent(eventType string, payload []byte) (e interface{}, err err
 	case "pr:participant:status":
 		e = &PullRequestParticipantStatusEvent{}
 		return e, json.Unmarshal(payload, e)
+	case "repo:modified":
+		e = &RepoModifiedEvent{}
+		return e, json.Unmarshal(payload, e)
+	case "repo:refs_changed":
+		e = &RepoRefsChangedEvent{}
+		return e, json.Unmarshal(payload, e)
 	default:
 		return nil, fmt.Errorf("unknown webhook event type: %q", eventType)
 	}
@@ -58,6 +64,30 @@ func (a *ParticipantStatusEvent) Key() string {
 	return fmt.Sprintf("%s:%d:%d", a.Action, a.User.ID, a.CreatedDate)
 }
 
+// RepoModifiedEvent is sent when a repository is renamed, moved to another
+// project or has its description changed.
+type RepoModifiedEvent struct {
+	Actor User  `json:"actor"`
+	Old   *Repo `json:"old"`
+	New   *Repo `json:"new"`
+}
+
+// RepoRefsChangedEvent is sent when branches or tags are pushed to, created
+// in or deleted from a repository.
+type RepoRefsChangedEvent struct {
+	Actor      User        `json:"actor"`
+	Repository *Repo       `json:"repository"`
+	Changes    []RefChange `json:"changes"`
+}
+
+// RefChange is a change of a single ref in a RepoRefsChangedEvent.
+type RefChange struct {
+	RefID    string `json:"refId"`
+	FromHash string `json:"fromHash"`
+	ToHash   string `json:"toHash"`
+	Type     string `json:"type"`
+}
+
 type BuildStatusEvent struct {
 	Commit       string        `json:"commit"`
 	Status       BuildStatus   `json:"status"`
@@ -0,0 +1,292 @@
+package gerrit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/opentracing-contrib/go-stdlib/nethttp"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
+)
+
+var requestCounter = metrics.NewRequestMeter("gerrit_requests_count", "Total number of requests sent to the Gerrit API.")
+
+// xssiPrefix is the magic prefix Gerrit prepends to every JSON response body to prevent
+// cross-site script inclusion. It must be stripped before decoding the response.
+var xssiPrefix = []byte(")]}'")
+
+// ProjectsPageSize is the number of projects requested per page when listing projects.
+const ProjectsPageSize = 500
+
+// Client access a Gerrit instance via the REST API. All requests are made against the
+// authenticated "/a/" endpoints, so both Username and Password are required.
+type Client struct {
+	// HTTP Client used to communicate with the API
+	httpClient httpcli.Doer
+
+	// URL is the base URL of the Gerrit instance.
+	URL *url.URL
+
+	// The username and HTTP password credentials for accessing the server.
+	Username, Password string
+}
+
+// NewClient creates a new Gerrit API client with the given base URL and credentials. If a
+// nil httpClient is provided, http.DefaultClient will be used.
+func NewClient(baseURL *url.URL, username, password string, httpClient httpcli.Doer) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	httpClient = requestCounter.Doer(httpClient, func(u *url.URL) string {
+		// The path component following "/a/" maps to the type of API request we
+		// are making.
+		var category string
+		if i := strings.Index(u.Path, "/a/"); i >= 0 {
+			category = strings.SplitN(u.Path[i+3:], "/", 2)[0]
+		}
+		return category
+	})
+
+	// Gerrit is commonly served under a path prefix (such as https://example.com/gerrit/),
+	// so API paths are resolved relative to the base URL.
+	u := *baseURL
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+
+	return &Client{
+		httpClient: httpClient,
+		URL:        &u,
+		Username:   username,
+		Password:   password,
+	}
+}
+
+// Project describes a Gerrit project (ProjectInfo in the Gerrit REST API).
+type Project struct {
+	// ID is the URL-encoded project name.
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Parent      string `json:"parent,omitempty"`
+	Description string `json:"description,omitempty"`
+	State       string `json:"state,omitempty"`
+}
+
+// ListProjects returns all projects matched by the given query, which is passed as-is to
+// the "list projects" endpoint (such as "?p=platform/" or "?type=CODE"). The result is keyed
+// by project name and include descriptions. Pagination is handled transparently.
+func (c *Client) ListProjects(ctx context.Context, query string) (map[string]*Project, error) {
+	qry, err := url.ParseQuery(strings.TrimPrefix(query, "?"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid project query %q", query)
+	}
+	qry.Set("d", "")
+	qry.Set("n", strconv.Itoa(ProjectsPageSize))
+
+	all := map[string]*Project{}
+	for skip := 0; ; skip += ProjectsPageSize {
+		qry.Set("S", strconv.Itoa(skip))
+
+		var page map[string]*Project
+		if err := c.get(ctx, "a/projects/", qry, &page); err != nil {
+			return nil, err
+		}
+
+		for name, p := range page {
+			p.Name = name
+			all[name] = p
+		}
+
+		if len(page) < ProjectsPageSize {
+			return all, nil
+		}
+	}
+}
+
+// GetProject returns the project with the given name.
+func (c *Client) GetProject(ctx context.Context, name string) (*Project, error) {
+	var p Project
+	if err := c.get(ctx, "a/projects/"+url.PathEscape(name), nil, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Account describes a Gerrit account (AccountInfo in the Gerrit REST API).
+type Account struct {
+	ID       int64  `json:"_account_id"`
+	Name     string `json:"name,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+// GetAccount returns the account with the given username.
+func (c *Client) GetAccount(ctx context.Context, username string) (*Account, error) {
+	var a Account
+	if err := c.get(ctx, "a/accounts/"+url.PathEscape(username), nil, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// Group describes a Gerrit group (GroupInfo in the Gerrit REST API).
+type Group struct {
+	// ID is the UUID of the group. Permission rules refer to groups by this value.
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+// ListAccountGroups returns the groups the given account is a direct or indirect member of.
+func (c *Client) ListAccountGroups(ctx context.Context, accountID int64) ([]*Group, error) {
+	var groups []*Group
+	path := "a/accounts/" + strconv.FormatInt(accountID, 10) + "/groups"
+	if err := c.get(ctx, path, nil, &groups); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// ListGroupMembers returns the accounts that are members of the given group, including the
+// members of included groups.
+func (c *Client) ListGroupMembers(ctx context.Context, groupID string) ([]*Account, error) {
+	var members []*Account
+	path := "a/groups/" + url.PathEscape(groupID) + "/members/"
+	if err := c.get(ctx, path, url.Values{"recursive": {"true"}}, &members); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// Possible values of PermissionRule.Action.
+const (
+	ActionAllow = "ALLOW"
+	ActionDeny  = "DENY"
+	ActionBlock = "BLOCK"
+)
+
+// PermissionRule is a single rule of a permission, granted to a group.
+type PermissionRule struct {
+	Action string `json:"action"`
+	Force  bool   `json:"force,omitempty"`
+}
+
+// Permission is a named permission of an access section, with its rules keyed by group UUID.
+type Permission struct {
+	Exclusive bool                       `json:"exclusive,omitempty"`
+	Rules     map[string]*PermissionRule `json:"rules"`
+}
+
+// AccessSection lists the permissions granted on a ref pattern, keyed by permission name
+// (such as "read").
+type AccessSection struct {
+	Permissions map[string]*Permission `json:"permissions"`
+}
+
+// ProjectAccess describes the access rights of a project (ProjectAccessInfo in the Gerrit
+// REST API). Only the sections defined on the project itself are included; inherited rights
+// must be looked up on InheritsFrom.
+type ProjectAccess struct {
+	InheritsFrom *Project                  `json:"inherits_from,omitempty"`
+	Local        map[string]*AccessSection `json:"local"`
+}
+
+// ListAccess returns the access rights of the given projects, keyed by project name. The
+// client's account needs read access to refs/meta/config of the projects to see all their
+// access sections.
+func (c *Client) ListAccess(ctx context.Context, projects ...string) (map[string]*ProjectAccess, error) {
+	access := map[string]*ProjectAccess{}
+	if len(projects) == 0 {
+		return access, nil
+	}
+
+	if err := c.get(ctx, "a/access/", url.Values{"project": projects}, &access); err != nil {
+		return nil, err
+	}
+	return access, nil
+}
+
+// get requests the given path, which must already be escaped and is resolved relative to the
+// base URL of the client.
+func (c *Client) get(ctx context.Context, path string, qry url.Values, result interface{}) error {
+	if len(qry) > 0 {
+		path += "?" + qry.Encode()
+	}
+
+	req, err := http.NewRequest("GET", path, nil)
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, req, result)
+}
+
+func (c *Client) do(ctx context.Context, req *http.Request, result interface{}) error {
+	req.URL = c.URL.ResolveReference(req.URL)
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(c.Username, c.Password)
+
+	req, ht := nethttp.TraceRequest(ot.GetTracer(ctx),
+		req.WithContext(ctx),
+		nethttp.OperationName("Gerrit"),
+		nethttp.ClientTrace(false))
+	defer ht.Finish()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	bs, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return errors.WithStack(&httpError{
+			URL:        req.URL,
+			StatusCode: resp.StatusCode,
+			Body:       bs,
+		})
+	}
+
+	if result != nil {
+		return json.Unmarshal(bytes.TrimPrefix(bs, xssiPrefix), result)
+	}
+
+	return nil
+}
+
+type httpError struct {
+	StatusCode int
+	URL        *url.URL
+	Body       []byte
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("Gerrit API HTTP error: code=%d url=%q body=%q", e.StatusCode, e.URL, e.Body)
+}
+
+func (e *httpError) Unauthorized() bool {
+	return e.StatusCode == http.StatusUnauthorized
+}
+
+func (e *httpError) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
+
+// IsNotFound reports whether err is a Gerrit API error with a 404 status code.
+func IsNotFound(err error) bool {
+	e, ok := errors.Cause(err).(*httpError)
+	return ok && e.NotFound()
+}
@@ -0,0 +1,162 @@
+package gerrit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func newTestClient(t *testing.T, h http.HandlerFunc) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "admin" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		h(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	u, err := url.Parse(srv.URL + "/gerrit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewClient(u, "admin", "secret", nil)
+}
+
+func TestClient_ListProjects(t *testing.T) {
+	var queries []url.Values
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/gerrit/a/projects/" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		queries = append(queries, r.URL.Query())
+
+		// Serve one full page followed by a partial one.
+		fmt.Fprint(w, ")]}'\n{")
+		n := ProjectsPageSize
+		if r.URL.Query().Get("S") != "0" {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				fmt.Fprint(w, ",")
+			}
+			skip, _ := strconv.Atoi(r.URL.Query().Get("S"))
+			name := fmt.Sprintf("platform/p%d", skip+i)
+			fmt.Fprintf(w, "%q: {\"id\": %q, \"parent\": \"All-Projects\"}", name, url.PathEscape(name))
+		}
+		fmt.Fprint(w, "}")
+	})
+
+	projects, err := cli.ListProjects(context.Background(), "?p=platform/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if have, want := len(projects), ProjectsPageSize+1; have != want {
+		t.Fatalf("unexpected number of projects. want=%d have=%d", want, have)
+	}
+
+	want := &Project{ID: "platform%2Fp0", Name: "platform/p0", Parent: "All-Projects"}
+	if diff := cmp.Diff(want, projects["platform/p0"]); diff != "" {
+		t.Errorf("unexpected project (-want +got):\n%s", diff)
+	}
+
+	wantQueries := []url.Values{
+		{"p": {"platform/"}, "d": {""}, "n": {"500"}, "S": {"0"}},
+		{"p": {"platform/"}, "d": {""}, "n": {"500"}, "S": {"500"}},
+	}
+	if diff := cmp.Diff(wantQueries, queries); diff != "" {
+		t.Errorf("unexpected queries (-want +got):\n%s", diff)
+	}
+}
+
+func TestClient_GetAccount(t *testing.T) {
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gerrit/a/accounts/alice":
+			fmt.Fprint(w, ")]}'\n{\"_account_id\": 1000, \"name\": \"Alice\", \"username\": \"alice\"}")
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	account, err := cli.GetAccount(context.Background(), "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&Account{ID: 1000, Name: "Alice", Username: "alice"}, account); diff != "" {
+		t.Errorf("unexpected account (-want +got):\n%s", diff)
+	}
+
+	if _, err := cli.GetAccount(context.Background(), "bob"); !IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestClient_ListAccess(t *testing.T) {
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if diff := cmp.Diff([]string{"platform/build", "All-Projects"}, r.URL.Query()["project"]); diff != "" {
+			t.Errorf("unexpected projects (-want +got):\n%s", diff)
+		}
+		fmt.Fprint(w, `)]}'
+{
+  "platform/build": {
+    "inherits_from": {"id": "All-Projects", "name": "All-Projects"},
+    "local": {
+      "refs/heads/*": {"permissions": {"read": {"rules": {"abc": {"action": "ALLOW"}}}}}
+    }
+  },
+  "All-Projects": {
+    "local": {
+      "refs/*": {"permissions": {"read": {"rules": {"global:Anonymous-Users": {"action": "BLOCK"}}}}}
+    }
+  }
+}`)
+	})
+
+	access, err := cli.ListAccess(context.Background(), "platform/build", "All-Projects")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]*ProjectAccess{
+		"platform/build": {
+			InheritsFrom: &Project{ID: "All-Projects", Name: "All-Projects"},
+			Local: map[string]*AccessSection{
+				"refs/heads/*": {Permissions: map[string]*Permission{
+					"read": {Rules: map[string]*PermissionRule{"abc": {Action: ActionAllow}}},
+				}},
+			},
+		},
+		"All-Projects": {
+			Local: map[string]*AccessSection{
+				"refs/*": {Permissions: map[string]*Permission{
+					"read": {Rules: map[string]*PermissionRule{"global:Anonymous-Users": {Action: ActionBlock}}},
+				}},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, access); diff != "" {
+		t.Errorf("unexpected access (-want +got):\n%s", diff)
+	}
+}
+
+func TestClient_Unauthorized(t *testing.T) {
+	cli := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {})
+	cli.Password = "wrong"
+
+	_, err := cli.GetProject(context.Background(), "platform/build")
+	if e, ok := errors.Cause(err).(*httpError); !ok || !e.Unauthorized() {
+		t.Errorf("expected unauthorized error, got %v", err)
+	}
+}
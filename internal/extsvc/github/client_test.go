@@ -152,6 +152,7 @@ func TestClient_ListAffiliatedRepositories(t *testing.T) {
 					URL:              "https://github.com/sourcegraph-vcr-repos/private-org-repo-1",
 					IsPrivate:        true,
 					ViewerPermission: "ADMIN",
+					DefaultBranchRef: &Ref{Name: "master"},
 				}, {
 					ID:               "MDEwOlJlcG9zaXRvcnkyNjMwMzQwNzM=",
 					DatabaseID:       263034073,
@@ -159,18 +160,21 @@ func TestClient_ListAffiliatedRepositories(t *testing.T) {
 					URL:              "https://github.com/sourcegraph-vcr/private-user-repo-1",
 					IsPrivate:        true,
 					ViewerPermission: "ADMIN",
+					DefaultBranchRef: &Ref{Name: "master"},
 				}, {
 					ID:               "MDEwOlJlcG9zaXRvcnkyNjMwMzM5NDk=",
 					DatabaseID:       263033949,
 					NameWithOwner:    "sourcegraph-vcr/public-user-repo-1",
 					URL:              "https://github.com/sourcegraph-vcr/public-user-repo-1",
 					ViewerPermission: "ADMIN",
+					DefaultBranchRef: &Ref{Name: "master"},
 				}, {
 					ID:               "MDEwOlJlcG9zaXRvcnkyNjMwMzM3NjE=",
 					DatabaseID:       263033761,
 					NameWithOwner:    "sourcegraph-vcr-repos/public-org-repo-1",
 					URL:              "https://github.com/sourcegraph-vcr-repos/public-org-repo-1",
 					ViewerPermission: "ADMIN",
+					DefaultBranchRef: &Ref{Name: "master"},
 				},
 			},
 		},
@@ -184,12 +188,14 @@ func TestClient_ListAffiliatedRepositories(t *testing.T) {
 					NameWithOwner:    "sourcegraph-vcr/public-user-repo-1",
 					URL:              "https://github.com/sourcegraph-vcr/public-user-repo-1",
 					ViewerPermission: "ADMIN",
+					DefaultBranchRef: &Ref{Name: "master"},
 				}, {
 					ID:               "MDEwOlJlcG9zaXRvcnkyNjMwMzM3NjE=",
 					DatabaseID:       263033761,
 					NameWithOwner:    "sourcegraph-vcr-repos/public-org-repo-1",
 					URL:              "https://github.com/sourcegraph-vcr-repos/public-org-repo-1",
 					ViewerPermission: "ADMIN",
+					DefaultBranchRef: &Ref{Name: "master"},
 				},
 			},
 		},
@@ -204,6 +210,7 @@ func TestClient_ListAffiliatedRepositories(t *testing.T) {
 					URL:              "https://github.com/sourcegraph-vcr-repos/private-org-repo-1",
 					IsPrivate:        true,
 					ViewerPermission: "ADMIN",
+					DefaultBranchRef: &Ref{Name: "master"},
 				}, {
 					ID:               "MDEwOlJlcG9zaXRvcnkyNjMwMzQwNzM=",
 					DatabaseID:       263034073,
@@ -211,6 +218,7 @@ func TestClient_ListAffiliatedRepositories(t *testing.T) {
 					URL:              "https://github.com/sourcegraph-vcr/private-user-repo-1",
 					IsPrivate:        true,
 					ViewerPermission: "ADMIN",
+					DefaultBranchRef: &Ref{Name: "master"},
 				},
 			},
 		},
@@ -232,6 +240,9 @@ func TestClient_ListAffiliatedRepositories(t *testing.T) {
 	}
 }
 
+// NOTE: The VCR recordings of the pull request tests predate querying the
+// signature of commits, so their golden files have a null Signature. Re-record
+// them with -update to include the signatures GitHub returns.
 func TestClient_LoadPullRequests(t *testing.T) {
 	cli, save := newClient(t, "LoadPullRequests")
 	defer save()
@@ -290,6 +301,51 @@ func TestClient_LoadPullRequests(t *testing.T) {
 	}
 }
 
+func TestClient_LoadPullRequests_signature(t *testing.T) {
+	mock := mockHTTPResponseBody{
+		responseBody: `
+{
+  "data": {
+    "repo_0": {
+      "repo_0_1": {
+        "number": 1,
+        "commits": {
+          "nodes": [
+            {"commit": {"oid": "a", "signature": {"isValid": true, "state": "VALID"}}}
+          ]
+        }
+      }
+    },
+    "repo_1": {
+      "repo_1_2": {
+        "number": 2,
+        "commits": {
+          "nodes": [
+            {"commit": {"oid": "b", "signature": null}}
+          ]
+        }
+      }
+    }
+  }
+}
+`,
+	}
+	c := NewClient(&url.URL{Scheme: "https", Host: "example.com", Path: "/"}, "", &mock)
+
+	signed := &PullRequest{RepoWithOwner: "sourcegraph/signed", Number: 1}
+	unsigned := &PullRequest{RepoWithOwner: "sourcegraph/unsigned", Number: 2}
+	if err := c.LoadPullRequests(context.Background(), signed, unsigned); err != nil {
+		t.Fatal(err)
+	}
+
+	if have, want := signed.Commits.Nodes[0].Commit.Signature, (&GitSignature{IsValid: true, State: "VALID"}); !reflect.DeepEqual(have, want) {
+		t.Errorf("signature of signed commit:\nhave: %+v\nwant: %+v", have, want)
+	}
+	if have := unsigned.Commits.Nodes[0].Commit.Signature; have != nil {
+		t.Errorf("signature of unsigned commit:\nhave: %+v\nwant: nil", have)
+	}
+}
+
 func TestClient_CreatePullRequest(t *testing.T) {
 	cli, save := newClient(t, "CreatePullRequest")
 	defer save()
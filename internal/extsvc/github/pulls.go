@@ -259,10 +259,19 @@ type CommitWithChecks struct {
 		OID           string
 		CheckSuites   struct{ Nodes []CheckSuite }
 		Status        Status
+		Signature     *GitSignature
 		CommittedDate time.Time
 	}
 }
 
+// GitSignature is the signature of a commit, which is nil for unsigned
+// commits. IsValid is only true if GitHub verified it against the public keys
+// of the committer's account.
+type GitSignature struct {
+	IsValid bool
+	State   string
+}
+
 // PullRequestReviewComment represents a review comment on a given pull request.
 type PullRequestReviewComment struct {
 	DatabaseID          int64
@@ -780,6 +789,10 @@ fragment commitWithChecks on Commit {
       }
     }
   }
+  signature {
+    isValid
+    state
+  }
   committedDate
 }
 
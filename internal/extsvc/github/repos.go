@@ -35,6 +35,81 @@ type Repository struct {
 	IsFork           bool   // whether the repository is a fork of another repository
 	IsArchived       bool   // whether the repository is archived on the code host
 	ViewerPermission string // ADMIN, WRITE, READ, or empty if unknown. Only the graphql api populates this. https://developer.github.com/v4/enum/repositorypermission/
+
+	// The fields below are omitted from the JSON encoding when they are not
+	// set, which is the case for repositories cached before they were added.
+	Stargazers       *TotalCount       `json:",omitempty"` // the users who starred the repository
+	PrimaryLanguage  *Language         `json:",omitempty"` // the primary language of the repository, if known
+	DefaultBranchRef *Ref              `json:",omitempty"` // the default branch of the repository, or nil if it is empty
+	RepositoryTopics *RepositoryTopics `json:",omitempty"` // the topics of the repository
+}
+
+// TotalCount is a GraphQL connection of which only the total count is fetched.
+type TotalCount struct {
+	TotalCount int
+}
+
+// Language is a programming language on GitHub.
+type Language struct {
+	Name string
+}
+
+// Ref is a Git reference on GitHub.
+type Ref struct {
+	Name string
+}
+
+// RepositoryTopics is the connection of the topics of a repository.
+type RepositoryTopics struct {
+	Nodes []RepositoryTopic
+}
+
+// RepositoryTopic connects a repository to a topic.
+type RepositoryTopic struct {
+	Topic Topic
+}
+
+// Topic is a topic on GitHub.
+type Topic struct {
+	Name string
+}
+
+// Stars returns the number of users who starred the repository.
+func (r *Repository) Stars() int {
+	if r.Stargazers == nil {
+		return 0
+	}
+	return r.Stargazers.TotalCount
+}
+
+// Language returns the primary language of the repository, or the empty
+// string if it is unknown.
+func (r *Repository) Language() string {
+	if r.PrimaryLanguage == nil {
+		return ""
+	}
+	return r.PrimaryLanguage.Name
+}
+
+// DefaultBranch returns the name of the default branch of the repository, or
+// the empty string if it is unknown.
+func (r *Repository) DefaultBranch() string {
+	if r.DefaultBranchRef == nil {
+		return ""
+	}
+	return r.DefaultBranchRef.Name
+}
+
+// Topics returns the names of the topics of the repository.
+func (r *Repository) Topics() []string {
+	if r.RepositoryTopics == nil {
+		return nil
+	}
+	topics := make([]string, 0, len(r.RepositoryTopics.Nodes))
+	for _, n := range r.RepositoryTopics.Nodes {
+		topics = append(topics, n.Topic.Name)
+	}
+	return topics
 }
 
 // repositoryFieldsGraphQLFragment returns a GraphQL fragment that contains the fields needed to populate the
@@ -52,6 +127,22 @@ fragment RepositoryFields on Repository {
 	isFork
 	isArchived
 	viewerPermission
+	stargazers {
+		totalCount
+	}
+	primaryLanguage {
+		name
+	}
+	defaultBranchRef {
+		name
+	}
+	repositoryTopics(first: 100) {
+		nodes {
+			topic {
+				name
+			}
+		}
+	}
 }
 	`
 	}
@@ -68,6 +159,22 @@ fragment RepositoryFields on Repository {
 	isPrivate
 	isFork
 	isArchived
+	stargazers {
+		totalCount
+	}
+	primaryLanguage {
+		name
+	}
+	defaultBranchRef {
+		name
+	}
+	repositoryTopics(first: 100) {
+		nodes {
+			topic {
+				name
+			}
+		}
+	}
 }
 	`
 }
@@ -228,6 +335,11 @@ type restRepository struct {
 	Fork        bool
 	Archived    bool
 	Permissions restRepositoryPermissions `json:"permissions"`
+
+	StargazersCount int      `json:"stargazers_count"`
+	Language        string   `json:"language"`
+	DefaultBranch   string   `json:"default_branch"`
+	Topics          []string `json:"topics"` // only returned with the mercy preview media type
 }
 
 // getRepositoryFromAPI attempts to fetch a repository from the GitHub API without use of the redis cache.
@@ -249,7 +361,7 @@ func (c *Client) getRepositoryFromAPI(ctx context.Context, owner, name string) (
 // convertRestRepo converts repo information returned by the rest API
 // to a standard format.
 func convertRestRepo(restRepo restRepository) *Repository {
-	return &Repository{
+	repo := &Repository{
 		ID:               restRepo.ID,
 		DatabaseID:       restRepo.DatabaseID,
 		NameWithOwner:    restRepo.FullName,
@@ -260,6 +372,22 @@ func convertRestRepo(restRepo restRepository) *Repository {
 		IsArchived:       restRepo.Archived,
 		ViewerPermission: convertRestRepoPermissions(restRepo.Permissions),
 	}
+	if restRepo.StargazersCount > 0 {
+		repo.Stargazers = &TotalCount{TotalCount: restRepo.StargazersCount}
+	}
+	if restRepo.Language != "" {
+		repo.PrimaryLanguage = &Language{Name: restRepo.Language}
+	}
+	if restRepo.DefaultBranch != "" {
+		repo.DefaultBranchRef = &Ref{Name: restRepo.DefaultBranch}
+	}
+	if len(restRepo.Topics) > 0 {
+		repo.RepositoryTopics = new(RepositoryTopics)
+		for _, name := range restRepo.Topics {
+			repo.RepositoryTopics.Nodes = append(repo.RepositoryTopics.Nodes, RepositoryTopic{Topic: Topic{Name: name}})
+		}
+	}
+	return repo
 }
 
 // convertRestRepoPermissions converts repo information returned by the rest API
@@ -0,0 +1,91 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	eventTypeHeader = "X-Gitlab-Event"
+	tokenHeader     = "X-Gitlab-Token"
+)
+
+// ErrIgnoredEvent is returned by ParseSystemHookEvent for system hook events
+// other than project and push events, which are sent for all hooks
+// regardless of their triggers and can be ignored.
+var ErrIgnoredEvent = errors.New("ignored system hook event")
+
+// WebhookEventType returns the type of the webhook event in the request, such
+// as "System Hook".
+func WebhookEventType(r *http.Request) string {
+	return r.Header.Get(eventTypeHeader)
+}
+
+// WebhookToken returns the secret token the webhook in the request was
+// configured with.
+func WebhookToken(r *http.Request) string {
+	return r.Header.Get(tokenHeader)
+}
+
+// ParseWebhookEvent parses the payload of a webhook event of the given type.
+// Only system hooks and push hooks are supported.
+func ParseWebhookEvent(eventType string, payload []byte) (e interface{}, err error) {
+	switch eventType {
+	case "System Hook":
+		return ParseSystemHookEvent(payload)
+	case "Push Hook", "Tag Push Hook":
+		e = &PushEvent{}
+		return e, json.Unmarshal(payload, e)
+	default:
+		return nil, fmt.Errorf("unknown webhook event type: %q", eventType)
+	}
+}
+
+// ParseSystemHookEvent parses the payload of a system hook event. Only project
+// and push events are supported, ErrIgnoredEvent is returned for all others.
+func ParseSystemHookEvent(payload []byte) (e interface{}, err error) {
+	var head struct {
+		EventName string `json:"event_name"`
+	}
+	if err := json.Unmarshal(payload, &head); err != nil {
+		return nil, err
+	}
+
+	switch head.EventName {
+	case "project_create", "project_destroy", "project_rename", "project_transfer", "project_update":
+		e = &ProjectEvent{}
+		return e, json.Unmarshal(payload, e)
+	case "push", "tag_push":
+		e = &PushEvent{}
+		return e, json.Unmarshal(payload, e)
+	default:
+		return nil, ErrIgnoredEvent
+	}
+}
+
+// ProjectEvent is a system hook event sent when a project is created,
+// destroyed, renamed, transferred to another namespace or updated.
+type ProjectEvent struct {
+	EventName            string     `json:"event_name"`
+	ProjectID            int        `json:"project_id"`
+	PathWithNamespace    string     `json:"path_with_namespace"`
+	OldPathWithNamespace string     `json:"old_path_with_namespace,omitempty"`
+	ProjectVisibility    Visibility `json:"project_visibility"`
+}
+
+// PushEvent is sent by push hooks and system hooks when branches or tags are
+// pushed to a project.
+type PushEvent struct {
+	ObjectKind string `json:"object_kind"`
+	EventName  string `json:"event_name"`
+	Ref        string `json:"ref"`
+	Before     string `json:"before"`
+	After      string `json:"after"`
+	ProjectID  int    `json:"project_id"`
+	Project    struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+}
@@ -27,6 +27,9 @@ type Project struct {
 	Visibility        Visibility     `json:"visibility"`                    // "private", "internal", or "public"
 	ForkedFromProject *ProjectCommon `json:"forked_from_project,omitempty"` // If non-nil, the project from which this project was forked
 	Archived          bool           `json:"archived"`
+	TagList           []string       `json:"tag_list,omitempty"`       // topics of the project
+	StarCount         int            `json:"star_count,omitempty"`     // number of users who starred the project
+	DefaultBranch     string         `json:"default_branch,omitempty"` // default branch of the project, or empty if it has no commits
 }
 
 type ProjectCommon struct {
@@ -13,6 +13,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -60,6 +61,9 @@ func NewClient(cli httpcli.Doer) *Client {
 		Addrs: func(ctx context.Context) []string {
 			return conf.Get().ServiceConnections.GitServers
 		},
+		Placements: func(ctx context.Context) map[string]string {
+			return conf.Get().ServiceConnections.GitServerPlacements
+		},
 		HTTPClient:  cli,
 		HTTPLimiter: parallel.NewRun(500),
 		// Use the binary name for UserAgent. This should effectively identify
@@ -82,6 +86,11 @@ type Client struct {
 	// concurrent use. It may return different results at different times.
 	Addrs func(ctx context.Context) []string
 
+	// Placements is a function which returns the addresses of the
+	// gitservers of repositories that were moved off the gitserver their
+	// name hashes to, by normalized repository name. It may be nil.
+	Placements func(ctx context.Context) map[string]string
+
 	// UserAgent is a string identifing who the client is. It will be logged in
 	// the telemetry in gitserver.
 	UserAgent string
@@ -90,7 +99,32 @@ type Client struct {
 // AddrForRepo returns the gitserver address to use for the given repo name.
 func (c *Client) AddrForRepo(ctx context.Context, repo api.RepoName) string {
 	repo = protocol.NormalizeRepo(repo) // in case the caller didn't already normalize it
-	return c.addrForKey(ctx, string(repo))
+	addrs := c.Addrs(ctx)
+	if len(addrs) == 0 {
+		panic("unexpected state: no gitserver addresses")
+	}
+	return addrForRepo(addrs, c.placements(ctx), string(repo))
+}
+
+func (c *Client) placements(ctx context.Context) map[string]string {
+	if c.Placements == nil {
+		return nil
+	}
+	return c.Placements(ctx)
+}
+
+// addrForRepo returns the address of the gitserver the repo was moved to, or
+// else of the gitserver its name hashes to. Placements on gitservers that
+// aren't in addrs anymore are ignored.
+func addrForRepo(addrs []string, placements map[string]string, repo string) string {
+	if addr, ok := placements[repo]; ok {
+		for _, a := range addrs {
+			if a == addr {
+				return addr
+			}
+		}
+	}
+	return addrForKey(addrs, repo)
 }
 
 // addrForKey returns the gitserver address to use for the given string key,
@@ -485,6 +519,7 @@ func (c *Client) ListCloned(ctx context.Context) ([]string, error) {
 		repos []string
 	)
 	addrs := c.Addrs(ctx)
+	placements := c.placements(ctx)
 	for _, addr := range addrs {
 		wg.Add(1)
 		go func(addr string) {
@@ -495,7 +530,7 @@ func (c *Client) ListCloned(ctx context.Context) ([]string, error) {
 			if len(r) > 0 {
 				filtered := r[:0]
 				for _, repo := range r {
-					if addrForKey(addrs, repo) == addr {
+					if addrForRepo(addrs, placements, repo) == addr {
 						filtered = append(filtered, repo)
 					}
 				}
@@ -513,6 +548,75 @@ func (c *Client) ListCloned(ctx context.Context) ([]string, error) {
 	return repos, err
 }
 
+// ExecLog returns the recent exec requests of repo (or all repos if empty)
+// and their aggregates from the exec log of gitservers, with at most limit
+// entries and aggregates. Only the gitserver of repo is queried if it is set.
+func (c *Client) ExecLog(ctx context.Context, repo api.RepoName, limit int) (*protocol.ExecLogResponse, error) {
+	addrs := c.Addrs(ctx)
+	if repo != "" {
+		addrs = []string{c.AddrForRepo(ctx, repo)}
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		err  error
+		resp protocol.ExecLogResponse
+	)
+	for _, addr := range addrs {
+		wg.Add(1)
+		go func(addr string) {
+			defer wg.Done()
+			r, e := c.execLogOne(ctx, addr, repo, limit)
+			mu.Lock()
+			defer mu.Unlock()
+			if e != nil {
+				err = e
+				return
+			}
+			resp.Entries = append(resp.Entries, r.Entries...)
+			resp.Stats = append(resp.Stats, r.Stats...)
+		}(addr)
+	}
+	wg.Wait()
+	if err != nil {
+		return nil, err
+	}
+
+	// Repos are on a single gitserver, so the aggregates of gitservers don't
+	// overlap.
+	sort.Slice(resp.Entries, func(i, j int) bool { return resp.Entries[i].Time.After(resp.Entries[j].Time) })
+	sort.SliceStable(resp.Stats, func(i, j int) bool { return resp.Stats[i].TotalDuration > resp.Stats[j].TotalDuration })
+	if len(resp.Entries) > limit {
+		resp.Entries = resp.Entries[:limit]
+	}
+	if len(resp.Stats) > limit {
+		resp.Stats = resp.Stats[:limit]
+	}
+	return &resp, nil
+}
+
+func (c *Client) execLogOne(ctx context.Context, addr string, repo api.RepoName, limit int) (*protocol.ExecLogResponse, error) {
+	q := url.Values{"limit": []string{strconv.Itoa(limit)}}
+	if repo != "" {
+		q.Set("repo", string(repo))
+	}
+	resp, err := c.do(ctx, repo, "GET", "http://"+addr+"/debug/exec-log?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &url.Error{URL: resp.Request.URL.String(), Op: "ExecLog", Err: fmt.Errorf("ExecLog: http status %d", resp.StatusCode)}
+	}
+	var r protocol.ExecLogResponse
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
 // GetGitolitePhabricatorMetadata returns Phabricator metadata for a Gitolite repository fetched via
 // a user-provided command.
 func (c *Client) GetGitolitePhabricatorMetadata(ctx context.Context, gitoliteHost string, repoName api.RepoName) (*protocol.GitolitePhabricatorMetadataResponse, error) {
@@ -822,6 +926,131 @@ func (c *Client) Remove(ctx context.Context, repo api.RepoName) error {
 	return nil
 }
 
+// MoveRepo copies the clone of a repository from the gitserver it is on to
+// the gitserver at target, which verifies the copy. It returns the address of
+// the gitserver the repository was on, which keeps serving its clone until
+// the repository is placed on target (see Placements) and MarkMoved is
+// called.
+func (c *Client) MoveRepo(ctx context.Context, repo api.RepoName, target string) (source string, err error) {
+	source, err = c.CheckMoveTarget(ctx, repo, target)
+	if err != nil {
+		return "", err
+	}
+
+	req := &protocol.RepoMoveRequest{
+		Repo:   repo,
+		Target: target,
+	}
+	resp, err := c.httpPost(ctx, repo, "http://"+source+"/repo-move", req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		// best-effort inclusion of body in error message
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 200))
+		return "", &url.Error{URL: resp.Request.URL.String(), Op: "RepoMove", Err: fmt.Errorf("RepoMove: http status %d: %s", resp.StatusCode, string(body))}
+	}
+	return source, nil
+}
+
+// CheckMoveTarget returns an error if the repository can't be moved to the
+// gitserver at target, and otherwise the address of the gitserver it is on.
+func (c *Client) CheckMoveTarget(ctx context.Context, repo api.RepoName, target string) (source string, err error) {
+	found := false
+	for _, addr := range c.Addrs(ctx) {
+		found = found || addr == target
+	}
+	if !found {
+		return "", fmt.Errorf("unknown gitserver %q", target)
+	}
+	source = c.AddrForRepo(ctx, repo)
+	if source == target {
+		return "", fmt.Errorf("repository %s is already on gitserver %s", repo, target)
+	}
+	return source, nil
+}
+
+// MarkMoved tells the gitserver at source that the repository was placed on
+// the gitserver at target after MoveRepo copied it there. The gitserver at
+// source removes its clone once all services picked up the new placement.
+func (c *Client) MarkMoved(ctx context.Context, repo api.RepoName, source, target string) error {
+	req := &protocol.RepoMovedRequest{
+		Repo:   repo,
+		Target: target,
+	}
+	resp, err := c.httpPost(ctx, repo, "http://"+source+"/repo-moved", req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		// best-effort inclusion of body in error message
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 200))
+		return &url.Error{URL: resp.Request.URL.String(), Op: "RepoMoved", Err: fmt.Errorf("RepoMoved: http status %d: %s", resp.StatusCode, string(body))}
+	}
+	return nil
+}
+
+// Rename moves the clone of a repository that was renamed on its code host
+// from its old name to its new name, so that it doesn't need to be cloned
+// again. The request is sent to the gitserver that has the clone of the old
+// name. If the new name belongs to another gitserver, the clone is copied
+// there like MoveRepo does. Rename reports false if there was no clone to
+// move, or the new name was already cloned.
+func (c *Client) Rename(ctx context.Context, from, to api.RepoName) (renamed bool, err error) {
+	source, target := c.AddrForRepo(ctx, from), c.AddrForRepo(ctx, to)
+	if source != target {
+		return c.renameAcrossGitservers(ctx, from, to, source, target)
+	}
+
+	req := &protocol.RepoRenameRequest{
+		From: from,
+		To:   to,
+	}
+	resp, err := c.httpPost(ctx, from, "rename", req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		// best-effort inclusion of body in error message
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 200))
+		return false, &url.Error{URL: resp.Request.URL.String(), Op: "RepoRename", Err: fmt.Errorf("RepoRename: http status %d: %s", resp.StatusCode, string(body))}
+	}
+
+	var res protocol.RepoRenameResponse
+	err = json.NewDecoder(resp.Body).Decode(&res)
+	return res.Renamed, err
+}
+
+// renameAcrossGitservers copies the clone of from on the source gitserver to
+// to on the target gitserver, which verifies the copy. The clone of from is
+// removed from source once the move grace period passed.
+func (c *Client) renameAcrossGitservers(ctx context.Context, from, to api.RepoName, source, target string) (bool, error) {
+	req := &protocol.RepoMoveRequest{
+		Repo:       from,
+		Target:     target,
+		TargetRepo: to,
+	}
+	resp, err := c.httpPost(ctx, from, "http://"+source+"/repo-move", req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound, http.StatusConflict:
+		// Nothing to move, or the new name was cloned already.
+		return false, nil
+	default:
+		// best-effort inclusion of body in error message
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 200))
+		return false, &url.Error{URL: resp.Request.URL.String(), Op: "RepoRename", Err: fmt.Errorf("RepoRename: http status %d: %s", resp.StatusCode, string(body))}
+	}
+}
+
 func (c *Client) httpPost(ctx context.Context, repo api.RepoName, op string, payload interface{}) (resp *http.Response, err error) {
 	return c.do(ctx, repo, "POST", op, payload)
 }
@@ -57,6 +57,139 @@ func TestClient_ListCloned(t *testing.T) {
 	}
 }
 
+func TestClient_Placements(t *testing.T) {
+	addrs := []string{"gitserver-0", "gitserver-1"}
+	cli := &gitserver.Client{
+		Addrs: func(ctx context.Context) []string { return addrs },
+		Placements: func(ctx context.Context) map[string]string {
+			// repo0-b hashes to gitserver-1, and placements on unknown
+			// gitservers are ignored.
+			return map[string]string{"repo0-b": "gitserver-0", "repo1-a": "gitserver-2"}
+		},
+		HTTPClient: httpcli.DoerFunc(func(r *http.Request) (*http.Response, error) {
+			switch r.URL.String() {
+			case "http://gitserver-0/list?cloned":
+				return &http.Response{
+					Body: ioutil.NopCloser(bytes.NewBufferString(`["repo0-a", "repo0-b"]`)),
+				}, nil
+			case "http://gitserver-1/list?cloned":
+				return &http.Response{
+					Body: ioutil.NopCloser(bytes.NewBufferString(`["repo0-b", "repo1-a", "repo1-b"]`)),
+				}, nil
+			default:
+				return nil, fmt.Errorf("unexpected url: %s", r.URL.String())
+			}
+		}),
+	}
+
+	ctx := context.Background()
+	for repo, want := range map[api.RepoName]string{
+		"repo0-a": "gitserver-0",
+		"repo0-b": "gitserver-0",
+		"repo1-a": "gitserver-1",
+	} {
+		if got := cli.AddrForRepo(ctx, repo); got != want {
+			t.Errorf("%s: got gitserver %s, want %s", repo, got, want)
+		}
+	}
+
+	want := []string{"repo0-a", "repo0-b", "repo1-a", "repo1-b"}
+	got, err := cli.ListCloned(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(got)
+	if !cmp.Equal(want, got, cmpopts.EquateEmpty()) {
+		t.Errorf("mismatch for (-want +got):\n%s", cmp.Diff(want, got))
+	}
+
+	if _, err := cli.MoveRepo(ctx, "repo0-a", "gitserver-0"); err == nil {
+		t.Error("expected an error moving a repo to the gitserver it is on")
+	}
+	if _, err := cli.MoveRepo(ctx, "repo0-a", "gitserver-2"); err == nil {
+		t.Error("expected an error moving a repo to an unknown gitserver")
+	}
+}
+
+func TestClient_Rename(t *testing.T) {
+	var got []string
+	cli := &gitserver.Client{
+		Addrs: func(ctx context.Context) []string { return []string{"gitserver-0", "gitserver-1"} },
+		Placements: func(ctx context.Context) map[string]string {
+			return map[string]string{"old": "gitserver-0", "same": "gitserver-0", "other": "gitserver-1"}
+		},
+		HTTPClient: httpcli.DoerFunc(func(r *http.Request) (*http.Response, error) {
+			body, _ := ioutil.ReadAll(r.Body)
+			got = append(got, r.URL.String()+" "+string(body))
+			resp := &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(bytes.NewBufferString(`{"Renamed": true}`))}
+			if r.URL.Path == "/repo-move" {
+				resp.Body = ioutil.NopCloser(&bytes.Buffer{})
+			}
+			return resp, nil
+		}),
+	}
+
+	ctx := context.Background()
+	for _, to := range []api.RepoName{"same", "other"} {
+		renamed, err := cli.Rename(ctx, "old", to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !renamed {
+			t.Errorf("rename to %s: expected renamed", to)
+		}
+	}
+
+	want := []string{
+		`http://gitserver-0/rename {"From":"old","To":"same"}`,
+		`http://gitserver-0/repo-move {"Repo":"old","Target":"gitserver-1","TargetRepo":"other"}`,
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("mismatch for (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestClient_ExecLog(t *testing.T) {
+	addrs := []string{"gitserver-0", "gitserver-1"}
+	cli := &gitserver.Client{
+		Addrs: func(ctx context.Context) []string { return addrs },
+		HTTPClient: httpcli.DoerFunc(func(r *http.Request) (*http.Response, error) {
+			switch r.URL.String() {
+			case "http://gitserver-0/debug/exec-log?limit=2":
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(bytes.NewBufferString(`{"Entries": [{"Repo": "repo0", "Time": "2020-01-01T00:00:02Z"}], "Stats": [{"Repo": "repo0", "TotalDuration": 1}]}`)),
+				}, nil
+			case "http://gitserver-1/debug/exec-log?limit=2":
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(bytes.NewBufferString(`{"Entries": [{"Repo": "repo1", "Time": "2020-01-01T00:00:03Z"}, {"Repo": "repo1", "Time": "2020-01-01T00:00:01Z"}], "Stats": [{"Repo": "repo1", "TotalDuration": 2}, {"Repo": "repo2", "TotalDuration": 0}]}`)),
+				}, nil
+			default:
+				return nil, fmt.Errorf("unexpected url: %s", r.URL.String())
+			}
+		}),
+	}
+
+	resp, err := cli.ExecLog(context.Background(), "", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries, stats []string
+	for _, e := range resp.Entries {
+		entries = append(entries, string(e.Repo)+"@"+e.Time.Format("05"))
+	}
+	for _, s := range resp.Stats {
+		stats = append(stats, string(s.Repo))
+	}
+	if want := []string{"repo1@03", "repo0@02"}; !cmp.Equal(want, entries) {
+		t.Errorf("entries mismatch (-want +got):\n%s", cmp.Diff(want, entries))
+	}
+	if want := []string{"repo1", "repo0"}; !cmp.Equal(want, stats) {
+		t.Errorf("stats mismatch (-want +got):\n%s", cmp.Diff(want, stats))
+	}
+}
+
 func TestClient_Archive(t *testing.T) {
 	root, err := ioutil.TempDir("", t.Name())
 	if err != nil {
@@ -102,6 +102,47 @@ type RepoDeleteRequest struct {
 	Repo api.RepoName
 }
 
+// RepoRenameRequest is a request to move the clone of a repository that was
+// renamed on its code host to the location of its new name on gitserver.
+type RepoRenameRequest struct {
+	// From is the name the repository was cloned as.
+	From api.RepoName
+	// To is the new name of the repository.
+	To api.RepoName
+}
+
+// RepoRenameResponse is the response to a RepoRenameRequest.
+type RepoRenameResponse struct {
+	// Renamed is whether the clone was moved. It is false if the repository
+	// wasn't cloned under its old name, or was already cloned under its new
+	// name.
+	Renamed bool
+}
+
+// RepoMoveRequest is a request to copy the clone of a repository to another
+// gitserver, which verifies the copy before replacing its own clone of the
+// repository (if any) with it.
+type RepoMoveRequest struct {
+	// Repo is the repository to copy.
+	Repo api.RepoName
+	// Target is the address of the gitserver to copy the clone to.
+	Target string
+	// TargetRepo, if set, is the name of the copy on the target gitserver,
+	// for repositories that were renamed. Unlike moves, renames keep the
+	// clone of TargetRepo on the target gitserver if it has one.
+	TargetRepo api.RepoName
+}
+
+// RepoMovedRequest is a request to mark the clone of a repository as moved to
+// another gitserver, once the repository is placed there. The clone is
+// removed an hour later.
+type RepoMovedRequest struct {
+	// Repo is the repository that was moved.
+	Repo api.RepoName
+	// Target is the address of the gitserver the repository was moved to.
+	Target string
+}
+
 // RepoInfoRequest is a request for information about multiple repositories on gitserver.
 type RepoInfoRequest struct {
 	// Repos are the repositories to get information about.
@@ -122,6 +163,31 @@ type RepoInfo struct {
 	// recloned automatically, so this time is likely to move forward
 	// periodically.
 	CloneTime *time.Time
+
+	// CloneStrategy is how the repository is cloned: "full" for clones of
+	// all refs and their contents, otherwise a comma-separated list of
+	// "blobless" for partial clones without blobs and
+	// "default-branch-and-tags" for clones of only these refs.
+	CloneStrategy string `json:",omitempty"`
+
+	// LastMaintenance is when the git maintenance tasks (repack,
+	// commit-graph and multi-pack-index) last ran on the repository, or nil
+	// if they never ran.
+	LastMaintenance *time.Time
+
+	// LastBackup is when the repository was last backed up to the backup
+	// store, or nil if it never was (or backups are disabled). It is the time
+	// of the restored backup for repositories cloned from a backup.
+	LastBackup *time.Time
+
+	// VCS is the version control system of the repository: "git", or
+	// "perforce" and "mercurial" for repositories converted to git.
+	VCS string `json:",omitempty"`
+
+	// LastSyncError is the error of the last clone or fetch of the
+	// repository, or "" if it succeeded. It is only kept in memory, so it is
+	// "" after gitserver restarts until the next sync.
+	LastSyncError string `json:",omitempty"`
 }
 
 // RepoInfoResponse is the response to a repository information request
@@ -142,6 +208,7 @@ type RepoCloneProgress struct {
 	CloneInProgress bool   // whether the repository is currently being cloned
 	CloneProgress   string // a progress message from the running clone command.
 	Cloned          bool   // whether the repository has been cloned successfully
+	CloneStrategy   string `json:",omitempty"` // how the repository is (being) cloned, see RepoInfo.CloneStrategy
 }
 
 // RepoCloneProgressResponse is the response to a repository clone progress request
@@ -222,3 +289,71 @@ type CreateCommitFromPatchError struct {
 func (e *CreateCommitFromPatchError) Error() string {
 	return e.InternalError
 }
+
+// ExecLogEntry describes a recent exec request served by gitserver.
+type ExecLogEntry struct {
+	// Time is when the request started.
+	Time time.Time
+
+	Repo api.RepoName
+	Args []string
+
+	// Client is the user agent of the client, which identifies the calling
+	// service.
+	Client string
+
+	// Trace is the ID (or URL) of the trace of the request, if it was traced.
+	Trace string `json:",omitempty"`
+
+	// Duration is the duration of the whole request, including the fetch
+	// of a missing revision.
+	Duration time.Duration
+
+	// CmdDuration is the duration of the git command, or zero if no command
+	// ran.
+	CmdDuration time.Duration
+
+	// Status is the exit status of the git command, or why no command ran
+	// (such as "clone-in-progress").
+	Status string
+
+	StdoutBytes int64
+	StderrBytes int64
+
+	// Slow is whether the git command is regarded as slow for its kind.
+	Slow bool
+}
+
+// ExecLogStats aggregates the recent exec requests of a repository that ran
+// the same kind of command.
+type ExecLogStats struct {
+	Repo api.RepoName
+
+	// Command is the git subcommand and the names of the flags it was run
+	// with (without their values), such as "log -S --format".
+	Command string
+
+	Count         int
+	SlowCount     int
+	FailureCount  int
+	TotalDuration time.Duration
+	MaxDuration   time.Duration
+	StdoutBytes   int64
+
+	// Clients are the user agents of the clients that ran the command.
+	Clients []string
+
+	// SlowestTrace is the trace of the slowest request, if it was traced.
+	SlowestTrace string `json:",omitempty"`
+}
+
+// ExecLogResponse is the response to a request for the exec log of
+// gitserver.
+type ExecLogResponse struct {
+	// Entries are the most recent exec requests, most recent first.
+	Entries []ExecLogEntry
+
+	// Stats are the aggregated exec requests, with the largest total
+	// duration first.
+	Stats []ExecLogStats
+}
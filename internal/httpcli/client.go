@@ -9,7 +9,6 @@ import (
 	"github.com/gregjones/httpcache"
 	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
-	"github.com/sourcegraph/sourcegraph/internal/httputil"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 )
 
@@ -60,18 +59,29 @@ type Factory struct {
 // NewExternalHTTPClientFactory returns an httpcli.Factory with common options
 // and middleware pre-set for communicating to external services.
 func NewExternalHTTPClientFactory() *Factory {
+	return newExternalHTTPClientFactory(conditionalCache)
+}
+
+// newExternalHTTPClientFactory returns the Factory of
+// NewExternalHTTPClientFactory with the given conditional request cache.
+//
+// It doesn't use NewCachedTransportOpt: httpcache would answer the conditional
+// requests with its own copy of the response, hiding the 304 Not Modified
+// responses from the conditional request middleware and storing every
+// response twice. It would also serve fresh responses without a request, whose
+// stale rate limit headers are ignored by the rate limit monitors.
+func newExternalHTTPClientFactory(c httpcache.Cache) *Factory {
 	return NewFactory(
 		// TODO(tsenart): Use middle for Prometheus instrumentation later.
 		NewMiddleware(
 			ContextErrorMiddleware,
+			NewConditionalRequestMiddleware(c),
 		),
 		NewTimeoutOpt(60*time.Second),
-		// ExternalTransportOpt needs to be before TracedTransportOpt and
-		// NewCachedTransportOpt since it wants to extract a http.Transport,
-		// not a generic http.RoundTripper.
+		// ExternalTransportOpt needs to be before TracedTransportOpt since it
+		// wants to extract a http.Transport, not a generic http.RoundTripper.
 		ExternalTransportOpt,
 		TracedTransportOpt,
-		NewCachedTransportOpt(httputil.Cache, true),
 	)
 }
 
@@ -0,0 +1,195 @@
+package httpcli
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/gregjones/httpcache"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sourcegraph/sourcegraph/internal/rcache"
+)
+
+// XFromConditionalCache is the header set on the responses that
+// ConditionalRequestMiddleware served from its cache. Unlike the ones marked
+// with httpcache.XFromCache, these responses were revalidated with the server
+// and their other headers, such as the ones reporting the rate limit, are up
+// to date.
+const XFromConditionalCache = "X-From-Conditional-Cache"
+
+// conditionalCache stores the responses of ConditionalRequestMiddleware. The
+// TTL of a week matches the one of httputil.Cache.
+var conditionalCache = rcache.NewWithTTL("http-conditional", 604800)
+
+// maxConditionalBodySize is the maximum size of a response body stored by
+// ConditionalRequestMiddleware. Larger responses are passed through as is.
+const maxConditionalBodySize = 16 * 1024 * 1024
+
+// conditionalKeyHeaders are the request headers that distinguish the cache
+// entries of requests to the same URL, so that responses are never shared
+// between different tokens or representations.
+var conditionalKeyHeaders = []string{"Authorization", "Private-Token", "Accept"}
+
+var conditionalRequestsCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "src_httpcli_conditional_requests_total",
+	Help: "Counts cacheable GET requests to external services by whether a 304 Not Modified response was served from the cache (hit) or not (miss).",
+}, []string{"host", "result"})
+
+var conditionalRateLimitSavedCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "src_httpcli_conditional_requests_rate_limit_saved_total",
+	Help: "Counts responses served from the cache by external services reporting a rate limit, such as GitHub, which doesn't count 304 Not Modified responses against it.",
+}, []string{"host"})
+
+func init() {
+	prometheus.MustRegister(conditionalRequestsCounter)
+	prometheus.MustRegister(conditionalRateLimitSavedCounter)
+}
+
+// conditionalEntry is a cached response of ConditionalRequestMiddleware.
+type conditionalEntry struct {
+	Header http.Header
+	Body   []byte
+}
+
+// NewConditionalRequestMiddleware returns a Middleware that makes GET requests
+// conditional on the ETag and Last-Modified date of the last response to the
+// same URL with the same credentials, which are stored in the given Cache.
+// When the server answers with 304 Not Modified, the stored response is
+// returned instead, so that clients transparently see a 200 OK marked with the
+// XFromConditionalCache header.
+func NewConditionalRequestMiddleware(c httpcache.Cache) Middleware {
+	return func(cli Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if !isConditionalCacheable(req) {
+				return cli.Do(req)
+			}
+
+			key := conditionalCacheKey(req)
+			entry, ok := getConditionalEntry(c, key)
+			if ok {
+				req = req.Clone(req.Context())
+				if etag := entry.Header.Get("ETag"); etag != "" {
+					req.Header.Set("If-None-Match", etag)
+				}
+				if lastModified := entry.Header.Get("Last-Modified"); lastModified != "" {
+					req.Header.Set("If-Modified-Since", lastModified)
+				}
+			}
+
+			resp, err := cli.Do(req)
+			if err != nil {
+				return resp, err
+			}
+
+			host := req.URL.Host
+			if ok && resp.StatusCode == http.StatusNotModified {
+				conditionalRequestsCounter.WithLabelValues(host, "hit").Inc()
+				if resp.Header.Get("X-RateLimit-Remaining") != "" {
+					conditionalRateLimitSavedCounter.WithLabelValues(host).Inc()
+				}
+				return notModifiedResponse(resp, entry), nil
+			}
+
+			conditionalRequestsCounter.WithLabelValues(host, "miss").Inc()
+			if resp.StatusCode != http.StatusOK || !isConditionalStorable(resp) {
+				if ok {
+					c.Delete(key)
+				}
+				return resp, nil
+			}
+			return storeConditionalEntry(c, key, resp)
+		})
+	}
+}
+
+// isConditionalCacheable returns true for the requests whose responses can
+// be cached by ConditionalRequestMiddleware.
+func isConditionalCacheable(req *http.Request) bool {
+	return req.Method == "GET" &&
+		req.Header.Get("Range") == "" &&
+		req.Header.Get("If-None-Match") == "" &&
+		req.Header.Get("If-Modified-Since") == ""
+}
+
+// isConditionalStorable returns true if the response can be revalidated with
+// a conditional request and may be stored.
+func isConditionalStorable(resp *http.Response) bool {
+	if strings.Contains(strings.ToLower(resp.Header.Get("Cache-Control")), "no-store") {
+		return false
+	}
+	return resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != ""
+}
+
+// conditionalCacheKey returns the key of the request's cache entry. The
+// credentials are hashed so that they aren't stored in the cache.
+func conditionalCacheKey(req *http.Request) string {
+	h := sha256.New()
+	for _, name := range conditionalKeyHeaders {
+		io.WriteString(h, name+": "+req.Header.Get(name)+"\n")
+	}
+	return req.URL.String() + " " + hex.EncodeToString(h.Sum(nil))
+}
+
+func getConditionalEntry(c httpcache.Cache, key string) (*conditionalEntry, bool) {
+	b, ok := c.Get(key)
+	if !ok {
+		return nil, false
+	}
+	var e conditionalEntry
+	if err := json.Unmarshal(b, &e); err != nil {
+		c.Delete(key)
+		return nil, false
+	}
+	return &e, true
+}
+
+// storeConditionalEntry reads the body of the response and stores it in the
+// cache, returning a response whose body can still be read by the caller.
+func storeConditionalEntry(c httpcache.Cache, key string, resp *http.Response) (*http.Response, error) {
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxConditionalBodySize+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	if len(body) > maxConditionalBodySize {
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	if b, err := json.Marshal(conditionalEntry{Header: resp.Header, Body: body}); err == nil {
+		c.Set(key, b)
+	}
+	return resp, nil
+}
+
+// notModifiedResponse turns the 304 Not Modified response into the stored 200
+// OK response, updated with the headers of the 304 response, such as the ones
+// reporting the rate limit.
+func notModifiedResponse(resp *http.Response, entry *conditionalEntry) *http.Response {
+	resp.Body.Close()
+
+	header := entry.Header
+	for name, values := range resp.Header {
+		header[name] = values
+	}
+	header.Del("Content-Length")
+	header.Set(XFromConditionalCache, "1")
+
+	resp.Status = "200 OK"
+	resp.StatusCode = http.StatusOK
+	resp.Header = header
+	resp.ContentLength = int64(len(entry.Body))
+	resp.Body = ioutil.NopCloser(bytes.NewReader(entry.Body))
+	return resp
+}
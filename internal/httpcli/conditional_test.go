@@ -0,0 +1,170 @@
+package httpcli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gregjones/httpcache"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestConditionalRequestMiddleware(t *testing.T) {
+	var (
+		version  = 1
+		requests []string
+	)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, fmt.Sprintf("%s %s %s", r.Method, r.Header.Get("Authorization"), r.Header.Get("If-None-Match")))
+
+		etag := fmt.Sprintf(`"v%d"`, version)
+		w.Header().Set("ETag", etag)
+		w.Header().Set("X-RateLimit-Remaining", fmt.Sprint(5000-len(requests)))
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fmt.Fprintf(w, "repos v%d", version)
+	}))
+	defer s.Close()
+
+	cli := NewConditionalRequestMiddleware(httpcache.NewMemoryCache())(http.DefaultClient)
+
+	do := func(method, token string) (body string, header http.Header) {
+		t.Helper()
+		req, err := http.NewRequest(method, s.URL+"/repos", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", token)
+		resp, err := cli.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status %d", resp.StatusCode)
+		}
+		b, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b), resp.Header
+	}
+
+	for i, tc := range []struct {
+		method, token string
+		bump          bool
+		body          string
+		cached        bool
+		rateLimit     string
+	}{
+		{method: "GET", token: "a", body: "repos v1", rateLimit: "4999"},
+		{method: "GET", token: "a", body: "repos v1", cached: true, rateLimit: "4998"},
+		{method: "GET", token: "b", body: "repos v1", rateLimit: "4997"},
+		{method: "POST", token: "a", body: "repos v1", rateLimit: "4996"},
+		{method: "GET", token: "a", bump: true, body: "repos v2", rateLimit: "4995"},
+		{method: "GET", token: "a", body: "repos v2", cached: true, rateLimit: "4994"},
+	} {
+		if tc.bump {
+			version++
+		}
+		body, header := do(tc.method, tc.token)
+		if body != tc.body {
+			t.Errorf("%d: have body %q, want %q", i, body, tc.body)
+		}
+		if cached := header.Get(XFromConditionalCache) != ""; cached != tc.cached {
+			t.Errorf("%d: have cached %v, want %v", i, cached, tc.cached)
+		}
+		if have := header.Get("X-RateLimit-Remaining"); have != tc.rateLimit {
+			t.Errorf("%d: have rate limit remaining %q, want %q", i, have, tc.rateLimit)
+		}
+	}
+
+	want := []string{
+		"GET a ",
+		`GET a "v1"`,
+		"GET b ",
+		"POST a ",
+		`GET a "v1"`,
+		`GET a "v2"`,
+	}
+	if strings.Join(requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected requests:\nhave %q\nwant %q", requests, want)
+	}
+}
+
+func TestExternalHTTPClientFactoryConditionalRequests(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		ExperimentalFeatures: &schema.ExperimentalFeatures{},
+	}})
+	defer conf.Mock(nil)
+
+	var requests []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Header.Get("If-None-Match"))
+
+		// Responses of code hosts are usually cacheable for a while, which
+		// must not keep the requests from reaching the server.
+		w.Header().Set("Cache-Control", "private, max-age=60")
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("X-RateLimit-Remaining", fmt.Sprint(5000-len(requests)))
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fmt.Fprint(w, "repos v1")
+	}))
+	defer s.Close()
+
+	cli, err := newExternalHTTPClientFactory(httpcache.NewMemoryCache()).Doer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, wantCached := range []bool{false, true, true} {
+		req, err := http.NewRequest("GET", s.URL+"/repos", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := cli.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resp.StatusCode != http.StatusOK || string(b) != "repos v1" {
+			t.Errorf("%d: unexpected response %d %q", i, resp.StatusCode, b)
+		}
+		if cached := resp.Header.Get(XFromConditionalCache) != ""; cached != wantCached {
+			t.Errorf("%d: have cached %v, want %v", i, cached, wantCached)
+		}
+		if resp.Header.Get(httpcache.XFromCache) != "" {
+			t.Errorf("%d: unexpected response served by httpcache", i)
+		}
+	}
+
+	if want := []string{"", `"v1"`, `"v1"`}; strings.Join(requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected requests:\nhave %q\nwant %q", requests, want)
+	}
+
+	host := strings.TrimPrefix(s.URL, "http://")
+	if have := testutil.ToFloat64(conditionalRequestsCounter.WithLabelValues(host, "hit")); have != 2 {
+		t.Errorf("have %v hits, want 2", have)
+	}
+	if have := testutil.ToFloat64(conditionalRequestsCounter.WithLabelValues(host, "miss")); have != 1 {
+		t.Errorf("have %v misses, want 1", have)
+	}
+	if have := testutil.ToFloat64(conditionalRateLimitSavedCounter.WithLabelValues(host)); have != 2 {
+		t.Errorf("have %v rate limit saved, want 2", have)
+	}
+}
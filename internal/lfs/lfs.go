@@ -0,0 +1,74 @@
+// Package lfs parses Git LFS pointer files.
+//
+// Repositories using Git LFS store pointer files in git instead of the
+// content of large files. The content is stored on the LFS server of the code
+// host and is addressed by the SHA-256 of the content. See
+// https://github.com/git-lfs/git-lfs/blob/master/docs/spec.md
+package lfs
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// MaxPointerSize is the maximum size of a pointer file. Larger files are
+// never pointers.
+const MaxPointerSize = 1024
+
+// Pointer is a parsed Git LFS pointer file.
+type Pointer struct {
+	// OID is the hex-encoded SHA-256 of the content of the file.
+	OID string
+
+	// Size is the size of the content of the file in bytes.
+	Size int64
+}
+
+var versionLine = []byte("version https://git-lfs.github.com/spec/v1")
+
+// ParsePointer parses the content of a file as a pointer. It returns false if
+// the content is not a pointer.
+func ParsePointer(content []byte) (*Pointer, bool) {
+	if len(content) > MaxPointerSize || !bytes.HasPrefix(content, versionLine) {
+		return nil, false
+	}
+
+	var p Pointer
+	hasSize := false
+	for _, line := range bytes.Split(content, []byte("\n")) {
+		key, value := line, []byte(nil)
+		if i := bytes.IndexByte(line, ' '); i >= 0 {
+			key, value = line[:i], line[i+1:]
+		}
+		switch string(key) {
+		case "oid":
+			if !bytes.HasPrefix(value, []byte("sha256:")) {
+				return nil, false
+			}
+			p.OID = string(value[len("sha256:"):])
+		case "size":
+			size, err := strconv.ParseInt(string(value), 10, 64)
+			if err != nil || size < 0 {
+				return nil, false
+			}
+			p.Size = size
+			hasSize = true
+		}
+	}
+	if !validOID(p.OID) || !hasSize {
+		return nil, false
+	}
+	return &p, true
+}
+
+func validOID(oid string) bool {
+	if len(oid) != 64 {
+		return false
+	}
+	for _, c := range oid {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,35 @@
+package lfs
+
+import (
+	"strings"
+	"testing"
+)
+
+const oid = "4d7a214614ab2935c943f9e0ff69d22eadbb8f32b1258daaa5e2ca24d17e2393"
+
+func TestParsePointer(t *testing.T) {
+	p, ok := ParsePointer([]byte("version https://git-lfs.github.com/spec/v1\noid sha256:" + oid + "\nsize 12345\n"))
+	if !ok {
+		t.Fatal("expected a pointer")
+	}
+	if want := (Pointer{OID: oid, Size: 12345}); *p != want {
+		t.Fatalf("got %+v, want %+v", *p, want)
+	}
+}
+
+func TestParsePointer_notPointer(t *testing.T) {
+	for name, content := range map[string]string{
+		"empty":      "",
+		"text":       "hello world\n",
+		"no oid":     "version https://git-lfs.github.com/spec/v1\nsize 1\n",
+		"no size":    "version https://git-lfs.github.com/spec/v1\noid sha256:" + oid + "\n",
+		"bad size":   "version https://git-lfs.github.com/spec/v1\noid sha256:" + oid + "\nsize -1\n",
+		"bad oid":    "version https://git-lfs.github.com/spec/v1\noid sha256:abc\nsize 1\n",
+		"other hash": "version https://git-lfs.github.com/spec/v1\noid sha1:" + oid + "\nsize 1\n",
+		"too large":  "version https://git-lfs.github.com/spec/v1\noid sha256:" + oid + "\nsize 1\n" + strings.Repeat("x", MaxPointerSize),
+	} {
+		if p, ok := ParsePointer([]byte(content)); ok {
+			t.Errorf("%s: expected no pointer, got %+v", name, p)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package objectstorage
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+type localStore struct {
+	root string
+}
+
+var _ Store = &localStore{}
+
+// NewLocalStore creates a store backed by the given directory. This is useful when
+// the directory is a volume shared by all replicas of a service.
+func NewLocalStore(root string) (Store, error) {
+	if err := os.MkdirAll(root, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	return &localStore{root: root}, nil
+}
+
+// Get returns a reader for the object with the given key.
+func (s *localStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	f, err := os.Open(s.filename(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotExist
+		}
+
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Upload writes the content of the given reader to the object with the given key. The
+// content is written to a temporary file which is moved into place once complete so that
+// concurrent readers never observe a partial object.
+func (s *localStore) Upload(ctx context.Context, key string, r io.Reader) (err error) {
+	filename := s.filename(key)
+	if err := os.MkdirAll(filepath.Dir(filename), os.ModePerm); err != nil {
+		return err
+	}
+
+	tempFile, err := ioutil.TempFile(filepath.Dir(filename), ".upload-")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tempFile.Name())
+		}
+	}()
+
+	if _, err := io.Copy(tempFile, r); err != nil {
+		if closeErr := tempFile.Close(); closeErr != nil {
+			err = multierror.Append(err, closeErr)
+		}
+		return err
+	}
+
+	if err := tempFile.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tempFile.Name(), filename)
+}
+
+// Delete removes the object with the given key.
+func (s *localStore) Delete(ctx context.Context, key string) error {
+	if err := os.Remove(s.filename(key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// List returns the objects whose key begins with the given prefix.
+func (s *localStore) List(ctx context.Context, prefix string) ([]Object, error) {
+	// Only walk the deepest directory that can contain matching keys
+	dir := prefix
+	if !strings.HasSuffix(dir, "/") {
+		dir = path.Dir(dir)
+	}
+
+	var objects []Object
+	err := filepath.Walk(s.filename(dir), func(filename string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+
+			return err
+		}
+
+		if info.IsDir() || strings.HasPrefix(info.Name(), ".upload-") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.root, filename)
+		if err != nil {
+			return err
+		}
+
+		if key := filepath.ToSlash(rel); strings.HasPrefix(key, prefix) {
+			objects = append(objects, Object{
+				Key:          key,
+				Size:         info.Size(),
+				LastModified: info.ModTime(),
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+func (s *localStore) filename(key string) string {
+	return filepath.Join(s.root, filepath.FromSlash(key))
+}
@@ -0,0 +1,84 @@
+package objectstorage
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLocalStore(t *testing.T) {
+	root, err := ioutil.TempDir("", "objectstorage-")
+	if err != nil {
+		t.Fatalf("unexpected error creating test directory: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(root) })
+
+	store, err := NewLocalStore(root)
+	if err != nil {
+		t.Fatalf("unexpected error creating store: %s", err)
+	}
+
+	ctx := context.Background()
+	for key, content := range map[string]string{
+		"uploads/1.gz":     "upload 1",
+		"uploads/2.gz":     "upload 2",
+		"dbs/1/sqlite.db":  "db 1",
+		"dbs/10/sqlite.db": "db 10",
+	} {
+		if err := store.Upload(ctx, key, strings.NewReader(content)); err != nil {
+			t.Fatalf("unexpected error uploading %s: %s", key, err)
+		}
+	}
+
+	rc, err := store.Get(ctx, "dbs/10/sqlite.db")
+	if err != nil {
+		t.Fatalf("unexpected error getting object: %s", err)
+	}
+	content, err := ioutil.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		t.Fatalf("unexpected error reading object: %s", err)
+	}
+	if string(content) != "db 10" {
+		t.Errorf("unexpected content. want=%q have=%q", "db 10", content)
+	}
+
+	if _, err := store.Get(ctx, "dbs/2/sqlite.db"); err != ErrNotExist {
+		t.Errorf("unexpected error getting missing object. want=%q have=%q", ErrNotExist, err)
+	}
+
+	if err := store.Delete(ctx, "uploads/1.gz"); err != nil {
+		t.Fatalf("unexpected error deleting object: %s", err)
+	}
+	if err := store.Delete(ctx, "uploads/1.gz"); err != nil {
+		t.Fatalf("unexpected error deleting missing object: %s", err)
+	}
+
+	for prefix, expectedKeys := range map[string][]string{
+		"uploads/": {"uploads/2.gz"},
+		"dbs/":     {"dbs/1/sqlite.db", "dbs/10/sqlite.db"},
+		"dbs/1":    {"dbs/1/sqlite.db", "dbs/10/sqlite.db"},
+		"dbs/1/":   {"dbs/1/sqlite.db"},
+		"missing/": nil,
+	} {
+		objects, err := store.List(ctx, prefix)
+		if err != nil {
+			t.Fatalf("unexpected error listing objects: %s", err)
+		}
+
+		var keys []string
+		for _, object := range objects {
+			keys = append(keys, object.Key)
+		}
+		sort.Strings(keys)
+
+		if diff := cmp.Diff(expectedKeys, keys); diff != "" {
+			t.Errorf("unexpected keys for prefix %q (-want +got):\n%s", prefix, diff)
+		}
+	}
+}
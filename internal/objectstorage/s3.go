@@ -0,0 +1,162 @@
+package objectstorage
+
+import (
+	"context"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/awserr"
+	"github.com/aws/aws-sdk-go-v2/aws/external"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/s3manager"
+	"github.com/pkg/errors"
+)
+
+// S3Config configures a store backed by an S3-compatible object storage service.
+type S3Config struct {
+	// Bucket is the name of the bucket in which objects are stored.
+	Bucket string
+
+	// Endpoint overrides the default AWS endpoint. This allows the use of other
+	// S3-compatible services such as MinIO or the GCS interoperability API.
+	Endpoint string
+
+	// Region is the region of the bucket.
+	Region string
+
+	// AccessKeyID and SecretAccessKey are static credentials. If unset, credentials
+	// are read from the default AWS credential chain.
+	AccessKeyID     string
+	SecretAccessKey string
+
+	// ForcePathStyle addresses buckets by path instead of by subdomain, which is
+	// required by most self-hosted S3-compatible services.
+	ForcePathStyle bool
+}
+
+type s3Store struct {
+	bucket   string
+	client   *s3.Client
+	uploader *s3manager.Uploader
+}
+
+var _ Store = &s3Store{}
+
+// NewS3Store creates a store backed by an S3-compatible object storage service.
+func NewS3Store(config S3Config) (Store, error) {
+	if config.Bucket == "" {
+		return nil, errors.New("no bucket supplied")
+	}
+
+	awsConfig, err := external.LoadDefaultAWSConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "external.LoadDefaultAWSConfig")
+	}
+
+	if config.Region != "" {
+		awsConfig.Region = config.Region
+	}
+	if config.Endpoint != "" {
+		awsConfig.EndpointResolver = aws.ResolveWithEndpointURL(config.Endpoint)
+	}
+	if config.AccessKeyID != "" {
+		awsConfig.Credentials = aws.StaticCredentialsProvider{
+			Value: aws.Credentials{
+				AccessKeyID:     config.AccessKeyID,
+				SecretAccessKey: config.SecretAccessKey,
+				Source:          "objectstorage",
+			},
+		}
+	}
+
+	client := s3.New(awsConfig)
+	client.ForcePathStyle = config.ForcePathStyle
+
+	return &s3Store{
+		bucket:   config.Bucket,
+		client:   client,
+		uploader: s3manager.NewUploaderWithClient(client),
+	}, nil
+}
+
+// Get returns a reader for the object with the given key.
+func (s *s3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}).Send(ctx)
+	if err != nil {
+		if isNoSuchKey(err) {
+			return nil, ErrNotExist
+		}
+
+		return nil, errors.Wrap(err, "s3.GetObject")
+	}
+
+	return resp.Body, nil
+}
+
+// Upload writes the content of the given reader to the object with the given key. Large
+// objects are transparently uploaded in several parts.
+func (s *s3Store) Upload(ctx context.Context, key string, r io.Reader) error {
+	if _, err := s.uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Body:   r,
+	}); err != nil {
+		return errors.Wrap(err, "s3manager.Upload")
+	}
+
+	return nil
+}
+
+// Delete removes the object with the given key.
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	if _, err := s.client.DeleteObjectRequest(&s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}).Send(ctx); err != nil && !isNoSuchKey(err) {
+		return errors.Wrap(err, "s3.DeleteObject")
+	}
+
+	return nil
+}
+
+// List returns the objects whose key begins with the given prefix.
+func (s *s3Store) List(ctx context.Context, prefix string) ([]Object, error) {
+	paginator := s3.NewListObjectsV2Paginator(s.client.ListObjectsV2Request(&s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	}))
+
+	var objects []Object
+	for paginator.Next(ctx) {
+		for _, object := range paginator.CurrentPage().Contents {
+			objects = append(objects, Object{
+				Key:          aws.StringValue(object.Key),
+				Size:         aws.Int64Value(object.Size),
+				LastModified: aws.TimeValue(object.LastModified),
+			})
+		}
+	}
+
+	if err := paginator.Err(); err != nil {
+		return nil, errors.Wrap(err, "s3.ListObjectsV2")
+	}
+
+	return objects, nil
+}
+
+// isNoSuchKey returns true if the error is a response for a missing object. Services
+// respond with NotFound instead of NoSuchKey to requests without a response body, such
+// as HEAD requests.
+func isNoSuchKey(err error) bool {
+	if awsErr, ok := err.(awserr.Error); ok {
+		switch awsErr.Code() {
+		case s3.ErrCodeNoSuchKey, "NotFound":
+			return true
+		}
+	}
+
+	return false
+}
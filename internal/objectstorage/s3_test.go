@@ -0,0 +1,172 @@
+package objectstorage
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeS3 is an in-memory S3 service that supports the requests of s3Store.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)
+	if parts[0] != "bucket" {
+		writeS3Error(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+	var key string
+	if len(parts) == 2 {
+		key = parts[1]
+	}
+
+	switch {
+	case r.Method == "GET" && key == "":
+		type content struct {
+			Key          string
+			LastModified string
+			Size         int
+		}
+		result := struct {
+			XMLName     xml.Name `xml:"ListBucketResult"`
+			Name        string
+			KeyCount    int
+			IsTruncated bool
+			Contents    []content
+		}{Name: "bucket"}
+		for k, v := range f.objects {
+			if strings.HasPrefix(k, r.URL.Query().Get("prefix")) {
+				result.Contents = append(result.Contents, content{Key: k, LastModified: "2020-01-01T00:00:00.000Z", Size: len(v)})
+			}
+		}
+		result.KeyCount = len(result.Contents)
+		_ = xml.NewEncoder(w).Encode(result)
+
+	case r.Method == "GET":
+		content, ok := f.objects[key]
+		if !ok {
+			writeS3Error(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		_, _ = w.Write([]byte(content))
+
+	case r.Method == "PUT":
+		content, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		f.objects[key] = string(content)
+
+	case r.Method == "DELETE":
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+	}
+}
+
+func writeS3Error(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, code)
+}
+
+func TestS3Store(t *testing.T) {
+	ts := httptest.NewServer(&fakeS3{objects: map[string]string{}})
+	defer ts.Close()
+
+	newStore := func(bucket string) Store {
+		store, err := NewS3Store(S3Config{
+			Bucket:          bucket,
+			Endpoint:        ts.URL,
+			Region:          "us-east-1",
+			AccessKeyID:     "key",
+			SecretAccessKey: "secret",
+			ForcePathStyle:  true,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error creating store: %s", err)
+		}
+		return store
+	}
+	store := newStore("bucket")
+
+	ctx := context.Background()
+	for key, content := range map[string]string{
+		"uploads/1.gz":     "upload 1",
+		"uploads/2.gz":     "upload 2",
+		"dbs/1/sqlite.db":  "db 1",
+		"dbs/10/sqlite.db": "db 10",
+	} {
+		if err := store.Upload(ctx, key, strings.NewReader(content)); err != nil {
+			t.Fatalf("unexpected error uploading %s: %s", key, err)
+		}
+	}
+
+	rc, err := store.Get(ctx, "dbs/10/sqlite.db")
+	if err != nil {
+		t.Fatalf("unexpected error getting object: %s", err)
+	}
+	content, err := ioutil.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		t.Fatalf("unexpected error reading object: %s", err)
+	}
+	if string(content) != "db 10" {
+		t.Errorf("unexpected content. want=%q have=%q", "db 10", content)
+	}
+
+	if _, err := store.Get(ctx, "dbs/2/sqlite.db"); err != ErrNotExist {
+		t.Errorf("unexpected error getting missing object. want=%q have=%q", ErrNotExist, err)
+	}
+	// Other errors, such as a missing bucket, are not mistaken for missing objects.
+	if _, err := newStore("missing").Get(ctx, "dbs/1/sqlite.db"); err == nil || err == ErrNotExist {
+		t.Errorf("unexpected error getting object from missing bucket. have=%v", err)
+	}
+
+	if err := store.Delete(ctx, "uploads/1.gz"); err != nil {
+		t.Fatalf("unexpected error deleting object: %s", err)
+	}
+	if err := store.Delete(ctx, "uploads/1.gz"); err != nil {
+		t.Fatalf("unexpected error deleting missing object: %s", err)
+	}
+
+	for prefix, expectedKeys := range map[string][]string{
+		"uploads/": {"uploads/2.gz"},
+		"dbs/":     {"dbs/1/sqlite.db", "dbs/10/sqlite.db"},
+		"dbs/1/":   {"dbs/1/sqlite.db"},
+		"missing/": nil,
+	} {
+		objects, err := store.List(ctx, prefix)
+		if err != nil {
+			t.Fatalf("unexpected error listing objects: %s", err)
+		}
+
+		var keys []string
+		for _, object := range objects {
+			keys = append(keys, object.Key)
+		}
+		sort.Strings(keys)
+
+		if diff := cmp.Diff(expectedKeys, keys); diff != "" {
+			t.Errorf("unexpected keys for prefix %q (-want +got):\n%s", prefix, diff)
+		}
+	}
+}
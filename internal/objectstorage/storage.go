@@ -0,0 +1,39 @@
+// Package objectstorage stores objects durably in a local directory or an
+// S3-compatible object storage service.
+package objectstorage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrNotExist occurs when a requested object does not exist in the store.
+var ErrNotExist = errors.New("object does not exist")
+
+// Store is a durable location for objects such as LSIF bundles or repository
+// backups, which can be shared by several replicas of a service.
+type Store interface {
+	// Get returns a reader for the object with the given key. If no such object
+	// exists, ErrNotExist is returned.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Upload writes the content of the given reader to the object with the given key,
+	// replacing any previous content.
+	Upload(ctx context.Context, key string, r io.Reader) error
+
+	// Delete removes the object with the given key. Removing an object that does not
+	// exist is not an error.
+	Delete(ctx context.Context, key string) error
+
+	// List returns the objects whose key begins with the given prefix.
+	List(ctx context.Context, prefix string) ([]Object, error)
+}
+
+// Object describes an object in a store.
+type Object struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
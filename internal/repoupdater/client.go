@@ -232,6 +232,42 @@ func (c *Client) SchedulePermsSync(ctx context.Context, args protocol.PermsSyncR
 	return errors.New(res.Error)
 }
 
+// ForwardWebhook forwards the webhook request of a code host of the given kind
+// ("github", "gitlab" or "bitbucket-server") with the given payload to the
+// repo-updater, which applies the repository events it contains. It returns
+// the status code of the repo-updater's response.
+func (c *Client) ForwardWebhook(ctx context.Context, kind string, r *http.Request, payload []byte) (int, error) {
+	u := c.URL + "/webhooks/" + kind
+	if r.URL.RawQuery != "" {
+		u += "?" + r.URL.RawQuery
+	}
+
+	req, err := http.NewRequest("POST", u, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	for k, vs := range r.Header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		bs, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return resp.StatusCode, errors.Wrap(err, "read response body")
+		}
+		return resp.StatusCode, errors.New(string(bs))
+	}
+	return resp.StatusCode, nil
+}
+
 // SyncExternalService requests the given external service to be synced.
 func (c *Client) SyncExternalService(ctx context.Context, svc api.ExternalService) (*protocol.ExternalServiceSyncResult, error) {
 	req := &protocol.ExternalServiceSyncRequest{ExternalService: svc}
@@ -265,6 +301,56 @@ func (c *Client) SyncExternalService(ctx context.Context, svc api.ExternalServic
 	return &result, nil
 }
 
+// ExternalServiceDryRun returns the names of the repositories that syncing the
+// given external service would add, modify and delete, at most sampleSize of
+// each if it is positive. The external service doesn't need to be saved, which
+// allows checking a configuration change before saving it.
+func (c *Client) ExternalServiceDryRun(ctx context.Context, svc api.ExternalService, sampleSize int) (*protocol.ExternalServiceDryRunResult, error) {
+	req := &protocol.ExternalServiceDryRunRequest{ExternalService: svc, SampleSize: sampleSize}
+	resp, err := c.httpPost(ctx, "external-service-dry-run", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	bs, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read response body")
+	}
+
+	var result protocol.ExternalServiceDryRunResult
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return nil, errors.New(string(bs))
+	} else if err = json.Unmarshal(bs, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RestoreRepo restores the repository with the given name, if it was deleted
+// within the purge grace period.
+func (c *Client) RestoreRepo(ctx context.Context, name api.RepoName) (*protocol.RestoreRepoResponse, error) {
+	req := &protocol.RestoreRepoRequest{Repo: name}
+	resp, err := c.httpPost(ctx, "restore-repo", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	bs, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read response body")
+	}
+
+	var res protocol.RestoreRepoResponse
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return nil, errors.New(string(bs))
+	} else if err = json.Unmarshal(bs, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
 // RepoExternalServices requests the external services associated with a
 // repository with the given id.
 func (c *Client) RepoExternalServices(ctx context.Context, id api.RepoID) ([]api.ExternalService, error) {
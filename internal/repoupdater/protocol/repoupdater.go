@@ -26,6 +26,13 @@ type RepoScheduleState struct {
 	Total           int
 	IntervalSeconds int
 	Due             time.Time
+
+	// Class is the name of the update class of the repo, or empty if it
+	// belongs to none. The other fields below are the SLA of the class.
+	Class              string `json:",omitempty"`
+	MinIntervalSeconds int
+	MaxIntervalSeconds int
+	Priority           int
 }
 
 type RepoQueueState struct {
@@ -59,6 +66,19 @@ type ExcludeRepoResponse struct {
 	ExternalServices []api.ExternalService
 }
 
+// RestoreRepoRequest is a request to restore a repository that was deleted
+// because no external service yields it anymore.
+type RestoreRepoRequest struct {
+	// Repo is the name the repository had before it was deleted.
+	Repo api.RepoName
+}
+
+// RestoreRepoResponse is returned in response to a RestoreRepoRequest.
+type RestoreRepoResponse struct {
+	ID   api.RepoID
+	Name string
+}
+
 // RepoLookupArgs is a request for information about a repository on repoupdater.
 //
 // Exactly one of Repo and ExternalRepo should be set.
@@ -196,6 +216,33 @@ type ExternalServiceSyncResult struct {
 	Error           string
 }
 
+// ExternalServiceDryRunRequest is a request to list the repositories that
+// syncing an external service with the given, possibly unsaved, configuration
+// would add, modify and remove.
+type ExternalServiceDryRunRequest struct {
+	ExternalService api.ExternalService
+	// SampleSize limits the number of names in each list of the result if it
+	// is positive. The counts of the result are never limited.
+	SampleSize int
+}
+
+// ExternalServiceDryRunResult is the result of an ExternalServiceDryRunRequest.
+type ExternalServiceDryRunResult struct {
+	// Added, Modified and Deleted are the names of the repositories that
+	// would be added, modified and deleted, ordered by name.
+	Added    []string
+	Modified []string
+	Deleted  []string
+
+	// AddedCount, ModifiedCount, DeletedCount and UnmodifiedCount are the
+	// numbers of repositories that would be added, modified, deleted and left
+	// unmodified.
+	AddedCount      int
+	ModifiedCount   int
+	DeletedCount    int
+	UnmodifiedCount int
+}
+
 type CloningProgress struct {
 	Message string
 }
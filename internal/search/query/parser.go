@@ -633,6 +633,15 @@ func (p *parser) ParseParameter() (Parameter, bool, error) {
 	}
 
 	p.pos += advance
+	if f := strings.ToLower(field); f == FieldRepo || f == "r" {
+		// Repo predicates like has.topic(payments) contain parentheses
+		// that must not be parsed as a group.
+		if n := scanRepoPredicate(p.buf[p.pos:]); n > 0 {
+			value := string(p.buf[p.pos : p.pos+n])
+			p.pos += n
+			return Parameter{Field: field, Value: value, Negated: negated}, true, nil
+		}
+	}
 	value, err := p.ParseFieldValue()
 	if err != nil {
 		return Parameter{}, false, err
@@ -662,6 +662,16 @@ func TestParse(t *testing.T) {
 			WantGrammar:   `(and "repo:foo bar" ":\\")`,
 			WantHeuristic: Same,
 		},
+		{
+			Input:         `repo:has.topic(payments) foo`,
+			WantGrammar:   `(and "repo:has.topic(payments)" "foo")`,
+			WantHeuristic: Same,
+		},
+		{
+			Input:         `(repo:has.key(owner:team-a) or -repo:has.topic(archive)) foo`,
+			WantGrammar:   `(and (or "repo:has.key(owner:team-a)" "-repo:has.topic(archive)") "foo")`,
+			WantHeuristic: Same,
+		},
 	}
 	for _, tt := range cases {
 		t.Run(tt.Name, func(t *testing.T) {
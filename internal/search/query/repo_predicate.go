@@ -0,0 +1,80 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Repo predicates are values of the repo: field that filter repositories by
+// their metadata instead of matching their names.
+const (
+	// RepoPredicateTopic matches repositories with a topic, as in
+	// repo:has.topic(payments).
+	RepoPredicateTopic = "topic"
+	// RepoPredicateKey matches repositories with a key set by a site admin,
+	// optionally with a value, as in repo:has.key(owner) or
+	// repo:has.key(owner:team-a).
+	RepoPredicateKey = "key"
+)
+
+// RepoPredicate is a parsed repo predicate.
+type RepoPredicate struct {
+	Name  string
+	Value string
+}
+
+// KeyValue splits the value of a RepoPredicateKey predicate into the key and
+// the value. The value is nil if the predicate only requires the key to be set.
+func (p RepoPredicate) KeyValue() (key string, value *string) {
+	i := strings.Index(p.Value, ":")
+	if i < 0 {
+		return p.Value, nil
+	}
+	v := p.Value[i+1:]
+	return p.Value[:i], &v
+}
+
+var repoPredicatePattern = regexp.MustCompile(`^has\.([a-zA-Z]+)\(([^()]*)\)`)
+
+// scanRepoPredicate scans a repo predicate at the start of buf, returning its
+// length, or zero if buf doesn't start with a repo predicate that is followed
+// by whitespace, a closing parenthesis or the end of buf. It allows the and/or
+// parser to scan the parentheses of a predicate as part of the value.
+func scanRepoPredicate(buf []byte) int {
+	n := len(repoPredicatePattern.Find(buf))
+	if n == 0 || n == len(buf) {
+		return n
+	}
+	if r, _ := utf8.DecodeRune(buf[n:]); !unicode.IsSpace(r) && r != ')' {
+		return 0
+	}
+	return n
+}
+
+// ParseRepoPredicate parses a value of the repo: field as a repo predicate. It
+// returns false if the value is not a predicate, in which case it is a regular
+// expression matching repository names. An error is returned for predicates
+// that are not recognized or have no value.
+func ParseRepoPredicate(value string) (RepoPredicate, bool, error) {
+	m := repoPredicatePattern.FindStringSubmatch(value)
+	if m == nil || len(m[0]) != len(value) {
+		return RepoPredicate{}, false, nil
+	}
+
+	p := RepoPredicate{Name: strings.ToLower(m[1]), Value: strings.TrimSpace(m[2])}
+	switch p.Name {
+	case RepoPredicateTopic, RepoPredicateKey:
+	default:
+		return p, true, fmt.Errorf("unknown repo predicate has.%s(...), expected has.topic(...) or has.key(...)", m[1])
+	}
+	if p.Value == "" {
+		return p, true, fmt.Errorf("repo predicate has.%s(...) requires a value", p.Name)
+	}
+	if key, _ := p.KeyValue(); p.Name == RepoPredicateKey && key == "" {
+		return p, true, fmt.Errorf("repo predicate has.%s(...) requires a key", p.Name)
+	}
+	return p, true, nil
+}
@@ -0,0 +1,86 @@
+package query
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseRepoPredicate(t *testing.T) {
+	cases := []struct {
+		Input   string
+		Want    RepoPredicate
+		WantOK  bool
+		WantErr string
+	}{
+		{Input: "github.com/foo/bar"},
+		{Input: `^has\.topic\(x\)$`},
+		{Input: "has.topic(payments)/bar"},
+		{
+			Input:  "has.topic(payments)",
+			Want:   RepoPredicate{Name: RepoPredicateTopic, Value: "payments"},
+			WantOK: true,
+		},
+		{
+			Input:  "has.key(owner:team-a)",
+			Want:   RepoPredicate{Name: RepoPredicateKey, Value: "owner:team-a"},
+			WantOK: true,
+		},
+		{
+			Input:   "has.stars(100)",
+			Want:    RepoPredicate{Name: "stars", Value: "100"},
+			WantOK:  true,
+			WantErr: "unknown repo predicate has.stars(...), expected has.topic(...) or has.key(...)",
+		},
+		{
+			Input:   "has.topic( )",
+			Want:    RepoPredicate{Name: RepoPredicateTopic},
+			WantOK:  true,
+			WantErr: "repo predicate has.topic(...) requires a value",
+		},
+		{
+			Input:   "has.key(:team-a)",
+			Want:    RepoPredicate{Name: RepoPredicateKey, Value: ":team-a"},
+			WantOK:  true,
+			WantErr: "repo predicate has.key(...) requires a key",
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.Input, func(t *testing.T) {
+			got, ok, err := ParseRepoPredicate(tt.Input)
+			if have := fmt.Sprint(err); (err == nil) != (tt.WantErr == "") || (err != nil && have != tt.WantErr) {
+				t.Errorf("unexpected error. want=%q have=%q", tt.WantErr, have)
+			}
+			if ok != tt.WantOK {
+				t.Errorf("got ok=%v, want %v", ok, tt.WantOK)
+			}
+			if diff := cmp.Diff(tt.Want, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestRepoPredicate_KeyValue(t *testing.T) {
+	for _, tt := range []struct {
+		Value     string
+		WantKey   string
+		WantValue *string
+	}{
+		{Value: "owner", WantKey: "owner"},
+		{Value: "owner:team-a", WantKey: "owner", WantValue: strPtr("team-a")},
+		{Value: "url:https://example.com", WantKey: "url", WantValue: strPtr("https://example.com")},
+		{Value: "owner:", WantKey: "owner", WantValue: strPtr("")},
+	} {
+		key, value := RepoPredicate{Name: RepoPredicateKey, Value: tt.Value}.KeyValue()
+		if key != tt.WantKey {
+			t.Errorf("%s: got key %q, want %q", tt.Value, key, tt.WantKey)
+		}
+		if diff := cmp.Diff(tt.WantValue, value); diff != "" {
+			t.Errorf("%s: unexpected value (-want +got):\n%s", tt.Value, diff)
+		}
+	}
+}
+
+func strPtr(s string) *string { return &s }
@@ -216,6 +216,9 @@ func (q AndOrQuery) valueToTypedValue(field, value string, label labels) []*type
 
 	case
 		FieldRepo, "r":
+		if _, ok, _ := ParseRepoPredicate(value); ok {
+			return []*types.Value{{String: &value}}
+		}
 		return []*types.Value{{Regexp: parseRegexpOrPanic(field, value)}}
 
 	case
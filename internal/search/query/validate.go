@@ -203,6 +203,13 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 		return nil
 	}
 
+	isValidRepoValue := func() error {
+		if _, ok, err := ParseRepoPredicate(value); ok {
+			return err
+		}
+		return isValidRegexp()
+	}
+
 	isBoolean := func() error {
 		if _, err := parseBool(value); err != nil {
 			return err
@@ -254,7 +261,7 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 		return satisfies(isSingular, isBoolean, isNotNegated)
 	case
 		FieldRepo:
-		return satisfies(isValidRegexp)
+		return satisfies(isValidRepoValue)
 	case
 		FieldRepoGroup:
 		return satisfies(isSingular, isNotNegated)
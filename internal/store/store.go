@@ -16,11 +16,13 @@ import (
 	"sync"
 	"time"
 
+	"github.com/inconshreveable/log15"
 	opentracing "github.com/opentracing/opentracing-go"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/diskcache"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/lfs"
 	"github.com/sourcegraph/sourcegraph/internal/metrics"
 	"github.com/sourcegraph/sourcegraph/internal/mutablelimiter"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
@@ -111,9 +113,28 @@ func (s *Store) Start() {
 	})
 }
 
+// Submodule is a Git submodule of a repository whose contents are included in
+// its archive.
+type Submodule struct {
+	// Path is the path of the submodule relative to the repository root.
+	Path string
+
+	// Repo is the repository the submodule commit is fetched from.
+	Repo api.RepoName
+
+	// Commit is the commit of Repo pinned by the submodule.
+	Commit api.CommitID
+}
+
 // PrepareZip returns the path to a local zip archive of repo at commit.
 // It will first consult the local cache, otherwise will fetch from the network.
 func (s *Store) PrepareZip(ctx context.Context, repo gitserver.Repo, commit api.CommitID) (path string, err error) {
+	return s.PrepareZipWithSubmodules(ctx, repo, commit, nil)
+}
+
+// PrepareZipWithSubmodules is like PrepareZip, but the archive also contains
+// the contents of submodules under their paths.
+func (s *Store) PrepareZipWithSubmodules(ctx context.Context, repo gitserver.Repo, commit api.CommitID, submodules []Submodule) (path string, err error) {
 	span, ctx := ot.StartSpanFromContext(ctx, "Store.prepareZip")
 	ext.Component.Set(span, "store")
 	defer func() {
@@ -132,11 +153,20 @@ func (s *Store) PrepareZip(ctx context.Context, repo gitserver.Repo, commit api.
 	if len(commit) != 40 {
 		return "", errors.Errorf("commit must be resolved (repo=%q, commit=%q)", repo.Name, commit)
 	}
+	for _, sm := range submodules {
+		if len(sm.Commit) != 40 {
+			return "", errors.Errorf("submodule commit must be resolved (repo=%q, commit=%q)", sm.Repo, sm.Commit)
+		}
+	}
 
 	largeFilePatterns := conf.Get().SearchLargeFiles
 
 	// key is a sha256 hash since we want to use it for the disk name
-	h := sha256.Sum256([]byte(fmt.Sprintf("%q %q %q", repo.Name, commit, largeFilePatterns)))
+	keyStr := fmt.Sprintf("%q %q %q", repo.Name, commit, largeFilePatterns)
+	if len(submodules) > 0 {
+		keyStr += fmt.Sprintf(" %q", submodules)
+	}
+	h := sha256.Sum256([]byte(keyStr))
 	key := hex.EncodeToString(h[:])
 	span.LogKV("key", key)
 
@@ -152,7 +182,7 @@ func (s *Store) PrepareZip(ctx context.Context, repo gitserver.Repo, commit api.
 		// since we're just going to close it again immediately.
 		bgctx := opentracing.ContextWithSpan(context.Background(), opentracing.SpanFromContext(ctx))
 		f, err := s.cache.Open(bgctx, key, func(ctx context.Context) (io.ReadCloser, error) {
-			return s.fetch(ctx, repo, commit, largeFilePatterns)
+			return s.fetch(ctx, repo, commit, submodules, largeFilePatterns)
 		})
 		var path string
 		if f != nil {
@@ -179,7 +209,7 @@ func (s *Store) PrepareZip(ctx context.Context, repo gitserver.Repo, commit api.
 // fetch fetches an archive from the network and stores it on disk. It does
 // not populate the in-memory cache. You should probably be calling
 // prepareZip.
-func (s *Store) fetch(ctx context.Context, repo gitserver.Repo, commit api.CommitID, largeFilePatterns []string) (rc io.ReadCloser, err error) {
+func (s *Store) fetch(ctx context.Context, repo gitserver.Repo, commit api.CommitID, submodules []Submodule, largeFilePatterns []string) (rc io.ReadCloser, err error) {
 	fetchQueueSize.Inc()
 	ctx, releaseFetchLimiter, err := s.fetchLimiter.Acquire(ctx) // Acquire concurrent fetches semaphore
 	if err != nil {
@@ -240,7 +270,10 @@ func (s *Store) fetch(ctx context.Context, repo gitserver.Repo, commit api.Commi
 		defer r.Close()
 		tr := tar.NewReader(r)
 		zw := zip.NewWriter(pw)
-		err := copySearchable(tr, zw, largeFilePatterns)
+		err := copySearchable(tr, zw, "", largeFilePatterns)
+		if err == nil {
+			err = s.copySubmodules(ctx, zw, submodules, largeFilePatterns)
+		}
 		if err1 := zw.Close(); err == nil {
 			err = err1
 		}
@@ -252,10 +285,33 @@ func (s *Store) fetch(ctx context.Context, repo gitserver.Repo, commit api.Commi
 	return pr, nil
 }
 
-// copySearchable copies searchable files from tr to zw. A searchable file is
-// any file that is a candidate for being searched (under size limit and
-// non-binary).
-func copySearchable(tr *tar.Reader, zw *zip.Writer, largeFilePatterns []string) error {
+// copySubmodules copies the searchable files of each submodule to zw under
+// the submodule path. Submodules whose archive can't be fetched (for example
+// because their repository isn't cloned yet) are skipped.
+func (s *Store) copySubmodules(ctx context.Context, zw *zip.Writer, submodules []Submodule, largeFilePatterns []string) error {
+	for _, sm := range submodules {
+		r, err := s.FetchTar(ctx, gitserver.Repo{Name: sm.Repo}, sm.Commit)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			log15.Warn("store: failed to fetch submodule archive", "repo", sm.Repo, "commit", sm.Commit, "path", sm.Path, "error", err)
+			submoduleFetchFailed.Inc()
+			continue
+		}
+		err = copySearchable(tar.NewReader(r), zw, strings.TrimSuffix(sm.Path, "/")+"/", largeFilePatterns)
+		r.Close()
+		if err != nil {
+			return errors.Wrapf(err, "submodule %s (%s@%s)", sm.Path, sm.Repo, sm.Commit)
+		}
+	}
+	return nil
+}
+
+// copySearchable copies searchable files from tr to zw, prefixing their names
+// with prefix. A searchable file is any file that is a candidate for being
+// searched (under size limit and non-binary).
+func copySearchable(tr *tar.Reader, zw *zip.Writer, prefix string, largeFilePatterns []string) error {
 	// 32*1024 is the same size used by io.Copy
 	buf := make([]byte, 32*1024)
 	for {
@@ -279,9 +335,11 @@ func copySearchable(tr *tar.Reader, zw *zip.Writer, largeFilePatterns []string)
 			continue
 		}
 
+		name := prefix + hdr.Name
+
 		// We are happy with the file, so we can write it to zw.
 		w, err := zw.CreateHeader(&zip.FileHeader{
-			Name:   hdr.Name,
+			Name:   name,
 			Method: zip.Store,
 		})
 		if err != nil {
@@ -301,10 +359,26 @@ func copySearchable(tr *tar.Reader, zw *zip.Writer, largeFilePatterns []string)
 
 		// We do not search the content of large files unless they are
 		// allowed.
-		if hdr.Size > maxFileSize && !ignoreSizeMax(hdr.Name, largeFilePatterns) {
+		if hdr.Size > maxFileSize && !ignoreSizeMax(name, largeFilePatterns) {
 			continue
 		}
 
+		// Git LFS pointer files whose content gitserver didn't download
+		// stand in for files that are usually large or binary, so we treat
+		// them like large files.
+		if hdr.Size <= lfs.MaxPointerSize {
+			if int64(n) < hdr.Size {
+				m, err := io.ReadFull(tr, buf[n:hdr.Size])
+				n += m
+				if err != nil {
+					return err
+				}
+			}
+			if _, ok := lfs.ParsePointer(buf[:n]); ok {
+				continue
+			}
+		}
+
 		// Heuristic: Assume file is binary if first 256 bytes contain a
 		// 0x00. Best effort, so ignore err. We only search names of binary files.
 		if n > 0 && bytes.IndexByte(buf[:n], 0x00) >= 0 {
@@ -394,6 +468,10 @@ var (
 		Name: "searcher_store_fetch_failed",
 		Help: "The total number of archive fetches that failed.",
 	})
+	submoduleFetchFailed = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "searcher_store_submodule_fetch_failed",
+		Help: "The total number of submodule archive fetches that failed.",
+	})
 )
 
 // temporaryError wraps an error but adds the Temporary method. It does not
@@ -413,4 +491,5 @@ func init() {
 	prometheus.MustRegister(fetching)
 	prometheus.MustRegister(fetchQueueSize)
 	prometheus.MustRegister(fetchFailed)
+	prometheus.MustRegister(submoduleFetchFailed)
 }
@@ -2,11 +2,13 @@ package store
 
 import (
 	"archive/tar"
+	"archive/zip"
 	"bytes"
 	"context"
 	"io"
 	"io/ioutil"
 	"os"
+	"reflect"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -148,6 +150,104 @@ func TestIngoreSizeMax(t *testing.T) {
 	}
 }
 
+func TestCopySearchable_lfsPointer(t *testing.T) {
+	files := map[string]string{
+		"README": "hello\n",
+		"big.bin": "version https://git-lfs.github.com/spec/v1\n" +
+			"oid sha256:4d7a214614ab2935c943f9e0ff69d22eadbb8f32b1258daaa5e2ca24d17e2393\n" +
+			"size 12345\n",
+	}
+
+	var tarBuf bytes.Buffer
+	tw := tar.NewWriter(&tarBuf)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(content))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var zipBuf bytes.Buffer
+	zw := zip.NewWriter(&zipBuf)
+	if err := copySearchable(tar.NewReader(&tarBuf), zw, "", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(zipBuf.Bytes()), int64(zipBuf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for _, f := range zr.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ioutil.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got[f.Name] = string(b)
+	}
+
+	// Like large files, only the names of LFS pointer files are searched.
+	want := map[string]string{"README": "hello\n", "big.bin": ""}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestPrepareZipWithSubmodules(t *testing.T) {
+	s, cleanup := tmpStore(t)
+	defer cleanup()
+
+	archives := map[api.RepoName]map[string]string{
+		"foo":  {"main.go": "package main\n"},
+		"bar":  {"lib/lib.go": "package lib\n"},
+		"gone": nil,
+	}
+	s.FetchTar = func(ctx context.Context, repo gitserver.Repo, commit api.CommitID) (io.ReadCloser, error) {
+		files, ok := archives[repo.Name]
+		if !ok || files == nil {
+			return nil, errors.Errorf("repo %s not found", repo.Name)
+		}
+		return makeTar(t, files), nil
+	}
+
+	submodules := []Submodule{
+		{Path: "vendor/bar", Repo: "bar", Commit: "cafebabecafebabecafebabecafebabecafebabe"},
+		{Path: "vendor/gone", Repo: "gone", Commit: "cafebabecafebabecafebabecafebabecafebabe"},
+	}
+	path, err := s.PrepareZipWithSubmodules(context.Background(), gitserver.Repo{Name: "foo"}, "deadbeefdeadbeefdeadbeefdeadbeefdeadbeef", submodules)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	var got []string
+	for _, f := range zr.File {
+		got = append(got, f.Name)
+	}
+
+	// Submodules that can't be fetched are skipped.
+	want := []string{"main.go", "vendor/bar/lib/lib.go"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
 func tmpStore(t *testing.T) (*Store, func()) {
 	d, err := ioutil.TempDir("", "store_test")
 	if err != nil {
@@ -158,6 +258,23 @@ func tmpStore(t *testing.T) (*Store, func()) {
 	}, func() { os.RemoveAll(d) }
 }
 
+func makeTar(t *testing.T, files map[string]string) io.ReadCloser {
+	buf := new(bytes.Buffer)
+	w := tar.NewWriter(buf)
+	for name, content := range files {
+		if err := w.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(content))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return ioutil.NopCloser(bytes.NewReader(buf.Bytes()))
+}
+
 func emptyTar(t *testing.T) io.ReadCloser {
 	buf := new(bytes.Buffer)
 	w := tar.NewWriter(buf)
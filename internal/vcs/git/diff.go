@@ -17,6 +17,14 @@ type DiffOptions struct {
 	// These fields must be valid <commit> inputs as defined by gitrevisions(7).
 	Base string
 	Head string
+
+	// Direct compares Base and Head directly (Base..Head) instead of comparing Head against
+	// the merge base of Base and Head (Base...Head). This is required when Base is a
+	// descendant of Head.
+	Direct bool
+
+	// Paths restricts the diff to the given paths when non-empty.
+	Paths []string
 }
 
 // Diff returns an iterator that can be used to access the diff between two
@@ -26,7 +34,7 @@ func Diff(ctx context.Context, opts DiffOptions) (*DiffFileIterator, error) {
 	rangeType := "..."
 	// Rare case: the base is the empty tree, in which case we must use ..
 	// instead of ... as the latter only works for commits.
-	if opts.Base == DevNullSHA {
+	if opts.Base == DevNullSHA || opts.Direct {
 		rangeType = ".."
 	}
 	rangeSpec := opts.Base + rangeType + opts.Head
@@ -36,7 +44,7 @@ func Diff(ctx context.Context, opts DiffOptions) (*DiffFileIterator, error) {
 		return nil, fmt.Errorf("invalid diff range argument: %q", rangeSpec)
 	}
 
-	rdr, err := ExecReader(ctx, opts.Repo, []string{
+	args := []string{
 		"diff",
 		"--find-renames",
 		// TODO(eseliger): Enable once we have support for copy detection in go-diff
@@ -48,7 +56,10 @@ func Diff(ctx context.Context, opts DiffOptions) (*DiffFileIterator, error) {
 		"--no-prefix",
 		rangeSpec,
 		"--",
-	})
+	}
+	args = append(args, opts.Paths...)
+
+	rdr, err := ExecReader(ctx, opts.Repo, args)
 	if err != nil {
 		return nil, errors.Wrap(err, "executing git diff")
 	}
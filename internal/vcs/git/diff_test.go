@@ -36,6 +36,8 @@ func TestDiff(t *testing.T) {
 			want string
 		}{
 			{opts: DiffOptions{Base: "foo", Head: "bar"}, want: "foo...bar"},
+			{opts: DiffOptions{Base: "foo", Head: "bar", Direct: true}, want: "foo..bar"},
+			{opts: DiffOptions{Base: DevNullSHA, Head: "bar"}, want: DevNullSHA + "..bar"},
 		} {
 			t.Run("rangeSpec: "+tc.want, func(t *testing.T) {
 				Mocks.ExecReader = func(args []string) (io.ReadCloser, error) {
@@ -50,6 +52,18 @@ func TestDiff(t *testing.T) {
 		}
 	})
 
+	t.Run("paths", func(t *testing.T) {
+		Mocks.ExecReader = func(args []string) (io.ReadCloser, error) {
+			if have := strings.Join(args[6:], " "); have != "-- foo.go bar/baz.go" {
+				t.Errorf("unexpected trailing arguments: have: %s; want: %s", have, "-- foo.go bar/baz.go")
+			}
+			return nil, nil
+		}
+		defer ResetMocks()
+
+		_, _ = Diff(ctx, DiffOptions{Base: "foo", Head: "bar", Paths: []string{"foo.go", "bar/baz.go"}})
+	})
+
 	t.Run("ExecReader error", func(t *testing.T) {
 		Mocks.ExecReader = func(args []string) (io.ReadCloser, error) {
 			return nil, errors.New("ExecReader error")
@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TABLE IF EXISTS repo_kvps;
+
+ALTER TABLE repo DROP COLUMN IF EXISTS default_branch;
+ALTER TABLE repo DROP COLUMN IF EXISTS stars;
+ALTER TABLE repo DROP COLUMN IF EXISTS topics;
+
+COMMIT;
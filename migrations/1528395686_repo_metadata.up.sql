@@ -0,0 +1,16 @@
+BEGIN;
+
+ALTER TABLE repo ADD COLUMN IF NOT EXISTS topics text[] NOT NULL DEFAULT '{}';
+ALTER TABLE repo ADD COLUMN IF NOT EXISTS stars integer NOT NULL DEFAULT 0;
+ALTER TABLE repo ADD COLUMN IF NOT EXISTS default_branch text;
+
+CREATE TABLE IF NOT EXISTS repo_kvps (
+    repo_id integer NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    key text NOT NULL,
+    value text,
+    PRIMARY KEY (repo_id, key)
+);
+
+CREATE INDEX IF NOT EXISTS repo_kvps_key_value_idx ON repo_kvps(key, value);
+
+COMMIT;
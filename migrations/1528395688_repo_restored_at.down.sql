@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE repo DROP COLUMN IF EXISTS restored_at;
+
+COMMIT;
@@ -70,6 +70,16 @@
 // 1528395683_empty.up.sql (159B)
 // 1528395684_lsif_num_resets.down.sql (293B)
 // 1528395684_lsif_num_resets.up.sql (340B)
+// 1528395685_repo_redirects.down.sql (54B)
+// 1528395685_repo_redirects.up.sql (303B)
+// 1528395686_repo_metadata.down.sql (198B)
+// 1528395686_repo_metadata.up.sql (494B)
+// 1528395687_gitserver_repo_placements.down.sql (65B)
+// 1528395687_gitserver_repo_placements.up.sql (243B)
+// 1528395688_repo_restored_at.down.sql (69B)
+// 1528395688_repo_restored_at.up.sql (97B)
+// 1528395689_gitserver_repo_moves.down.sql (60B)
+// 1528395689_gitserver_repo_moves.up.sql (331B)
 
 package migrations
 
@@ -1538,6 +1548,206 @@ func _1528395684_lsif_num_resetsUpSql() (*asset, error) {
 	return a, nil
 }
 
+var __1528395685_repo_redirectsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x36\x00\xc9\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x72\x65\x70\x6f\x5f\x72\x65\x64\x69\x72\x65\x63\x74\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xd8\x9e\x77\x6e\x36\x00\x00\x00")
+
+func _1528395685_repo_redirectsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395685_repo_redirectsDownSql,
+		"1528395685_repo_redirects.down.sql",
+	)
+}
+
+func _1528395685_repo_redirectsDownSql() (*asset, error) {
+	bytes, err := _1528395685_repo_redirectsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395685_repo_redirects.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xc, 0x86, 0x87, 0x24, 0x3b, 0xbb, 0xc7, 0xf7, 0x57, 0xca, 0xa0, 0xcf, 0x73, 0x2, 0xee, 0x24, 0x40, 0x9, 0xc9, 0xe0, 0xf, 0x3c, 0x85, 0xe6, 0xf8, 0x22, 0xf1, 0xf4, 0x8d, 0xb9, 0xd6, 0xc}}
+	return a, nil
+}
+
+var __1528395685_repo_redirectsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\xcf\xb1\x6a\xc3\x30\x10\xc6\xf1\x5d\x4f\xf1\x8d\x36\xf4\x0d\x32\x29\xf6\xb9\x88\xca\x72\x91\x15\x48\x26\x63\xec\xa3\xd5\x60\x3b\x28\x07\x29\x7d\xfa\x82\xd2\x62\xe8\x90\x51\xfc\xd1\x8f\xfb\x8e\xf4\x6a\xdc\x41\xa9\xca\x93\x0e\x84\xa0\x8f\x96\x60\x1a\xb8\x2e\x80\xce\xa6\x0f\x3d\x12\x5f\xb7\x21\xf1\x1c\x13\x4f\x72\x43\xa1\x00\x60\x1d\x17\xc6\x14\x85\xbf\x04\xef\xde\xb4\xda\x5f\xf0\x46\x97\x97\x1c\xf3\x8f\x38\x23\xae\xc2\x1f\x9c\x32\xe6\x4e\xd6\xc2\x53\x43\x9e\x5c\x45\x0f\xb5\x88\x73\x89\xce\xa1\x26\x4b\x81\x50\xe9\xbe\xd2\x35\x3d\x8c\x29\xf1\x28\x3c\x0f\xa3\x40\xe2\xc2\x37\x19\x97\x2b\xee\x51\x3e\xf3\x13\xdf\xdb\xca\xbb\x5b\x53\xa3\x4f\x36\x60\xdd\xee\x45\xa9\xca\x7d\x8f\x71\x35\x9d\x9f\xee\x19\xfe\x8e\xed\xdc\xbf\x52\xfc\x96\xcc\x75\x6d\x6b\xc2\x41\xfd\x0c\x00\xe2\xd5\x46\xf2\x2f\x01\x00\x00")
+
+func _1528395685_repo_redirectsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395685_repo_redirectsUpSql,
+		"1528395685_repo_redirects.up.sql",
+	)
+}
+
+func _1528395685_repo_redirectsUpSql() (*asset, error) {
+	bytes, err := _1528395685_repo_redirectsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395685_repo_redirects.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x56, 0x45, 0x82, 0xa8, 0xe2, 0x53, 0x54, 0x87, 0x93, 0x23, 0x76, 0xd8, 0xd6, 0x3b, 0xda, 0xb6, 0x1d, 0x76, 0x5a, 0xcc, 0x17, 0x25, 0x8, 0x49, 0xf8, 0xd9, 0x39, 0x32, 0xb3, 0xd2, 0x24, 0x62}}
+	return a, nil
+}
+
+var __1528395686_repo_metadataDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x4a\x2d\xc8\x8f\xcf\x2e\x2b\x28\xb6\xe6\xe2\x72\xf4\x09\x71\x0d\x82\xaa\x00\x89\x2b\x80\x75\x38\xfb\xfb\x84\xfa\xfa\x21\x69\x49\x49\x4d\x4b\x2c\xcd\x29\x89\x4f\x2a\x4a\xcc\x4b\xce\xb0\x26\x56\x5b\x71\x49\x62\x51\x31\xd1\xaa\x4b\xf2\x0b\x32\x93\x41\x8e\x72\xf6\xf7\xf5\xf5\x0c\xb1\xe6\x02\x0c\x00\x78\xac\xac\xee\xc6\x00\x00\x00")
+
+func _1528395686_repo_metadataDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395686_repo_metadataDownSql,
+		"1528395686_repo_metadata.down.sql",
+	)
+}
+
+func _1528395686_repo_metadataDownSql() (*asset, error) {
+	bytes, err := _1528395686_repo_metadataDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395686_repo_metadata.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x83, 0x89, 0xcb, 0x6, 0x4a, 0xf1, 0xbd, 0xe0, 0x1, 0xb0, 0x61, 0xe3, 0x17, 0xfd, 0x15, 0x2d, 0x2a, 0x79, 0xaf, 0xc7, 0x47, 0xb7, 0x19, 0xde, 0x58, 0xcd, 0x16, 0x81, 0x29, 0x13, 0xb7, 0xe5}}
+	return a, nil
+}
+
+var __1528395686_repo_metadataUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x8e\x41\x6b\xc2\x30\x18\x86\xef\xf9\x15\xef\xcd\x0a\x1e\x76\xef\x29\x36\x9f\x23\x2c\x4d\x47\x8c\xa0\x8c\x11\x3a\x9b\x6d\xa5\xa2\xd2\x46\xb1\x8c\xfd\xf7\x61\x2a\x8e\x21\x3b\x78\x4c\x1e\xde\xe7\x7b\xa6\xf4\x28\x75\xca\x18\x57\x96\x0c\x2c\x9f\x2a\x42\xeb\xf7\x3b\x70\x21\x90\x15\x6a\x91\x6b\xc8\x19\x74\x61\x41\x4b\x39\xb7\x73\x84\xdd\xbe\x5e\x77\x08\xfe\x14\x5e\x5e\x23\xd0\x0b\xa5\x20\x68\xc6\x17\xca\x62\xf4\xf5\x3d\x4a\xef\xd0\x75\xa1\x6c\x3b\xd4\xdb\xe0\x3f\x7c\x7b\xab\x7b\xb8\xc7\x55\xf9\xf7\xf2\xb0\x09\xee\xad\x2d\xb7\xeb\xcf\x98\x98\x32\x96\x19\xe2\x96\x2e\x82\xbf\x83\xb3\xce\x35\xc7\x7d\x87\x84\x01\x88\xa9\xae\xae\x6e\x73\x0c\xcd\xc8\x90\xce\x68\xd8\x24\x75\x35\x46\xa1\x21\x48\x91\x25\x64\x7c\x9e\x71\x41\x93\xe8\x68\x7c\x1f\x2f\x5f\xc7\xc3\xf7\xb1\xdc\x1c\x7c\x04\xc3\xfb\xd9\xc8\x9c\x9b\x15\x9e\x68\x85\xe4\x72\x77\x82\xc6\xf7\x63\x36\xfe\x8d\x96\x5a\xd0\xf2\xbf\x68\xd7\xf8\xde\x45\xaf\xab\xab\xd3\xb9\xe7\x8a\x92\xc6\xf7\x13\x44\x16\x6d\x45\x9e\x4b\x9b\xb2\x9f\x01\x00\xf5\x40\x26\x24\xee\x01\x00\x00")
+
+func _1528395686_repo_metadataUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395686_repo_metadataUpSql,
+		"1528395686_repo_metadata.up.sql",
+	)
+}
+
+func _1528395686_repo_metadataUpSql() (*asset, error) {
+	bytes, err := _1528395686_repo_metadataUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395686_repo_metadata.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x64, 0xd, 0xab, 0xa3, 0x62, 0x82, 0x4b, 0xc3, 0x9d, 0x91, 0x1a, 0x9e, 0xb6, 0xdf, 0x51, 0xae, 0xd, 0x0, 0x9e, 0xee, 0xd6, 0x7d, 0xb0, 0x65, 0x6a, 0x37, 0x7e, 0x56, 0xfe, 0xd5, 0x90, 0x39}}
+	return a, nil
+}
+
+var __1528395687_gitserver_repo_placementsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x41\x00\xbe\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x67\x69\x74\x73\x65\x72\x76\x65\x72\x5f\x72\x65\x70\x6f\x5f\x70\x6c\x61\x63\x65\x6d\x65\x6e\x74\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x36\xcf\xb7\xc4\x41\x00\x00\x00")
+
+func _1528395687_gitserver_repo_placementsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395687_gitserver_repo_placementsDownSql,
+		"1528395687_gitserver_repo_placements.down.sql",
+	)
+}
+
+func _1528395687_gitserver_repo_placementsDownSql() (*asset, error) {
+	bytes, err := _1528395687_gitserver_repo_placementsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395687_gitserver_repo_placements.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x10, 0xb9, 0x11, 0x8b, 0x4, 0xc6, 0xe6, 0x1e, 0xb4, 0xc5, 0x3c, 0x4, 0x64, 0x65, 0xa7, 0xaf, 0xfd, 0x70, 0x40, 0x98, 0x9c, 0xfb, 0x8a, 0x5f, 0x7e, 0xd0, 0x27, 0xe7, 0x2c, 0x0, 0x47, 0x2e}}
+	return a, nil
+}
+
+var __1528395687_gitserver_repo_placementsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x44\x8e\xcd\x4a\xc4\x30\x14\x85\xf7\x79\x8a\xb3\x9c\x82\x6f\xd0\x55\xa6\xbd\x95\x62\x9a\x4a\x9a\x01\x67\x55\x82\xb9\x8c\x01\xfb\x43\x7a\x75\xc4\xa7\x17\x22\xe8\xf2\x1c\xbe\xf3\x73\xa6\xc7\xde\xd6\x4a\x35\x8e\xb4\x27\x78\x7d\x36\x84\xbe\x83\x1d\x3d\xe8\xa5\x9f\xfc\x84\x5b\x92\x83\xf3\x27\xe7\x39\xf3\xbe\xcd\xfb\x7b\x78\xe5\x85\x57\x39\x70\x52\x00\x50\xdc\x14\x91\x56\xe1\x1b\x67\x3c\xbb\x7e\xd0\xee\x8a\x27\xba\xc2\x51\x47\x8e\x6c\x43\x53\xc1\x4e\x29\x56\x18\x2d\x5a\x32\xe4\x09\x8d\x9e\x1a\xdd\xd2\x43\xa9\xf9\x9f\x09\x31\x66\x08\x7f\x49\x79\x61\x2f\xc6\xfc\x12\x1f\x7b\x0c\xc2\x71\x0e\x02\x49\x0b\x1f\x12\x96\x1d\xf7\x24\x6f\x45\xe2\x7b\x5b\xf9\x2f\x81\x96\x3a\x7d\x31\x1e\xeb\x76\x3f\x55\xaa\xaa\x95\x6a\xc6\x61\xe8\x7d\xad\x7e\x06\x00\x1a\x0b\x5f\x7a\xf3\x00\x00\x00")
+
+func _1528395687_gitserver_repo_placementsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395687_gitserver_repo_placementsUpSql,
+		"1528395687_gitserver_repo_placements.up.sql",
+	)
+}
+
+func _1528395687_gitserver_repo_placementsUpSql() (*asset, error) {
+	bytes, err := _1528395687_gitserver_repo_placementsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395687_gitserver_repo_placements.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x4c, 0xa, 0x5c, 0xe5, 0xe3, 0xce, 0x66, 0xf6, 0x5b, 0xe6, 0x90, 0x4d, 0xce, 0x84, 0x65, 0x65, 0x1a, 0xa2, 0x1d, 0x59, 0x83, 0x3e, 0x6d, 0x32, 0x23, 0x71, 0x3e, 0x71, 0x77, 0xd6, 0x60, 0xc2}}
+	return a, nil
+}
+
+var __1528395688_repo_restored_atDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x45\x00\xba\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x72\x65\x70\x6f\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x72\x65\x73\x74\x6f\x72\x65\x64\x5f\x61\x74\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xbb\x2d\x6e\xa4\x45\x00\x00\x00")
+
+func _1528395688_repo_restored_atDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395688_repo_restored_atDownSql,
+		"1528395688_repo_restored_at.down.sql",
+	)
+}
+
+func _1528395688_repo_restored_atDownSql() (*asset, error) {
+	bytes, err := _1528395688_repo_restored_atDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395688_repo_restored_at.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x8c, 0xb3, 0x7a, 0xe5, 0x6d, 0xd2, 0xe, 0x5c, 0x4a, 0x31, 0x98, 0x95, 0x3e, 0xaa, 0xf6, 0x8f, 0x4c, 0xbb, 0xe8, 0xea, 0x89, 0x78, 0xc8, 0xda, 0x1f, 0x92, 0xf, 0x89, 0xee, 0xe, 0x23, 0x56}}
+	return a, nil
+}
+
+var __1528395688_repo_restored_atUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x61\x00\x9e\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x72\x65\x70\x6f\x20\x41\x44\x44\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x4e\x4f\x54\x20\x45\x58\x49\x53\x54\x53\x20\x72\x65\x73\x74\x6f\x72\x65\x64\x5f\x61\x74\x20\x74\x69\x6d\x65\x73\x74\x61\x6d\x70\x20\x77\x69\x74\x68\x20\x74\x69\x6d\x65\x20\x7a\x6f\x6e\x65\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x69\x19\x12\x1f\x61\x00\x00\x00")
+
+func _1528395688_repo_restored_atUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395688_repo_restored_atUpSql,
+		"1528395688_repo_restored_at.up.sql",
+	)
+}
+
+func _1528395688_repo_restored_atUpSql() (*asset, error) {
+	bytes, err := _1528395688_repo_restored_atUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395688_repo_restored_at.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x23, 0x59, 0xd0, 0xaa, 0x70, 0xcb, 0x7, 0x42, 0xa, 0xf, 0x6c, 0xc1, 0x2e, 0x86, 0x2b, 0x28, 0xeb, 0xfb, 0xa6, 0x11, 0x67, 0xe8, 0x6d, 0x1f, 0xda, 0x64, 0x48, 0xd2, 0xab, 0x30, 0x9b, 0x9d}}
+	return a, nil
+}
+
+var __1528395689_gitserver_repo_movesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x3c\x00\xc3\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x67\x69\x74\x73\x65\x72\x76\x65\x72\x5f\x72\x65\x70\x6f\x5f\x6d\x6f\x76\x65\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x7e\x34\xf4\xbd\x3c\x00\x00\x00")
+
+func _1528395689_gitserver_repo_movesDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395689_gitserver_repo_movesDownSql,
+		"1528395689_gitserver_repo_moves.down.sql",
+	)
+}
+
+func _1528395689_gitserver_repo_movesDownSql() (*asset, error) {
+	bytes, err := _1528395689_gitserver_repo_movesDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395689_gitserver_repo_moves.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x52, 0x82, 0xca, 0x90, 0x84, 0x4f, 0x20, 0x50, 0x26, 0x28, 0x29, 0x68, 0xdc, 0x1b, 0xf1, 0x75, 0x63, 0x76, 0x24, 0xba, 0x38, 0x83, 0x92, 0x79, 0x22, 0x2c, 0x1f, 0x80, 0x3c, 0xe4, 0x5c, 0xc}}
+	return a, nil
+}
+
+var __1528395689_gitserver_repo_movesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\x8e\xcd\x6a\xeb\x30\x10\x85\xf7\x7a\x8a\xb3\xb4\xe1\xbe\x81\x57\x8a\x3d\xbe\x98\xfa\xa7\xd8\x0a\x34\x2b\x23\xd0\xd4\x11\xd4\x76\x90\xa6\x49\xe9\xd3\x17\x94\x92\x6e\x4a\x97\x33\xf3\xcd\x77\xce\x81\xfe\x37\x7d\xa1\x54\x39\x92\x36\x04\xa3\x0f\x2d\xa1\xa9\xd1\x0f\x06\xf4\xd2\x4c\x66\xc2\xe2\x25\x72\xb8\x72\x98\x03\x5f\xf6\x79\xdd\xaf\x1c\x91\x29\x00\x48\x0b\xef\xe0\x37\xe1\x85\x03\x9e\xc7\xa6\xd3\xe3\x09\x4f\x74\xc2\x48\x35\x8d\xd4\x97\x34\x25\x2c\xf3\x2e\xc7\xd0\xa3\xa2\x96\x0c\xa1\xd4\x53\xa9\x2b\xfa\x97\x34\x3f\x09\xd6\xb9\x00\xe1\x0f\x49\x05\xfa\x63\xdb\xde\x89\x28\x56\xf8\xb7\xc3\xab\xf5\x6f\xef\x81\xe7\x95\x63\xb4\xcb\x1d\x79\xbc\x04\x61\x37\x5b\x81\xf8\x95\xa3\xd8\xf5\x82\x9b\x97\x73\x1a\xf1\xb9\x6f\xfc\x70\xa1\xa2\x5a\x1f\x5b\x83\x6d\xbf\x65\xf9\xb7\xd9\x6f\x3e\x9e\xff\x16\xa8\xbc\x50\xaa\x1c\xba\xae\x31\x85\xfa\x1a\x00\x88\x79\x2d\x54\x4b\x01\x00\x00")
+
+func _1528395689_gitserver_repo_movesUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395689_gitserver_repo_movesUpSql,
+		"1528395689_gitserver_repo_moves.up.sql",
+	)
+}
+
+func _1528395689_gitserver_repo_movesUpSql() (*asset, error) {
+	bytes, err := _1528395689_gitserver_repo_movesUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395689_gitserver_repo_moves.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x58, 0x99, 0x53, 0x39, 0x58, 0xa2, 0x62, 0xbd, 0xf6, 0xa7, 0x4e, 0x2b, 0x87, 0x14, 0xf6, 0x59, 0xec, 0x81, 0x98, 0x6c, 0xf0, 0xc4, 0x74, 0x22, 0xfe, 0xc, 0x48, 0x28, 0xad, 0xf7, 0x54, 0xc4}}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -1699,6 +1909,16 @@ var _bindata = map[string]func() (*asset, error){
 	"1528395683_empty.up.sql":                                                 _1528395683_emptyUpSql,
 	"1528395684_lsif_num_resets.down.sql":                                     _1528395684_lsif_num_resetsDownSql,
 	"1528395684_lsif_num_resets.up.sql":                                       _1528395684_lsif_num_resetsUpSql,
+	"1528395685_repo_redirects.down.sql":                                      _1528395685_repo_redirectsDownSql,
+	"1528395685_repo_redirects.up.sql":                                        _1528395685_repo_redirectsUpSql,
+	"1528395686_repo_metadata.down.sql":                                       _1528395686_repo_metadataDownSql,
+	"1528395686_repo_metadata.up.sql":                                         _1528395686_repo_metadataUpSql,
+	"1528395687_gitserver_repo_placements.down.sql":                           _1528395687_gitserver_repo_placementsDownSql,
+	"1528395687_gitserver_repo_placements.up.sql":                             _1528395687_gitserver_repo_placementsUpSql,
+	"1528395688_repo_restored_at.down.sql":                                    _1528395688_repo_restored_atDownSql,
+	"1528395688_repo_restored_at.up.sql":                                      _1528395688_repo_restored_atUpSql,
+	"1528395689_gitserver_repo_moves.down.sql": _1528395689_gitserver_repo_movesDownSql,
+	"1528395689_gitserver_repo_moves.up.sql":   _1528395689_gitserver_repo_movesUpSql,
 }
 
 // AssetDebug is true if the assets were built with the debug flag enabled.
@@ -1815,6 +2035,16 @@ var _bintree = &bintree{nil, map[string]*bintree{
 	"1528395683_empty.up.sql":                                                 {_1528395683_emptyUpSql, map[string]*bintree{}},
 	"1528395684_lsif_num_resets.down.sql":                                     {_1528395684_lsif_num_resetsDownSql, map[string]*bintree{}},
 	"1528395684_lsif_num_resets.up.sql":                                       {_1528395684_lsif_num_resetsUpSql, map[string]*bintree{}},
+	"1528395685_repo_redirects.down.sql":                                      {_1528395685_repo_redirectsDownSql, map[string]*bintree{}},
+	"1528395685_repo_redirects.up.sql":                                        {_1528395685_repo_redirectsUpSql, map[string]*bintree{}},
+	"1528395686_repo_metadata.down.sql":                                       {_1528395686_repo_metadataDownSql, map[string]*bintree{}},
+	"1528395686_repo_metadata.up.sql":                                         {_1528395686_repo_metadataUpSql, map[string]*bintree{}},
+	"1528395687_gitserver_repo_placements.down.sql":                           {_1528395687_gitserver_repo_placementsDownSql, map[string]*bintree{}},
+	"1528395687_gitserver_repo_placements.up.sql":                             {_1528395687_gitserver_repo_placementsUpSql, map[string]*bintree{}},
+	"1528395688_repo_restored_at.down.sql":                                    {_1528395688_repo_restored_atDownSql, map[string]*bintree{}},
+	"1528395688_repo_restored_at.up.sql":                                      {_1528395688_repo_restored_atUpSql, map[string]*bintree{}},
+	"1528395689_gitserver_repo_moves.down.sql": &bintree{_1528395689_gitserver_repo_movesDownSql, map[string]*bintree{}},
+	"1528395689_gitserver_repo_moves.up.sql":   &bintree{_1528395689_gitserver_repo_movesUpSql, map[string]*bintree{}},
 }}
 
 // RestoreAsset restores an asset under the given directory.
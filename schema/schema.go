@@ -311,6 +311,18 @@ type BuiltinAuthProvider struct {
 	Type        string `json:"type"`
 }
 
+// CampaignsCommitSigning description: Signs the commits that gitserver creates for campaigns and patches, so that they can be pushed to branches that require signed commits. The signing key is a secret: it is only readable by site admins and gitserver.
+type CampaignsCommitSigning struct {
+	// CommitterEmail description: The committer email of signed commits, which should belong to the account that the public key was added to on the code host.
+	CommitterEmail string `json:"committerEmail,omitempty"`
+	// CommitterName description: The committer name of signed commits. Code hosts only show signatures as verified if the committer email belongs to the account that the public key was added to.
+	CommitterName string `json:"committerName,omitempty"`
+	// Format description: The format of the signing key and of the signatures.
+	Format string `json:"format,omitempty"`
+	// SigningKey description: The private signing key without a passphrase: an ASCII-armored GPG secret key for the gpg format, or an OpenSSH private key for the ssh format.
+	SigningKey string `json:"signingKey"`
+}
+
 // CloneURLToRepositoryName description: Describes a mapping from clone URL to repository name. The `from` field contains a regular expression with named capturing groups. The `to` field contains a template string that references capturing group names. For instance, if `from` is "^../(?P<name>\w+)$" and `to` is "github.com/user/{name}", the clone URL "../myRepository" would be mapped to the repository name "github.com/user/myRepository".
 type CloneURLToRepositoryName struct {
 	// From description: A regular expression that matches a set of clone URLs. The regular expression should use the Go regular expression syntax (https://golang.org/pkg/regexp/) and contain at least one named capturing group. The regular expression matches partially by default, so use "^...$" if whole-string matching is desired.
@@ -318,6 +330,16 @@ type CloneURLToRepositoryName struct {
 	// To description: The repository name output pattern. This should use `{matchGroup}` syntax to reference the capturing groups from the `from` field.
 	To string `json:"to"`
 }
+type CodeIntelRetentionPolicy struct {
+	// KeepMostRecentPerBranch description: The number of most recent uploads to keep for each branch. Uploads that are not among the most recent uploads of any branch containing their commit are removed. If zero or omitted, uploads are not removed based on their recency.
+	KeepMostRecentPerBranch int `json:"keepMostRecentPerBranch,omitempty"`
+	// KeepTaggedCommits description: Keep uploads of tagged commits (such as releases) forever, regardless of the other settings of this policy.
+	KeepTaggedCommits bool `json:"keepTaggedCommits,omitempty"`
+	// NonDefaultBranchMaxAgeDays description: The number of days after which uploads of commits that are not on the default branch are removed. If zero or omitted, such uploads do not expire.
+	NonDefaultBranchMaxAgeDays int `json:"nonDefaultBranchMaxAgeDays,omitempty"`
+	// RepositoryPattern description: A regular expression matched against repository names. If omitted, the policy applies to all repositories.
+	RepositoryPattern string `json:"repositoryPattern,omitempty"`
+}
 
 // CustomGitFetchMapping description: Mapping from Git clone URl domain/path to git fetch command. The `domainPath` field contains the Git clone URL domain/path part. The `fetch` field contains the custom git fetch command.
 type CustomGitFetchMapping struct {
@@ -360,6 +382,12 @@ type ExcludedBitbucketServerRepo struct {
 	// Pattern description: Regular expression which matches against the name of a Bitbucket Server repo.
 	Pattern string `json:"pattern,omitempty"`
 }
+type ExcludedGerritProject struct {
+	// Name description: The name of a Gerrit project ("platform/build") to exclude from mirroring.
+	Name string `json:"name,omitempty"`
+	// Pattern description: Regular expression which matches against the name of a Gerrit project.
+	Pattern string `json:"pattern,omitempty"`
+}
 type ExcludedGitHubRepo struct {
 	// Archived description: If set to true, archived repositories will be excluded.
 	Archived bool `json:"archived,omitempty"`
@@ -430,6 +458,48 @@ type ExternalIdentity struct {
 	Type           string `json:"type"`
 }
 
+// GerritAuthorization description: If non-null, enforces Gerrit repository permissions. A user can see a repository if one of the Gerrit groups they belong to is allowed to read "refs/*" (or "refs/heads/*") on the project or on one of the projects it inherits access rights from. Sourcegraph assumes usernames are identical in Sourcegraph and Gerrit accounts and `auth.enableUsernameChanges` must be set to false for security reasons.
+type GerritAuthorization struct {
+	// Ttl description: The TTL of how long to cache the set of repositories a user has access to. This is 3 hours by default.
+	//
+	// Decreasing the TTL will increase the load on the code host API.
+	Ttl string `json:"ttl,omitempty"`
+}
+
+// GerritConnection description: Configuration for a connection to Gerrit.
+type GerritConnection struct {
+	// Authorization description: If non-null, enforces Gerrit repository permissions. A user can see a repository if one of the Gerrit groups they belong to is allowed to read "refs/*" (or "refs/heads/*") on the project or on one of the projects it inherits access rights from. Sourcegraph assumes usernames are identical in Sourcegraph and Gerrit accounts and `auth.enableUsernameChanges` must be set to false for security reasons.
+	Authorization *GerritAuthorization `json:"authorization,omitempty"`
+	// Exclude description: A list of projects to never mirror from this Gerrit instance. Takes precedence over "projects" and "projectQuery" configuration. Supports excluding by name ({"name": "platform/build"}) or by regular expression ({"pattern": "^device/.*"}).
+	Exclude []*ExcludedGerritProject `json:"exclude,omitempty"`
+	// Password description: The HTTP password of the Gerrit account (generated under Settings > HTTP Credentials). Also set the corresponding "username" field.
+	Password string `json:"password"`
+	// ProjectQuery description: An array of strings specifying which Gerrit projects to mirror on Sourcegraph. Each string is a URL query that is passed to the Gerrit "list projects" endpoint (https://gerrit-review.googlesource.com/Documentation/rest-api-projects.html#list-projects). Examples: "?p=platform/" (name prefix), "?r=device/.*" (name regex), "?m=kernel" (name substring), "?type=CODE&state=ACTIVE".
+	//
+	// The special string "none" can be used as the only element to disable this feature. Projects matched by multiple query strings are only imported once.
+	ProjectQuery []string `json:"projectQuery,omitempty"`
+	// Projects description: A list of Gerrit projects to mirror, identified by their full name (such as "platform/build").
+	Projects []string `json:"projects,omitempty"`
+	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for a Gerrit project. In the pattern, the variable "{host}" is replaced with the Gerrit URL's host (such as gerrit.example.com), and "{name}" is replaced with the Gerrit project's name (such as "platform/build").
+	//
+	// For example, if your Gerrit is https://gerrit.example.com and your Sourcegraph is https://src.example.com, then a repositoryPathPattern of "{host}/{name}" would mean that a Gerrit project named platform/build is available on Sourcegraph at https://src.example.com/gerrit.example.com/platform/build.
+	//
+	// It is important that the Sourcegraph repository name generated with this pattern be unique to this code host. If different code hosts generate repository names that collide, Sourcegraph's behavior is undefined.
+	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
+	// Url description: URL of a Gerrit instance, such as https://gerrit.example.com.
+	Url string `json:"url"`
+	// Username description: The username of the Gerrit account used to list projects, clone repositories and read permissions. Also set the corresponding "password" field.
+	Username string `json:"username"`
+}
+type GitCloneStrategy struct {
+	// Blobless description: Clone the repository as a blobless partial clone (git clone --filter=blob:none), which only clones commits and trees. File contents are fetched from the code host when they are first needed, for example to search or view a file. The code host must support partial clones.
+	Blobless bool `json:"blobless,omitempty"`
+	// DefaultBranchAndTagsOnly description: Only clone the default branch and tags of the repository, instead of all branches and other refs such as pull requests.
+	DefaultBranchAndTagsOnly bool `json:"defaultBranchAndTagsOnly,omitempty"`
+	// RepositoryPattern description: A regular expression matched against repository names.
+	RepositoryPattern string `json:"repositoryPattern"`
+}
+
 // GitHubAuthProvider description: Configures the GitHub (or GitHub Enterprise) OAuth authentication provider for SSO. In addition to specifying this configuration object, you must also create a OAuth App on your GitHub instance: https://developer.github.com/apps/building-oauth-apps/creating-an-oauth-app/. When a user signs into Sourcegraph or links their GitHub account to their existing Sourcegraph account, GitHub will prompt the user for the repo scope.
 type GitHubAuthProvider struct {
 	// AllowOrgs description: Restricts new logins to members of these GitHub organizations. Existing sessions won't be invalidated. Leave empty or unset for no org restrictions.
@@ -529,6 +599,14 @@ type GitHubWebhook struct {
 	Secret string `json:"secret"`
 }
 
+// GitLFS description: Git LFS support. When enabled, gitserver downloads the Git LFS objects of repositories using Git LFS from their code hosts, with the same credentials used to clone them, so that archives used for search contain the actual file contents instead of LFS pointer files. Only HTTP(S) clone URLs are supported.
+type GitLFS struct {
+	// Enabled description: Whether gitserver downloads Git LFS objects.
+	Enabled bool `json:"enabled,omitempty"`
+	// MaxObjectSizeBytes description: The maximum size in bytes of Git LFS objects that are downloaded. Larger files are served as LFS pointer files and only their names are searched.
+	MaxObjectSizeBytes int `json:"maxObjectSizeBytes,omitempty"`
+}
+
 // GitLabAuthProvider description: Configures the GitLab OAuth authentication provider for SSO. In addition to specifying this configuration object, you must also create a OAuth App on your GitLab instance: https://docs.gitlab.com/ee/integration/oauth_provider.html. The application should have `api` and `read_user` scopes and the callback URL set to the concatenation of your Sourcegraph instance URL and "/.auth/gitlab/callback".
 type GitLabAuthProvider struct {
 	// ClientID description: The Client ID of the GitLab OAuth app, accessible from https://gitlab.com/oauth/applications (or the same path on your private GitLab instance).
@@ -591,6 +669,8 @@ type GitLabConnection struct {
 	Token string `json:"token"`
 	// Url description: URL of a GitLab instance, such as https://gitlab.example.com or (for GitLab.com) https://gitlab.com.
 	Url string `json:"url"`
+	// Webhooks description: An array of configurations defining existing GitLab system hooks or project and group webhooks that send repository and push events back to Sourcegraph.
+	Webhooks []*GitLabWebhook `json:"webhooks,omitempty"`
 }
 type GitLabNameTransformation struct {
 	// Regex description: The regex to match for the occurrences of its replacement.
@@ -612,6 +692,22 @@ type GitLabRateLimit struct {
 	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second.
 	RequestsPerHour float64 `json:"requestsPerHour"`
 }
+type GitLabWebhook struct {
+	// Secret description: The secret token used when creating the system hook or webhook
+	Secret string `json:"secret"`
+}
+type GitUpdateClass struct {
+	// MaxIntervalSeconds description: The maximum time (in seconds) between scheduled fetches of a repository. Defaults to 8 hours, or to minIntervalSeconds if that is higher.
+	MaxIntervalSeconds int `json:"maxIntervalSeconds,omitempty"`
+	// MinIntervalSeconds description: The minimum time (in seconds) between scheduled fetches of a repository. Defaults to 45 seconds, or to maxIntervalSeconds if that is lower.
+	MinIntervalSeconds int `json:"minIntervalSeconds,omitempty"`
+	// Name description: The name of the class, shown in the update schedule of its repositories and used as the class label of the src_repoupdater_sched_update_lag_seconds metric.
+	Name string `json:"name"`
+	// Priority description: Scheduled fetches of repositories in classes with a higher priority are run before the ones in classes with a lower priority when they are due at the same time. Repositories that belong to no class have priority 0. Fetches requested for a single repository, such as on push, are always run before scheduled ones.
+	Priority int `json:"priority,omitempty"`
+	// RepositoryPattern description: A regular expression matched against repository names. If omitted, the class applies to all repositories.
+	RepositoryPattern string `json:"repositoryPattern,omitempty"`
+}
 
 // GitoliteConnection description: Configuration for a connection to Gitolite.
 type GitoliteConnection struct {
@@ -722,6 +818,24 @@ type Log struct {
 	// Sentry description: Configuration for Sentry
 	Sentry *Sentry `json:"sentry,omitempty"`
 }
+
+// ManifestConnection description: Configuration for a connection to Git repositories listed in a manifest, such as one produced by a build system. The manifest is fetched from a URL or read from a file in a repository on every sync. Either "url", or "repository" and "path" must be set.
+type ManifestConnection struct {
+	// Path description: Path of the manifest file in "repository". The file has the same format as the document fetched from "url".
+	Path string `json:"path,omitempty"`
+	// Repository description: Name of the Sourcegraph repository that contains the manifest file. The repository must be synced by another code host connection. Also set the corresponding "path" field.
+	Repository string `json:"repository,omitempty"`
+	// RepositoryPathPattern description: The pattern used to generate the Sourcegraph repository name of manifest entries without a "name". In the pattern, the variable "{base}" is replaced with the host of the clone URL, and "{repo}" is replaced with the path of the clone URL.
+	//
+	// It is important that the Sourcegraph repository names be unique. If different code hosts generate repository names that collide, Sourcegraph's behavior is undefined.
+	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
+	// Revision description: Revision of "repository" that the manifest file is read from. Defaults to the default branch.
+	Revision string `json:"revision,omitempty"`
+	// Token description: A token sent as a bearer token in the Authorization header when fetching the manifest from "url".
+	Token string `json:"token,omitempty"`
+	// Url description: URL of the manifest, which is a JSON or YAML document with a "repos" list. Each entry has the clone "url" of a repository, and optionally its "name" on Sourcegraph, its "description" and whether it is "private".
+	Url string `json:"url,omitempty"`
+}
 type Notice struct {
 	// Dismissible description: Whether this notice can be dismissed (closed) by the user.
 	Dismissible bool `json:"dismissible,omitempty"`
@@ -811,7 +925,7 @@ type OpenIDConnectAuthProvider struct {
 	Type               string `json:"type"`
 }
 
-// OtherExternalServiceConnection description: Configuration for a Connection to Git repositories for which an external service integration isn't yet available.
+// OtherExternalServiceConnection description: Configuration for a Connection to Git repositories for which an external service integration isn't yet available. Perforce depots (perforce:// and perforce+ssl:// URLs) and Mercurial repositories (hg+https://, hg+http:// and hg+ssh:// URLs) are converted to Git.
 type OtherExternalServiceConnection struct {
 	Repos []string `json:"repos"`
 	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for the repositories. In the pattern, the variable "{base}" is replaced with the Git clone base URL host and path, and "{repo}" is replaced with the repository path taken from the `repos` field.
@@ -1042,8 +1156,12 @@ type SiteConfiguration struct {
 	//
 	// Only available in Sourcegraph Enterprise.
 	Branding *Branding `json:"branding,omitempty"`
+	// CampaignsCommitSigning description: Signs the commits that gitserver creates for campaigns and patches, so that they can be pushed to branches that require signed commits. The signing key is a secret: it is only readable by site admins and gitserver.
+	CampaignsCommitSigning *CampaignsCommitSigning `json:"campaigns.commitSigning,omitempty"`
 	// CampaignsReadAccessEnabled description: Enables read-only access to campaigns for non-site-admin users. This is a setting for the experimental campaigns feature. These will only have an effect when campaigns is enabled with `{"experimentalFeatures": {"automation": "enabled"}}`.
 	CampaignsReadAccessEnabled *bool `json:"campaigns.readAccess.enabled,omitempty"`
+	// CodeIntelRetentionPolicies description: Retention policies for precise code intelligence uploads. The first policy whose repository pattern matches a repository's name applies to that repository. Uploads of repositories without a matching policy are only removed under disk pressure. Uploads visible at the tip of the default branch are never removed by a retention policy.
+	CodeIntelRetentionPolicies []*CodeIntelRetentionPolicy `json:"codeIntel.retentionPolicies,omitempty"`
 	// CorsOrigin description: Required when using any of the native code host integrations for Phabricator, GitLab, or Bitbucket Server. It is a space-separated list of allowed origins for cross-origin HTTP requests which should be the base URL for your Phabricator, GitLab, or Bitbucket Server instance.
 	CorsOrigin string `json:"corsOrigin,omitempty"`
 	// DebugSearchSymbolsParallelism description: (debug) controls the amount of symbol search parallelism. Defaults to 20. It is not recommended to change this outside of debugging scenarios. This option will be removed in a future version.
@@ -1072,8 +1190,16 @@ type SiteConfiguration struct {
 	ExternalURL string `json:"externalURL,omitempty"`
 	// GitCloneURLToRepositoryName description: JSON array of configuration that maps from Git clone URL to repository name. Sourcegraph automatically resolves remote clone URLs to their proper code host. However, there may be non-remote clone URLs (e.g., in submodule declarations) that Sourcegraph cannot automatically map to a code host. In this case, use this field to specify the mapping. The mappings are tried in the order they are specified and take precedence over automatic mappings.
 	GitCloneURLToRepositoryName []*CloneURLToRepositoryName `json:"git.cloneURLToRepositoryName,omitempty"`
+	// GitCloneStrategies description: How repositories are cloned and fetched. By default, all branches, tags and other refs of a repository are cloned with all of their contents. A repository uses the first strategy whose repository pattern matches its name. Changing the strategy of a cloned repository reclones it.
+	GitCloneStrategies []*GitCloneStrategy `json:"gitCloneStrategies,omitempty"`
+	// GitLFS description: Git LFS support. When enabled, gitserver downloads the Git LFS objects of repositories using Git LFS from their code hosts, with the same credentials used to clone them, so that archives used for search contain the actual file contents instead of LFS pointer files. Only HTTP(S) clone URLs are supported.
+	GitLFS *GitLFS `json:"gitLFS,omitempty"`
 	// GitMaxConcurrentClones description: Maximum number of git clone processes that will be run concurrently to update repositories.
 	GitMaxConcurrentClones int `json:"gitMaxConcurrentClones,omitempty"`
+	// GitServeRepositories description: Whether users can clone and fetch repositories from Sourcegraph with git at `<Sourcegraph URL>/.git/<repository name>`, for example to use Sourcegraph as a mirror for CI systems. Repository permissions are enforced. Git clients authenticate with an access token as the username or password of HTTP basic auth. Pushing is not supported.
+	GitServeRepositories bool `json:"gitServeRepositories,omitempty"`
+	// GitUpdateClasses description: Classes of repositories that are updated from their code hosts at different intervals. A repository belongs to the first class whose repository pattern matches its name. Repositories are fetched more often the more recently they changed, within the minimum and maximum interval of their class. Repositories that belong to no class are fetched at intervals between 45 seconds and 8 hours.
+	GitUpdateClasses []*GitUpdateClass `json:"gitUpdateClasses,omitempty"`
 	// GithubClientID description: Client ID for GitHub. (DEPRECATED)
 	GithubClientID string `json:"githubClientID,omitempty"`
 	// GithubClientSecret description: Client secret for GitHub. (DEPRECATED)
@@ -1114,6 +1240,10 @@ type SiteConfiguration struct {
 	PermissionsUserMapping *PermissionsUserMapping `json:"permissions.userMapping,omitempty"`
 	// RepoListUpdateInterval description: Interval (in minutes) for checking code hosts (such as GitHub, Gitolite, etc.) for new repositories.
 	RepoListUpdateInterval int `json:"repoListUpdateInterval,omitempty"`
+	// RepoPurgeGracePeriod description: Time (in hours) that the clones of repositories removed from all external services are kept on disk. Within this period, a site admin can restore such a repository without recloning it. Clones of removed repositories are purged once a week, on Saturday night.
+	RepoPurgeGracePeriod int `json:"repoPurgeGracePeriod,omitempty"`
+	// SearchIncludeSubmodules description: Whether unindexed searches include the contents of Git submodules. A submodule is searched if its clone URL maps to a repository on Sourcegraph (using the code host connections and git.cloneURLToRepositoryName). Matches in a submodule are attributed to that repository at the commit pinned by the submodule. Nested submodules are not searched.
+	SearchIncludeSubmodules bool `json:"search.includeSubmodules,omitempty"`
 	// SearchIndexEnabled description: Whether indexed search is enabled. If unset Sourcegraph detects the environment to decide if indexed search is enabled. Indexed search is RAM heavy, and is disabled by default in the single docker image. All other environments will have it enabled by default. The size of all your repository working copies is the amount of additional RAM required.
 	SearchIndexEnabled *bool `json:"search.index.enabled,omitempty"`
 	// SearchIndexSymbolsEnabled description: Whether indexed symbol search is enabled. This is contingent on the indexed search configuration, and is true by default for instances with indexed search enabled. Enabling this will cause every repository to re-index, which is a time consuming (several hours) operation. Additionally, it requires more storage and ram to accommodate the added symbols information in the search index.
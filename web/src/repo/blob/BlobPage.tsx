@@ -1,6 +1,7 @@
 import * as H from 'history'
 import { isEqual, pick } from 'lodash'
 import AlertCircleIcon from 'mdi-react/AlertCircleIcon'
+import prettyBytes from 'pretty-bytes'
 import * as React from 'react'
 import { combineLatest, Observable, Subject, Subscription } from 'rxjs'
 import { catchError, distinctUntilChanged, map, mapTo, startWith, switchMap, tap } from 'rxjs/operators'
@@ -61,12 +62,18 @@ const fetchBlob = memoizeObservable(
                     repository(name: $repoName) {
                         commit(rev: $commitID) {
                             file(path: $filePath) {
+                                __typename
                                 content
                                 richHTML
                                 highlight(disableTimeout: $disableTimeout, isLightTheme: $isLightTheme) {
                                     aborted
                                     html
                                 }
+                                ... on GitBlob {
+                                    lfs {
+                                        byteSize
+                                    }
+                                }
                             }
                         }
                     }
@@ -300,6 +307,14 @@ export class BlobPage extends React.PureComponent<Props, State> {
                         history={this.props.history}
                     />
                 )}
+                {this.state.blobOrError.__typename === 'GitBlob' && this.state.blobOrError.lfs && (
+                    <div className="blob-page__lfs">
+                        <div className="alert alert-info">
+                            This file is stored in Git LFS ({prettyBytes(this.state.blobOrError.lfs.byteSize)}). Only
+                            its LFS pointer is shown.
+                        </div>
+                    </div>
+                )}
                 {!this.state.blobOrError.richHTML && this.state.blobOrError.highlight.aborted && (
                     <div className="blob-page__aborted">
                         <div className="alert alert-info">
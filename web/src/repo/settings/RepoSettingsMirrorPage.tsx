@@ -83,12 +83,28 @@ class UpdateMirrorRepositoryActionContainer extends React.PureComponent<UpdateMi
                             {updateSchedule.index + 1} out of {updateSchedule.total} in the schedule)
                         </div>
                     )}
+                    {updateSchedule?.class && (
+                        <div>
+                            Update class <code>{updateSchedule.class}</code> (updated every{' '}
+                            {updateSchedule.minIntervalSeconds} to {updateSchedule.maxIntervalSeconds} seconds)
+                        </div>
+                    )}
                     {this.props.repo.mirrorInfo.updateQueue && !this.props.repo.mirrorInfo.updateQueue.updating && (
                         <div>
                             Queued for update (position {this.props.repo.mirrorInfo.updateQueue.index + 1} out of{' '}
                             {this.props.repo.mirrorInfo.updateQueue.total} in the queue)
                         </div>
                     )}
+                    {this.props.repo.mirrorInfo.lastBackupAt && (
+                        <div>
+                            Last backed up: <Timestamp date={this.props.repo.mirrorInfo.lastBackupAt} />
+                        </div>
+                    )}
+                    {this.props.repo.mirrorInfo.lastSyncError && (
+                        <div>
+                            Last update failed: <code>{this.props.repo.mirrorInfo.lastSyncError}</code>
+                        </div>
+                    )}
                 </>
             )
             if (!updateSchedule) {
@@ -100,7 +116,14 @@ class UpdateMirrorRepositoryActionContainer extends React.PureComponent<UpdateMi
             buttonLabel = 'Refresh now'
         } else {
             title = 'Clone this repository'
-            description = 'This repository has not yet been cloned from its remote repository.'
+            description = this.props.repo.mirrorInfo.lastSyncError ? (
+                <>
+                    Cloning this repository from its remote repository failed:{' '}
+                    <code>{this.props.repo.mirrorInfo.lastSyncError}</code>
+                </>
+            ) : (
+                'This repository has not yet been cloned from its remote repository.'
+            )
             buttonLabel = 'Clone now'
         }
 
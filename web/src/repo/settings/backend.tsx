@@ -27,12 +27,17 @@ export function fetchRepository(name: string): Observable<GQL.IRepository> {
                             due
                             index
                             total
+                            class
+                            minIntervalSeconds
+                            maxIntervalSeconds
                         }
                         updateQueue {
                             updating
                             index
                             total
                         }
+                        lastBackupAt
+                        lastSyncError
                     }
                     externalServices {
                         nodes {
@@ -9,9 +9,11 @@ import React from 'react'
 import awsCodeCommitSchemaJSON from '../../../schema/aws_codecommit.schema.json'
 import bitbucketCloudSchemaJSON from '../../../schema/bitbucket_cloud.schema.json'
 import bitbucketServerSchemaJSON from '../../../schema/bitbucket_server.schema.json'
+import gerritSchemaJSON from '../../../schema/gerrit.schema.json'
 import githubSchemaJSON from '../../../schema/github.schema.json'
 import gitlabSchemaJSON from '../../../schema/gitlab.schema.json'
 import gitoliteSchemaJSON from '../../../schema/gitolite.schema.json'
+import manifestSchemaJSON from '../../../schema/manifest.schema.json'
 import otherExternalServiceSchemaJSON from '../../../schema/other_external_service.schema.json'
 import phabricatorSchemaJSON from '../../../schema/phabricator.schema.json'
 import { PhabricatorIcon } from '../../../shared/src/components/icons'
@@ -98,6 +100,8 @@ const editorActionComments = {
     //    (https://docs.sourcegraph.com/admin/auth).
     // 3. Update the fields below to match the properties of this auth provider
     //    (https://docs.sourcegraph.com/admin/repo/permissions#sudo-access-token).`,
+    enforcePermissionsGerrit:
+        '// Prerequisite: Sourcegraph and Gerrit usernames must be identical, and the account in this config must be able to read the access rights of all projects (https://docs.sourcegraph.com/admin/external_service/gerrit#repository-permissions).',
 }
 
 const Field = (props: { children: React.ReactChildren | string | string[] }): JSX.Element => (
@@ -959,6 +963,89 @@ const GITOLITE: AddExternalServiceOptions = {
         },
     ],
 }
+const GERRIT: AddExternalServiceOptions = {
+    kind: GQL.ExternalServiceKind.GERRIT,
+    title: 'Gerrit',
+    icon: GitIcon,
+    jsonSchema: gerritSchemaJSON,
+    defaultDisplayName: 'Gerrit',
+    defaultConfig: `{
+  "url": "https://gerrit.example.com",
+  "username": "<username>",
+  "password": "<HTTP password>",
+  "projectQuery": ["?type=CODE"]
+}`,
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    In the configuration below, set <Field>url</Field> to the URL of your Gerrit instance.
+                </li>
+                <li>
+                    Set <Field>username</Field> and <Field>password</Field> to the credentials of a Gerrit account.
+                    The password is the account's HTTP password, generated under{' '}
+                    <strong>Settings &gt; HTTP Credentials</strong> in Gerrit.
+                </li>
+                <li>
+                    Use <Field>projects</Field> and <Field>projectQuery</Field> to select the projects to mirror.
+                </li>
+            </ol>
+            <p>
+                See{' '}
+                <a
+                    rel="noopener noreferrer"
+                    target="_blank"
+                    href="https://docs.sourcegraph.com/admin/external_service/gerrit#configuration"
+                >
+                    the docs for more advanced options
+                </a>
+                , or try one of the buttons below.
+            </p>
+        </div>
+    ),
+    editorActions: [
+        {
+            id: 'addProject',
+            label: 'Add a project',
+            run: config => {
+                const value = '<project>'
+                const edits = setProperty(config, ['projects', -1], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'setProjectQuery',
+            label: 'Add projects matching a prefix',
+            run: config => {
+                const value = '?p=<prefix>'
+                const edits = setProperty(config, ['projectQuery', -1], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'excludeProject',
+            label: 'Exclude a project',
+            run: config => {
+                const value = { name: '<project>' }
+                const edits = setProperty(config, ['exclude', -1], value, defaultFormattingOptions)
+                return { edits, selectText: '<project>' }
+            },
+        },
+        {
+            id: 'enforcePermissions',
+            label: 'Enforce permissions',
+            run: config => {
+                const value = {
+                    COMMENT_SENTINEL: true,
+                    ttl: '3h',
+                }
+                const comment = editorActionComments.enforcePermissionsGerrit
+                const edit = editWithComment(config, ['authorization'], value, comment)
+                return { edits: [edit], selectText: comment }
+            },
+        },
+    ],
+}
 const PHABRICATOR_SERVICE: AddExternalServiceOptions = {
     kind: GQL.ExternalServiceKind.PHABRICATOR,
     title: 'Phabricator connection',
@@ -1009,6 +1096,65 @@ const PHABRICATOR_SERVICE: AddExternalServiceOptions = {
         },
     ],
 }
+const MANIFEST: AddExternalServiceOptions = {
+    kind: GQL.ExternalServiceKind.MANIFEST,
+    title: 'Repository manifest',
+    icon: GitIcon,
+    jsonSchema: manifestSchemaJSON,
+    defaultDisplayName: 'Repository manifest',
+    defaultConfig: `{
+  "url": "https://example.com/repos.json"
+}`,
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    In the configuration below, set <Field>url</Field> to the URL of a JSON or YAML manifest that lists
+                    the clone URLs of your repositories under <Field>repos</Field>.
+                </li>
+                <li>
+                    Alternatively, set <Field>repository</Field> and <Field>path</Field> to read the manifest from a
+                    file in a repository that is already on Sourcegraph.
+                </li>
+            </ol>
+            <p>
+                The manifest is read again on every sync, so repositories added to or removed from it are added to or
+                removed from Sourcegraph. See{' '}
+                <a
+                    rel="noopener noreferrer"
+                    target="_blank"
+                    href="https://docs.sourcegraph.com/admin/external_service/manifest#configuration"
+                >
+                    the docs for the manifest format and more options
+                </a>
+                .
+            </p>
+        </div>
+    ),
+    editorActions: [
+        {
+            id: 'setToken',
+            label: 'Set access token',
+            run: config => {
+                const value = '<access token>'
+                const edits = setProperty(config, ['token'], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+        {
+            id: 'readFromRepository',
+            label: 'Read from a repository',
+            run: config => {
+                const value = '<Sourcegraph repository name>'
+                const edits = [
+                    ...setProperty(config, ['url'], undefined, defaultFormattingOptions),
+                    ...setProperty(config, ['repository'], value, defaultFormattingOptions),
+                ]
+                return { edits, selectText: value }
+            },
+        },
+    ],
+}
 const GENERIC_GIT: AddExternalServiceOptions = {
     kind: GQL.ExternalServiceKind.OTHER,
     title: 'Generic Git host',
@@ -1074,6 +1220,8 @@ export const codeHostExternalServices: Record<string, AddExternalServiceOptions>
     bitbucketserver: BITBUCKET_SERVER,
     aws_codecommit: AWS_CODE_COMMIT,
     gitolite: GITOLITE,
+    gerrit: GERRIT,
+    manifest: MANIFEST,
     git: GENERIC_GIT,
 }
 
@@ -1092,6 +1240,8 @@ export const defaultExternalServices: Record<GQL.ExternalServiceKind, AddExterna
     [GQL.ExternalServiceKind.BITBUCKETSERVER]: BITBUCKET_SERVER,
     [GQL.ExternalServiceKind.GITLAB]: GITLAB_DOTCOM,
     [GQL.ExternalServiceKind.GITOLITE]: GITOLITE,
+    [GQL.ExternalServiceKind.GERRIT]: GERRIT,
+    [GQL.ExternalServiceKind.MANIFEST]: MANIFEST,
     [GQL.ExternalServiceKind.PHABRICATOR]: PHABRICATOR_SERVICE,
     [GQL.ExternalServiceKind.OTHER]: GENERIC_GIT,
     [GQL.ExternalServiceKind.AWSCODECOMMIT]: AWS_CODE_COMMIT,